@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/importers"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	customerusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/duplicates"
+	"github.com/CAVAh/api-tech-challenge/src/infra/audit"
+	"github.com/CAVAh/api-tech-challenge/src/infra/auth"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/repositories"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/routes"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"gopkg.in/validator.v2"
+)
+
+// runCommand escolhe o subcomando: serve (padrão), keys rotate, reencrypt, audit verify, duplicates detect
+// ou customers import
+func runCommand(args []string, keyring *encryption.Keyring) error {
+	if len(args) == 0 {
+		return routes.HandleRequests()
+	}
+
+	switch args[0] {
+	case "serve":
+		return routes.HandleRequests()
+	case "keys":
+		if len(args) < 2 || args[1] != "rotate" {
+			return fmt.Errorf("uso: keys rotate")
+		}
+		return rotateKeys(context.Background(), keyring)
+	case "reencrypt":
+		return reencrypt(context.Background(), keyring, args[1:])
+	case "audit":
+		if len(args) < 2 || args[1] != "verify" {
+			return fmt.Errorf("uso: audit verify [--batch-size n]")
+		}
+		return verifyAudit(context.Background(), args[2:])
+	case "duplicates":
+		if len(args) < 2 || args[1] != "detect" {
+			return fmt.Errorf("uso: duplicates detect [--batch-size n] [--min-score x] [--max-block-size n]")
+		}
+		return detectDuplicates(context.Background(), args[2:])
+	case "customers":
+		if len(args) < 2 || args[1] != "import" {
+			return fmt.Errorf("uso: customers import --file arquivo [--format csv|ndjson] [--on-conflict skip|upsert] [--dry-run] [--report arquivo] [--batch-size n]")
+		}
+		return importCustomers(context.Background(), args[2:])
+	default:
+		return fmt.Errorf("comando desconhecido: %s", args[0])
+	}
+}
+
+// rotateKeys embrulha as chaves de dados com a chave mestra ativa e cria uma nova chave de dados.
+// As réplicas em execução passam a usá-la em até ENCRYPTION_KEYRING_REFRESH.
+func rotateKeys(ctx context.Context, keyring *encryption.Keyring) error {
+	rewrapped, err := keyring.Rewrap(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := keyring.Rotate(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Chave de dados rotacionada", "keyId", id, "rewrapped", rewrapped)
+
+	return nil
+}
+
+// reencrypt regrava CPF e email de todos os clientes com a chave de dados ativa
+func reencrypt(ctx context.Context, keyring *encryption.Keyring, args []string) error {
+	flags := flag.NewFlagSet("reencrypt", flag.ContinueOnError)
+	batchSize := flags.Int("batch-size", 500, "clientes por lote")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *batchSize <= 0 {
+		return fmt.Errorf("batch-size deve ser positivo")
+	}
+
+	repository := repositories.CustomerReencryptionRepository{DB: database.DB, Keyring: keyring}
+
+	var lastID uint
+	total := 0
+	for {
+		nextID, updated, err := repository.ReencryptBatch(ctx, lastID, *batchSize)
+		if err != nil {
+			return err
+		}
+
+		total += updated
+		if nextID == 0 {
+			break
+		}
+
+		lastID = nextID
+		slog.Info("Lote recriptografado", "lastId", lastID, "updated", updated)
+	}
+
+	slog.Info("Recriptografia concluída", "updated", total)
+
+	return nil
+}
+
+// verifyAudit confere a cadeia de hashes da auditoria. O hash da última entrada deve ser guardado
+// fora do banco: comparado na próxima verificação, revela também a remoção das entradas finais.
+func verifyAudit(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("audit verify", flag.ContinueOnError)
+	batchSize := flags.Int("batch-size", 1000, "entradas por lote")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *batchSize <= 0 {
+		return fmt.Errorf("batch-size deve ser positivo")
+	}
+
+	result, err := audit.Verify(ctx, repositories.AuditRepository{DB: database.DB}, *batchSize)
+	if err != nil {
+		return err
+	}
+
+	if result.BrokenAt != 0 {
+		return fmt.Errorf("cadeia da auditoria inválida na sequência %d (%d entradas íntegras antes dela)", result.BrokenAt, result.Checked)
+	}
+
+	slog.Info("Cadeia da auditoria íntegra", "entries", result.Checked, "headSequence", result.HeadSequence, "headHash", result.HeadHash)
+
+	return nil
+}
+
+// detectDuplicates grava os possíveis clientes duplicados para revisão em /admin/customers/duplicates.
+// Pode ser agendado: cada execução substitui os pares pendentes da anterior.
+func detectDuplicates(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("duplicates detect", flag.ContinueOnError)
+	batchSize := flags.Int("batch-size", usecases.DefaultBatchSize, "clientes por lote")
+	minScore := flags.Float64("min-score", usecases.DefaultMinScore, "score mínimo para gravar um par, entre 0 e 1")
+	maxBlockSize := flags.Int("max-block-size", usecases.DefaultMaxBlockSize, "maior grupo de clientes comparados entre si")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *batchSize <= 0 || *maxBlockSize <= 1 {
+		return fmt.Errorf("batch-size deve ser positivo e max-block-size maior que 1")
+	}
+
+	if *minScore <= 0 || *minScore > 1 {
+		return fmt.Errorf("min-score deve estar entre 0 e 1")
+	}
+
+	usecase := usecases.DetectDuplicatesUsecase{
+		DuplicateRepository: repositories.DuplicateRepository{DB: database.DB},
+		MinScore:            *minScore,
+		MaxBlockSize:        *maxBlockSize,
+		BatchSize:           *batchSize,
+		Tracer:              tracing.UsecaseTracer{},
+	}
+
+	result, err := usecase.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Detecção de duplicidades concluída", "customers", result.Customers, "candidates", result.Candidates)
+
+	return nil
+}
+
+// importCustomers cadastra clientes de um CSV ou NDJSON (--file - lê da entrada padrão). As linhas
+// ignoradas e as que falharam vão para --report, uma linha JSON cada, ou para o log sem ele.
+func importCustomers(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("customers import", flag.ContinueOnError)
+	file := flags.String("file", "", "arquivo a importar; - lê da entrada padrão")
+	format := flags.String("format", "", "csv ou ndjson; sem ele vale a extensão do arquivo")
+	onConflict := flags.String("on-conflict", dtos.ImportConflictSkip, "skip ignora e upsert atualiza os clientes já cadastrados")
+	dryRun := flags.Bool("dry-run", false, "valida e simula sem gravar")
+	reportPath := flags.String("report", "", "arquivo NDJSON para o relatório das linhas ignoradas e com erro")
+	batchSize := flags.Int("batch-size", customerusecases.DefaultImportBatchSize, "linhas por lote")
+	actor := flags.String("actor", "cli", "quem importa, registrado na auditoria como cli:<actor>")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" || *batchSize <= 0 {
+		return fmt.Errorf("informe --file e um batch-size positivo")
+	}
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(*file)) {
+		case ".csv":
+			*format = importers.FormatCSV
+		case ".ndjson", ".jsonl":
+			*format = importers.FormatNDJSON
+		}
+	}
+
+	inputDto := dtos.ImportCustomersDto{Format: *format, OnConflict: *onConflict, DryRun: *dryRun}
+	if err := validator.Validate(inputDto); err != nil {
+		return err
+	}
+
+	input := io.Reader(os.Stdin)
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	source, err := importers.NewCustomerReader(inputDto.Format, input)
+	if err != nil {
+		return err
+	}
+
+	report := func(row dtos.ImportRowResultDto) error {
+		slog.Warn("Linha não importada", "line", row.Line, "outcome", row.Outcome, "customerId", row.CustomerID, "error", row.Error)
+		return nil
+	}
+	if *reportPath != "" {
+		f, err := os.Create(*reportPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		encoder := json.NewEncoder(f)
+		report = func(row dtos.ImportRowResultDto) error {
+			return encoder.Encode(row)
+		}
+	}
+
+	usecase := customerusecases.ImportCustomersUsecase{
+		Create: &customerusecases.CreateCustomerUsecase{
+			CustomerRepository:  routes.NewCustomerRepository(),
+			RequireVerification: utils.GetEnvBool("CUSTOMER_REQUIRE_VERIFICATION", false),
+			Tracer:              tracing.UsecaseTracer{},
+		},
+		BatchSize: *batchSize,
+		Indexes:   repositories.CustomerIndexes{},
+		OnBatch: func(summary dtos.ImportCustomersSummaryDto) {
+			slog.Info("Lote importado", "rows", summary.Rows, "created", summary.Created, "updated", summary.Updated, "skipped", summary.Skipped, "failed", summary.Failed)
+		},
+		Tracer: tracing.UsecaseTracer{},
+	}
+
+	ctx = audit.WithActor(ctx, audit.Actor{ID: "cli:" + *actor, Role: auth.RoleAdmin})
+
+	summary, err := usecase.Execute(ctx, source, inputDto, report)
+	if err != nil {
+		if summary != nil {
+			slog.Error("Importação interrompida", "rows", summary.Rows, "created", summary.Created, "updated", summary.Updated)
+		}
+		return err
+	}
+
+	slog.Info("Importação concluída", "dryRun", summary.DryRun, "rows", summary.Rows, "created", summary.Created, "updated", summary.Updated, "skipped", summary.Skipped, "failed", summary.Failed)
+
+	return nil
+}
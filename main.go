@@ -1,11 +1,45 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+	"os"
+
 	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
-	"github.com/CAVAh/api-tech-challenge/src/infra/web/routes"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/repositories"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
 )
 
 func main() {
+	logging.Setup()
+
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		logging.Fatal("Erro ao configurar tracing", err)
+	}
+
 	database.ConnectDB()
-	routes.HandleRequests()
+
+	keyring, err := encryption.Setup(context.Background(), repositories.EncryptionKeyRepository{DB: database.DB})
+	if err != nil {
+		logging.Fatal("Erro ao configurar a criptografia de campos", err)
+	}
+
+	exitCode := 0
+	if err := runCommand(os.Args[1:], keyring); err != nil {
+		slog.Error("Erro ao executar o comando", "error", err)
+		exitCode = 1
+	}
+
+	if err := database.CloseDB(); err != nil {
+		slog.Error("Erro ao fechar conexões com banco de dados", "error", err)
+	}
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		slog.Error("Erro ao exportar traces pendentes", "error", err)
+	}
+
+	os.Exit(exitCode)
 }
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/address"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/middlewares"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func ListCurrentAddresses(c *gin.Context, usecase *usecases.ListAddressesUsecase) {
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey))
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func GetCurrentAddress(c *gin.Context, usecase *usecases.GetAddressUsecase) {
+	addressID, ok := addressIDParam(c)
+	if !ok {
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), addressID)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func CreateCurrentAddress(c *gin.Context, usecase *usecases.CreateAddressUsecase) {
+	var inputDto dtos.CreateAddressDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func UpdateCurrentAddress(c *gin.Context, usecase *usecases.UpdateAddressUsecase) {
+	addressID, ok := addressIDParam(c)
+	if !ok {
+		return
+	}
+
+	var inputDto dtos.UpdateAddressDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), addressID, inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func DeleteCurrentAddress(c *gin.Context, usecase *usecases.DeleteAddressUsecase) {
+	addressID, ok := addressIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), addressID); err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func LookupCEP(c *gin.Context, usecase *usecases.LookupCEPUsecase) {
+	result, err := usecase.Execute(c.Request.Context(), c.Param("cep"))
+
+	if err != nil {
+		status := statusForError(err)
+		// na consulta direta um CEP inexistente é o recurso que não existe
+		if errors.Is(err, entities.ErrCEPNotFound) {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func addressIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("addressId"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "id de endereço inválido",
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// statusForError traduz os erros de domínio dos endereços para o status HTTP
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrCustomerNotFound), errors.Is(err, entities.ErrAddressNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrInvalidCEP), errors.Is(err, entities.ErrInvalidState), errors.Is(err, entities.ErrCEPRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, entities.ErrCEPNotFound), errors.Is(err, entities.ErrIncompleteAddress), errors.Is(err, entities.ErrAddressLimitReached):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, entities.ErrCEPProviderUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/audit"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func ListAuditEntries(c *gin.Context, usecase *usecases.ListAuditEntriesUsecase) {
+	var inputDto dtos.ListAuditEntriesDto
+
+	if err := c.ShouldBindQuery(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrInvalidCursor):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
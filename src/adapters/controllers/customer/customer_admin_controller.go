@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/importers"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func EraseCustomer(c *gin.Context, usecase *usecases.EraseCustomerUsecase) {
+	customerID, ok := customerIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := usecase.Execute(c.Request.Context(), customerID); err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangeCustomerStatus atende os métodos :activate, :suspend e :close; o destino vem da rota
+func ChangeCustomerStatus(c *gin.Context, usecase *usecases.ChangeCustomerStatusUsecase, target entities.CustomerStatus) {
+	customerID, ok := customerIDParam(c)
+	if !ok {
+		return
+	}
+
+	var inputDto dtos.ChangeCustomerStatusDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), customerID, target, inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func ListCustomerChanges(c *gin.Context, usecase *usecases.ListCustomerChangesUsecase) {
+	var inputDto dtos.ListCustomerChangesDto
+
+	if err := c.ShouldBindQuery(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// customerIDParam lê o :id da rota; em caso de valor inválido já responde 400
+func customerIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "id de cliente inválido",
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// ImportUpload limita o upload da importação: tamanho do corpo, duração da requisição, que passa
+// por cima dos timeouts do servidor, e quantas linhas do relatório voltam na resposta
+type ImportUpload struct {
+	MaxBytes      int64
+	Timeout       time.Duration
+	MaxReportRows int
+}
+
+// ImportCustomers recebe o arquivo cru no corpo, em CSV ou NDJSON, e o importa enquanto lê. Se a
+// importação parar no meio, a resposta de erro traz o resumo do que já foi gravado.
+func ImportCustomers(c *gin.Context, usecase *usecases.ImportCustomersUsecase, upload ImportUpload) {
+	var inputDto dtos.ImportCustomersDto
+
+	if err := c.ShouldBindQuery(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	format := inputDto.Format
+	if format == "" {
+		format = importers.FormatFromContentType(c.ContentType())
+	}
+
+	// arquivos grandes não cabem nos timeouts das demais rotas; sem suporte do writer, valem os do servidor
+	controller := http.NewResponseController(c.Writer)
+	_ = controller.SetReadDeadline(time.Now().Add(upload.Timeout))
+	_ = controller.SetWriteDeadline(time.Now().Add(upload.Timeout))
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, upload.MaxBytes)
+
+	source, err := importers.NewCustomerReader(format, body)
+	if err != nil {
+		c.JSON(importStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	report := dtos.ImportCustomersReportDto{Rows: []dtos.ImportRowResultDto{}}
+
+	summary, err := usecase.Execute(c.Request.Context(), source, inputDto, func(row dtos.ImportRowResultDto) error {
+		if len(report.Rows) < upload.MaxReportRows {
+			report.Rows = append(report.Rows, row)
+		} else {
+			report.RowsTruncated = true
+		}
+		return nil
+	})
+
+	if err != nil {
+		c.JSON(importStatus(err), gin.H{
+			"error":   err.Error(),
+			"summary": summary,
+		})
+		return
+	}
+
+	report.Summary = *summary
+
+	c.JSON(http.StatusOK, report)
+}
+
+func importStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return statusForError(err)
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newImportRouter(mockRepo *MockCustomerRepository, upload ImportUpload) *gin.Engine {
+	usecase := usecases.ImportCustomersUsecase{
+		Create: &usecases.CreateCustomerUsecase{CustomerRepository: mockRepo, Tracer: tracing.UsecaseTracer{}},
+		Tracer: tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.POST("/admin/customers:import", func(c *gin.Context) {
+		ImportCustomers(c, &usecase, upload)
+	})
+
+	return r
+}
+
+func TestImportCustomers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	mockRepo.On("FindByDocument", entities.CPFDocument("12345678900")).Return(nil, entities.ErrCustomerNotFound)
+	mockRepo.On("FindByDocument", entities.CPFDocument("98765432100")).Return(&entities.Customer{ID: 3}, nil)
+	mockRepo.On("Create", mock.Anything).Return(&entities.Customer{ID: 9}, nil)
+
+	r := newImportRouter(mockRepo, ImportUpload{MaxBytes: 1 << 20, Timeout: time.Minute, MaxReportRows: 1})
+
+	file := "name,cpf,email\n" +
+		"João da Silva,12345678900,joao@example.com\n" +
+		"Maria,98765432100,maria@example.com\n" +
+		"Sem Email,11122233344,\n"
+	req, _ := http.NewRequest(http.MethodPost, "/admin/customers:import", strings.NewReader(file))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report dtos.ImportCustomersReportDto
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, dtos.ImportCustomersSummaryDto{Rows: 3, Created: 1, Skipped: 1, Failed: 1}, report.Summary)
+	assert.Equal(t, []dtos.ImportRowResultDto{{Line: 3, Outcome: dtos.ImportRowSkipped, CustomerID: 3, Error: entities.ErrCustomerAlreadyExists.Error()}}, report.Rows)
+	assert.True(t, report.RowsTruncated)
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestImportCustomers_DryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	mockRepo.On("FindByDocument", mock.Anything).Return(nil, entities.ErrCustomerNotFound)
+
+	r := newImportRouter(mockRepo, ImportUpload{MaxBytes: 1 << 20, Timeout: time.Minute, MaxReportRows: 100})
+
+	file := `{"name":"João da Silva","cpf":"12345678900","email":"joao@example.com"}` + "\n"
+	req, _ := http.NewRequest(http.MethodPost, "/admin/customers:import?format=ndjson&dryRun=true", strings.NewReader(file))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"summary":{"dryRun":true,"rows":1,"created":1,"updated":0,"skipped":0,"failed":0},"rows":[],"rowsTruncated":false}`, w.Body.String())
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestImportCustomers_InvalidUpload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := newImportRouter(new(MockCustomerRepository), ImportUpload{MaxBytes: 64, Timeout: time.Minute, MaxReportRows: 100})
+
+	for name, tc := range map[string]struct {
+		query       string
+		contentType string
+		body        string
+		status      int
+	}{
+		"formato desconhecido":  {contentType: "application/json", body: "{}", status: http.StatusBadRequest},
+		"política desconhecida": {query: "?format=csv&onConflict=replace", body: "name,cpf,email\n", status: http.StatusBadRequest},
+		"cabeçalho sem email":   {query: "?format=csv", body: "name,cpf\n", status: http.StatusBadRequest},
+		"arquivo grande demais": {query: "?format=ndjson", body: strings.Repeat(`{"name":"a"}`+"\n", 10), status: http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/admin/customers:import"+tc.query, bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
@@ -9,6 +9,10 @@ import (
 	"gopkg.in/validator.v2"
 )
 
+// SessionReasonHeader carrega o motivo de uma sessão anônima emitida para cliente inativo na v1,
+// cujo corpo continua sendo só o token.
+const SessionReasonHeader = "X-Session-Reason"
+
 func ListCustomers(c *gin.Context, usecase *usecases.ListCustomerUsecase) {
 	var inputDto dtos.ListCustomerDto
 
@@ -21,7 +25,7 @@ func ListCustomers(c *gin.Context, usecase *usecases.ListCustomerUsecase) {
 		return
 	}
 
-	result, err := usecase.Execute(inputDto)
+	session, err := usecase.Execute(c.Request.Context(), inputDto)
 
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -30,11 +34,15 @@ func ListCustomers(c *gin.Context, usecase *usecases.ListCustomerUsecase) {
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	if session.Reason != "" {
+		c.Header(SessionReasonHeader, session.Reason)
+	}
+
+	c.JSON(http.StatusOK, session.Token)
 }
 
 func CreateCustomer(c *gin.Context, usecase *usecases.CreateCustomerUsecase) {
-	var inputDto dtos.CreateCustomerDto
+	var inputDto dtos.LegacyCreateCustomerDto
 
 	if err := c.ShouldBindJSON(&inputDto); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -50,7 +58,12 @@ func CreateCustomer(c *gin.Context, usecase *usecases.CreateCustomerUsecase) {
 		return
 	}
 
-	result, err := usecase.Execute(inputDto)
+	// a v1 continua só com CPF; documento e telefone são da v2
+	result, err := usecase.Execute(c.Request.Context(), dtos.CreateCustomerDto{
+		Name:  inputDto.Name,
+		CPF:   inputDto.CPF,
+		Email: inputDto.Email,
+	})
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -59,5 +72,11 @@ func CreateCustomer(c *gin.Context, usecase *usecases.CreateCustomerUsecase) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, result)
+	c.JSON(http.StatusCreated, dtos.LegacyCustomerDto{
+		ID:        result.ID,
+		Name:      result.Name,
+		CPF:       result.CPF,
+		Email:     result.Email,
+		CreatedAt: result.CreatedAt,
+	})
 }
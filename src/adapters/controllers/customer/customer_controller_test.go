@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -12,18 +13,54 @@ import (
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
 	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 // Mock do repositório de clientes
+type noopMetrics struct{}
+
+func (noopMetrics) TokenIssued(string) {}
+
+func (noopMetrics) LoyaltyPoints(string, int64) {}
+
+type noopAuditLog struct {
+	gateways.AuditLog
+}
+
+func (noopAuditLog) Record(ctx context.Context, entries ...entities.AuditEntry) error {
+	return nil
+}
+
+// staticRedirects liga ids incorporados numa fusão aos sobreviventes
+type staticRedirects map[uint]uint
+
+func (r staticRedirects) Resolve(ctx context.Context, id uint) (uint, error) {
+	if to, ok := r[id]; ok {
+		return to, nil
+	}
+	return id, nil
+}
+
+func (r staticRedirects) ResolveMany(ctx context.Context, ids []uint) (map[uint]uint, error) {
+	result := map[uint]uint{}
+	for _, id := range ids {
+		if to, ok := r[id]; ok {
+			result[id] = to
+		}
+	}
+	return result, nil
+}
+
 type MockCustomerRepository struct {
 	gateways.CustomerRepository
 	mock.Mock
 }
 
-func (m *MockCustomerRepository) Create(customer *entities.Customer) (*entities.Customer, error) {
+func (m *MockCustomerRepository) Create(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
 	args := m.Called(customer)
 	if args.Get(0) != nil {
 		return args.Get(0).(*entities.Customer), args.Error(1)
@@ -31,7 +68,7 @@ func (m *MockCustomerRepository) Create(customer *entities.Customer) (*entities.
 	return nil, args.Error(1)
 }
 
-func (m *MockCustomerRepository) FindFirstByCpf(customer *entities.Customer) (*entities.Customer, error) {
+func (m *MockCustomerRepository) FindFirstByCpf(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
 	args := m.Called(customer)
 	if args.Get(0) != nil {
 		return args.Get(0).(*entities.Customer), args.Error(1)
@@ -39,6 +76,30 @@ func (m *MockCustomerRepository) FindFirstByCpf(customer *entities.Customer) (*e
 	return nil, args.Error(1)
 }
 
+func (m *MockCustomerRepository) FindByDocument(ctx context.Context, document entities.IdentityDocument) (*entities.Customer, error) {
+	args := m.Called(document)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entities.Customer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCustomerRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	args := m.Called(id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*entities.Customer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCustomerRepository) FindByIDs(ctx context.Context, ids []uint) ([]entities.Customer, error) {
+	args := m.Called(ids)
+	if args.Get(0) != nil {
+		return args.Get(0).([]entities.Customer), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func TestListCustomer_InvalidInput(t *testing.T) {
 	// Configurar o gin em modo de teste
 	gin.SetMode(gin.TestMode)
@@ -49,6 +110,9 @@ func TestListCustomer_InvalidInput(t *testing.T) {
 	// Substituir o repositório real pelo mock no usecase
 	usecase := usecases.ListCustomerUsecase{
 		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Metrics:            noopMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	// Configurar o controlador com o mock do usecase
@@ -82,6 +146,9 @@ func TestListCustomer_UseCaseError(t *testing.T) {
 	// Substituir o repositório real pelo mock no usecase
 	usecase := usecases.ListCustomerUsecase{
 		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Metrics:            noopMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	// Configurar o controlador com o mock do usecase
@@ -112,6 +179,7 @@ func TestListCustomers(t *testing.T) {
 		Name:      "Customer 1",
 		CPF:       "12345678900",
 		Email:     "email@email.com",
+		Status:    entities.CustomerActive,
 		CreatedAt: "2021-01-01",
 	}
 
@@ -121,6 +189,9 @@ func TestListCustomers(t *testing.T) {
 	// Substituir o repositório real pelo mock no usecase
 	usecase := usecases.ListCustomerUsecase{
 		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Metrics:            noopMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	// Configurar o controlador com o mock do usecase
@@ -138,11 +209,46 @@ func TestListCustomers(t *testing.T) {
 
 	// Verificar o resultado
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(SessionReasonHeader))
 
 	// Verificar se o mock foi chamado corretamente
 	mockRepo.AssertExpectations(t)
 }
 
+func TestListCustomers_SuspendedCustomer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	mockRepo.On("FindFirstByCpf", mock.Anything).Return(&entities.Customer{ID: 1, Status: entities.CustomerSuspended}, nil)
+
+	usecase := usecases.ListCustomerUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Metrics:            noopMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.GET("/customers", func(c *gin.Context) {
+		ListCustomers(c, &usecase)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/customers?cpf=12345678900", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	// o corpo continua sendo só o token, agora anônimo
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "customer_suspended", w.Header().Get(SessionReasonHeader))
+
+	var token string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &token))
+	claims, err := utils.ParseJWT(token)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "1", claims.CustomerId)
+}
+
 func TestListCustomers_WithoutCpf(t *testing.T) {
 	// Configurar o gin em modo de teste
 	gin.SetMode(gin.TestMode)
@@ -153,6 +259,9 @@ func TestListCustomers_WithoutCpf(t *testing.T) {
 	// Substituir o repositório real pelo mock no usecase
 	usecase := usecases.ListCustomerUsecase{
 		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Metrics:            noopMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	// Configurar o controlador com o mock do usecase
@@ -185,6 +294,7 @@ func TestCreateCustomer_InvalidJSON(t *testing.T) {
 	// Substituir o repositório real pelo mock no usecase
 	usecase := usecases.CreateCustomerUsecase{
 		CustomerRepository: mockRepo,
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	// Configurar o controlador com o mock do usecase
@@ -217,6 +327,7 @@ func TestCreateCustomer_InvalidInput(t *testing.T) {
 	// Substituir o repositório real pelo mock no usecase
 	usecase := usecases.CreateCustomerUsecase{
 		CustomerRepository: mockRepo,
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	// Configurar o controlador com o mock do usecase
@@ -257,6 +368,7 @@ func TestCreateCustomer_UseCaseError(t *testing.T) {
 	// Substituir o repositório real pelo mock no usecase
 	usecase := usecases.CreateCustomerUsecase{
 		CustomerRepository: mockRepo,
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	// Configurar o controlador com o mock do usecase
@@ -295,6 +407,7 @@ func TestCreateCustomer(t *testing.T) {
 		Name:      "Customer 1",
 		CPF:       "12345678900",
 		Email:     "email@email.com",
+		Status:    entities.CustomerActive,
 		CreatedAt: "2021-01-01",
 	}
 
@@ -304,6 +417,7 @@ func TestCreateCustomer(t *testing.T) {
 	// Substituir o repositório real pelo mock no usecase
 	usecase := usecases.CreateCustomerUsecase{
 		CustomerRepository: mockRepo,
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	// Configurar o controlador com o mock do usecase
@@ -334,3 +448,30 @@ func TestCreateCustomer(t *testing.T) {
 	// Verificar se o mock foi chamado corretamente
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCreateCustomer_WithoutCpf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	usecase := usecases.CreateCustomerUsecase{
+		CustomerRepository: mockRepo,
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.Default()
+	r.POST("/customers", func(c *gin.Context) {
+		CreateCustomer(c, &usecase)
+	})
+
+	// a v1 recusa o cadastro sem CPF com o erro do validador, como antes da v2
+	inputJSON := `{"name":"Customer 1","email":"email@email.com","document":{"type":"passport","number":"X1234567","country":"PT"}}`
+	req, _ := http.NewRequest(http.MethodPost, "/customers", bytes.NewBufferString(inputJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":{"CPF":["invalid length"]}}`, w.Body.String())
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/middlewares"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func CreateSession(c *gin.Context, usecase *usecases.CreateSessionUsecase) {
+	var inputDto dtos.CreateSessionDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// SendSessionCode envia o código que confirma a sessão do cliente identificado pelo token
+func SendSessionCode(c *gin.Context, usecase *usecases.SendSessionCodeUsecase) {
+	var inputDto dtos.SendSessionCodeDto
+
+	// o corpo é opcional: sem ele vale o canal padrão
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&inputDto); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+// VerifySessionCode confere o código e devolve o token de sessão confirmada
+func VerifySessionCode(c *gin.Context, usecase *usecases.VerifySessionCodeUsecase) {
+	var inputDto dtos.VerifySessionCodeDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func CreateCustomerV2(c *gin.Context, usecase *usecases.CreateCustomerUsecase) {
+	var inputDto dtos.CreateCustomerDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func GetCurrentCustomer(c *gin.Context, usecase *usecases.GetCustomerUsecase) {
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey))
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportCurrentCustomerData devolve os dados do titular e o histórico de acessos (LGPD)
+func ExportCurrentCustomerData(c *gin.Context, usecase *usecases.ExportCustomerDataUsecase) {
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey))
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func UpdateCurrentCustomer(c *gin.Context, usecase *usecases.UpdateCustomerUsecase) {
+	var inputDto dtos.UpdateCustomerDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func EraseCurrentCustomer(c *gin.Context, usecase *usecases.EraseCustomerUsecase) {
+	if err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey)); err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func BatchGetCustomers(c *gin.Context, usecase *usecases.BatchGetCustomersUsecase) {
+	var inputDto dtos.BatchGetCustomersDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// statusForError traduz os erros de domínio para o status HTTP da v2
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrCustomerAlreadyExists), errors.Is(err, entities.ErrInvalidStatusChange):
+		return http.StatusConflict
+	case errors.Is(err, entities.ErrCustomerNotFound), errors.Is(err, entities.ErrSessionVerificationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrSessionChannelUnavailable), errors.Is(err, entities.ErrInvalidSessionCode),
+		errors.Is(err, entities.ErrSessionCodeExpired):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, entities.ErrSessionCodeResendTooSoon):
+		return http.StatusTooManyRequests
+	case errors.Is(err, entities.ErrSMSUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, entities.ErrTooManyIDs), errors.Is(err, entities.ErrInvalidCursor),
+		errors.Is(err, entities.ErrInvalidDocument), errors.Is(err, entities.ErrInvalidCPF), errors.Is(err, entities.ErrDocumentRequired),
+		errors.Is(err, entities.ErrInvalidPhone), errors.Is(err, entities.ErrAmbiguousSessionLookup),
+		errors.Is(err, entities.ErrUnknownImportFormat), errors.Is(err, entities.ErrInvalidImportHeader), errors.Is(err, entities.ErrInvalidImportRow):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/middlewares"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateSession(t *testing.T) {
+	// Configurar o gin em modo de teste
+	gin.SetMode(gin.TestMode)
+
+	// Criar o mock do repositório de clientes
+	mockRepo := new(MockCustomerRepository)
+	mockRepo.On("FindFirstByCpf", mock.Anything).Return(&entities.Customer{ID: 7, Status: entities.CustomerActive}, nil)
+
+	usecase := usecases.CreateSessionUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Metrics:            noopMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.POST("/v2/sessions", func(c *gin.Context) {
+		CreateSession(c, &usecase)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/v2/sessions", bytes.NewBufferString(`{"cpf":"12345678900"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Executar a requisição
+	r.ServeHTTP(w, req)
+
+	// Verificar o resultado
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var session entities.Session
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+	assert.True(t, session.Identified)
+
+	claims, err := utils.ParseJWT(session.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, "7", claims.CustomerId)
+}
+
+func TestCreateSession_InvalidInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	usecase := usecases.CreateSessionUsecase{
+		CustomerRepository: new(MockCustomerRepository),
+		AuditLog:           noopAuditLog{},
+		Metrics:            noopMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.POST("/v2/sessions", func(c *gin.Context) {
+		CreateSession(c, &usecase)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/v2/sessions", bytes.NewBufferString(`{"cpf":"invalid_cpf_format"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateCustomerV2_Duplicate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Configurar o mock para retornar o erro de cliente duplicado
+	mockRepo := new(MockCustomerRepository)
+	mockRepo.On("Create", mock.Anything).Return(nil, entities.ErrCustomerAlreadyExists)
+
+	usecase := usecases.CreateCustomerUsecase{
+		CustomerRepository: mockRepo,
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.POST("/v2/customers", func(c *gin.Context) {
+		CreateCustomerV2(c, &usecase)
+	})
+
+	inputJSON := `{"name":"Customer 1","cpf":"12345678900","email":"email@email.com"}`
+	req, _ := http.NewRequest(http.MethodPost, "/v2/customers", bytes.NewBufferString(inputJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "cliente já existe no sistema")
+}
+
+func TestGetCurrentCustomer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	expectedCustomer := entities.Customer{
+		ID:        7,
+		Name:      "Customer 1",
+		CPF:       "12345678900",
+		Email:     "email@email.com",
+		Status:    entities.CustomerActive,
+		CreatedAt: "2021-01-01",
+	}
+	mockRepo.On("FindByID", uint(7)).Return(&expectedCustomer, nil)
+
+	usecase := usecases.GetCustomerUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.GET("/v2/customers/me", middlewares.RequireVerifiedCustomerToken(staticRedirects{}, mockRepo), func(c *gin.Context) {
+		GetCurrentCustomer(c, &usecase)
+	})
+
+	token, _ := utils.NewVerifiedJWT(7, time.Now().Add(utils.VerifiedTokenTTL))
+	req, _ := http.NewRequest(http.MethodGet, "/v2/customers/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"cpf":"12345678900", "createdAt":"2021-01-01", "email":"email@email.com", "id":7, "name":"Customer 1", "status":"active"}`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCurrentCustomer_SuspendedCustomerToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	mockRepo.On("FindByID", uint(7)).Return(&entities.Customer{ID: 7, Name: "Customer 1", Status: entities.CustomerSuspended}, nil)
+
+	usecase := usecases.GetCustomerUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.GET("/v2/customers/me", middlewares.RequireVerifiedCustomerToken(staticRedirects{}, mockRepo), func(c *gin.Context) {
+		GetCurrentCustomer(c, &usecase)
+	})
+
+	// token emitido antes da suspensão
+	token, _ := utils.NewVerifiedJWT(7, time.Now().Add(utils.VerifiedTokenTTL))
+	req, _ := http.NewRequest(http.MethodGet, "/v2/customers/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":"conta do cliente não está ativa", "reason":"customer_suspended"}`, w.Body.String())
+	mockRepo.AssertNumberOfCalls(t, "FindByID", 1)
+}
+
+func TestGetCurrentCustomer_MergedCustomerToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	mockRepo.On("FindByID", uint(7)).Return(&entities.Customer{ID: 7, Name: "Customer 1", Status: entities.CustomerActive}, nil)
+
+	usecase := usecases.GetCustomerUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.GET("/v2/customers/me", middlewares.RequireVerifiedCustomerToken(staticRedirects{3: 7}, mockRepo), func(c *gin.Context) {
+		GetCurrentCustomer(c, &usecase)
+	})
+
+	// token emitido antes de o cliente 3 ser incorporado ao 7
+	token, _ := utils.NewVerifiedJWT(3, time.Now().Add(utils.VerifiedTokenTTL))
+	req, _ := http.NewRequest(http.MethodGet, "/v2/customers/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":7`)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "FindByID", uint(3))
+}
+
+func TestBatchGetCustomers_FollowsMergeRedirects(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	mockRepo.On("FindByIDs", []uint{1, 2, 3}).Return([]entities.Customer{{ID: 1, Name: "Customer 1"}}, nil)
+	mockRepo.On("FindByIDs", []uint{9}).Return([]entities.Customer{{ID: 9, Name: "Customer 9"}}, nil)
+
+	usecase := usecases.BatchGetCustomersUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Redirects:          staticRedirects{2: 9, 3: 1},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.POST("/v2/customers:batchGet", func(c *gin.Context) {
+		BatchGetCustomers(c, &usecase)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/v2/customers:batchGet", bytes.NewBufferString(`{"ids":[1,2,3]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"customers":[{"id":1,"name":"Customer 1","cpf":"","email":"","status":"","createdAt":""},{"id":9,"name":"Customer 9","cpf":"","email":"","status":"","createdAt":""}],"missingIds":[],"redirects":{"2":9,"3":1}}`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCurrentCustomer_AnonymousToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	usecase := usecases.GetCustomerUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.GET("/v2/customers/me", middlewares.RequireVerifiedCustomerToken(staticRedirects{}, mockRepo), func(c *gin.Context) {
+		GetCurrentCustomer(c, &usecase)
+	})
+
+	token, _ := utils.GenerateJWT(nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v2/customers/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+func TestGetCurrentCustomer_UnverifiedSessionToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	usecase := usecases.GetCustomerUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.GET("/v2/customers/me", middlewares.RequireVerifiedCustomerToken(staticRedirects{}, mockRepo), func(c *gin.Context) {
+		GetCurrentCustomer(c, &usecase)
+	})
+
+	// o token de sessão emitido só com o CPF não dá acesso aos dados pessoais
+	token, _ := utils.GenerateJWT(uint(7))
+	req, _ := http.NewRequest(http.MethodGet, "/v2/customers/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"reason":"session_not_verified"`)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+func TestBatchGetCustomers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	mockRepo.On("FindByIDs", []uint{1, 2}).Return([]entities.Customer{{ID: 1, Name: "Customer 1"}}, nil)
+
+	usecase := usecases.BatchGetCustomersUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		Redirects:          staticRedirects{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.POST("/v2/customers:batchGet", func(c *gin.Context) {
+		BatchGetCustomers(c, &usecase)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/v2/customers:batchGet", bytes.NewBufferString(`{"ids":[1,2]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"customers":[{"id":1,"name":"Customer 1","cpf":"","email":"","status":"","createdAt":""}],"missingIds":[2]}`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBatchGetCustomers_TooManyIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockCustomerRepository)
+	usecase := usecases.BatchGetCustomersUsecase{
+		CustomerRepository: mockRepo,
+		AuditLog:           noopAuditLog{},
+		MaxIDs:             1,
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	r := gin.New()
+	r.POST("/v2/customers:batchGet", func(c *gin.Context) {
+		BatchGetCustomers(c, &usecase)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/v2/customers:batchGet", bytes.NewBufferString(`{"ids":[1,2]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "FindByIDs", mock.Anything)
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/duplicates"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func ListDuplicates(c *gin.Context, usecase *usecases.ListDuplicatesUsecase) {
+	var inputDto dtos.ListDuplicatesDto
+
+	if err := c.ShouldBindQuery(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func DismissDuplicate(c *gin.Context, usecase *usecases.DismissDuplicateUsecase) {
+	candidateID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), candidateID)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func MergeCustomers(c *gin.Context, usecase *usecases.MergeCustomersUsecase) {
+	survivorID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var inputDto dtos.MergeCustomersDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), survivorID, inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// idParam lê um id numérico da rota; em caso de valor inválido já responde 400
+func idParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": name + " inválido",
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// statusForError traduz os erros de domínio da fusão de clientes para o status HTTP
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrDuplicateCandidateNotFound), errors.Is(err, entities.ErrCustomerNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrDuplicateAlreadyResolved), errors.Is(err, entities.ErrMergeNotAllowed),
+		errors.Is(err, entities.ErrAddressLimitReached):
+		return http.StatusConflict
+	case errors.Is(err, entities.ErrSelfMerge):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/fiscal"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/middlewares"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func GetCurrentFiscalProfile(c *gin.Context, usecase *usecases.GetFiscalProfileUsecase) {
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey))
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func SaveCurrentFiscalProfile(c *gin.Context, usecase *usecases.SaveFiscalProfileUsecase) {
+	var inputDto dtos.SaveFiscalProfileDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func DeleteCurrentFiscalProfile(c *gin.Context, usecase *usecases.DeleteFiscalProfileUsecase) {
+	if err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey)); err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResolveFiscalProfile devolve ao faturamento os dados da nota do cliente dono do token
+func ResolveFiscalProfile(c *gin.Context, usecase *usecases.ResolveFiscalProfileUsecase) {
+	var inputDto dtos.ResolveFiscalProfileDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// statusForError traduz os erros de domínio do perfil fiscal para o status HTTP.
+// O token no corpo é dado da requisição, não credencial: token inválido é 422, não 401.
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrCustomerNotFound), errors.Is(err, entities.ErrFiscalProfileNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrInvalidCPF), errors.Is(err, entities.ErrInvalidCNPJ), errors.Is(err, entities.ErrInvalidFiscalProfile):
+		return http.StatusBadRequest
+	case errors.Is(err, entities.ErrInvalidToken):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/loyalty"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/middlewares"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func GetCurrentLoyaltyBalance(c *gin.Context, usecase *usecases.GetLoyaltyBalanceUsecase) {
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey))
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func ListCurrentLoyaltyTransactions(c *gin.Context, usecase *usecases.ListLoyaltyTransactionsUsecase) {
+	var inputDto dtos.ListLoyaltyTransactionsDto
+
+	if err := c.ShouldBindQuery(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func EarnPoints(c *gin.Context, usecase *usecases.EarnPointsUsecase) {
+	customerID, ok := customerIDParam(c)
+	if !ok {
+		return
+	}
+
+	var inputDto dtos.EarnPointsDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), customerID, inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(postingStatus(result), result)
+}
+
+func RedeemPoints(c *gin.Context, usecase *usecases.RedeemPointsUsecase) {
+	customerID, ok := customerIDParam(c)
+	if !ok {
+		return
+	}
+
+	var inputDto dtos.RedeemPointsDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), customerID, inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(postingStatus(result), result)
+}
+
+func AdjustPoints(c *gin.Context, usecase *usecases.AdjustPointsUsecase) {
+	customerID, ok := customerIDParam(c)
+	if !ok {
+		return
+	}
+
+	var inputDto dtos.AdjustPointsDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), customerID, inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(postingStatus(result), result)
+}
+
+// postingStatus é 201 para um lançamento novo e 200 para a repetição de um já gravado
+func postingStatus(result *dtos.LoyaltyPostingDto) int {
+	if result.Replayed {
+		return http.StatusOK
+	}
+
+	return http.StatusCreated
+}
+
+func customerIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "id de cliente inválido",
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// statusForError traduz os erros de domínio da fidelidade para o status HTTP
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrCustomerNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrLoyaltyReferenceConflict):
+		return http.StatusConflict
+	case errors.Is(err, entities.ErrInsufficientPoints):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, entities.ErrInvalidPoints), errors.Is(err, entities.ErrInvalidCursor):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
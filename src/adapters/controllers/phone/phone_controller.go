@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/phone"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/middlewares"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func SendCurrentPhoneCode(c *gin.Context, usecase *usecases.SendPhoneCodeUsecase) {
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey))
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+func VerifyCurrentPhone(c *gin.Context, usecase *usecases.VerifyPhoneUsecase) {
+	var inputDto dtos.VerifyPhoneDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrCustomerNotFound), errors.Is(err, entities.ErrPhoneNotFound),
+		errors.Is(err, entities.ErrPhoneVerificationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrPhoneAlreadyVerified), errors.Is(err, entities.ErrPhoneAlreadyInUse):
+		return http.StatusConflict
+	case errors.Is(err, entities.ErrInvalidPhoneCode), errors.Is(err, entities.ErrPhoneCodeExpired):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, entities.ErrPhoneCodeResendTooSoon), errors.Is(err, entities.ErrPhoneCodeDailyLimit):
+		return http.StatusTooManyRequests
+	case errors.Is(err, entities.ErrSMSUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/preferences"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/middlewares"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func GetCurrentPreferences(c *gin.Context, usecase *usecases.GetPreferencesUsecase) {
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey))
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func UpdateCurrentPreferences(c *gin.Context, usecase *usecases.UpdatePreferencesUsecase) {
+	var inputDto dtos.UpdatePreferencesDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), c.GetUint(middlewares.CustomerIDKey), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetCustomerPreferences é a leitura dos serviços de cardápio e pedidos, por id de cliente
+func GetCustomerPreferences(c *gin.Context, usecase *usecases.GetPreferencesUsecase) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "id de cliente inválido",
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), uint(id))
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// statusForError traduz os erros de domínio das preferências para o status HTTP
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrCustomerNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrInvalidPreferences):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/webhook"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/validator.v2"
+)
+
+func CreateSubscription(c *gin.Context, usecase *usecases.CreateSubscriptionUsecase) {
+	var inputDto dtos.CreateWebhookSubscriptionDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func ListSubscriptions(c *gin.Context, usecase *usecases.ListSubscriptionsUsecase) {
+	result, err := usecase.Execute(c.Request.Context())
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func DeleteSubscription(c *gin.Context, usecase *usecases.DeleteSubscriptionUsecase) {
+	subscriptionID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := usecase.Execute(c.Request.Context(), subscriptionID); err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func ListDeliveries(c *gin.Context, usecase *usecases.ListDeliveriesUsecase) {
+	subscriptionID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var inputDto dtos.ListWebhookDeliveriesDto
+
+	if err := c.ShouldBindQuery(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := validator.Validate(inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), subscriptionID, inputDto)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func ReplayDelivery(c *gin.Context, usecase *usecases.ReplayDeliveryUsecase) {
+	subscriptionID, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	deliveryID, ok := idParam(c, "deliveryId")
+	if !ok {
+		return
+	}
+
+	result, err := usecase.Execute(c.Request.Context(), subscriptionID, deliveryID)
+
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+// idParam lê um id numérico da rota; em caso de valor inválido já responde 400
+func idParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": name + " inválido",
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// statusForError traduz os erros de domínio dos webhooks para o status HTTP
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, entities.ErrWebhookNotFound), errors.Is(err, entities.ErrWebhookDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entities.ErrInvalidWebhookURL), errors.Is(err, entities.ErrInvalidEventType), errors.Is(err, entities.ErrWeakWebhookSecret):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
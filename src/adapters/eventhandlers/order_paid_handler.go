@@ -0,0 +1,58 @@
+package eventhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/loyalty"
+	"gopkg.in/validator.v2"
+)
+
+// OrderPaidHandler credita os pontos dos pedidos pagos. O pedido é a referência do crédito,
+// então reentregas do mesmo evento não creditam de novo.
+type OrderPaidHandler struct {
+	EarnUsecase *usecases.EarnPointsUsecase
+}
+
+// Handle ignora outros tipos de evento e pedidos anônimos. Erros que uma nova entrega não
+// resolve (cliente inexistente, dados inválidos) são registrados no log e não voltam para a fila.
+func (h *OrderPaidHandler) Handle(ctx context.Context, envelope events.Envelope) error {
+	if envelope.Type != events.OrderPaid {
+		return nil
+	}
+
+	var data events.OrderPaidData
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		slog.ErrorContext(ctx, "Evento OrderPaid inválido descartado", "eventId", envelope.ID, "error", err)
+		return nil
+	}
+
+	if data.CustomerID == nil {
+		return nil
+	}
+
+	inputDto := dtos.EarnPointsDto{
+		Reference:   "order:" + data.OrderID,
+		AmountCents: data.AmountCents,
+	}
+
+	if data.OrderID == "" || validator.Validate(inputDto) != nil {
+		slog.ErrorContext(ctx, "Evento OrderPaid inválido descartado", "eventId", envelope.ID, "orderId", data.OrderID)
+		return nil
+	}
+
+	_, err := h.EarnUsecase.Execute(ctx, *data.CustomerID, inputDto)
+
+	switch {
+	case errors.Is(err, entities.ErrCustomerNotFound), errors.Is(err, entities.ErrLoyaltyReferenceConflict):
+		slog.WarnContext(ctx, "Pontos do pedido não creditados", "eventId", envelope.ID, "orderId", data.OrderID, "error", err)
+		return nil
+	default:
+		return err
+	}
+}
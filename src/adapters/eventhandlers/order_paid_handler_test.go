@@ -0,0 +1,131 @@
+package eventhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/loyalty"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLedger struct {
+	gateways.LoyaltyLedger
+	posted []entities.LoyaltyTransaction
+	err    error
+}
+
+func (f *fakeLedger) History(ctx context.Context, customerID uint) ([]entities.LoyaltyTransaction, error) {
+	return f.posted, nil
+}
+
+func (f *fakeLedger) Post(ctx context.Context, customerID uint, build func(history []entities.LoyaltyTransaction) ([]entities.LoyaltyTransaction, error)) ([]entities.LoyaltyTransaction, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	transactions, err := build(f.posted)
+	if err != nil {
+		return nil, err
+	}
+	f.posted = append(f.posted, transactions...)
+	return transactions, nil
+}
+
+type fakeCustomers struct {
+	gateways.CustomerRepository
+}
+
+func (f *fakeCustomers) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	if id != 7 {
+		return nil, entities.ErrCustomerNotFound
+	}
+	return &entities.Customer{ID: id}, nil
+}
+
+// fakeRedirects liga ids incorporados numa fusão aos sobreviventes
+type fakeRedirects map[uint]uint
+
+func (r fakeRedirects) Resolve(ctx context.Context, id uint) (uint, error) {
+	if to, ok := r[id]; ok {
+		return to, nil
+	}
+	return id, nil
+}
+
+func (r fakeRedirects) ResolveMany(ctx context.Context, ids []uint) (map[uint]uint, error) {
+	result := map[uint]uint{}
+	for _, id := range ids {
+		if to, ok := r[id]; ok {
+			result[id] = to
+		}
+	}
+	return result, nil
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) TokenIssued(string) {}
+
+func (noopMetrics) LoyaltyPoints(string, int64) {}
+
+func orderPaid(t *testing.T, data events.OrderPaidData) events.Envelope {
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	return events.Envelope{ID: "evt-1", Type: events.OrderPaid, Data: payload}
+}
+
+func newHandler(ledger *fakeLedger) *OrderPaidHandler {
+	tiers, _ := usecases.ParseLoyaltyTiers("bronze:0:1")
+
+	return &OrderPaidHandler{EarnUsecase: &usecases.EarnPointsUsecase{
+		CustomerRepository: &fakeCustomers{},
+		Redirects:          fakeRedirects{},
+		Ledger:             ledger,
+		Policy:             usecases.LoyaltyPolicy{PointsPerReal: 1, Expiry: usecases.ExpiryNever, Tiers: tiers},
+		Metrics:            noopMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
+	}}
+}
+
+func TestOrderPaidHandler_CreditsOrderOnce(t *testing.T) {
+	ledger := &fakeLedger{}
+	handler := newHandler(ledger)
+	customerID := uint(7)
+	envelope := orderPaid(t, events.OrderPaidData{OrderID: "42", CustomerID: &customerID, AmountCents: 2550})
+
+	require.NoError(t, handler.Handle(context.Background(), envelope))
+	require.NoError(t, handler.Handle(context.Background(), envelope))
+
+	require.Len(t, ledger.posted, 1)
+	assert.Equal(t, "order:42", ledger.posted[0].Reference)
+	assert.Equal(t, int64(25), ledger.posted[0].Points)
+}
+
+func TestOrderPaidHandler_SkipsWhatRetryWontFix(t *testing.T) {
+	ledger := &fakeLedger{}
+	handler := newHandler(ledger)
+	unknown := uint(9)
+
+	assert.NoError(t, handler.Handle(context.Background(), orderPaid(t, events.OrderPaidData{OrderID: "1", AmountCents: 1000})))
+	assert.NoError(t, handler.Handle(context.Background(), orderPaid(t, events.OrderPaidData{OrderID: "2", CustomerID: &unknown, AmountCents: 1000})))
+	assert.NoError(t, handler.Handle(context.Background(), events.Envelope{Type: events.OrderPaid, Data: []byte(`{"orderId":`)}))
+	assert.NoError(t, handler.Handle(context.Background(), events.Envelope{Type: "OrderCreated"}))
+	assert.Empty(t, ledger.posted)
+}
+
+func TestOrderPaidHandler_RetriesInfrastructureErrors(t *testing.T) {
+	ledger := &fakeLedger{err: errors.New("conexão recusada")}
+	handler := newHandler(ledger)
+	customerID := uint(7)
+
+	err := handler.Handle(context.Background(), orderPaid(t, events.OrderPaidData{OrderID: "42", CustomerID: &customerID, AmountCents: 2550}))
+	assert.Error(t, err)
+}
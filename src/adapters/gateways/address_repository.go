@@ -0,0 +1,15 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type AddressRepository interface {
+	// FindByCustomer devolve o caderno de endereços do cliente, do mais antigo para o mais novo
+	FindByCustomer(ctx context.Context, customerID uint) (*entities.AddressBook, error)
+	// Update grava as alterações que change faz no caderno atual, com o cliente travado
+	// até o fim da transação. Os endereços novos voltam com id e datas preenchidos.
+	Update(ctx context.Context, customerID uint, change func(book *entities.AddressBook) error) (*entities.AddressBook, error)
+}
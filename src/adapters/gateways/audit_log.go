@@ -0,0 +1,15 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type AuditLog interface {
+	// Record grava as entradas numa única transação. Ator, papel, IP, request id e horário
+	// são preenchidos a partir do contexto da requisição quando vierem vazios.
+	Record(ctx context.Context, entries ...entities.AuditEntry) error
+	// List devolve as entradas com sequência maior que afterSequence, em ordem
+	List(ctx context.Context, filter entities.AuditFilter, afterSequence uint64, limit int) ([]entities.AuditEntry, error)
+}
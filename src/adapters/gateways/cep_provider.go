@@ -0,0 +1,13 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// CEPProvider consulta o logradouro de um CEP de 8 dígitos. Devolve entities.ErrCEPNotFound
+// quando o CEP não existe e entities.ErrCEPProviderUnavailable quando a consulta falha.
+type CEPProvider interface {
+	Lookup(ctx context.Context, cep string) (*entities.CEPAddress, error)
+}
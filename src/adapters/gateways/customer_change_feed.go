@@ -0,0 +1,14 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type CustomerChangeFeed interface {
+	// ListChanges devolve as alterações depois da posição after, só de transações já encerradas,
+	// e a última posição lida (que pode vir depois da última alteração devolvida).
+	// hasMore indica que o limite foi atingido e pode haver mais alterações.
+	ListChanges(ctx context.Context, after entities.CustomerChangePosition, limit int) (changes []entities.CustomerChange, last entities.CustomerChangePosition, hasMore bool, err error)
+}
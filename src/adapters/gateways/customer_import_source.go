@@ -0,0 +1,9 @@
+package gateways
+
+import "github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+
+// CustomerImportSource lê um arquivo de importação linha a linha, sem carregá-lo inteiro.
+// Next devolve io.EOF no fim do arquivo; linhas inválidas voltam com Err e a leitura continua.
+type CustomerImportSource interface {
+	Next() (dtos.ImportCustomerRowDto, error)
+}
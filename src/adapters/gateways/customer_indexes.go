@@ -0,0 +1,10 @@
+package gateways
+
+import "github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+
+// CustomerIndexes calcula os índices cegos que o banco usa na unicidade de documento e email,
+// para comparar clientes sem guardar os dados em claro; a implementação usa o keyring
+type CustomerIndexes interface {
+	DocumentIndex(document entities.IdentityDocument) (string, error)
+	EmailIndex(email string) (string, error)
+}
@@ -1,10 +1,27 @@
 package gateways
 
 import (
+	"context"
+
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
 )
 
 type CustomerRepository interface {
-	Create(customer *entities.Customer) (*entities.Customer, error)
-	FindFirstByCpf(customer *entities.Customer) (*entities.Customer, error)
+	Create(ctx context.Context, customer *entities.Customer) (*entities.Customer, error)
+	FindFirstByCpf(ctx context.Context, customer *entities.Customer) (*entities.Customer, error)
+	// FindByDocument busca por qualquer documento de identificação, inclusive CPF
+	FindByDocument(ctx context.Context, document entities.IdentityDocument) (*entities.Customer, error)
+	// FindByPhone busca pelo telefone em E.164; só telefones verificados são encontrados
+	FindByPhone(ctx context.Context, phone string) (*entities.Customer, error)
+	FindByID(ctx context.Context, id uint) (*entities.Customer, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]entities.Customer, error)
+	Update(ctx context.Context, customer *entities.Customer) (*entities.Customer, error)
+	Erase(ctx context.Context, id uint) error
+	// Merge incorpora mergedID a survivorID: move os dados dependentes, anonimiza e remove o
+	// incorporado e deixa o redirecionamento do id dele. Devolve o sobrevivente, ou ErrAddressLimitReached
+	// se os endereços dos dois passarem de maxAddresses.
+	Merge(ctx context.Context, survivorID uint, mergedID uint, maxAddresses int) (*entities.Customer, error)
+	// ChangeStatus leva o cliente de from para to e grava o motivo no histórico. Se o status já não
+	// for from, por uma transição concorrente, devolve ErrInvalidStatusChange.
+	ChangeStatus(ctx context.Context, id uint, from entities.CustomerStatus, to entities.CustomerStatus, reason string) (*entities.CustomerStatusChange, error)
 }
@@ -0,0 +1,30 @@
+package gateways
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type DuplicateRepository interface {
+	// ScanCustomers lista os clientes ativos com id maior que afterID, em ordem de id
+	ScanCustomers(ctx context.Context, afterID uint, limit int) ([]entities.Customer, error)
+	// SaveCandidates grava os pares encontrados; pares já descartados ou fundidos não voltam a ficar pendentes
+	SaveCandidates(ctx context.Context, candidates []entities.DuplicateCandidate) error
+	// DeleteStaleCandidates remove os pares pendentes que a detecção não encontrou desde before
+	DeleteStaleCandidates(ctx context.Context, before time.Time) error
+	// ListCandidates lista os pares na situação status, do maior para o menor score
+	ListCandidates(ctx context.Context, status string, limit int) ([]entities.DuplicateCandidate, error)
+	// DismissCandidate marca um par pendente como descartado
+	DismissCandidate(ctx context.Context, id uint) (*entities.DuplicateCandidate, error)
+}
+
+// CustomerRedirects resolve ids de clientes incorporados por fusão. Tokens de sessão e referências
+// guardadas por outros serviços continuam valendo: o id incorporado responde pelo sobrevivente.
+type CustomerRedirects interface {
+	// Resolve devolve o sobrevivente de id, ou o próprio id se ele não foi incorporado
+	Resolve(ctx context.Context, id uint) (uint, error)
+	// ResolveMany devolve só os ids redirecionados, de id incorporado para sobrevivente
+	ResolveMany(ctx context.Context, ids []uint) (map[uint]uint, error)
+}
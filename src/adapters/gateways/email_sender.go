@@ -0,0 +1,8 @@
+package gateways
+
+import "context"
+
+// EmailSender envia um email de texto puro
+type EmailSender interface {
+	Send(ctx context.Context, to string, subject string, body string) error
+}
@@ -0,0 +1,14 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type FiscalProfileRepository interface {
+	// Find devolve entities.ErrFiscalProfileNotFound se o cliente não tem perfil fiscal
+	Find(ctx context.Context, customerID uint) (*entities.FiscalProfile, error)
+	Save(ctx context.Context, profile entities.FiscalProfile) (*entities.FiscalProfile, error)
+	Delete(ctx context.Context, customerID uint) error
+}
@@ -0,0 +1,19 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type LoyaltyLedger interface {
+	// History devolve todos os lançamentos do cliente em ordem de gravação
+	History(ctx context.Context, customerID uint) ([]entities.LoyaltyTransaction, error)
+	// List devolve os lançamentos do cliente com id maior que afterID, em ordem
+	List(ctx context.Context, customerID uint, afterID uint, limit int) ([]entities.LoyaltyTransaction, error)
+	// Post grava os lançamentos que build monta a partir do histórico atual, com o cliente
+	// travado até o fim da transação: o saldo visto por build não muda antes da gravação
+	Post(ctx context.Context, customerID uint, build func(history []entities.LoyaltyTransaction) ([]entities.LoyaltyTransaction, error)) ([]entities.LoyaltyTransaction, error)
+	// CustomerIDs lista os clientes com lançamentos, com id maior que afterID, para a expiração
+	CustomerIDs(ctx context.Context, afterID uint, limit int) ([]uint, error)
+}
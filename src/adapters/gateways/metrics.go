@@ -0,0 +1,9 @@
+package gateways
+
+// Metrics registra as métricas de negócio dos casos de uso; a implementação com Prometheus fica em infra/metrics
+type Metrics interface {
+	// TokenIssued conta um token de sessão emitido pelo tipo (entities.SessionType...)
+	TokenIssued(tokenType string)
+	// LoyaltyPoints soma os pontos lançados pelo tipo do lançamento, em valor absoluto
+	LoyaltyPoints(transactionType string, points int64)
+}
@@ -0,0 +1,22 @@
+package gateways
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// PhoneVerificationRepository guarda o código de verificação pendente de cada cliente.
+// Find devolve entities.ErrPhoneVerificationNotFound quando não há código pendente.
+type PhoneVerificationRepository interface {
+	Find(ctx context.Context, customerID uint) (*entities.PhoneVerification, error)
+	// Save substitui o código pendente do cliente
+	Save(ctx context.Context, verification entities.PhoneVerification, code string) error
+	// CountSent conta os códigos enviados ao cliente desde since, para qualquer telefone
+	CountSent(ctx context.Context, customerID uint, since time.Time) (int, error)
+	// Consume confere o código do telefone e o descarta. Código errado conta uma tentativa e devolve
+	// entities.ErrInvalidPhoneCode; vencido ou com maxAttempts esgotadas é descartado com
+	// entities.ErrPhoneCodeExpired.
+	Consume(ctx context.Context, customerID uint, phone string, code string, maxAttempts int, now time.Time) error
+}
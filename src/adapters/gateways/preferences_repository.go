@@ -0,0 +1,15 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type PreferencesRepository interface {
+	// Find devolve as preferências do cliente; sem preferências gravadas as listas vêm vazias
+	Find(ctx context.Context, customerID uint) (*entities.DietaryPreferences, error)
+	// Hash devolve só o hash das preferências, sem decifrá-las; vazio se não houver preferências
+	Hash(ctx context.Context, customerID uint) (string, error)
+	Save(ctx context.Context, preferences entities.DietaryPreferences) (*entities.DietaryPreferences, error)
+}
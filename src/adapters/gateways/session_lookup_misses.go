@@ -0,0 +1,9 @@
+package gateways
+
+import "context"
+
+// SessionLookupMisses recebe as buscas por cliente não cadastrado na emissão de sessão, que
+// alimentam o limite contra enumeração de documentos; a implementação fica em infra/ratelimit
+type SessionLookupMisses interface {
+	ReportMiss(ctx context.Context)
+}
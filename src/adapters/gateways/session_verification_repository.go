@@ -0,0 +1,20 @@
+package gateways
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// SessionVerificationRepository guarda o código pendente que confirma a sessão de cada cliente.
+// Find devolve entities.ErrSessionVerificationNotFound quando não há código pendente.
+type SessionVerificationRepository interface {
+	Find(ctx context.Context, customerID uint) (*entities.SessionVerification, error)
+	// Save substitui o código pendente do cliente
+	Save(ctx context.Context, verification entities.SessionVerification, code string) error
+	// Consume confere o código e o descarta. Código errado conta uma tentativa e devolve
+	// entities.ErrInvalidSessionCode; vencido ou com maxAttempts esgotadas é descartado com
+	// entities.ErrSessionCodeExpired.
+	Consume(ctx context.Context, customerID uint, code string, maxAttempts int, now time.Time) error
+}
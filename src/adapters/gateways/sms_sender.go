@@ -0,0 +1,8 @@
+package gateways
+
+import "context"
+
+// SMSSender envia um SMS para o telefone em E.164
+type SMSSender interface {
+	Send(ctx context.Context, phone string, message string) error
+}
@@ -0,0 +1,19 @@
+package gateways
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Tracer abre os spans dos casos de uso; a implementação com OpenTelemetry fica em infra/tracing
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span é um trecho do trace. Nunca adicione CPF, email ou tokens como atributo de span.
+type Span interface {
+	SetAttributes(attributes ...attribute.KeyValue)
+	// End registra o erro (se houver) e finaliza o span
+	End(err error)
+}
@@ -0,0 +1,15 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]entities.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, subscriptionID uint, status string, limit int) ([]entities.WebhookDelivery, error)
+	ReplayDelivery(ctx context.Context, subscriptionID uint, deliveryID uint) (*entities.WebhookDelivery, error)
+}
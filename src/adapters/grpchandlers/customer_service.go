@@ -0,0 +1,126 @@
+package grpchandlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	customerv1 "github.com/CAVAh/api-tech-challenge/src/infra/grpc/pb/customer/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/validator.v2"
+)
+
+// SessionReasonMetadata é a chave de metadados com o motivo de uma sessão anônima emitida para um
+// cliente que não está ativo.
+const SessionReasonMetadata = "session-reason"
+
+// CustomerService implementa o serviço gRPC sobre os mesmos casos de uso da API HTTP
+type CustomerService struct {
+	customerv1.UnimplementedCustomerServiceServer
+
+	GetUsecase      *usecases.GetCustomerUsecase
+	BatchGetUsecase *usecases.BatchGetCustomersUsecase
+	SessionUsecase  *usecases.CreateSessionUsecase
+	VerifyUsecase   *usecases.VerifyTokenUsecase
+}
+
+func (s *CustomerService) GetCustomer(ctx context.Context, req *customerv1.GetCustomerRequest) (*customerv1.GetCustomerResponse, error) {
+	customer, err := s.GetUsecase.Execute(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &customerv1.GetCustomerResponse{Customer: toProto(*customer)}, nil
+}
+
+func (s *CustomerService) BatchGetCustomers(ctx context.Context, req *customerv1.BatchGetCustomersRequest) (*customerv1.BatchGetCustomersResponse, error) {
+	ids := make([]uint, 0, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		ids = append(ids, uint(id))
+	}
+
+	result, err := s.BatchGetUsecase.Execute(ctx, dtos.BatchGetCustomersDto{IDs: ids})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &customerv1.BatchGetCustomersResponse{}
+	for _, customer := range result.Customers {
+		response.Customers = append(response.Customers, toProto(customer))
+	}
+	for _, id := range result.MissingIDs {
+		response.MissingIds = append(response.MissingIds, uint64(id))
+	}
+
+	return response, nil
+}
+
+func (s *CustomerService) IssueSession(ctx context.Context, req *customerv1.IssueSessionRequest) (*customerv1.IssueSessionResponse, error) {
+	inputDto := dtos.CreateSessionDto{CPF: req.GetCpf()}
+
+	if err := validator.Validate(inputDto); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	session, err := s.SessionUsecase.Execute(ctx, inputDto)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	// o contrato protobuf não tem o motivo; ele segue nos metadados da resposta
+	if session.Reason != "" {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(SessionReasonMetadata, session.Reason))
+	}
+
+	return &customerv1.IssueSessionResponse{
+		Token:      session.Token,
+		Identified: session.Identified,
+		ExpiresAt:  timestamppb.New(session.ExpiresAt),
+	}, nil
+}
+
+func (s *CustomerService) VerifyToken(ctx context.Context, req *customerv1.VerifyTokenRequest) (*customerv1.VerifyTokenResponse, error) {
+	verified, err := s.VerifyUsecase.Execute(ctx, req.GetToken())
+	if errors.Is(err, entities.ErrInvalidToken) {
+		return &customerv1.VerifyTokenResponse{Valid: false}, nil
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &customerv1.VerifyTokenResponse{
+		Valid:      true,
+		CustomerId: verified.CustomerID,
+		ExpiresAt:  timestamppb.New(verified.ExpiresAt),
+	}, nil
+}
+
+func toProto(customer entities.Customer) *customerv1.Customer {
+	return &customerv1.Customer{
+		Id:        uint64(customer.ID),
+		Name:      customer.Name,
+		Cpf:       customer.CPF,
+		Email:     customer.Email,
+		CreatedAt: customer.CreatedAt,
+	}
+}
+
+// toStatus traduz os erros de domínio para os códigos gRPC
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, entities.ErrCustomerNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, entities.ErrTooManyIDs):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entities.ErrCustomerAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,153 @@
+package grpchandlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	customerv1 "github.com/CAVAh/api-tech-challenge/src/infra/grpc/pb/customer/v1"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockCustomerRepository struct {
+	gateways.CustomerRepository
+	customers map[uint]entities.Customer
+}
+
+func (m *mockCustomerRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	customer, ok := m.customers[id]
+	if !ok {
+		return nil, entities.ErrCustomerNotFound
+	}
+	return &customer, nil
+}
+
+func (m *mockCustomerRepository) FindFirstByCpf(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	for _, found := range m.customers {
+		if found.CPF == customer.CPF {
+			return &found, nil
+		}
+	}
+	return nil, entities.ErrCustomerNotFound
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) TokenIssued(string) {}
+
+func (noopMetrics) LoyaltyPoints(string, int64) {}
+
+type noopAuditLog struct {
+	gateways.AuditLog
+}
+
+func (noopAuditLog) Record(ctx context.Context, entries ...entities.AuditEntry) error {
+	return nil
+}
+
+// mockRedirects liga ids incorporados numa fusão aos sobreviventes
+type mockRedirects map[uint]uint
+
+func (r mockRedirects) Resolve(ctx context.Context, id uint) (uint, error) {
+	if to, ok := r[id]; ok {
+		return to, nil
+	}
+	return id, nil
+}
+
+func (r mockRedirects) ResolveMany(ctx context.Context, ids []uint) (map[uint]uint, error) {
+	result := map[uint]uint{}
+	for _, id := range ids {
+		if to, ok := r[id]; ok {
+			result[id] = to
+		}
+	}
+	return result, nil
+}
+
+func newCustomerService() *CustomerService {
+	repo := &mockCustomerRepository{customers: map[uint]entities.Customer{
+		1: {ID: 1, Name: "John Doe", CPF: "12345678900", Email: "john@example.com", Status: entities.CustomerActive},
+		3: {ID: 3, Name: "Jane Doe", CPF: "98765432100", Email: "jane@example.com", Status: entities.CustomerSuspended},
+	}}
+	// o cliente 5 foi incorporado ao 1
+	redirects := mockRedirects{5: 1}
+
+	return &CustomerService{
+		GetUsecase:     &usecases.GetCustomerUsecase{CustomerRepository: repo, AuditLog: noopAuditLog{}, Redirects: redirects, Tracer: tracing.UsecaseTracer{}},
+		SessionUsecase: &usecases.CreateSessionUsecase{CustomerRepository: repo, AuditLog: noopAuditLog{}, Metrics: noopMetrics{}, Tracer: tracing.UsecaseTracer{}},
+		VerifyUsecase:  &usecases.VerifyTokenUsecase{CustomerRepository: repo, Redirects: redirects, Tracer: tracing.UsecaseTracer{}},
+	}
+}
+
+func TestGetCustomer(t *testing.T) {
+	service := newCustomerService()
+
+	resp, err := service.GetCustomer(context.Background(), &customerv1.GetCustomerRequest{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", resp.GetCustomer().GetName())
+
+	_, err = service.GetCustomer(context.Background(), &customerv1.GetCustomerRequest{Id: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	merged, err := service.GetCustomer(context.Background(), &customerv1.GetCustomerRequest{Id: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), merged.GetCustomer().GetId())
+}
+
+func TestIssueSessionAndVerifyToken(t *testing.T) {
+	service := newCustomerService()
+
+	session, err := service.IssueSession(context.Background(), &customerv1.IssueSessionRequest{Cpf: "12345678900"})
+	assert.NoError(t, err)
+	assert.True(t, session.GetIdentified())
+
+	verified, err := service.VerifyToken(context.Background(), &customerv1.VerifyTokenRequest{Token: session.GetToken()})
+	assert.NoError(t, err)
+	assert.True(t, verified.GetValid())
+	assert.Equal(t, "1", verified.GetCustomerId())
+
+	invalid, err := service.VerifyToken(context.Background(), &customerv1.VerifyTokenRequest{Token: "invalid"})
+	assert.NoError(t, err)
+	assert.False(t, invalid.GetValid())
+}
+
+func TestVerifyToken_MergedCustomer(t *testing.T) {
+	service := newCustomerService()
+	token, err := utils.GenerateJWT(uint(5))
+	assert.NoError(t, err)
+
+	verified, err := service.VerifyToken(context.Background(), &customerv1.VerifyTokenRequest{Token: token})
+	assert.NoError(t, err)
+	assert.True(t, verified.GetValid())
+	assert.Equal(t, "1", verified.GetCustomerId())
+}
+
+func TestIssueSession_InvalidCpf(t *testing.T) {
+	service := newCustomerService()
+
+	_, err := service.IssueSession(context.Background(), &customerv1.IssueSessionRequest{Cpf: "abc"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestIssueSessionAndVerifyToken_SuspendedCustomer(t *testing.T) {
+	service := newCustomerService()
+
+	session, err := service.IssueSession(context.Background(), &customerv1.IssueSessionRequest{Cpf: "98765432100"})
+	assert.NoError(t, err)
+	assert.False(t, session.GetIdentified())
+
+	// um token emitido antes da suspensão deixa de ser válido
+	token, err := utils.GenerateJWT(uint(3))
+	assert.NoError(t, err)
+
+	verified, err := service.VerifyToken(context.Background(), &customerv1.VerifyTokenRequest{Token: token})
+	assert.NoError(t, err)
+	assert.False(t, verified.GetValid())
+}
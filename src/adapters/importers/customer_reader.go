@@ -0,0 +1,199 @@
+package importers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"gopkg.in/validator.v2"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// maxLineBytes limita uma linha do NDJSON; um cliente ocupa poucas centenas de bytes
+const maxLineBytes = 64 * 1024
+
+// Colunas do CSV, com os nomes dos campos do POST /v2/customers; o documento vem achatado
+var csvColumns = map[string]bool{
+	"name":            true,
+	"cpf":             true,
+	"email":           true,
+	"phone":           true,
+	"documentType":    true,
+	"documentNumber":  true,
+	"documentCountry": true,
+}
+
+// FormatFromContentType escolhe o formato do upload pelo Content-Type; vazio quando não reconhece
+func FormatFromContentType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "text/csv":
+		return FormatCSV
+	case "application/x-ndjson", "application/jsonl":
+		return FormatNDJSON
+	default:
+		return ""
+	}
+}
+
+// NewCustomerReader lê clientes de um CSV com cabeçalho ou de um NDJSON com um CreateCustomerDto por linha.
+// Cada linha passa pela mesma validação do corpo de POST /v2/customers.
+func NewCustomerReader(format string, r io.Reader) (gateways.CustomerImportSource, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 4096), maxLineBytes)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, entities.ErrUnknownImportFormat
+	}
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+// newCSVReader lê o cabeçalho, aceitando vírgula ou ponto e vírgula (padrão do Excel em pt-BR) como
+// separador. Colunas desconhecidas são ignoradas, para aceitar exportações com colunas extras.
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	buffered := bufio.NewReader(r)
+
+	firstLine, err := buffered.Peek(buffered.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	firstLine, _, _ = bytes.Cut(firstLine, []byte("\n"))
+
+	reader := csv.NewReader(buffered)
+	reader.ReuseRecord = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: arquivo vazio", entities.ErrInvalidImportHeader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", entities.ErrInvalidImportHeader, err)
+	}
+
+	columns := make([]string, len(header))
+	present := map[string]bool{}
+	for i, name := range header {
+		// o Excel grava o BOM do UTF-8 no início do arquivo
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if !csvColumns[name] {
+			continue
+		}
+		if present[name] {
+			return nil, fmt.Errorf("%w: coluna %s repetida", entities.ErrInvalidImportHeader, name)
+		}
+		columns[i] = name
+		present[name] = true
+	}
+
+	if !present["name"] || !present["email"] || (!present["cpf"] && !present["documentNumber"]) {
+		return nil, fmt.Errorf("%w: são obrigatórias as colunas name, email e cpf ou documentNumber", entities.ErrInvalidImportHeader)
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (dtos.ImportCustomerRowDto, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return dtos.ImportCustomerRowDto{}, io.EOF
+	}
+
+	// erros de parse, inclusive a quantidade errada de colunas, ficam na linha; o leitor segue na próxima
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return dtos.ImportCustomerRowDto{
+			Line: parseErr.StartLine,
+			Err:  fmt.Errorf("%w: %s", entities.ErrInvalidImportRow, parseErr.Err),
+		}, nil
+	}
+	if err != nil {
+		return dtos.ImportCustomerRowDto{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	row := dtos.ImportCustomerRowDto{Line: line}
+
+	values := map[string]string{}
+	for i, value := range record {
+		if r.columns[i] != "" {
+			values[r.columns[i]] = strings.TrimSpace(value)
+		}
+	}
+
+	row.Customer = dtos.CreateCustomerDto{
+		Name:  values["name"],
+		CPF:   values["cpf"],
+		Email: values["email"],
+		Phone: values["phone"],
+	}
+
+	if values["documentType"] != "" || values["documentNumber"] != "" {
+		row.Customer.Document = &dtos.IdentityDocumentDto{
+			Type:    values["documentType"],
+			Number:  values["documentNumber"],
+			Country: values["documentCountry"],
+		}
+	}
+
+	row.Err = validator.Validate(row.Customer)
+
+	return row, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonReader) Next() (dtos.ImportCustomerRowDto, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		content := bytes.TrimSpace(r.scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		row := dtos.ImportCustomerRowDto{Line: r.line}
+		if err := json.Unmarshal(content, &row.Customer); err != nil {
+			row.Err = fmt.Errorf("%w: %s", entities.ErrInvalidImportRow, err)
+			return row, nil
+		}
+
+		row.Err = validator.Validate(row.Customer)
+
+		return row, nil
+	}
+
+	if err := r.scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return dtos.ImportCustomerRowDto{}, fmt.Errorf("linha %d: %w: mais de %d bytes", r.line+1, entities.ErrInvalidImportRow, maxLineBytes)
+	} else if err != nil {
+		return dtos.ImportCustomerRowDto{}, fmt.Errorf("linha %d: %w", r.line+1, err)
+	}
+
+	return dtos.ImportCustomerRowDto{}, io.EOF
+}
@@ -0,0 +1,129 @@
+package importers
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, source gateways.CustomerImportSource) []dtos.ImportCustomerRowDto {
+	var rows []dtos.ImportCustomerRowDto
+	for {
+		row, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestCustomerReader_CSV(t *testing.T) {
+	file := "\ufeffname,cpf,email,phone,loja\n" +
+		"João da Silva,12345678900,joao@example.com,(11) 98765-4321,Centro\n" +
+		"\"Maria, a do balcão\",98765432100,maria@example.com,,Centro\n" +
+		"Sem Email,11122233344,,,Centro\n" +
+		"Colunas,demais\n"
+
+	source, err := NewCustomerReader(FormatCSV, strings.NewReader(file))
+	require.NoError(t, err)
+
+	rows := readAll(t, source)
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, 2, rows[0].Line)
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, dtos.CreateCustomerDto{Name: "João da Silva", CPF: "12345678900", Email: "joao@example.com", Phone: "(11) 98765-4321"}, rows[0].Customer)
+
+	assert.NoError(t, rows[1].Err)
+	assert.Equal(t, "Maria, a do balcão", rows[1].Customer.Name)
+
+	assert.Equal(t, 4, rows[2].Line)
+	assert.Error(t, rows[2].Err)
+
+	assert.Equal(t, 5, rows[3].Line)
+	assert.ErrorIs(t, rows[3].Err, entities.ErrInvalidImportRow)
+}
+
+func TestCustomerReader_CSVSemicolonAndDocument(t *testing.T) {
+	file := "name;email;documentType;documentNumber;documentCountry\n" +
+		"Jean Dupont;jean@example.com;passport;AB123456;FR\n"
+
+	source, err := NewCustomerReader(FormatCSV, strings.NewReader(file))
+	require.NoError(t, err)
+
+	rows := readAll(t, source)
+	require.Len(t, rows, 1)
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, &dtos.IdentityDocumentDto{Type: "passport", Number: "AB123456", Country: "FR"}, rows[0].Customer.Document)
+}
+
+func TestCustomerReader_CSVInvalidHeader(t *testing.T) {
+	for name, file := range map[string]string{
+		"vazio":             "",
+		"sem documento":     "name,email\nJoão,joao@example.com\n",
+		"coluna repetida":   "name,email,cpf,cpf\n",
+		"sem colunas úteis": "nome,e-mail\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewCustomerReader(FormatCSV, strings.NewReader(file))
+			assert.ErrorIs(t, err, entities.ErrInvalidImportHeader)
+		})
+	}
+}
+
+func TestCustomerReader_NDJSON(t *testing.T) {
+	file := `{"name":"João da Silva","cpf":"12345678900","email":"joao@example.com"}` + "\n" +
+		"\n" +
+		`{"name":"Jean Dupont","document":{"type":"passport","number":"AB123456","country":"FR"},"email":"jean@example.com"}` + "\n" +
+		`{"name":` + "\n" +
+		`{"name":"CPF com máscara","cpf":"123.456.789-00","email":"cpf@example.com"}`
+
+	source, err := NewCustomerReader(FormatNDJSON, strings.NewReader(file))
+	require.NoError(t, err)
+
+	rows := readAll(t, source)
+	require.Len(t, rows, 4)
+
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, "12345678900", rows[0].Customer.CPF)
+
+	assert.Equal(t, 3, rows[1].Line)
+	assert.NoError(t, rows[1].Err)
+	assert.Equal(t, "AB123456", rows[1].Customer.Document.Number)
+
+	assert.Equal(t, 4, rows[2].Line)
+	assert.ErrorIs(t, rows[2].Err, entities.ErrInvalidImportRow)
+
+	// mesma validação do POST /v2/customers: CPF só com números
+	assert.Equal(t, 5, rows[3].Line)
+	assert.Error(t, rows[3].Err)
+}
+
+func TestCustomerReader_NDJSONLineTooLong(t *testing.T) {
+	file := `{"name":"` + strings.Repeat("a", maxLineBytes) + `"}` + "\n"
+
+	source, err := NewCustomerReader(FormatNDJSON, strings.NewReader(file))
+	require.NoError(t, err)
+
+	_, err = source.Next()
+	assert.ErrorIs(t, err, entities.ErrInvalidImportRow)
+}
+
+func TestNewCustomerReader_UnknownFormat(t *testing.T) {
+	_, err := NewCustomerReader("xlsx", strings.NewReader(""))
+	assert.ErrorIs(t, err, entities.ErrUnknownImportFormat)
+}
+
+func TestFormatFromContentType(t *testing.T) {
+	assert.Equal(t, FormatCSV, FormatFromContentType("text/csv; charset=utf-8"))
+	assert.Equal(t, FormatNDJSON, FormatFromContentType("application/x-ndjson"))
+	assert.Empty(t, FormatFromContentType("application/json"))
+}
@@ -0,0 +1,31 @@
+package dtos
+
+// CreateAddressDto cadastra um endereço. Logradouro, bairro, cidade e UF podem ser omitidos
+// quando o CEP os fornece; cidade e UF sempre vêm do CEP quando ele é encontrado.
+type CreateAddressDto struct {
+	Label        string `json:"label" validate:"nonzero,max=50"`
+	CEP          string `json:"cep" validate:"regexp=^[0-9]{5}-?[0-9]{3}$"`
+	Street       string `json:"street" validate:"max=200"`
+	Number       string `json:"number" validate:"nonzero,max=20"`
+	Complement   string `json:"complement" validate:"max=100"`
+	Neighborhood string `json:"neighborhood" validate:"max=100"`
+	City         string `json:"city" validate:"max=100"`
+	State        string `json:"state" validate:"regexp=^([A-Za-z]{2})?$"`
+	IsDefault    bool   `json:"isDefault"`
+}
+
+// UpdateAddressDto altera parcialmente o endereço; campos vazios são mantidos.
+// Um CEP novo substitui logradouro, bairro, cidade e UF pelos do CEP, salvo os informados;
+// cidade e UF só podem ser alteradas junto com o CEP.
+// IsDefault só torna o endereço padrão: para trocar o padrão, marque outro endereço.
+type UpdateAddressDto struct {
+	Label        string `json:"label" validate:"max=50"`
+	CEP          string `json:"cep" validate:"regexp=^([0-9]{5}-?[0-9]{3})?$"`
+	Street       string `json:"street" validate:"max=200"`
+	Number       string `json:"number" validate:"max=20"`
+	Complement   string `json:"complement" validate:"max=100"`
+	Neighborhood string `json:"neighborhood" validate:"max=100"`
+	City         string `json:"city" validate:"max=100"`
+	State        string `json:"state" validate:"regexp=^([A-Za-z]{2})?$"`
+	IsDefault    bool   `json:"isDefault"`
+}
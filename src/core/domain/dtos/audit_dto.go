@@ -0,0 +1,24 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type ListAuditEntriesDto struct {
+	Actor      string     `form:"actor" validate:"max=100"`
+	Action     string     `form:"action" validate:"max=50"`
+	TargetType string     `form:"targetType" validate:"max=50"`
+	TargetID   string     `form:"targetId" validate:"max=50"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Since      string     `form:"since" validate:"max=200"`
+	Limit      int        `form:"limit" validate:"min=0,max=1000"`
+}
+
+type AuditEntriesPageDto struct {
+	Entries    []entities.AuditEntry `json:"entries"`
+	NextCursor string                `json:"nextCursor"`
+	HasMore    bool                  `json:"hasMore"`
+}
@@ -0,0 +1,14 @@
+package dtos
+
+import "github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+
+type BatchGetCustomersDto struct {
+	IDs []uint `json:"ids" validate:"nonzero"`
+}
+
+type BatchGetCustomersResultDto struct {
+	Customers  []entities.Customer `json:"customers"`
+	MissingIDs []uint              `json:"missingIds"`
+	// Redirects liga cada id pedido que foi incorporado numa fusão ao id do sobrevivente
+	Redirects map[uint]uint `json:"redirects,omitempty"`
+}
@@ -1,7 +1,28 @@
 package dtos
 
+// CreateCustomerDto cadastra o cliente com CPF ou, para estrangeiros, com outro documento:
+// exatamente um dos dois deve ser informado. O telefone é opcional e chega sem verificação.
 type CreateCustomerDto struct {
+	Name     string               `json:"name" validate:"nonzero"`
+	CPF      string               `json:"cpf" validate:"regexp=^([0-9]{11})?$"`
+	Document *IdentityDocumentDto `json:"document"`
+	Email    string               `json:"email" validate:"nonzero, regexp=^[a-z0-9._-]+@[a-z0-9.-]+\\.[a-z]*$"`
+	Phone    string               `json:"phone" validate:"max=20"`
+}
+
+// LegacyCreateCustomerDto é o cadastro da v1, congelado: só CPF, sem documento nem telefone,
+// com as mesmas validações de antes da v2.
+type LegacyCreateCustomerDto struct {
 	Name  string `json:"name" validate:"nonzero"`
 	CPF   string `json:"cpf" validate:"len=11, regexp=^[0-9]*$"`
 	Email string `json:"email" validate:"nonzero, regexp=^[a-z0-9._-]+@[a-z0-9.-]+\\.[a-z]*$"`
 }
+
+// LegacyCustomerDto é o cliente devolvido pela v1, com os campos de antes da v2
+type LegacyCustomerDto struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	CPF       string `json:"cpf"`
+	Email     string `json:"email"`
+	CreatedAt string `json:"createdAt"`
+}
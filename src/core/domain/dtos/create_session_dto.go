@@ -0,0 +1,9 @@
+package dtos
+
+// CreateSessionDto identifica o cliente pelo CPF, por outro documento ou pelo telefone verificado;
+// sem nenhum deles a sessão é anônima
+type CreateSessionDto struct {
+	CPF      string               `json:"cpf" validate:"min=0,max=11,regexp=^[0-9]*$"`
+	Document *IdentityDocumentDto `json:"document"`
+	Phone    string               `json:"phone" validate:"max=20"`
+}
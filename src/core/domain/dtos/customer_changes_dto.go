@@ -0,0 +1,14 @@
+package dtos
+
+import "github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+
+type ListCustomerChangesDto struct {
+	Since string `form:"since" validate:"max=200"`
+	Limit int    `form:"limit" validate:"min=0,max=1000"`
+}
+
+type CustomerChangesPageDto struct {
+	Changes    []entities.CustomerChange `json:"changes"`
+	NextCursor string                    `json:"nextCursor"`
+	HasMore    bool                      `json:"hasMore"`
+}
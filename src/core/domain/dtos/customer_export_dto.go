@@ -0,0 +1,26 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// CustomerDataExportDto reúne os dados do titular (LGPD, art. 18) e quem acessou esses dados
+type CustomerDataExportDto struct {
+	Customer            entities.Customer             `json:"customer"`
+	Addresses           []entities.Address            `json:"addresses"`
+	Preferences         entities.DietaryPreferences   `json:"preferences"`
+	FiscalProfile       *entities.FiscalProfile       `json:"fiscalProfile,omitempty"`
+	LoyaltyTransactions []entities.LoyaltyTransaction `json:"loyaltyTransactions"`
+	AccessLog           []AccessRecordDto             `json:"accessLog"`
+	ExportedAt          time.Time                     `json:"exportedAt"`
+}
+
+// AccessRecordDto é a entrada de auditoria vista pelo titular, sem IP e request id de terceiros
+type AccessRecordDto struct {
+	OccurredAt time.Time `json:"occurredAt"`
+	Action     string    `json:"action"`
+	Actor      string    `json:"actor"`
+	Role       string    `json:"role"`
+}
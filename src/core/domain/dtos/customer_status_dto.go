@@ -0,0 +1,6 @@
+package dtos
+
+// ChangeCustomerStatusDto é o corpo das transições de status; o destino vem do método da rota
+type ChangeCustomerStatusDto struct {
+	Reason string `json:"reason" validate:"nonzero,max=500"`
+}
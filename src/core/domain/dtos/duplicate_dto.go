@@ -0,0 +1,11 @@
+package dtos
+
+type ListDuplicatesDto struct {
+	Status string `form:"status" validate:"regexp=^(pending|dismissed|merged)?$"`
+	Limit  int    `form:"limit" validate:"min=0,max=500"`
+}
+
+// MergeCustomersDto indica o cliente a ser incorporado; o sobrevivente vem na rota
+type MergeCustomersDto struct {
+	MergedCustomerID uint `json:"mergedCustomerId" validate:"min=1"`
+}
@@ -0,0 +1,15 @@
+package dtos
+
+// SaveFiscalProfileDto cria ou substitui o perfil fiscal. O documento é aceito com ou sem pontuação.
+type SaveFiscalProfileDto struct {
+	DocumentType      string `json:"documentType" validate:"nonzero"`
+	Document          string `json:"document" validate:"nonzero, max=18"`
+	LegalName         string `json:"legalName" validate:"max=255"`
+	StateRegistration string `json:"stateRegistration" validate:"max=20"`
+	FiscalEmail       string `json:"fiscalEmail" validate:"regexp=^([a-z0-9._-]+@[a-z0-9.-]+\\.[a-z]*)?$"`
+}
+
+// ResolveFiscalProfileDto traz o token de sessão do cliente que está pedindo a nota
+type ResolveFiscalProfileDto struct {
+	Token string `json:"token" validate:"nonzero"`
+}
@@ -0,0 +1,9 @@
+package dtos
+
+// IdentityDocumentDto é o documento de quem não tem CPF. Type é cpf, passport ou rne (RNE/CRNM);
+// country é o país emissor, obrigatório só para passaporte.
+type IdentityDocumentDto struct {
+	Type    string `json:"type" validate:"nonzero"`
+	Number  string `json:"number" validate:"nonzero, max=20"`
+	Country string `json:"country" validate:"regexp=^([A-Za-z]{2})?$"`
+}
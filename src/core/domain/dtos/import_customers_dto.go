@@ -0,0 +1,56 @@
+package dtos
+
+// Políticas para a linha cujo documento já está cadastrado
+const (
+	ImportConflictSkip   = "skip"
+	ImportConflictUpsert = "upsert"
+)
+
+// Resultados de cada linha importada. No dry-run indicam o que aconteceria.
+const (
+	ImportRowCreated = "created"
+	ImportRowUpdated = "updated"
+	ImportRowSkipped = "skipped"
+	ImportRowFailed  = "failed"
+)
+
+// ImportCustomersDto são as opções da importação em lote, na query do upload ou nas flags do comando.
+// Sem Format o upload usa o Content-Type; sem OnConflict as linhas já cadastradas são ignoradas.
+type ImportCustomersDto struct {
+	Format     string `form:"format" validate:"regexp=^(csv|ndjson)?$"`
+	OnConflict string `form:"onConflict" validate:"regexp=^(skip|upsert)?$"`
+	DryRun     bool   `form:"dryRun"`
+}
+
+// ImportCustomerRowDto é uma linha lida do arquivo. Err vem preenchido quando a linha está
+// malformada ou não passa na validação de CreateCustomerDto; as demais linhas seguem.
+type ImportCustomerRowDto struct {
+	Line     int
+	Customer CreateCustomerDto
+	Err      error
+}
+
+// ImportRowResultDto é uma linha do relatório: só as ignoradas e as que falharam entram nele
+type ImportRowResultDto struct {
+	Line       int    `json:"line"`
+	Outcome    string `json:"outcome"`
+	CustomerID uint   `json:"customerId,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type ImportCustomersSummaryDto struct {
+	DryRun  bool `json:"dryRun"`
+	Rows    int  `json:"rows"`
+	Created int  `json:"created"`
+	Updated int  `json:"updated"`
+	Skipped int  `json:"skipped"`
+	Failed  int  `json:"failed"`
+}
+
+// ImportCustomersReportDto é a resposta do upload; Rows traz até o limite de linhas do relatório
+// e RowsTruncated indica que houve mais
+type ImportCustomersReportDto struct {
+	Summary       ImportCustomersSummaryDto `json:"summary"`
+	Rows          []ImportRowResultDto      `json:"rows"`
+	RowsTruncated bool                      `json:"rowsTruncated"`
+}
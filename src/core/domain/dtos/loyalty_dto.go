@@ -0,0 +1,57 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// EarnPointsDto credita os pontos de um pedido pago. Reference identifica o pedido
+// e torna a chamada idempotente.
+type EarnPointsDto struct {
+	Reference   string `json:"reference" validate:"nonzero,max=100"`
+	AmountCents int64  `json:"amountCents" validate:"min=1"`
+}
+
+type RedeemPointsDto struct {
+	Reference   string `json:"reference" validate:"nonzero,max=100"`
+	Points      int64  `json:"points" validate:"min=1"`
+	Description string `json:"description" validate:"max=200"`
+}
+
+// AdjustPointsDto é uma correção manual; pontos negativos podem deixar o saldo devedor
+type AdjustPointsDto struct {
+	Reference   string     `json:"reference" validate:"nonzero,max=100"`
+	Points      int64      `json:"points" validate:"nonzero"`
+	Description string     `json:"description" validate:"nonzero,max=200"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+}
+
+type ListLoyaltyTransactionsDto struct {
+	Since string `form:"since" validate:"max=200"`
+	Limit int    `form:"limit" validate:"min=0,max=500"`
+}
+
+type LoyaltyBalanceDto struct {
+	CustomerID       uint                      `json:"customerId"`
+	Points           int64                     `json:"points"`
+	Tier             string                    `json:"tier"`
+	TierPoints       int64                     `json:"tierPoints"`
+	NextTier         string                    `json:"nextTier,omitempty"`
+	PointsToNextTier int64                     `json:"pointsToNextTier,omitempty"`
+	Expiring         []entities.ExpiringPoints `json:"expiring"`
+}
+
+// LoyaltyPostingDto é a resposta de um lançamento: o lançamento gravado (ou o original,
+// numa repetição, com Replayed) e o saldo atual
+type LoyaltyPostingDto struct {
+	Transaction entities.LoyaltyTransaction `json:"transaction"`
+	Replayed    bool                        `json:"replayed"`
+	Balance     LoyaltyBalanceDto           `json:"balance"`
+}
+
+type LoyaltyTransactionsPageDto struct {
+	Transactions []entities.LoyaltyTransaction `json:"transactions"`
+	NextCursor   string                        `json:"nextCursor"`
+	HasMore      bool                          `json:"hasMore"`
+}
@@ -0,0 +1,13 @@
+package dtos
+
+import "time"
+
+type VerifyPhoneDto struct {
+	Code string `json:"code" validate:"regexp=^[0-9]{6}$"`
+}
+
+// PhoneCodeSentDto informa até quando o código vale e a partir de quando outro pode ser pedido
+type PhoneCodeSentDto struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	ResendAt  time.Time `json:"resendAt"`
+}
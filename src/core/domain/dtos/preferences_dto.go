@@ -0,0 +1,9 @@
+package dtos
+
+// UpdatePreferencesDto substitui as preferências alimentares do cliente. Alérgenos e estilos
+// usam os códigos do vocabulário controlado; listas vazias ou omitidas apagam a preferência.
+type UpdatePreferencesDto struct {
+	Allergens           []string `json:"allergens" validate:"max=20"`
+	DietaryStyles       []string `json:"dietaryStyles" validate:"max=10"`
+	DislikedIngredients []string `json:"dislikedIngredients" validate:"max=30"`
+}
@@ -0,0 +1,20 @@
+package dtos
+
+import "time"
+
+// SendSessionCodeDto escolhe o canal do código que confirma a sessão. Sem canal, o código vai por SMS
+// quando o cliente tem telefone verificado e por email nos demais casos.
+type SendSessionCodeDto struct {
+	Channel string `json:"channel" validate:"regexp=^(sms|email)?$"`
+}
+
+type VerifySessionCodeDto struct {
+	Code string `json:"code" validate:"regexp=^[0-9]{6}$"`
+}
+
+// SessionCodeSentDto informa o canal usado, até quando o código vale e a partir de quando outro pode ser pedido
+type SessionCodeSentDto struct {
+	Channel   string    `json:"channel"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	ResendAt  time.Time `json:"resendAt"`
+}
@@ -0,0 +1,9 @@
+package dtos
+
+// UpdateCustomerDto altera parcialmente o cliente; campos vazios são mantidos.
+// O CPF identifica o cliente e não pode ser alterado; trocar o telefone exige verificá-lo de novo.
+type UpdateCustomerDto struct {
+	Name  string `json:"name" validate:"max=255"`
+	Email string `json:"email" validate:"regexp=^([a-z0-9._-]+@[a-z0-9.-]+\\.[a-z]*)?$"`
+	Phone string `json:"phone" validate:"max=20"`
+}
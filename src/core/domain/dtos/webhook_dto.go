@@ -0,0 +1,12 @@
+package dtos
+
+type CreateWebhookSubscriptionDto struct {
+	URL        string   `json:"url" validate:"nonzero,max=2048"`
+	EventTypes []string `json:"eventTypes" validate:"nonzero"`
+	Secret     string   `json:"secret" validate:"max=100"`
+}
+
+type ListWebhookDeliveriesDto struct {
+	Status string `form:"status" validate:"regexp=^(pending|succeeded|dead)?$"`
+	Limit  int    `form:"limit" validate:"min=0,max=500"`
+}
@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var cepPattern = regexp.MustCompile(`^[0-9]{5}-?[0-9]{3}$`)
+
+var brazilianStates = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// Address é um endereço de entrega do cliente. CEP guarda só os 8 dígitos.
+type Address struct {
+	ID           uint      `json:"id"`
+	Label        string    `json:"label"`
+	CEP          string    `json:"cep"`
+	Street       string    `json:"street"`
+	Number       string    `json:"number"`
+	Complement   string    `json:"complement,omitempty"`
+	Neighborhood string    `json:"neighborhood,omitempty"`
+	City         string    `json:"city"`
+	State        string    `json:"state"`
+	Default      bool      `json:"isDefault"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Complete indica se o endereço tem o mínimo para uma entrega. O bairro é opcional:
+// CEPs gerais de cidades pequenas não têm logradouro nem bairro.
+func (a Address) Complete() bool {
+	return a.Street != "" && a.Number != "" && a.City != "" && a.State != ""
+}
+
+// CEPAddress é o logradouro devolvido pelo provedor de CEP
+type CEPAddress struct {
+	CEP          string `json:"cep"`
+	Street       string `json:"street"`
+	Neighborhood string `json:"neighborhood"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+}
+
+// NormalizeCEP aceita o CEP com ou sem hífen e devolve os 8 dígitos
+func NormalizeCEP(cep string) (string, error) {
+	cep = strings.TrimSpace(cep)
+	if !cepPattern.MatchString(cep) {
+		return "", ErrInvalidCEP
+	}
+
+	return strings.ReplaceAll(cep, "-", ""), nil
+}
+
+func ValidState(state string) bool {
+	return brazilianStates[state]
+}
+
+// AddressBook é o caderno de endereços de um cliente. Havendo endereços, exatamente um é o padrão.
+type AddressBook struct {
+	CustomerID uint
+	Addresses  []Address
+}
+
+// Add inclui o endereço e devolve sua posição. O primeiro endereço é sempre o padrão.
+func (b *AddressBook) Add(address Address, limit int) (int, error) {
+	if len(b.Addresses) >= limit {
+		return 0, ErrAddressLimitReached
+	}
+
+	address.ID = 0
+	address.Default = address.Default || len(b.Addresses) == 0
+	b.Addresses = append(b.Addresses, address)
+
+	index := len(b.Addresses) - 1
+	if address.Default {
+		b.SetDefault(index)
+	}
+
+	return index, nil
+}
+
+func (b *AddressBook) Find(id uint) (int, error) {
+	for i, address := range b.Addresses {
+		if address.ID == id {
+			return i, nil
+		}
+	}
+
+	return 0, ErrAddressNotFound
+}
+
+// SetDefault torna padrão o endereço da posição index e desmarca os demais
+func (b *AddressBook) SetDefault(index int) {
+	for i := range b.Addresses {
+		b.Addresses[i].Default = i == index
+	}
+}
+
+// Remove exclui o endereço. Se ele era o padrão, o mais antigo dos restantes assume.
+func (b *AddressBook) Remove(id uint) error {
+	index, err := b.Find(id)
+	if err != nil {
+		return err
+	}
+
+	removed := b.Addresses[index]
+	b.Addresses = append(b.Addresses[:index], b.Addresses[index+1:]...)
+
+	if removed.Default && len(b.Addresses) > 0 {
+		b.SetDefault(0)
+	}
+
+	return nil
+}
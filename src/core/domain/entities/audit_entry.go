@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+)
+
+// Ações registradas na auditoria
+const (
+	AuditActionCustomerCreated      = "customer.created"
+	AuditActionCustomerUpdated      = "customer.updated"
+	AuditActionCustomerErased       = "customer.erased"
+	AuditActionCustomerExported     = "customer.exported"
+	AuditActionCustomerRead         = "customer.read"
+	AuditActionCustomerMerged       = "customer.merged"
+	AuditActionStatusChanged        = "customer.status_changed"
+	AuditActionCPFLookup            = "customer.cpf_lookup"
+	AuditActionSessionIssued        = "session.issued"
+	AuditActionSessionCodeSent      = "session.code_sent"
+	AuditActionSessionVerified      = "session.verified"
+	AuditActionAddressCreated       = "address.created"
+	AuditActionAddressUpdated       = "address.updated"
+	AuditActionAddressDeleted       = "address.deleted"
+	AuditActionPreferencesRead      = "preferences.read"
+	AuditActionPreferencesUpdated   = "preferences.updated"
+	AuditActionFiscalProfileRead    = "fiscal_profile.read"
+	AuditActionFiscalProfileUpdated = "fiscal_profile.updated"
+	AuditActionFiscalProfileDeleted = "fiscal_profile.deleted"
+	AuditActionPhoneCodeSent        = "phone.code_sent"
+	AuditActionPhoneVerified        = "phone.verified"
+)
+
+const AuditTargetCustomer = "customer"
+
+// AuditEntry é uma entrada do log de auditoria. Hash encadeia a entrada à anterior (PrevHash),
+// então alterar ou remover uma entrada quebra a verificação de todas as seguintes.
+// TargetID vazio indica que não há cliente associado (CPF não encontrado, sessão anônima).
+type AuditEntry struct {
+	Sequence   uint64    `json:"sequence"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Actor      string    `json:"actor"`
+	Role       string    `json:"role"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"targetType"`
+	TargetID   string    `json:"targetId"`
+	IP         string    `json:"ip"`
+	RequestID  string    `json:"requestId"`
+	PrevHash   string    `json:"prevHash"`
+	Hash       string    `json:"hash"`
+}
+
+// AuditFilter seleciona entradas na consulta; campos vazios não filtram
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+// CustomerAuditEntry monta a entrada de uma ação sobre um cliente; id zero fica sem alvo.
+// Ator, IP e request id são preenchidos por quem grava a entrada.
+func CustomerAuditEntry(action string, customerID uint) AuditEntry {
+	entry := AuditEntry{Action: action, TargetType: AuditTargetCustomer}
+	if customerID != 0 {
+		entry.TargetID = strconv.FormatUint(uint64(customerID), 10)
+	}
+
+	return entry
+}
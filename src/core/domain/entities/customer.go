@@ -1,9 +1,24 @@
 package entities
 
+// Customer é o cliente. Clientes com CPF têm só o CPF, com a mesma forma de antes dos documentos
+// estrangeiros; os demais têm o CPF vazio e o documento em Document. Phone é opcional.
+// Só clientes com Status active recebem sessão identificada.
 type Customer struct {
-	ID        uint   `json:"id"`
-	Name      string `json:"name"`
-	CPF       string `json:"cpf"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"createdAt"`
+	ID        uint              `json:"id"`
+	Name      string            `json:"name"`
+	CPF       string            `json:"cpf"`
+	Document  *IdentityDocument `json:"document,omitempty"`
+	Email     string            `json:"email"`
+	Phone     *CustomerPhone    `json:"phone,omitempty"`
+	Status    CustomerStatus    `json:"status"`
+	CreatedAt string            `json:"createdAt"`
+}
+
+// IdentityDocument devolve o documento do cliente, seja o CPF ou o estrangeiro
+func (c Customer) IdentityDocument() IdentityDocument {
+	if c.Document == nil {
+		return CPFDocument(c.CPF)
+	}
+
+	return *c.Document
 }
@@ -0,0 +1,40 @@
+package entities
+
+import "time"
+
+// Operações registradas no log de alterações de clientes
+const (
+	CustomerChangeCreated = "created"
+	CustomerChangeUpdated = "updated"
+	CustomerChangeErased  = "erased"
+	CustomerChangeMerged  = "merged"
+)
+
+// CustomerChange é uma entrada do feed de alterações. Customer traz o estado atual do cliente
+// e é nulo nas remoções (tombstones), que não carregam dados pessoais. Na fusão o tombstone
+// do cliente incorporado traz em MergedInto o id que responde por ele.
+type CustomerChange struct {
+	Sequence      uint64    `json:"-"`
+	Operation     string    `json:"operation"`
+	CustomerID    uint      `json:"customerId"`
+	ChangedAt     time.Time `json:"changedAt"`
+	ChangedFields []string  `json:"changedFields"`
+	Customer      *Customer `json:"customer"`
+	MergedInto    *uint     `json:"mergedInto,omitempty"`
+}
+
+// CustomerChangePosition é a posição de uma alteração no feed: o id da transação que a gravou
+// e a sequência dentro dela. O feed é ordenado por essa dupla.
+type CustomerChangePosition struct {
+	TransactionID uint64
+	Sequence      uint64
+}
+
+// Before indica se a posição vem antes de other no feed
+func (p CustomerChangePosition) Before(other CustomerChangePosition) bool {
+	if p.TransactionID != other.TransactionID {
+		return p.TransactionID < other.TransactionID
+	}
+
+	return p.Sequence < other.Sequence
+}
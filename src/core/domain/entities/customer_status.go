@@ -0,0 +1,58 @@
+package entities
+
+import "time"
+
+// CustomerStatus é a situação da conta do cliente
+type CustomerStatus string
+
+const (
+	CustomerPendingVerification CustomerStatus = "pending_verification"
+	CustomerActive              CustomerStatus = "active"
+	CustomerSuspended           CustomerStatus = "suspended"
+	CustomerClosed              CustomerStatus = "closed"
+)
+
+// customerStatusTransitions é a máquina de estados da conta:
+// pending_verification → active ↔ suspended → closed. closed é final.
+var customerStatusTransitions = map[CustomerStatus][]CustomerStatus{
+	CustomerPendingVerification: {CustomerActive},
+	CustomerActive:              {CustomerSuspended},
+	CustomerSuspended:           {CustomerActive, CustomerClosed},
+}
+
+// CanTransitionTo diz se a máquina de estados permite sair de s para target
+func (s CustomerStatus) CanTransitionTo(target CustomerStatus) bool {
+	for _, allowed := range customerStatusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanMerge diz se a conta pode entrar numa fusão, como sobrevivente ou incorporada. Suspensa e
+// encerrada ficam de fora: a fusão reativaria o histórico de quem foi bloqueado ou saiu.
+func (s CustomerStatus) CanMerge() bool {
+	return s == CustomerActive || s == CustomerPendingVerification
+}
+
+// SessionReason é o código devolvido junto da sessão anônima emitida para um cliente que não está
+// ativo. Clientes ativos não têm motivo.
+func (s CustomerStatus) SessionReason() string {
+	if s == CustomerActive {
+		return ""
+	}
+
+	return "customer_" + string(s)
+}
+
+// CustomerStatusChange é uma transição gravada, com o motivo informado pelo admin
+type CustomerStatusChange struct {
+	CustomerID uint           `json:"customerId"`
+	From       CustomerStatus `json:"from"`
+	To         CustomerStatus `json:"to"`
+	Reason     string         `json:"reason"`
+	ChangedBy  string         `json:"changedBy"`
+	ChangedAt  time.Time      `json:"changedAt"`
+}
@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+// Situações de um par suspeito de duplicidade
+const (
+	DuplicatePending   = "pending"
+	DuplicateDismissed = "dismissed"
+	DuplicateMerged    = "merged"
+)
+
+// Motivos que levaram o par à lista de suspeitos
+const (
+	DuplicateReasonName  = "name"
+	DuplicateReasonEmail = "email"
+	DuplicateReasonPhone = "phone"
+)
+
+// DuplicateCandidate é um par de clientes que a detecção julgou ser a mesma pessoa.
+// CustomerID é sempre o menor dos dois ids. Customers só vem preenchido na listagem do admin.
+type DuplicateCandidate struct {
+	ID              uint       `json:"id"`
+	CustomerID      uint       `json:"customerId"`
+	OtherCustomerID uint       `json:"otherCustomerId"`
+	Score           float64    `json:"score"`
+	Reasons         []string   `json:"reasons"`
+	Status          string     `json:"status"`
+	DetectedAt      time.Time  `json:"detectedAt"`
+	ResolvedAt      *time.Time `json:"resolvedAt,omitempty"`
+	Customers       []Customer `json:"customers,omitempty"`
+}
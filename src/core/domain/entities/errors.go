@@ -0,0 +1,69 @@
+package entities
+
+import "errors"
+
+var (
+	ErrCustomerAlreadyExists = errors.New("cliente já existe no sistema")
+	ErrCustomerNotFound      = errors.New("cliente não encontrado")
+	ErrTooManyIDs            = errors.New("quantidade de ids acima do limite permitido")
+	ErrInvalidToken          = errors.New("token de sessão inválido")
+	ErrInvalidCursor         = errors.New("cursor inválido")
+	ErrInvalidDocument       = errors.New("documento de identificação inválido")
+	ErrDocumentRequired      = errors.New("informe o CPF ou outro documento de identificação, não os dois")
+	ErrCustomerNotActive     = errors.New("conta do cliente não está ativa")
+	ErrInvalidStatusChange   = errors.New("transição de status não permitida para a situação atual da conta")
+
+	ErrWebhookNotFound         = errors.New("assinatura de webhook não encontrada")
+	ErrWebhookDeliveryNotFound = errors.New("entrega de webhook não encontrada")
+	ErrInvalidWebhookURL       = errors.New("url de webhook inválida")
+	ErrInvalidEventType        = errors.New("tipo de evento desconhecido")
+	ErrWeakWebhookSecret       = errors.New("segredo do webhook deve ter pelo menos 16 caracteres")
+
+	ErrInsufficientPoints       = errors.New("saldo de pontos insuficiente")
+	ErrInvalidPoints            = errors.New("quantidade de pontos inválida")
+	ErrLoyaltyReferenceConflict = errors.New("referência já usada em outro lançamento de pontos")
+
+	ErrAddressNotFound        = errors.New("endereço não encontrado")
+	ErrAddressLimitReached    = errors.New("limite de endereços por cliente atingido")
+	ErrIncompleteAddress      = errors.New("endereço incompleto: informe logradouro, número, cidade e UF")
+	ErrInvalidCEP             = errors.New("CEP inválido")
+	ErrInvalidState           = errors.New("UF inválida")
+	ErrCEPRequired            = errors.New("cidade e UF só podem ser alteradas junto com o CEP")
+	ErrCEPNotFound            = errors.New("CEP não encontrado")
+	ErrCEPProviderUnavailable = errors.New("consulta de CEP indisponível")
+
+	ErrInvalidPreferences = errors.New("preferências alimentares inválidas")
+
+	ErrFiscalProfileNotFound = errors.New("perfil fiscal não encontrado")
+	ErrInvalidFiscalProfile  = errors.New("perfil fiscal inválido")
+	ErrInvalidCPF            = errors.New("CPF inválido")
+	ErrInvalidCNPJ           = errors.New("CNPJ inválido")
+
+	ErrInvalidPhone              = errors.New("telefone inválido: informe um celular com DDD")
+	ErrPhoneNotFound             = errors.New("cliente sem telefone cadastrado")
+	ErrPhoneAlreadyVerified      = errors.New("telefone já verificado")
+	ErrPhoneAlreadyInUse         = errors.New("telefone já verificado por outro cliente")
+	ErrPhoneVerificationNotFound = errors.New("nenhum código de verificação pendente para o telefone")
+	ErrInvalidPhoneCode          = errors.New("código de verificação inválido")
+	ErrPhoneCodeExpired          = errors.New("código de verificação expirado; peça um novo")
+	ErrPhoneCodeResendTooSoon    = errors.New("aguarde para pedir um novo código de verificação")
+	ErrPhoneCodeDailyLimit       = errors.New("limite diário de códigos de verificação atingido; tente amanhã")
+	ErrAmbiguousSessionLookup    = errors.New("informe só um entre CPF, documento e telefone")
+
+	ErrSessionNotVerified          = errors.New("sessão não confirmada: confirme com o código enviado ao cliente")
+	ErrSMSUnavailable              = errors.New("envio de SMS indisponível")
+	ErrSessionChannelUnavailable   = errors.New("canal indisponível para o cliente: o SMS exige telefone verificado e o email, email cadastrado")
+	ErrSessionVerificationNotFound = errors.New("nenhum código de confirmação pendente para a sessão")
+	ErrInvalidSessionCode          = errors.New("código de confirmação inválido")
+	ErrSessionCodeExpired          = errors.New("código de confirmação expirado; peça um novo")
+	ErrSessionCodeResendTooSoon    = errors.New("aguarde para pedir um novo código de confirmação")
+
+	ErrDuplicateCandidateNotFound = errors.New("par de duplicidade não encontrado")
+	ErrDuplicateAlreadyResolved   = errors.New("par de duplicidade já resolvido")
+	ErrSelfMerge                  = errors.New("um cliente não pode ser incorporado a ele mesmo")
+	ErrMergeNotAllowed            = errors.New("só clientes ativos ou pendentes de verificação podem ser fundidos")
+
+	ErrUnknownImportFormat = errors.New("formato de importação desconhecido: use csv ou ndjson")
+	ErrInvalidImportHeader = errors.New("cabeçalho do CSV inválido")
+	ErrInvalidImportRow    = errors.New("linha malformada")
+)
@@ -0,0 +1,137 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type FiscalDocumentType string
+
+const (
+	FiscalDocumentCPF  FiscalDocumentType = "CPF"
+	FiscalDocumentCNPJ FiscalDocumentType = "CNPJ"
+)
+
+// StateRegistrationExempt é o valor da inscrição estadual de quem não contribui com ICMS
+const StateRegistrationExempt = "ISENTO"
+
+const MaxLegalNameChars = 255
+
+var (
+	documentSeparators       = strings.NewReplacer(".", "", "-", "", "/", "", " ", "")
+	cpfPattern               = regexp.MustCompile(`^[0-9]{11}$`)
+	cnpjPattern              = regexp.MustCompile(`^[0-9A-Z]{12}[0-9]{2}$`)
+	stateRegistrationPattern = regexp.MustCompile(`^[0-9A-Z]{2,14}$`)
+)
+
+// FiscalProfile são os dados que o cliente quer na nota fiscal: CPF ("CPF na nota") ou o CNPJ
+// da empresa. Document guarda só os dígitos (ou letras, no CNPJ alfanumérico), sem pontuação.
+type FiscalProfile struct {
+	CustomerID        uint               `json:"customerId"`
+	DocumentType      FiscalDocumentType `json:"documentType"`
+	Document          string             `json:"document"`
+	LegalName         string             `json:"legalName,omitempty"`
+	StateRegistration string             `json:"stateRegistration,omitempty"`
+	FiscalEmail       string             `json:"fiscalEmail,omitempty"`
+	UpdatedAt         time.Time          `json:"updatedAt"`
+}
+
+// NewFiscalProfile normaliza e valida o perfil fiscal. O documento passa pelos dígitos
+// verificadores do tipo informado; a razão social só é obrigatória para CNPJ.
+func NewFiscalProfile(customerID uint, documentType FiscalDocumentType, document, legalName, stateRegistration, fiscalEmail string) (FiscalProfile, error) {
+	profile := FiscalProfile{
+		CustomerID:        customerID,
+		DocumentType:      documentType,
+		Document:          NormalizeDocument(document),
+		LegalName:         strings.Join(strings.Fields(legalName), " "),
+		StateRegistration: NormalizeDocument(stateRegistration),
+		FiscalEmail:       strings.ToLower(strings.TrimSpace(fiscalEmail)),
+	}
+
+	switch documentType {
+	case FiscalDocumentCPF:
+		if !ValidCPF(profile.Document) {
+			return FiscalProfile{}, ErrInvalidCPF
+		}
+	case FiscalDocumentCNPJ:
+		if !ValidCNPJ(profile.Document) {
+			return FiscalProfile{}, ErrInvalidCNPJ
+		}
+		if profile.LegalName == "" {
+			return FiscalProfile{}, fmt.Errorf("%w: razão social é obrigatória para CNPJ", ErrInvalidFiscalProfile)
+		}
+	default:
+		return FiscalProfile{}, fmt.Errorf("%w: tipo de documento deve ser CPF ou CNPJ", ErrInvalidFiscalProfile)
+	}
+
+	if len([]rune(profile.LegalName)) > MaxLegalNameChars {
+		return FiscalProfile{}, fmt.Errorf("%w: razão social com mais de %d caracteres", ErrInvalidFiscalProfile, MaxLegalNameChars)
+	}
+
+	if profile.StateRegistration != "" && profile.StateRegistration != StateRegistrationExempt &&
+		!stateRegistrationPattern.MatchString(profile.StateRegistration) {
+		return FiscalProfile{}, fmt.Errorf("%w: inscrição estadual inválida", ErrInvalidFiscalProfile)
+	}
+
+	return profile, nil
+}
+
+// NormalizeDocument tira a pontuação de CPF, CNPJ e inscrição estadual e passa as letras para maiúsculas
+func NormalizeDocument(document string) string {
+	return strings.ToUpper(documentSeparators.Replace(strings.TrimSpace(document)))
+}
+
+// ValidCPF confere os dois dígitos verificadores de um CPF só com dígitos.
+// Sequências repetidas (111.111.111-11) passam no cálculo, mas não são CPFs.
+func ValidCPF(cpf string) bool {
+	if !cpfPattern.MatchString(cpf) || repeatedChars(cpf) {
+		return false
+	}
+
+	for length := 9; length <= 10; length++ {
+		sum := 0
+		for i := 0; i < length; i++ {
+			sum += int(cpf[i]-'0') * (length + 1 - i)
+		}
+
+		if digit := sum * 10 % 11 % 10; digit != int(cpf[length]-'0') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ValidCNPJ confere os dígitos verificadores de um CNPJ numérico ou alfanumérico
+// (IN RFB 2.229/2024): cada caractere vale seu código ASCII menos 48, então os dígitos valem o próprio número.
+func ValidCNPJ(cnpj string) bool {
+	if !cnpjPattern.MatchString(cnpj) || repeatedChars(cnpj) {
+		return false
+	}
+
+	for length := 12; length <= 13; length++ {
+		sum := 0
+		for i := 0; i < length; i++ {
+			// pesos de 2 a 9 da direita para a esquerda, recomeçando em 2
+			weight := (length-1-i)%8 + 2
+			sum += int(cnpj[i]-'0') * weight
+		}
+
+		digit := 0
+		if remainder := sum % 11; remainder >= 2 {
+			digit = 11 - remainder
+		}
+
+		if digit != int(cnpj[length]-'0') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func repeatedChars(value string) bool {
+	return strings.Count(value, value[:1]) == len(value)
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+)
+
+type DocumentType string
+
+const (
+	DocumentCPF      DocumentType = "cpf"
+	DocumentPassport DocumentType = "passport"
+	// DocumentRNE cobre o RNE e a CRNM que o substituiu: o número tem o mesmo formato
+	DocumentRNE DocumentType = "rne"
+)
+
+// DocumentCountryBrazil é o país emissor do CPF e do RNE/CRNM
+const DocumentCountryBrazil = "BR"
+
+var (
+	// passaporte no padrão ICAO 9303: até 9 caracteres alfanuméricos
+	passportPattern = regexp.MustCompile(`^[0-9A-Z]{5,9}$`)
+	// RNE/CRNM: letra, seis dígitos e o dígito verificador (número ou letra)
+	rnePattern     = regexp.MustCompile(`^[A-Z][0-9]{6}[0-9A-Z]$`)
+	countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// IdentityDocument é o documento que identifica o cliente. Country é o país emissor (ISO 3166-1 alfa-2);
+// a unicidade vale por tipo e país, então o mesmo número de passaporte pode existir em países diferentes.
+type IdentityDocument struct {
+	Type    DocumentType `json:"type"`
+	Number  string       `json:"number"`
+	Country string       `json:"country"`
+}
+
+// NewIdentityDocument normaliza e valida o documento conforme o tipo. CPF e RNE/CRNM são
+// sempre brasileiros; o passaporte exige o país emissor.
+func NewIdentityDocument(documentType DocumentType, number, country string) (IdentityDocument, error) {
+	document := IdentityDocument{
+		Type:    DocumentType(strings.ToLower(string(documentType))),
+		Number:  NormalizeDocument(number),
+		Country: strings.ToUpper(strings.TrimSpace(country)),
+	}
+
+	switch document.Type {
+	case DocumentCPF:
+		if !ValidCPF(document.Number) {
+			return IdentityDocument{}, ErrInvalidCPF
+		}
+	case DocumentPassport:
+		if !passportPattern.MatchString(document.Number) || !countryPattern.MatchString(document.Country) {
+			return IdentityDocument{}, ErrInvalidDocument
+		}
+		return document, nil
+	case DocumentRNE:
+		if !rnePattern.MatchString(document.Number) {
+			return IdentityDocument{}, ErrInvalidDocument
+		}
+	default:
+		return IdentityDocument{}, ErrInvalidDocument
+	}
+
+	if document.Country != "" && document.Country != DocumentCountryBrazil {
+		return IdentityDocument{}, ErrInvalidDocument
+	}
+	document.Country = DocumentCountryBrazil
+
+	return document, nil
+}
+
+// CPFDocument é o documento dos clientes cadastrados só com CPF
+func CPFDocument(cpf string) IdentityDocument {
+	return IdentityDocument{Type: DocumentCPF, Number: cpf, Country: DocumentCountryBrazil}
+}
+
+// Key identifica o documento para unicidade e busca: tipo, país e número
+func (d IdentityDocument) Key() string {
+	return string(d.Type) + ":" + d.Country + ":" + d.Number
+}
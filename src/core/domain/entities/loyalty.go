@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// Tipos de lançamento do extrato de pontos
+const (
+	LoyaltyEarn   = "earn"
+	LoyaltyRedeem = "redeem"
+	LoyaltyAdjust = "adjust"
+	LoyaltyExpire = "expire"
+)
+
+// LoyaltyTransaction é um lançamento do extrato de pontos. Points é positivo nos créditos e
+// negativo nos débitos. Reference identifica o lançamento na origem (pedido, resgate, ajuste)
+// e é única por tipo, o que torna repetições idempotentes.
+type LoyaltyTransaction struct {
+	ID          uint   `json:"id"`
+	CustomerID  uint   `json:"customerId"`
+	Type        string `json:"type"`
+	Points      int64  `json:"points"`
+	Reference   string `json:"reference"`
+	Description string `json:"description,omitempty"`
+	AmountCents int64  `json:"amountCents,omitempty"`
+	// ExpiresAt é o vencimento de um crédito na política de validade por lançamento
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// LotID aponta, num lançamento de expiração, o crédito que venceu
+	LotID     *uint     `json:"lotId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// LoyaltyTier é uma faixa do programa: quem acumulou ao menos MinPoints na janela da política
+// entra na faixa e ganha pontos multiplicados por Multiplier
+type LoyaltyTier struct {
+	Name       string  `json:"name"`
+	MinPoints  int64   `json:"minPoints"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// ExpiringPoints são pontos de um crédito que vencem em ExpiresAt
+type ExpiringPoints struct {
+	Points    int64     `json:"points"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// phoneCountryBrazil é o código de país do Brasil no E.164
+const phoneCountryBrazil = "55"
+
+// brazilianAreaCodes são os DDDs em uso segundo o plano de numeração da Anatel
+var brazilianAreaCodes = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true, "16": true, "17": true, "18": true, "19": true,
+	"21": true, "22": true, "24": true, "27": true, "28": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "37": true, "38": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true, "47": true, "48": true, "49": true,
+	"51": true, "53": true, "54": true, "55": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true, "66": true, "67": true, "68": true, "69": true,
+	"71": true, "73": true, "74": true, "75": true, "77": true, "79": true,
+	"81": true, "82": true, "83": true, "84": true, "85": true, "86": true, "87": true, "88": true, "89": true,
+	"91": true, "92": true, "93": true, "94": true, "95": true, "96": true, "97": true, "98": true, "99": true,
+}
+
+// CustomerPhone é o celular do cliente em E.164. Só o telefone verificado por SMS identifica o cliente.
+type CustomerPhone struct {
+	Number   string `json:"number"`
+	Verified bool   `json:"verified"`
+}
+
+// PhoneVerification é o código de verificação pendente do cliente; o código em si só existe no SMS
+type PhoneVerification struct {
+	CustomerID uint
+	Phone      string
+	Attempts   int
+	SentAt     time.Time
+	ExpiresAt  time.Time
+}
+
+// NormalizePhone devolve o celular em E.164. Sem "+" o número é brasileiro: DDD válido e nove dígitos
+// começando por 9, com ou sem o 0 da discagem interurbana e o 55. Com "+" e outro código de país,
+// para clientes estrangeiros, vale só o limite de 15 dígitos do E.164.
+func NormalizePhone(raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	international := strings.HasPrefix(value, "+")
+
+	digits := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+			return -1
+		default:
+			return 'x'
+		}
+	}, strings.TrimPrefix(value, "+"))
+
+	if strings.ContainsRune(digits, 'x') {
+		return "", ErrInvalidPhone
+	}
+
+	if international && !strings.HasPrefix(digits, phoneCountryBrazil) {
+		if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+			return "", ErrInvalidPhone
+		}
+		return "+" + digits, nil
+	}
+
+	national := digits
+	switch {
+	case international:
+		national = strings.TrimPrefix(digits, phoneCountryBrazil)
+	case len(digits) == 13 && strings.HasPrefix(digits, phoneCountryBrazil):
+		national = digits[2:]
+	case len(digits) == 12 && strings.HasPrefix(digits, "0"):
+		national = digits[1:]
+	}
+
+	if len(national) != 11 || !brazilianAreaCodes[national[:2]] || national[2] != '9' {
+		return "", ErrInvalidPhone
+	}
+
+	return "+" + phoneCountryBrazil + national, nil
+}
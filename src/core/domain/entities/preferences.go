@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Vocabulário controlado dos alérgenos: os de declaração obrigatória da ANVISA (RDC 26/2015)
+// mais gergelim. O cardápio usa os mesmos códigos.
+var Allergens = []string{
+	"peanuts", "tree_nuts", "milk", "lactose", "eggs", "fish",
+	"crustaceans", "soy", "wheat", "gluten", "sesame",
+}
+
+var DietaryStyles = []string{"vegetarian", "vegan", "pescatarian", "gluten_free", "lactose_free"}
+
+const (
+	MaxDislikedIngredients     = 30
+	MaxDislikedIngredientChars = 50
+)
+
+// DietaryPreferences são as restrições e preferências alimentares do cliente. São dados de
+// saúde (LGPD, art. 5º, II). Hash muda sempre que as preferências mudam e é vazio enquanto o
+// cliente não tem preferências gravadas.
+type DietaryPreferences struct {
+	CustomerID          uint       `json:"customerId"`
+	Allergens           []string   `json:"allergens"`
+	DietaryStyles       []string   `json:"dietaryStyles"`
+	DislikedIngredients []string   `json:"dislikedIngredients"`
+	Hash                string     `json:"hash,omitempty"`
+	UpdatedAt           *time.Time `json:"updatedAt,omitempty"`
+}
+
+// NewDietaryPreferences valida os códigos contra o vocabulário e normaliza as listas:
+// sem repetições, em ordem alfabética, com os ingredientes em minúsculas
+func NewDietaryPreferences(customerID uint, allergens, dietaryStyles, dislikedIngredients []string) (DietaryPreferences, error) {
+	preferences := DietaryPreferences{
+		CustomerID:          customerID,
+		Allergens:           []string{},
+		DietaryStyles:       []string{},
+		DislikedIngredients: []string{},
+	}
+
+	for _, allergen := range allergens {
+		if !slices.Contains(Allergens, allergen) {
+			return DietaryPreferences{}, fmt.Errorf("%w: alérgeno desconhecido %q", ErrInvalidPreferences, allergen)
+		}
+		preferences.Allergens = append(preferences.Allergens, allergen)
+	}
+
+	for _, style := range dietaryStyles {
+		if !slices.Contains(DietaryStyles, style) {
+			return DietaryPreferences{}, fmt.Errorf("%w: estilo alimentar desconhecido %q", ErrInvalidPreferences, style)
+		}
+		preferences.DietaryStyles = append(preferences.DietaryStyles, style)
+	}
+
+	for _, ingredient := range dislikedIngredients {
+		ingredient = strings.ToLower(strings.Join(strings.Fields(ingredient), " "))
+		if ingredient == "" || len([]rune(ingredient)) > MaxDislikedIngredientChars {
+			return DietaryPreferences{}, fmt.Errorf("%w: ingrediente deve ter de 1 a %d caracteres", ErrInvalidPreferences, MaxDislikedIngredientChars)
+		}
+		preferences.DislikedIngredients = append(preferences.DislikedIngredients, ingredient)
+	}
+
+	preferences.Allergens = sortedUnique(preferences.Allergens)
+	preferences.DietaryStyles = sortedUnique(preferences.DietaryStyles)
+	preferences.DislikedIngredients = sortedUnique(preferences.DislikedIngredients)
+
+	if len(preferences.DislikedIngredients) > MaxDislikedIngredients {
+		return DietaryPreferences{}, fmt.Errorf("%w: no máximo %d ingredientes", ErrInvalidPreferences, MaxDislikedIngredients)
+	}
+
+	return preferences, nil
+}
+
+// Canonical é a forma das preferências usada no hash: listas normalizadas e separadores fixos
+func (p DietaryPreferences) Canonical() string {
+	return "a=" + strings.Join(p.Allergens, ",") +
+		";d=" + strings.Join(p.DietaryStyles, ",") +
+		";i=" + strings.Join(p.DislikedIngredients, ",")
+}
+
+func sortedUnique(values []string) []string {
+	slices.Sort(values)
+	return slices.Compact(values)
+}
@@ -0,0 +1,46 @@
+package entities
+
+import "time"
+
+// Tipos de sessão emitida, usados na métrica de tokens
+const (
+	SessionTypeAnonymous        = "anonymous"
+	SessionTypeIdentified       = "identified"
+	SessionTypeUnknownCPF       = "unknown_cpf"
+	SessionTypeInactiveCustomer = "inactive_customer"
+	SessionTypeVerified         = "verified"
+)
+
+// Canais do código que confirma a sessão de um cliente identificado
+const (
+	SessionChannelSMS   = "sms"
+	SessionChannelEmail = "email"
+)
+
+// Session é o token emitido. PreferencesHash, também presente no token, só vem quando a
+// emissão inclui o hash das preferências e o cliente tem preferências gravadas. Reason explica a
+// sessão anônima de um cliente encontrado mas que não está ativo (customer_suspended, por exemplo).
+type Session struct {
+	Token           string    `json:"token"`
+	Identified      bool      `json:"identified"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+	PreferencesHash string    `json:"preferencesHash,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+}
+
+type VerifiedToken struct {
+	CustomerID      string    `json:"customerId"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+	PreferencesHash string    `json:"preferencesHash,omitempty"`
+}
+
+// SessionVerification é o código pendente que confirma a sessão do cliente. Destination é o telefone
+// verificado ou o email para onde o código foi enviado; o código em si só existe na mensagem.
+type SessionVerification struct {
+	CustomerID  uint
+	Channel     string
+	Destination string
+	Attempts    int
+	SentAt      time.Time
+	ExpiresAt   time.Time
+}
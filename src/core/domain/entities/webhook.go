@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Situações de uma entrega de webhook. Uma entrega com falha continua pending até esgotar as tentativas
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryDead      = "dead"
+)
+
+type WebhookSubscription struct {
+	ID         uint      `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	Secret     string    `json:"secret,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type WebhookDelivery struct {
+	ID             uint                     `json:"id"`
+	SubscriptionID uint                     `json:"subscriptionId"`
+	EventID        string                   `json:"eventId"`
+	EventType      string                   `json:"eventType"`
+	Payload        json.RawMessage          `json:"payload"`
+	Status         string                   `json:"status"`
+	Attempts       int                      `json:"attempts"`
+	NextAttemptAt  time.Time                `json:"nextAttemptAt"`
+	LastStatusCode int                      `json:"lastStatusCode"`
+	LastError      string                   `json:"lastError"`
+	DeliveredAt    *time.Time               `json:"deliveredAt"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	AttemptLog     []WebhookDeliveryAttempt `json:"attemptLog"`
+}
+
+type WebhookDeliveryAttempt struct {
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"statusCode"`
+	Error      string    `json:"error"`
+	DurationMs int64     `json:"durationMs"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
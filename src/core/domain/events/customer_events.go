@@ -0,0 +1,91 @@
+package events
+
+import (
+	"strconv"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+const (
+	CustomerAggregate = "customer"
+
+	CustomerCreated = "CustomerCreated"
+	CustomerUpdated = "CustomerUpdated"
+	CustomerErased  = "CustomerErased"
+	CustomersMerged = "CustomersMerged"
+
+	customerSchemaVersion = 1
+)
+
+// CustomerEventTypes lista os eventos que podem ser assinados por webhooks
+var CustomerEventTypes = []string{CustomerCreated, CustomerUpdated, CustomerErased, CustomersMerged}
+
+// CustomerSnapshot é o estado do cliente enviado nos eventos. O CPF não é publicado.
+type CustomerSnapshot struct {
+	ID        uint                    `json:"id"`
+	Name      string                  `json:"name"`
+	Email     string                  `json:"email"`
+	Status    entities.CustomerStatus `json:"status"`
+	CreatedAt string                  `json:"createdAt"`
+}
+
+type CustomerCreatedData struct {
+	Customer CustomerSnapshot `json:"customer"`
+}
+
+type CustomerUpdatedData struct {
+	Customer      CustomerSnapshot `json:"customer"`
+	ChangedFields []string         `json:"changedFields"`
+}
+
+// CustomerErasedData não carrega dados pessoais: os consumidores devem apagar o que tiverem do id
+type CustomerErasedData struct {
+	CustomerID uint `json:"customerId"`
+}
+
+// CustomersMergedData traz o sobrevivente da fusão. Os consumidores devem passar para ele o que
+// tiverem do cliente incorporado, que a partir daqui só existe como redirecionamento.
+type CustomersMergedData struct {
+	Customer         CustomerSnapshot `json:"customer"`
+	MergedCustomerID uint             `json:"mergedCustomerId"`
+}
+
+func NewCustomerCreated(customer entities.Customer) (Envelope, error) {
+	return newEnvelope(CustomerCreated, customerSchemaVersion, CustomerAggregate, customerAggregateID(customer.ID), CustomerCreatedData{
+		Customer: snapshot(customer),
+	})
+}
+
+func NewCustomerUpdated(customer entities.Customer, changedFields []string) (Envelope, error) {
+	return newEnvelope(CustomerUpdated, customerSchemaVersion, CustomerAggregate, customerAggregateID(customer.ID), CustomerUpdatedData{
+		Customer:      snapshot(customer),
+		ChangedFields: changedFields,
+	})
+}
+
+func NewCustomerErased(customerID uint) (Envelope, error) {
+	return newEnvelope(CustomerErased, customerSchemaVersion, CustomerAggregate, customerAggregateID(customerID), CustomerErasedData{
+		CustomerID: customerID,
+	})
+}
+
+func NewCustomersMerged(survivor entities.Customer, mergedCustomerID uint) (Envelope, error) {
+	return newEnvelope(CustomersMerged, customerSchemaVersion, CustomerAggregate, customerAggregateID(survivor.ID), CustomersMergedData{
+		Customer:         snapshot(survivor),
+		MergedCustomerID: mergedCustomerID,
+	})
+}
+
+func customerAggregateID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func snapshot(customer entities.Customer) CustomerSnapshot {
+	return CustomerSnapshot{
+		ID:        customer.ID,
+		Name:      customer.Name,
+		Email:     customer.Email,
+		Status:    customer.Status,
+		CreatedAt: customer.CreatedAt,
+	}
+}
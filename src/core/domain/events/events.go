@@ -0,0 +1,37 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Envelope é o formato publicado para os outros serviços.
+// SchemaVersion muda quando o Data de um tipo de evento muda de forma incompatível.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   string          `json:"aggregateId"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func newEnvelope(eventType string, schemaVersion int, aggregateType string, aggregateID string, data interface{}) (Envelope, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	return Envelope{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		SchemaVersion: schemaVersion,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		OccurredAt:    time.Now().UTC(),
+		Data:          payload,
+	}, nil
+}
@@ -0,0 +1,13 @@
+package events
+
+// Eventos publicados pelo serviço de pedidos e consumidos aqui
+const (
+	OrderPaid = "OrderPaid"
+)
+
+// OrderPaidData é o pagamento confirmado de um pedido. CustomerID é nulo nos pedidos anônimos.
+type OrderPaidData struct {
+	OrderID     string `json:"orderId"`
+	CustomerID  *uint  `json:"customerId"`
+	AmountCents int64  `json:"amountCents"`
+}
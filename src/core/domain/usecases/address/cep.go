@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// DefaultMaxAddresses é o limite de endereços por cliente quando MaxAddresses não é informado
+const DefaultMaxAddresses = 10
+
+// lookupCEP consulta o CEP fora da transação do caderno. Com o provedor fora do ar devolve nil
+// e unavailable: o endereço ainda pode ser aceito se vier completo.
+func lookupCEP(ctx context.Context, provider gateways.CEPProvider, cep string) (_ *entities.CEPAddress, unavailable bool, err error) {
+	found, err := provider.Lookup(ctx, cep)
+	if errors.Is(err, entities.ErrCEPProviderUnavailable) {
+		return nil, true, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return found, false, nil
+}
+
+// completeAddress aplica o resultado do CEP e valida o endereço. Cidade e UF são as do CEP;
+// logradouro e bairro do CEP só preenchem os campos vazios, já que o cliente pode detalhá-los.
+func completeAddress(address *entities.Address, found *entities.CEPAddress, unavailable bool) error {
+	address.State = strings.ToUpper(address.State)
+
+	if found != nil {
+		address.City = found.City
+		address.State = found.State
+		if address.Street == "" {
+			address.Street = found.Street
+		}
+		if address.Neighborhood == "" {
+			address.Neighborhood = found.Neighborhood
+		}
+	}
+
+	if !address.Complete() {
+		if unavailable {
+			return entities.ErrCEPProviderUnavailable
+		}
+		return entities.ErrIncompleteAddress
+	}
+
+	if !entities.ValidState(address.State) {
+		return entities.ErrInvalidState
+	}
+
+	return nil
+}
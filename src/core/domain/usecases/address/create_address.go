@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type CreateAddressUsecase struct {
+	AddressRepository gateways.AddressRepository
+	CEPProvider       gateways.CEPProvider
+	MaxAddresses      int
+	Tracer            gateways.Tracer
+}
+
+func (r *CreateAddressUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.CreateAddressDto) (_ *entities.Address, err error) {
+	ctx, span := r.Tracer.Start(ctx, "CreateAddressUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	cep, err := entities.NormalizeCEP(inputDto.CEP)
+	if err != nil {
+		return nil, err
+	}
+
+	found, unavailable, err := lookupCEP(ctx, r.CEPProvider, cep)
+	if err != nil {
+		return nil, err
+	}
+
+	address := entities.Address{
+		Label:        inputDto.Label,
+		CEP:          cep,
+		Street:       inputDto.Street,
+		Number:       inputDto.Number,
+		Complement:   inputDto.Complement,
+		Neighborhood: inputDto.Neighborhood,
+		City:         inputDto.City,
+		State:        inputDto.State,
+		Default:      inputDto.IsDefault,
+	}
+
+	if err := completeAddress(&address, found, unavailable); err != nil {
+		return nil, err
+	}
+
+	maxAddresses := r.MaxAddresses
+	if maxAddresses <= 0 {
+		maxAddresses = DefaultMaxAddresses
+	}
+
+	var index int
+	book, err := r.AddressRepository.Update(ctx, customerID, func(book *entities.AddressBook) error {
+		index, err = book.Add(address, maxAddresses)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &book.Addresses[index], nil
+}
@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/cep"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAddressRepository aplica as alterações numa cópia do caderno, como a transação do repositório
+type memoryAddressRepository struct {
+	book   entities.AddressBook
+	nextID uint
+}
+
+func (m *memoryAddressRepository) FindByCustomer(ctx context.Context, customerID uint) (*entities.AddressBook, error) {
+	book := m.copy()
+	return &book, nil
+}
+
+func (m *memoryAddressRepository) Update(ctx context.Context, customerID uint, change func(book *entities.AddressBook) error) (*entities.AddressBook, error) {
+	book := m.copy()
+	if err := change(&book); err != nil {
+		return nil, err
+	}
+
+	for i := range book.Addresses {
+		if book.Addresses[i].ID == 0 {
+			m.nextID++
+			book.Addresses[i].ID = m.nextID
+		}
+	}
+
+	m.book = book
+	result := m.copy()
+	return &result, nil
+}
+
+func (m *memoryAddressRepository) copy() entities.AddressBook {
+	return entities.AddressBook{CustomerID: m.book.CustomerID, Addresses: append([]entities.Address{}, m.book.Addresses...)}
+}
+
+type unavailableCEPProvider struct{}
+
+func (unavailableCEPProvider) Lookup(ctx context.Context, cep string) (*entities.CEPAddress, error) {
+	return nil, entities.ErrCEPProviderUnavailable
+}
+
+func fixtureProvider(t *testing.T) *cep.FixtureProvider {
+	provider, err := cep.DefaultFixtureProvider()
+	require.NoError(t, err)
+	return provider
+}
+
+func TestCreateAddressUsecase_FillsFromCEP(t *testing.T) {
+	repo := &memoryAddressRepository{}
+	usecase := CreateAddressUsecase{AddressRepository: repo, CEPProvider: fixtureProvider(t), Tracer: tracing.UsecaseTracer{}}
+
+	address, err := usecase.Execute(context.Background(), 7, dtos.CreateAddressDto{
+		Label:  "Casa",
+		CEP:    "01310-100",
+		Number: "1000",
+		City:   "Santos",
+		State:  "RJ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "01310100", address.CEP)
+	assert.Equal(t, "Avenida Paulista", address.Street)
+	assert.Equal(t, "Bela Vista", address.Neighborhood)
+	assert.Equal(t, "São Paulo", address.City, "cidade e UF vêm do CEP")
+	assert.Equal(t, "SP", address.State)
+	assert.True(t, address.Default, "o primeiro endereço é o padrão")
+}
+
+func TestCreateAddressUsecase_DefaultMovesToNewAddress(t *testing.T) {
+	repo := &memoryAddressRepository{}
+	usecase := CreateAddressUsecase{AddressRepository: repo, CEPProvider: fixtureProvider(t), Tracer: tracing.UsecaseTracer{}}
+
+	_, err := usecase.Execute(context.Background(), 7, dtos.CreateAddressDto{Label: "Casa", CEP: "01310100", Number: "1"})
+	require.NoError(t, err)
+	_, err = usecase.Execute(context.Background(), 7, dtos.CreateAddressDto{Label: "Trabalho", CEP: "01001000", Number: "2"})
+	require.NoError(t, err)
+	third, err := usecase.Execute(context.Background(), 7, dtos.CreateAddressDto{Label: "Pais", CEP: "20040002", Number: "3", IsDefault: true})
+	require.NoError(t, err)
+
+	assert.True(t, third.Default)
+	assert.False(t, repo.book.Addresses[0].Default)
+	assert.False(t, repo.book.Addresses[1].Default)
+}
+
+func TestCreateAddressUsecase_Errors(t *testing.T) {
+	repo := &memoryAddressRepository{book: entities.AddressBook{Addresses: []entities.Address{{ID: 1}, {ID: 2}}}}
+
+	tests := []struct {
+		name         string
+		provider     gateways.CEPProvider
+		maxAddresses int
+		input        dtos.CreateAddressDto
+		want         error
+	}{
+		{"CEP inexistente", fixtureProvider(t), 10, dtos.CreateAddressDto{Label: "Casa", CEP: "99999999", Number: "1"}, entities.ErrCEPNotFound},
+		{"CEP geral sem logradouro", fixtureProvider(t), 10, dtos.CreateAddressDto{Label: "Casa", CEP: "13165000", Number: "1"}, entities.ErrIncompleteAddress},
+		{"provedor fora do ar e endereço incompleto", unavailableCEPProvider{}, 10, dtos.CreateAddressDto{Label: "Casa", CEP: "01310100", Number: "1"}, entities.ErrCEPProviderUnavailable},
+		{"provedor fora do ar e UF inexistente", unavailableCEPProvider{}, 10, dtos.CreateAddressDto{Label: "Casa", CEP: "01310100", Street: "Rua A", Number: "1", City: "X", State: "XX"}, entities.ErrInvalidState},
+		{"limite atingido", fixtureProvider(t), 2, dtos.CreateAddressDto{Label: "Casa", CEP: "01310100", Number: "1"}, entities.ErrAddressLimitReached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := CreateAddressUsecase{AddressRepository: repo, CEPProvider: tt.provider, MaxAddresses: tt.maxAddresses, Tracer: tracing.UsecaseTracer{}}
+
+			_, err := usecase.Execute(context.Background(), 7, tt.input)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestCreateAddressUsecase_ProviderUnavailableAcceptsCompleteAddress(t *testing.T) {
+	usecase := CreateAddressUsecase{AddressRepository: &memoryAddressRepository{}, CEPProvider: unavailableCEPProvider{}, Tracer: tracing.UsecaseTracer{}}
+
+	address, err := usecase.Execute(context.Background(), 7, dtos.CreateAddressDto{
+		Label: "Casa", CEP: "01310100", Street: "Avenida Paulista", Number: "1000", City: "São Paulo", State: "sp",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SP", address.State)
+}
@@ -0,0 +1,25 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// DeleteAddressUsecase exclui o endereço; se era o padrão, o mais antigo dos restantes assume
+type DeleteAddressUsecase struct {
+	AddressRepository gateways.AddressRepository
+	Tracer            gateways.Tracer
+}
+
+func (r *DeleteAddressUsecase) Execute(ctx context.Context, customerID uint, addressID uint) (err error) {
+	ctx, span := r.Tracer.Start(ctx, "DeleteAddressUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	_, err = r.AddressRepository.Update(ctx, customerID, func(book *entities.AddressBook) error {
+		return book.Remove(addressID)
+	})
+
+	return err
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type GetAddressUsecase struct {
+	AddressRepository gateways.AddressRepository
+	Tracer            gateways.Tracer
+}
+
+func (r *GetAddressUsecase) Execute(ctx context.Context, customerID uint, addressID uint) (_ *entities.Address, err error) {
+	ctx, span := r.Tracer.Start(ctx, "GetAddressUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	book, err := r.AddressRepository.FindByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := book.Find(addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &book.Addresses[index], nil
+}
@@ -0,0 +1,25 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type ListAddressesUsecase struct {
+	AddressRepository gateways.AddressRepository
+	Tracer            gateways.Tracer
+}
+
+func (r *ListAddressesUsecase) Execute(ctx context.Context, customerID uint) (_ []entities.Address, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ListAddressesUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	book, err := r.AddressRepository.FindByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return book.Addresses, nil
+}
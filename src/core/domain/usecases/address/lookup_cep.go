@@ -0,0 +1,26 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// LookupCEPUsecase consulta o logradouro de um CEP para o cliente completar o endereço
+type LookupCEPUsecase struct {
+	CEPProvider gateways.CEPProvider
+	Tracer      gateways.Tracer
+}
+
+func (r *LookupCEPUsecase) Execute(ctx context.Context, cep string) (_ *entities.CEPAddress, err error) {
+	ctx, span := r.Tracer.Start(ctx, "LookupCEPUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	cep, err = entities.NormalizeCEP(cep)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.CEPProvider.Lookup(ctx, cep)
+}
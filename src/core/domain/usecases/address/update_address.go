@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type UpdateAddressUsecase struct {
+	AddressRepository gateways.AddressRepository
+	CEPProvider       gateways.CEPProvider
+	Tracer            gateways.Tracer
+}
+
+func (r *UpdateAddressUsecase) Execute(ctx context.Context, customerID uint, addressID uint, inputDto dtos.UpdateAddressDto) (_ *entities.Address, err error) {
+	ctx, span := r.Tracer.Start(ctx, "UpdateAddressUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	if inputDto.CEP == "" && (inputDto.City != "" || inputDto.State != "") {
+		return nil, entities.ErrCEPRequired
+	}
+
+	var cep string
+	var found *entities.CEPAddress
+	var unavailable bool
+
+	// a consulta do CEP fica fora da transação para não segurar o cliente travado
+	if inputDto.CEP != "" {
+		if cep, err = entities.NormalizeCEP(inputDto.CEP); err != nil {
+			return nil, err
+		}
+
+		if found, unavailable, err = lookupCEP(ctx, r.CEPProvider, cep); err != nil {
+			return nil, err
+		}
+	}
+
+	var index int
+	book, err := r.AddressRepository.Update(ctx, customerID, func(book *entities.AddressBook) error {
+		index, err = book.Find(addressID)
+		if err != nil {
+			return err
+		}
+
+		address := book.Addresses[index]
+
+		// CEP novo: logradouro, bairro, cidade e UF antigos não valem mais
+		if cep != "" && cep != address.CEP {
+			address.CEP = cep
+			address.Street = ""
+			address.Neighborhood = ""
+			address.City = ""
+			address.State = ""
+		}
+
+		applyAddressChanges(&address, inputDto)
+
+		if err := completeAddress(&address, found, unavailable); err != nil {
+			return err
+		}
+
+		book.Addresses[index] = address
+		if inputDto.IsDefault {
+			book.SetDefault(index)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &book.Addresses[index], nil
+}
+
+func applyAddressChanges(address *entities.Address, inputDto dtos.UpdateAddressDto) {
+	if inputDto.Label != "" {
+		address.Label = inputDto.Label
+	}
+	if inputDto.Street != "" {
+		address.Street = inputDto.Street
+	}
+	if inputDto.Number != "" {
+		address.Number = inputDto.Number
+	}
+	if inputDto.Complement != "" {
+		address.Complement = inputDto.Complement
+	}
+	if inputDto.Neighborhood != "" {
+		address.Neighborhood = inputDto.Neighborhood
+	}
+	if inputDto.City != "" {
+		address.City = inputDto.City
+	}
+	if inputDto.State != "" {
+		address.State = inputDto.State
+	}
+}
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func paulista() entities.Address {
+	return entities.Address{
+		ID: 1, Label: "Casa", CEP: "01310100", Street: "Avenida Paulista", Number: "1000",
+		Neighborhood: "Bela Vista", City: "São Paulo", State: "SP", Default: true,
+	}
+}
+
+func TestUpdateAddressUsecase_NewCEPReplacesStreet(t *testing.T) {
+	repo := &memoryAddressRepository{book: entities.AddressBook{Addresses: []entities.Address{paulista()}}, nextID: 1}
+	usecase := UpdateAddressUsecase{AddressRepository: repo, CEPProvider: fixtureProvider(t), Tracer: tracing.UsecaseTracer{}}
+
+	address, err := usecase.Execute(context.Background(), 7, 1, dtos.UpdateAddressDto{CEP: "20040-002", Number: "50"})
+	require.NoError(t, err)
+	assert.Equal(t, "Rua da Assembleia", address.Street)
+	assert.Equal(t, "Centro", address.Neighborhood)
+	assert.Equal(t, "Rio de Janeiro", address.City)
+	assert.Equal(t, "RJ", address.State)
+	assert.Equal(t, "50", address.Number)
+	assert.Equal(t, "Casa", address.Label)
+}
+
+func TestUpdateAddressUsecase_KeepsCEPFieldsWithoutNewCEP(t *testing.T) {
+	repo := &memoryAddressRepository{book: entities.AddressBook{Addresses: []entities.Address{paulista()}}, nextID: 1}
+	usecase := UpdateAddressUsecase{AddressRepository: repo, CEPProvider: unavailableCEPProvider{}, Tracer: tracing.UsecaseTracer{}}
+
+	address, err := usecase.Execute(context.Background(), 7, 1, dtos.UpdateAddressDto{Complement: "apto 12"})
+	require.NoError(t, err)
+	assert.Equal(t, "apto 12", address.Complement)
+	assert.Equal(t, "São Paulo", address.City)
+
+	_, err = usecase.Execute(context.Background(), 7, 1, dtos.UpdateAddressDto{City: "Campinas"})
+	assert.ErrorIs(t, err, entities.ErrCEPRequired)
+
+	_, err = usecase.Execute(context.Background(), 7, 2, dtos.UpdateAddressDto{Label: "Trabalho"})
+	assert.ErrorIs(t, err, entities.ErrAddressNotFound)
+}
+
+func TestUpdateAddressUsecase_SetDefault(t *testing.T) {
+	second := paulista()
+	second.ID, second.Label, second.Default = 2, "Trabalho", false
+	repo := &memoryAddressRepository{book: entities.AddressBook{Addresses: []entities.Address{paulista(), second}}, nextID: 2}
+	usecase := UpdateAddressUsecase{AddressRepository: repo, CEPProvider: fixtureProvider(t), Tracer: tracing.UsecaseTracer{}}
+
+	address, err := usecase.Execute(context.Background(), 7, 2, dtos.UpdateAddressDto{IsDefault: true})
+	require.NoError(t, err)
+	assert.True(t, address.Default)
+	assert.False(t, repo.book.Addresses[0].Default)
+}
@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	customerusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+)
+
+const DefaultAuditLimit = 100
+
+type ListAuditEntriesUsecase struct {
+	AuditLog gateways.AuditLog
+	Tracer   gateways.Tracer
+}
+
+// Execute devolve uma página da auditoria em ordem de gravação; o cursor segue o formato do feed de alterações
+func (r *ListAuditEntriesUsecase) Execute(ctx context.Context, inputDto dtos.ListAuditEntriesDto) (_ *dtos.AuditEntriesPageDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ListAuditEntriesUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	after, err := customerusecases.DecodeChangeCursor(inputDto.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := inputDto.Limit
+	if limit == 0 {
+		limit = DefaultAuditLimit
+	}
+
+	filter := entities.AuditFilter{
+		Actor:      inputDto.Actor,
+		Action:     inputDto.Action,
+		TargetType: inputDto.TargetType,
+		TargetID:   inputDto.TargetID,
+		From:       inputDto.From,
+		To:         inputDto.To,
+	}
+
+	entries, err := r.AuditLog.List(ctx, filter, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	last := after
+	if len(entries) > 0 {
+		last = entries[len(entries)-1].Sequence
+	}
+
+	return &dtos.AuditEntriesPageDto{
+		Entries:    entries,
+		NextCursor: customerusecases.EncodeChangeCursor(last),
+		HasMore:    len(entries) == limit,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DefaultBatchGetMaxIDs é o limite de ids por chamada quando MaxIDs não é informado
+const DefaultBatchGetMaxIDs = 100
+
+type BatchGetCustomersUsecase struct {
+	CustomerRepository gateways.CustomerRepository
+	AuditLog           gateways.AuditLog
+	Redirects          gateways.CustomerRedirects
+	MaxIDs             int
+	Tracer             gateways.Tracer
+}
+
+func (r *BatchGetCustomersUsecase) Execute(ctx context.Context, inputDto dtos.BatchGetCustomersDto) (_ *dtos.BatchGetCustomersResultDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "BatchGetCustomersUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	ids := uniqueIDs(inputDto.IDs)
+	span.SetAttributes(attribute.Int("customer.ids", len(ids)))
+
+	maxIDs := r.MaxIDs
+	if maxIDs <= 0 {
+		maxIDs = DefaultBatchGetMaxIDs
+	}
+
+	if len(ids) > maxIDs {
+		return nil, entities.ErrTooManyIDs
+	}
+
+	result := &dtos.BatchGetCustomersResultDto{
+		Customers:  []entities.Customer{},
+		MissingIDs: []uint{},
+	}
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	customers, err := r.CustomerRepository.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]bool, len(customers))
+	for _, customer := range customers {
+		found[customer.ID] = true
+	}
+
+	customers, redirects, err := r.followRedirects(ctx, ids, found, customers)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]entities.AuditEntry, 0, len(customers))
+	for _, customer := range customers {
+		entries = append(entries, entities.CustomerAuditEntry(entities.AuditActionCustomerRead, customer.ID))
+	}
+
+	if len(entries) > 0 {
+		if err := r.AuditLog.Record(ctx, entries...); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Customers = customers
+	if len(redirects) > 0 {
+		result.Redirects = redirects
+	}
+	for _, id := range ids {
+		if !found[id] && redirects[id] == 0 {
+			result.MissingIDs = append(result.MissingIDs, id)
+		}
+	}
+
+	return result, nil
+}
+
+// followRedirects procura entre os ids não encontrados os que foram incorporados numa fusão e
+// acrescenta os sobreviventes que ainda não estavam no resultado
+func (r *BatchGetCustomersUsecase) followRedirects(ctx context.Context, ids []uint, found map[uint]bool, customers []entities.Customer) ([]entities.Customer, map[uint]uint, error) {
+	missing := make([]uint, 0)
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) == 0 {
+		return customers, nil, nil
+	}
+
+	redirects, err := r.Redirects.ResolveMany(ctx, missing)
+	if err != nil || len(redirects) == 0 {
+		return customers, nil, err
+	}
+
+	survivorIDs := make([]uint, 0, len(redirects))
+	for _, id := range missing {
+		if survivorID, ok := redirects[id]; ok && !found[survivorID] {
+			found[survivorID] = true
+			survivorIDs = append(survivorIDs, survivorID)
+		}
+	}
+
+	if len(survivorIDs) > 0 {
+		survivors, err := r.CustomerRepository.FindByIDs(ctx, survivorIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		customers = append(customers, survivors...)
+	}
+
+	return customers, redirects, nil
+}
+
+// uniqueIDs remove ids repetidos mantendo a ordem do pedido
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBatchGetCustomerRepository struct {
+	gateways.CustomerRepository
+	mockFindByIDs func(context.Context, []uint) ([]entities.Customer, error)
+}
+
+func (m *mockBatchGetCustomerRepository) FindByIDs(ctx context.Context, ids []uint) ([]entities.Customer, error) {
+	return m.mockFindByIDs(ctx, ids)
+}
+
+// mockRedirects liga ids incorporados numa fusão aos sobreviventes
+type mockRedirects map[uint]uint
+
+func (r mockRedirects) Resolve(ctx context.Context, id uint) (uint, error) {
+	if to, ok := r[id]; ok {
+		return to, nil
+	}
+	return id, nil
+}
+
+func (r mockRedirects) ResolveMany(ctx context.Context, ids []uint) (map[uint]uint, error) {
+	result := map[uint]uint{}
+	for _, id := range ids {
+		if to, ok := r[id]; ok {
+			result[id] = to
+		}
+	}
+	return result, nil
+}
+
+func TestBatchGetCustomersUsecase_Execute(t *testing.T) {
+	mockCustomerRepo := &mockBatchGetCustomerRepository{}
+
+	usecase := BatchGetCustomersUsecase{
+		CustomerRepository: mockCustomerRepo,
+		AuditLog:           &mockAuditLog{},
+		Redirects:          mockRedirects{},
+		MaxIDs:             3,
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	t.Run("retorna encontrados e ausentes", func(t *testing.T) {
+		var requested []uint
+		mockCustomerRepo.mockFindByIDs = func(ctx context.Context, ids []uint) ([]entities.Customer, error) {
+			requested = ids
+			return []entities.Customer{{ID: 1}, {ID: 3}}, nil
+		}
+
+		result, err := usecase.Execute(context.Background(), dtos.BatchGetCustomersDto{IDs: []uint{1, 2, 1, 3}})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{1, 2, 3}, requested)
+		assert.Len(t, result.Customers, 2)
+		assert.Equal(t, []uint{2}, result.MissingIDs)
+	})
+
+	t.Run("acima do limite", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.BatchGetCustomersDto{IDs: []uint{1, 2, 3, 4}})
+		assert.ErrorIs(t, err, entities.ErrTooManyIDs)
+	})
+
+	t.Run("erro ao buscar clientes", func(t *testing.T) {
+		mockCustomerRepo.mockFindByIDs = func(ctx context.Context, ids []uint) ([]entities.Customer, error) {
+			return nil, fmt.Errorf("Erro ao buscar clientes")
+		}
+
+		_, err := usecase.Execute(context.Background(), dtos.BatchGetCustomersDto{IDs: []uint{1}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ChangeCustomerStatusUsecase aplica as transições pedidas pelo admin, dentro da máquina de estados
+// de entities.CustomerStatus. Pedir o status em que o cliente já está também é uma transição inválida.
+type ChangeCustomerStatusUsecase struct {
+	CustomerRepository gateways.CustomerRepository
+	Tracer             gateways.Tracer
+}
+
+func (r *ChangeCustomerStatusUsecase) Execute(ctx context.Context, customerID uint, target entities.CustomerStatus, inputDto dtos.ChangeCustomerStatusDto) (_ *entities.CustomerStatusChange, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ChangeCustomerStatusUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)), attribute.String("customer.status", string(target)))
+
+	customer, err := r.CustomerRepository.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !customer.Status.CanTransitionTo(target) {
+		return nil, entities.ErrInvalidStatusChange
+	}
+
+	return r.CustomerRepository.ChangeStatus(ctx, customerID, customer.Status, target, inputDto.Reason)
+}
@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStatusCustomerRepository struct {
+	gateways.CustomerRepository
+	status  entities.CustomerStatus
+	changes []entities.CustomerStatusChange
+}
+
+func (m *mockStatusCustomerRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	if id != 1 {
+		return nil, entities.ErrCustomerNotFound
+	}
+	return &entities.Customer{ID: 1, Status: m.status}, nil
+}
+
+func (m *mockStatusCustomerRepository) ChangeStatus(ctx context.Context, id uint, from, to entities.CustomerStatus, reason string) (*entities.CustomerStatusChange, error) {
+	change := entities.CustomerStatusChange{CustomerID: id, From: from, To: to, Reason: reason}
+	m.changes = append(m.changes, change)
+	m.status = to
+	return &change, nil
+}
+
+func TestChangeCustomerStatusUsecase_Execute(t *testing.T) {
+	repo := &mockStatusCustomerRepository{status: entities.CustomerPendingVerification}
+	usecase := ChangeCustomerStatusUsecase{CustomerRepository: repo, Tracer: tracing.UsecaseTracer{}}
+	reason := dtos.ChangeCustomerStatusDto{Reason: "motivo"}
+
+	t.Run("pendente não pode ser suspenso", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), 1, entities.CustomerSuspended, reason)
+		assert.ErrorIs(t, err, entities.ErrInvalidStatusChange)
+	})
+
+	t.Run("ciclo de vida completo", func(t *testing.T) {
+		for _, target := range []entities.CustomerStatus{
+			entities.CustomerActive,
+			entities.CustomerSuspended,
+			entities.CustomerActive,
+			entities.CustomerSuspended,
+			entities.CustomerClosed,
+		} {
+			change, err := usecase.Execute(context.Background(), 1, target, reason)
+			assert.NoError(t, err)
+			assert.Equal(t, target, change.To)
+			assert.Equal(t, "motivo", change.Reason)
+		}
+		assert.Len(t, repo.changes, 5)
+		assert.Equal(t, entities.CustomerSuspended, repo.changes[4].From)
+	})
+
+	t.Run("encerrado é definitivo", func(t *testing.T) {
+		for _, target := range []entities.CustomerStatus{entities.CustomerActive, entities.CustomerSuspended, entities.CustomerClosed} {
+			_, err := usecase.Execute(context.Background(), 1, target, reason)
+			assert.ErrorIs(t, err, entities.ErrInvalidStatusChange)
+		}
+	})
+
+	t.Run("ativo não pode ser encerrado sem suspensão", func(t *testing.T) {
+		repo.status = entities.CustomerActive
+		_, err := usecase.Execute(context.Background(), 1, entities.CustomerClosed, reason)
+		assert.ErrorIs(t, err, entities.ErrInvalidStatusChange)
+	})
+
+	t.Run("cliente inexistente", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), 2, entities.CustomerActive, reason)
+		assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+	})
+}
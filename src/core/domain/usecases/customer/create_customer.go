@@ -1,21 +1,71 @@
 package usecases
 
 import (
+	"context"
+
 	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// CreateCustomerUsecase cadastra o cliente como active, ou como pending_verification quando
+// RequireVerification está ligado; nesse caso a ativação é feita por um administrador.
 type CreateCustomerUsecase struct {
-	CustomerRepository gateways.CustomerRepository
+	CustomerRepository  gateways.CustomerRepository
+	RequireVerification bool
+	Tracer              gateways.Tracer
 }
 
-func (r *CreateCustomerUsecase) Execute(inputDto dtos.CreateCustomerDto) (*entities.Customer, error) {
+func (r *CreateCustomerUsecase) Execute(ctx context.Context, inputDto dtos.CreateCustomerDto) (_ *entities.Customer, err error) {
+	ctx, span := r.Tracer.Start(ctx, "CreateCustomerUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	customer, document, err := r.newCustomer(inputDto)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("customer.document_type", string(document.Type)))
+
+	return r.CustomerRepository.Create(ctx, customer)
+}
+
+// newCustomer aplica as regras do cadastro sem gravar: documento, telefone e status inicial.
+// Também é usado pela importação em lote, inclusive no dry-run.
+func (r *CreateCustomerUsecase) newCustomer(inputDto dtos.CreateCustomerDto) (*entities.Customer, *entities.IdentityDocument, error) {
+	document, err := identityDocument(inputDto.CPF, inputDto.Document)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if document == nil {
+		return nil, nil, entities.ErrDocumentRequired
+	}
+
 	customer := entities.Customer{
-		Name:  inputDto.Name,
-		CPF:   inputDto.CPF,
-		Email: inputDto.Email,
+		Name:   inputDto.Name,
+		Email:  inputDto.Email,
+		Status: entities.CustomerActive,
+	}
+
+	if r.RequireVerification {
+		customer.Status = entities.CustomerPendingVerification
+	}
+
+	if document.Type == entities.DocumentCPF {
+		customer.CPF = document.Number
+	} else {
+		customer.Document = document
+	}
+
+	if inputDto.Phone != "" {
+		phone, err := entities.NormalizePhone(inputDto.Phone)
+		if err != nil {
+			return nil, nil, err
+		}
+		customer.Phone = &entities.CustomerPhone{Number: phone}
 	}
 
-	return r.CustomerRepository.Create(&customer)
+	return &customer, document, nil
 }
@@ -1,22 +1,24 @@
 package usecases
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockCreateCustomerRepository struct {
 	gateways.CustomerRepository
-	mockCreate func(*entities.Customer) (*entities.Customer, error)
+	mockCreate func(context.Context, *entities.Customer) (*entities.Customer, error)
 }
 
-func (m *mockCreateCustomerRepository) Create(customer *entities.Customer) (*entities.Customer, error) {
-	return m.mockCreate(customer)
+func (m *mockCreateCustomerRepository) Create(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	return m.mockCreate(ctx, customer)
 }
 
 func TestCreateCustomerUsecase_Execute(t *testing.T) {
@@ -24,6 +26,7 @@ func TestCreateCustomerUsecase_Execute(t *testing.T) {
 
 	usecase := CreateCustomerUsecase{
 		CustomerRepository: mockCustomerRepo,
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	inputDto := dtos.CreateCustomerDto{
@@ -33,20 +36,149 @@ func TestCreateCustomerUsecase_Execute(t *testing.T) {
 	}
 
 	t.Run("valid input", func(t *testing.T) {
-		mockCustomerRepo.mockCreate = func(customer *entities.Customer) (*entities.Customer, error) {
+		mockCustomerRepo.mockCreate = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
 			return &entities.Customer{}, nil
 		}
 
-		_, err := usecase.Execute(inputDto)
+		_, err := usecase.Execute(context.Background(), inputDto)
 		assert.NoError(t, err)
 	})
 
 	t.Run("erro ao criar cliente", func(t *testing.T) {
-		mockCustomerRepo.mockCreate = func(customer *entities.Customer) (*entities.Customer, error) {
+		mockCustomerRepo.mockCreate = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
 			return nil, fmt.Errorf("Erro ao criar cliente")
 		}
 
-		_, err := usecase.Execute(inputDto)
+		_, err := usecase.Execute(context.Background(), inputDto)
 		assert.Error(t, err)
 	})
 }
+
+func TestCreateCustomerUsecase_InitialStatus(t *testing.T) {
+	var created *entities.Customer
+	repo := &mockCreateCustomerRepository{mockCreate: func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+		created = customer
+		return customer, nil
+	}}
+	inputDto := dtos.CreateCustomerDto{Name: "John Doe", CPF: "12345678900", Email: "john@example.com"}
+
+	usecase := CreateCustomerUsecase{CustomerRepository: repo, Tracer: tracing.UsecaseTracer{}}
+	_, err := usecase.Execute(context.Background(), inputDto)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.CustomerActive, created.Status)
+
+	usecase.RequireVerification = true
+	_, err = usecase.Execute(context.Background(), inputDto)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.CustomerPendingVerification, created.Status)
+}
+
+func TestCreateCustomerUsecase_IdentityDocuments(t *testing.T) {
+	var created *entities.Customer
+	usecase := CreateCustomerUsecase{
+		CustomerRepository: &mockCreateCustomerRepository{mockCreate: func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+			created = customer
+			return customer, nil
+		}},
+		Tracer: tracing.UsecaseTracer{},
+	}
+
+	t.Run("passaporte", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.CreateCustomerDto{
+			Name:     "Jane Doe",
+			Email:    "jane@example.com",
+			Document: &dtos.IdentityDocumentDto{Type: "passport", Number: "ab 123456", Country: "fr"},
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, created.CPF)
+		assert.Equal(t, &entities.IdentityDocument{Type: entities.DocumentPassport, Number: "AB123456", Country: "FR"}, created.Document)
+	})
+
+	t.Run("CRNM", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.CreateCustomerDto{
+			Name:     "Jane Doe",
+			Email:    "jane@example.com",
+			Document: &dtos.IdentityDocumentDto{Type: "rne", Number: "V123456-7"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "BR", created.Document.Country)
+	})
+
+	t.Run("CPF no objeto vira o CPF do cliente", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.CreateCustomerDto{
+			Name:     "João da Silva",
+			Email:    "joao@example.com",
+			Document: &dtos.IdentityDocumentDto{Type: "cpf", Number: "529.982.247-25"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "52998224725", created.CPF)
+		assert.Nil(t, created.Document)
+	})
+
+	invalid := map[string]struct {
+		input dtos.CreateCustomerDto
+		err   error
+	}{
+		"sem documento":           {dtos.CreateCustomerDto{Name: "Jane Doe"}, entities.ErrDocumentRequired},
+		"CPF e documento":         {dtos.CreateCustomerDto{Name: "Jane Doe", CPF: "12345678900", Document: &dtos.IdentityDocumentDto{Type: "passport", Number: "AB123456", Country: "FR"}}, entities.ErrDocumentRequired},
+		"passaporte sem país":     {dtos.CreateCustomerDto{Name: "Jane Doe", Document: &dtos.IdentityDocumentDto{Type: "passport", Number: "AB123456"}}, entities.ErrInvalidDocument},
+		"RNE de outro país":       {dtos.CreateCustomerDto{Name: "Jane Doe", Document: &dtos.IdentityDocumentDto{Type: "rne", Number: "V1234567", Country: "AR"}}, entities.ErrInvalidDocument},
+		"tipo desconhecido":       {dtos.CreateCustomerDto{Name: "Jane Doe", Document: &dtos.IdentityDocumentDto{Type: "rg", Number: "123456789"}}, entities.ErrInvalidDocument},
+		"dígito do CPF no objeto": {dtos.CreateCustomerDto{Name: "Jane Doe", Document: &dtos.IdentityDocumentDto{Type: "cpf", Number: "52998224726"}}, entities.ErrInvalidCPF},
+	}
+
+	for name, test := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := usecase.Execute(context.Background(), test.input)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestCreateCustomerUsecase_Phone(t *testing.T) {
+	var created *entities.Customer
+	usecase := CreateCustomerUsecase{
+		CustomerRepository: &mockCreateCustomerRepository{mockCreate: func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+			created = customer
+			return customer, nil
+		}},
+		Tracer: tracing.UsecaseTracer{},
+	}
+
+	valid := map[string]string{
+		"só dígitos":               "11987654321",
+		"com máscara":              "(11) 98765-4321",
+		"com 0 de longa distância": "011 98765-4321",
+		"com código do país":       "+55 11 98765-4321",
+		"com 55 sem o +":           "5511987654321",
+	}
+
+	for name, phone := range valid {
+		t.Run(name, func(t *testing.T) {
+			_, err := usecase.Execute(context.Background(), dtos.CreateCustomerDto{Name: "João da Silva", Email: "joao@example.com", CPF: "12345678900", Phone: phone})
+			assert.NoError(t, err)
+			assert.Equal(t, &entities.CustomerPhone{Number: "+5511987654321"}, created.Phone)
+		})
+	}
+
+	t.Run("celular estrangeiro", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.CreateCustomerDto{Name: "Jane Doe", Email: "jane@example.com", CPF: "12345678900", Phone: "+33 6 12 34 56 78"})
+		assert.NoError(t, err)
+		assert.Equal(t, "+33612345678", created.Phone.Number)
+	})
+
+	invalid := map[string]string{
+		"fixo":               "(11) 3333-4444",
+		"DDD inexistente":    "(20) 98765-4321",
+		"celular sem o nono": "(11) 8765-4321",
+		"letras":             "11 9876-ABCD",
+		"longo demais":       "+1234567890123456",
+	}
+
+	for name, phone := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := usecase.Execute(context.Background(), dtos.CreateCustomerDto{Name: "João da Silva", CPF: "12345678900", Phone: phone})
+			assert.ErrorIs(t, err, entities.ErrInvalidPhone)
+		})
+	}
+}
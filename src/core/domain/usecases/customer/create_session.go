@@ -0,0 +1,152 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CreateSessionUsecase emite o token de sessão. Com EmbedPreferencesHash o token de um cliente
+// identificado leva o hash das preferências alimentares, para o quiosque saber quando relê-las.
+type CreateSessionUsecase struct {
+	CustomerRepository    gateways.CustomerRepository
+	PreferencesRepository gateways.PreferencesRepository
+	AuditLog              gateways.AuditLog
+	Metrics               gateways.Metrics
+	EmbedPreferencesHash  bool
+	// LookupMisses recebe as buscas por cliente não cadastrado; opcional
+	LookupMisses gateways.SessionLookupMisses
+	Tracer       gateways.Tracer
+}
+
+func (r *CreateSessionUsecase) Execute(ctx context.Context, inputDto dtos.CreateSessionDto) (_ *entities.Session, err error) {
+	ctx, span := r.Tracer.Start(ctx, "CreateSessionUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	lookupType, findCustomer, err := r.customerLookup(inputDto)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem CPF, documento nem telefone, gere o token com customerId nulo
+	if findCustomer == nil {
+		return r.issueSession(ctx, span, 0, 0, entities.SessionTypeAnonymous, "")
+	}
+
+	span.SetAttributes(attribute.String("customer.lookup_type", lookupType))
+
+	// Se o cliente existir, gere o token com o customerId do cliente
+	foundCustomer, err := findCustomer(ctx)
+
+	// Cliente pendente, suspenso ou encerrado recebe token anônimo com o motivo
+	if err == nil && foundCustomer.Status != entities.CustomerActive {
+		return r.issueSession(ctx, span, 0, foundCustomer.ID, entities.SessionTypeInactiveCustomer, foundCustomer.Status.SessionReason())
+	}
+
+	if err == nil {
+		return r.issueSession(ctx, span, foundCustomer.ID, foundCustomer.ID, entities.SessionTypeIdentified, "")
+	}
+
+	// Se o cliente não existir, gere o token com customerId nulo
+	// e conte a tentativa para o limite contra enumeração de documentos.
+	// Falhas na busca não contam: não dizem nada sobre o documento.
+	if errors.Is(err, entities.ErrCustomerNotFound) && r.LookupMisses != nil {
+		r.LookupMisses.ReportMiss(ctx)
+	}
+
+	return r.issueSession(ctx, span, 0, 0, entities.SessionTypeUnknownCPF, "")
+}
+
+// customerLookup escolhe a busca do cliente: o CPF pelo caminho de sempre, os demais documentos pelo
+// índice do documento e o telefone pelo índice dos telefones verificados. Sem nenhum deles a busca é nil.
+func (r *CreateSessionUsecase) customerLookup(inputDto dtos.CreateSessionDto) (string, func(context.Context) (*entities.Customer, error), error) {
+	if inputDto.Phone != "" {
+		if inputDto.CPF != "" || inputDto.Document != nil {
+			return "", nil, entities.ErrAmbiguousSessionLookup
+		}
+
+		phone, err := entities.NormalizePhone(inputDto.Phone)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return "phone", func(ctx context.Context) (*entities.Customer, error) {
+			return r.CustomerRepository.FindByPhone(ctx, phone)
+		}, nil
+	}
+
+	document, err := identityDocument(inputDto.CPF, inputDto.Document)
+	if err != nil || document == nil {
+		return "", nil, err
+	}
+
+	return string(document.Type), func(ctx context.Context) (*entities.Customer, error) {
+		if document.Type == entities.DocumentCPF {
+			return r.CustomerRepository.FindFirstByCpf(ctx, &entities.Customer{CPF: document.Number})
+		}
+
+		return r.CustomerRepository.FindByDocument(ctx, *document)
+	}, nil
+}
+
+// issueSession emite o token e registra na auditoria a busca por CPF (quando houve) e a emissão.
+// foundID é o cliente encontrado na busca, que só vai no token (customerID) quando está ativo.
+// Sem a auditoria gravada o token não é entregue.
+func (r *CreateSessionUsecase) issueSession(ctx context.Context, span gateways.Span, customerID uint, foundID uint, tokenType string, reason string) (*entities.Session, error) {
+	span.SetAttributes(attribute.String("token.type", tokenType))
+
+	var claimID interface{}
+	if customerID != 0 {
+		claimID = customerID
+	}
+
+	preferencesHash := r.preferencesHash(ctx, customerID)
+
+	expiresAt := time.Now().Add(utils.TokenTTL)
+	token, err := utils.NewSessionJWT(claimID, preferencesHash, expiresAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []entities.AuditEntry{entities.CustomerAuditEntry(entities.AuditActionSessionIssued, customerID)}
+	if tokenType != entities.SessionTypeAnonymous {
+		entries = append([]entities.AuditEntry{entities.CustomerAuditEntry(entities.AuditActionCPFLookup, foundID)}, entries...)
+	}
+
+	if err := r.AuditLog.Record(ctx, entries...); err != nil {
+		return nil, err
+	}
+
+	r.Metrics.TokenIssued(tokenType)
+
+	return &entities.Session{
+		Token:           token,
+		Identified:      customerID != 0,
+		ExpiresAt:       expiresAt,
+		PreferencesHash: preferencesHash,
+		Reason:          reason,
+	}, nil
+}
+
+// preferencesHash é opcional no token: se a leitura falhar a sessão sai sem ele
+func (r *CreateSessionUsecase) preferencesHash(ctx context.Context, customerID uint) string {
+	if !r.EmbedPreferencesHash || customerID == 0 {
+		return ""
+	}
+
+	hash, err := r.PreferencesRepository.Hash(ctx, customerID)
+	if err != nil {
+		slog.WarnContext(ctx, "Erro ao ler o hash das preferências; token emitido sem ele", "error", err)
+		return ""
+	}
+
+	return hash
+}
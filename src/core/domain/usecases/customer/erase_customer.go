@@ -0,0 +1,21 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+)
+
+// EraseCustomerUsecase atende ao pedido de eliminação de dados (LGPD):
+// os dados pessoais são anonimizados e o cliente deixa de ser encontrado
+type EraseCustomerUsecase struct {
+	CustomerRepository gateways.CustomerRepository
+	Tracer             gateways.Tracer
+}
+
+func (r *EraseCustomerUsecase) Execute(ctx context.Context, customerID uint) (err error) {
+	ctx, span := r.Tracer.Start(ctx, "EraseCustomerUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	return r.CustomerRepository.Erase(ctx, customerID)
+}
@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+const exportAuditPageSize = 500
+
+// ExportCustomerDataUsecase atende o pedido de acesso do titular (LGPD, art. 18):
+// devolve os dados do cliente, os endereços, as preferências alimentares, o perfil fiscal, o extrato de pontos e o histórico de quem os acessou ou alterou
+type ExportCustomerDataUsecase struct {
+	CustomerRepository      gateways.CustomerRepository
+	AddressRepository       gateways.AddressRepository
+	PreferencesRepository   gateways.PreferencesRepository
+	FiscalProfileRepository gateways.FiscalProfileRepository
+	LoyaltyLedger           gateways.LoyaltyLedger
+	AuditLog                gateways.AuditLog
+	Tracer                  gateways.Tracer
+}
+
+func (r *ExportCustomerDataUsecase) Execute(ctx context.Context, customerID uint) (_ *dtos.CustomerDataExportDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ExportCustomerDataUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	customer, err := r.CustomerRepository.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	addressBook, err := r.AddressRepository.FindByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := r.PreferencesRepository.Find(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	fiscalProfile, err := r.FiscalProfileRepository.Find(ctx, customerID)
+	if err != nil && !errors.Is(err, entities.ErrFiscalProfileNotFound) {
+		return nil, err
+	}
+
+	loyaltyTransactions, err := r.LoyaltyLedger.History(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entities.AuditFilter{
+		TargetType: entities.AuditTargetCustomer,
+		TargetID:   strconv.FormatUint(uint64(customerID), 10),
+	}
+
+	accessLog := []dtos.AccessRecordDto{}
+	var after uint64
+	for {
+		entries, err := r.AuditLog.List(ctx, filter, after, exportAuditPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			accessLog = append(accessLog, dtos.AccessRecordDto{
+				OccurredAt: entry.OccurredAt,
+				Action:     entry.Action,
+				Actor:      entry.Actor,
+				Role:       entry.Role,
+			})
+			after = entry.Sequence
+		}
+
+		if len(entries) < exportAuditPageSize {
+			break
+		}
+	}
+
+	if err := r.AuditLog.Record(ctx, entities.CustomerAuditEntry(entities.AuditActionCustomerExported, customerID)); err != nil {
+		return nil, err
+	}
+
+	return &dtos.CustomerDataExportDto{
+		Customer:            *customer,
+		Addresses:           addressBook.Addresses,
+		Preferences:         *preferences,
+		FiscalProfile:       fiscalProfile,
+		LoyaltyTransactions: loyaltyTransactions,
+		AccessLog:           accessLog,
+		ExportedAt:          time.Now().UTC(),
+	}, nil
+}
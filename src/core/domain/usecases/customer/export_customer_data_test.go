@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuditLog struct {
+	recorded []entities.AuditEntry
+	stored   []entities.AuditEntry
+}
+
+func (m *mockAuditLog) Record(ctx context.Context, entries ...entities.AuditEntry) error {
+	m.recorded = append(m.recorded, entries...)
+	return nil
+}
+
+func (m *mockAuditLog) List(ctx context.Context, filter entities.AuditFilter, afterSequence uint64, limit int) ([]entities.AuditEntry, error) {
+	result := []entities.AuditEntry{}
+	for _, entry := range m.stored {
+		if entry.Sequence > afterSequence && entry.TargetID == filter.TargetID && len(result) < limit {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+type mockExportCustomerRepository struct {
+	gateways.CustomerRepository
+}
+
+func (m *mockExportCustomerRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	if id != 7 {
+		return nil, entities.ErrCustomerNotFound
+	}
+	return &entities.Customer{ID: 7, Name: "Jane Doe"}, nil
+}
+
+type mockExportLoyaltyLedger struct {
+	gateways.LoyaltyLedger
+}
+
+func (m *mockExportLoyaltyLedger) History(ctx context.Context, customerID uint) ([]entities.LoyaltyTransaction, error) {
+	return []entities.LoyaltyTransaction{{ID: 1, CustomerID: customerID, Type: entities.LoyaltyEarn, Points: 25}}, nil
+}
+
+type mockExportAddressRepository struct {
+	gateways.AddressRepository
+}
+
+func (m *mockExportAddressRepository) FindByCustomer(ctx context.Context, customerID uint) (*entities.AddressBook, error) {
+	return &entities.AddressBook{CustomerID: customerID, Addresses: []entities.Address{{ID: 3, Label: "Casa", CEP: "01310100", Default: true}}}, nil
+}
+
+type mockExportPreferencesRepository struct {
+	gateways.PreferencesRepository
+}
+
+func (m *mockExportPreferencesRepository) Find(ctx context.Context, customerID uint) (*entities.DietaryPreferences, error) {
+	return &entities.DietaryPreferences{CustomerID: customerID, Allergens: []string{"peanuts"}, DietaryStyles: []string{}, DislikedIngredients: []string{}}, nil
+}
+
+type mockExportFiscalProfileRepository struct {
+	gateways.FiscalProfileRepository
+}
+
+func (m *mockExportFiscalProfileRepository) Find(ctx context.Context, customerID uint) (*entities.FiscalProfile, error) {
+	return nil, entities.ErrFiscalProfileNotFound
+}
+
+func TestExportCustomerDataUsecase_Execute(t *testing.T) {
+	auditLog := &mockAuditLog{stored: []entities.AuditEntry{
+		{Sequence: 1, Action: entities.AuditActionCustomerCreated, TargetID: "7", Actor: "anonymous", IP: "10.0.0.1"},
+		{Sequence: 2, Action: entities.AuditActionCustomerRead, TargetID: "8", Actor: "ops"},
+		{Sequence: 3, Action: entities.AuditActionCustomerRead, TargetID: "7", Actor: "ops", Role: "admin"},
+	}}
+	usecase := ExportCustomerDataUsecase{CustomerRepository: &mockExportCustomerRepository{}, AddressRepository: &mockExportAddressRepository{}, PreferencesRepository: &mockExportPreferencesRepository{}, FiscalProfileRepository: &mockExportFiscalProfileRepository{}, LoyaltyLedger: &mockExportLoyaltyLedger{}, AuditLog: auditLog, Tracer: tracing.UsecaseTracer{}}
+
+	t.Run("exporta dados e acessos", func(t *testing.T) {
+		export, err := usecase.Execute(context.Background(), 7)
+		require.NoError(t, err)
+		assert.Equal(t, "Jane Doe", export.Customer.Name)
+		require.Len(t, export.Addresses, 1)
+		assert.Equal(t, "01310100", export.Addresses[0].CEP)
+		assert.Equal(t, []string{"peanuts"}, export.Preferences.Allergens)
+		assert.Nil(t, export.FiscalProfile)
+		require.Len(t, export.LoyaltyTransactions, 1)
+		assert.Equal(t, int64(25), export.LoyaltyTransactions[0].Points)
+		require.Len(t, export.AccessLog, 2)
+		assert.Equal(t, "ops", export.AccessLog[1].Actor)
+
+		require.Len(t, auditLog.recorded, 1)
+		assert.Equal(t, entities.AuditActionCustomerExported, auditLog.recorded[0].Action)
+		assert.Equal(t, "7", auditLog.recorded[0].TargetID)
+	})
+
+	t.Run("cliente inexistente", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), 9)
+		assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+	})
+}
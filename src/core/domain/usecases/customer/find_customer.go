@@ -1,39 +1,29 @@
 package usecases
 
 import (
+	"context"
+
 	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
-	"github.com/CAVAh/api-tech-challenge/src/utils"
 )
 
+// ListCustomerUsecase é o contrato congelado da v1: o corpo da resposta é só o token como string,
+// e o motivo de uma sessão anônima para cliente inativo vai num cabeçalho. A emissão em si fica no
+// CreateSessionUsecase, usado também pela v2.
 type ListCustomerUsecase struct {
 	CustomerRepository gateways.CustomerRepository
+	AuditLog           gateways.AuditLog
+	Metrics            gateways.Metrics
+	LookupMisses       gateways.SessionLookupMisses
+	Tracer             gateways.Tracer
 }
 
-func (r *ListCustomerUsecase) Execute(inputDto dtos.ListCustomerDto) (string, error) {
-	var customer entities.Customer
-
-	// Se o CPF for vazio, gere o token com customerId nulo
-	if inputDto.CPF == "" {
-		token, err := utils.GenerateJWT(nil)
-
-		return token, err
-	}
-
-	customer.CPF = inputDto.CPF
-
-	// Se o cliente existir, gere o token com o customerId do cliente
-	foundCustomer, err := r.CustomerRepository.FindFirstByCpf(&customer)
-
-	if err == nil {
-		token, err := utils.GenerateJWT(foundCustomer.ID)
-
-		return token, err
-	}
+func (r *ListCustomerUsecase) Execute(ctx context.Context, inputDto dtos.ListCustomerDto) (_ *entities.Session, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ListCustomerUsecase.Execute")
+	defer func() { span.End(err) }()
 
-	// Se o cliente não existir, gere o token com customerId nulo
-	token, err := utils.GenerateJWT(nil)
+	sessionUsecase := CreateSessionUsecase{CustomerRepository: r.CustomerRepository, AuditLog: r.AuditLog, Metrics: r.Metrics, LookupMisses: r.LookupMisses, Tracer: r.Tracer}
 
-	return token, err
+	return sessionUsecase.Execute(ctx, dtos.CreateSessionDto{CPF: inputDto.CPF})
 }
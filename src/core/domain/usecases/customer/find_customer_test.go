@@ -1,29 +1,58 @@
 package usecases
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockListCustomerRepository struct {
 	gateways.CustomerRepository
-	mockFindFirstByCpf func(*entities.Customer) (*entities.Customer, error)
+	mockFindFirstByCpf func(context.Context, *entities.Customer) (*entities.Customer, error)
+	mockFindByDocument func(context.Context, entities.IdentityDocument) (*entities.Customer, error)
+	mockFindByPhone    func(context.Context, string) (*entities.Customer, error)
 }
 
-func (m *mockListCustomerRepository) FindFirstByCpf(customer *entities.Customer) (*entities.Customer, error) {
-	return m.mockFindFirstByCpf(customer)
+func (m *mockListCustomerRepository) FindFirstByCpf(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	return m.mockFindFirstByCpf(ctx, customer)
 }
 
+func (m *mockListCustomerRepository) FindByPhone(ctx context.Context, phone string) (*entities.Customer, error) {
+	return m.mockFindByPhone(ctx, phone)
+}
+
+func (m *mockListCustomerRepository) FindByDocument(ctx context.Context, document entities.IdentityDocument) (*entities.Customer, error) {
+	return m.mockFindByDocument(ctx, document)
+}
+
+// mockMetrics conta os tokens emitidos por tipo
+type mockMetrics struct {
+	tokens map[string]int
+}
+
+func (m *mockMetrics) TokenIssued(tokenType string) {
+	if m.tokens == nil {
+		m.tokens = map[string]int{}
+	}
+	m.tokens[tokenType]++
+}
+
+func (m *mockMetrics) LoyaltyPoints(string, int64) {}
+
 func TestListCustomerUsecase_Execute(t *testing.T) {
 	mockCustomerRepo := &mockListCustomerRepository{}
 
 	usecase := ListCustomerUsecase{
 		CustomerRepository: mockCustomerRepo,
+		AuditLog:           &mockAuditLog{},
+		Metrics:            &mockMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
 	}
 
 	inputDto := dtos.ListCustomerDto{
@@ -31,26 +60,263 @@ func TestListCustomerUsecase_Execute(t *testing.T) {
 	}
 
 	t.Run("valid input", func(t *testing.T) {
-		mockCustomerRepo.mockFindFirstByCpf = func(customer *entities.Customer) (*entities.Customer, error) {
+		mockCustomerRepo.mockFindFirstByCpf = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
 			return &entities.Customer{}, nil
 		}
 
-		_, err := usecase.Execute(inputDto)
+		_, err := usecase.Execute(context.Background(), inputDto)
 		assert.NoError(t, err)
 	})
 
 	t.Run("erro ao listar cliente", func(t *testing.T) {
-		mockCustomerRepo.mockFindFirstByCpf = func(customer *entities.Customer) (*entities.Customer, error) {
+		mockCustomerRepo.mockFindFirstByCpf = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
 			return nil, fmt.Errorf("Erro ao listar cliente")
 		}
 
-		_, err := usecase.Execute(inputDto)
+		_, err := usecase.Execute(context.Background(), inputDto)
 		assert.NoError(t, err)
 	})
 
 	t.Run("cpf vazio", func(t *testing.T) {
 		inputDto.CPF = ""
-		_, err := usecase.Execute(inputDto)
+		_, err := usecase.Execute(context.Background(), inputDto)
+		assert.NoError(t, err)
+	})
+}
+
+func TestListCustomerUsecase_Execute_TokenMetrics(t *testing.T) {
+	mockCustomerRepo := &mockListCustomerRepository{}
+	metrics := &mockMetrics{}
+
+	usecase := ListCustomerUsecase{
+		CustomerRepository: mockCustomerRepo,
+		AuditLog:           &mockAuditLog{},
+		Metrics:            metrics,
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	t.Run("token identificado", func(t *testing.T) {
+		mockCustomerRepo.mockFindFirstByCpf = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+			return &entities.Customer{ID: 1, Status: entities.CustomerActive}, nil
+		}
+
+		_, err := usecase.Execute(context.Background(), dtos.ListCustomerDto{CPF: "12345678900"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, metrics.tokens[entities.SessionTypeIdentified])
+	})
+
+	t.Run("cpf desconhecido", func(t *testing.T) {
+		mockCustomerRepo.mockFindFirstByCpf = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+			return nil, entities.ErrCustomerNotFound
+		}
+
+		_, err := usecase.Execute(context.Background(), dtos.ListCustomerDto{CPF: "12345678900"})
 		assert.NoError(t, err)
+		assert.Equal(t, 1, metrics.tokens[entities.SessionTypeUnknownCPF])
 	})
+
+	t.Run("token anônimo", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.ListCustomerDto{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, metrics.tokens[entities.SessionTypeAnonymous])
+	})
+}
+
+func TestCreateSessionUsecase_InactiveCustomer(t *testing.T) {
+	mockCustomerRepo := &mockListCustomerRepository{}
+	auditLog := &mockAuditLog{}
+
+	usecase := CreateSessionUsecase{
+		CustomerRepository: mockCustomerRepo,
+		AuditLog:           auditLog,
+		Metrics:            &mockMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	for status, reason := range map[entities.CustomerStatus]string{
+		entities.CustomerPendingVerification: "customer_pending_verification",
+		entities.CustomerSuspended:           "customer_suspended",
+		entities.CustomerClosed:              "customer_closed",
+	} {
+		t.Run(string(status), func(t *testing.T) {
+			auditLog.recorded = nil
+			metrics := &mockMetrics{}
+			usecase.Metrics = metrics
+			mockCustomerRepo.mockFindFirstByCpf = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+				return &entities.Customer{ID: 5, Status: status}, nil
+			}
+
+			session, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{CPF: "12345678900"})
+			assert.NoError(t, err)
+			assert.False(t, session.Identified)
+			assert.Equal(t, reason, session.Reason)
+			assert.Equal(t, 1, metrics.tokens[entities.SessionTypeInactiveCustomer])
+
+			// a busca continua auditada com o cliente encontrado, mas a sessão não o identifica
+			assert.Len(t, auditLog.recorded, 2)
+			assert.Equal(t, "5", auditLog.recorded[0].TargetID)
+			assert.Empty(t, auditLog.recorded[1].TargetID)
+		})
+	}
+}
+
+func TestCreateSessionUsecase_Audit(t *testing.T) {
+	mockCustomerRepo := &mockListCustomerRepository{}
+	auditLog := &mockAuditLog{}
+
+	usecase := CreateSessionUsecase{
+		CustomerRepository: mockCustomerRepo,
+		AuditLog:           auditLog,
+		Metrics:            &mockMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	t.Run("busca por CPF e emissão", func(t *testing.T) {
+		auditLog.recorded = nil
+		mockCustomerRepo.mockFindFirstByCpf = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+			return &entities.Customer{ID: 5, Status: entities.CustomerActive}, nil
+		}
+
+		_, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{CPF: "12345678900"})
+		assert.NoError(t, err)
+		assert.Len(t, auditLog.recorded, 2)
+		assert.Equal(t, entities.AuditActionCPFLookup, auditLog.recorded[0].Action)
+		assert.Equal(t, "5", auditLog.recorded[0].TargetID)
+		assert.Equal(t, entities.AuditActionSessionIssued, auditLog.recorded[1].Action)
+	})
+
+	t.Run("sessão anônima não registra busca", func(t *testing.T) {
+		auditLog.recorded = nil
+
+		_, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{})
+		assert.NoError(t, err)
+		assert.Len(t, auditLog.recorded, 1)
+		assert.Equal(t, entities.AuditActionSessionIssued, auditLog.recorded[0].Action)
+		assert.Empty(t, auditLog.recorded[0].TargetID)
+	})
+}
+
+func TestCreateSessionUsecase_IdentityDocument(t *testing.T) {
+	mockCustomerRepo := &mockListCustomerRepository{}
+
+	usecase := CreateSessionUsecase{
+		CustomerRepository: mockCustomerRepo,
+		AuditLog:           &mockAuditLog{},
+		Metrics:            &mockMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	t.Run("passaporte busca pelo documento", func(t *testing.T) {
+		var searched entities.IdentityDocument
+		mockCustomerRepo.mockFindByDocument = func(ctx context.Context, document entities.IdentityDocument) (*entities.Customer, error) {
+			searched = document
+			return &entities.Customer{ID: 7, Status: entities.CustomerActive}, nil
+		}
+
+		session, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{
+			Document: &dtos.IdentityDocumentDto{Type: "passport", Number: "AB123456", Country: "FR"},
+		})
+		assert.NoError(t, err)
+		assert.True(t, session.Identified)
+		assert.Equal(t, entities.IdentityDocument{Type: entities.DocumentPassport, Number: "AB123456", Country: "FR"}, searched)
+	})
+
+	t.Run("CPF no objeto usa a busca por CPF", func(t *testing.T) {
+		mockCustomerRepo.mockFindFirstByCpf = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+			assert.Equal(t, "52998224725", customer.CPF)
+			return &entities.Customer{ID: 5, Status: entities.CustomerActive}, nil
+		}
+
+		session, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{
+			Document: &dtos.IdentityDocumentDto{Type: "cpf", Number: "529.982.247-25"},
+		})
+		assert.NoError(t, err)
+		assert.True(t, session.Identified)
+	})
+
+	t.Run("documento inválido", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{
+			Document: &dtos.IdentityDocumentDto{Type: "passport", Number: "AB", Country: "FR"},
+		})
+		assert.ErrorIs(t, err, entities.ErrInvalidDocument)
+	})
+}
+
+func TestCreateSessionUsecase_Phone(t *testing.T) {
+	mockCustomerRepo := &mockListCustomerRepository{}
+
+	usecase := CreateSessionUsecase{
+		CustomerRepository: mockCustomerRepo,
+		AuditLog:           &mockAuditLog{},
+		Metrics:            &mockMetrics{},
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	t.Run("telefone verificado identifica o cliente", func(t *testing.T) {
+		mockCustomerRepo.mockFindByPhone = func(ctx context.Context, phone string) (*entities.Customer, error) {
+			assert.Equal(t, "+5511987654321", phone)
+			return &entities.Customer{ID: 9, Status: entities.CustomerActive}, nil
+		}
+
+		session, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{Phone: "(11) 98765-4321"})
+		assert.NoError(t, err)
+		assert.True(t, session.Identified)
+	})
+
+	t.Run("telefone desconhecido gera sessão anônima", func(t *testing.T) {
+		mockCustomerRepo.mockFindByPhone = func(ctx context.Context, phone string) (*entities.Customer, error) {
+			return nil, entities.ErrCustomerNotFound
+		}
+
+		session, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{Phone: "+55 11 98765-4321"})
+		assert.NoError(t, err)
+		assert.False(t, session.Identified)
+	})
+
+	t.Run("telefone inválido", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{Phone: "11 3333-4444"})
+		assert.ErrorIs(t, err, entities.ErrInvalidPhone)
+	})
+
+	t.Run("telefone e CPF juntos", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{CPF: "12345678900", Phone: "11987654321"})
+		assert.ErrorIs(t, err, entities.ErrAmbiguousSessionLookup)
+	})
+}
+
+// mockLookupMisses conta as buscas sem sucesso reportadas ao limite
+type mockLookupMisses struct {
+	misses int
+}
+
+func (m *mockLookupMisses) ReportMiss(context.Context) {
+	m.misses++
+}
+
+func TestCreateSessionUsecase_ReportsOnlyNotFoundAsMiss(t *testing.T) {
+	mockCustomerRepo := &mockListCustomerRepository{}
+	lookupMisses := &mockLookupMisses{}
+
+	usecase := CreateSessionUsecase{
+		CustomerRepository: mockCustomerRepo,
+		AuditLog:           &mockAuditLog{},
+		Metrics:            &mockMetrics{},
+		LookupMisses:       lookupMisses,
+		Tracer:             tracing.UsecaseTracer{},
+	}
+
+	mockCustomerRepo.mockFindFirstByCpf = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+		return nil, entities.ErrCustomerNotFound
+	}
+	_, err := usecase.Execute(context.Background(), dtos.CreateSessionDto{CPF: "12345678900"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, lookupMisses.misses)
+
+	// falha no banco não diz nada sobre o CPF e não conta para o bloqueio
+	mockCustomerRepo.mockFindFirstByCpf = func(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+		return nil, fmt.Errorf("conexão recusada")
+	}
+	_, err = usecase.Execute(context.Background(), dtos.CreateSessionDto{CPF: "12345678900"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, lookupMisses.misses)
 }
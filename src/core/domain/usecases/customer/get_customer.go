@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type GetCustomerUsecase struct {
+	CustomerRepository gateways.CustomerRepository
+	AuditLog           gateways.AuditLog
+	Redirects          gateways.CustomerRedirects
+	Tracer             gateways.Tracer
+}
+
+func (r *GetCustomerUsecase) Execute(ctx context.Context, customerID uint) (_ *entities.Customer, err error) {
+	ctx, span := r.Tracer.Start(ctx, "GetCustomerUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	customer, err := FindCustomer(ctx, r.CustomerRepository, r.Redirects, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.AuditLog.Record(ctx, entities.CustomerAuditEntry(entities.AuditActionCustomerRead, customer.ID)); err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
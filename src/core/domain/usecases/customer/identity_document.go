@@ -0,0 +1,27 @@
+package usecases
+
+import (
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// identityDocument lê o documento do pedido: o campo cpf, como antes dos documentos estrangeiros,
+// ou o objeto document. Informar os dois é erro; nenhum devolve nil.
+// O CPF do campo cpf mantém a validação de sempre (11 dígitos); o do objeto confere os dígitos verificadores.
+func identityDocument(cpf string, document *dtos.IdentityDocumentDto) (*entities.IdentityDocument, error) {
+	switch {
+	case cpf != "" && document != nil:
+		return nil, entities.ErrDocumentRequired
+	case cpf != "":
+		result := entities.CPFDocument(cpf)
+		return &result, nil
+	case document != nil:
+		result, err := entities.NewIdentityDocument(entities.DocumentType(document.Type), document.Number, document.Country)
+		if err != nil {
+			return nil, err
+		}
+		return &result, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,242 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const DefaultImportBatchSize = 500
+
+// ImportCustomersUsecase cadastra clientes em lote com as mesmas regras do CreateCustomerUsecase.
+// O arquivo é lido em lotes de BatchSize linhas, então o tamanho dele não pesa na memória; cada
+// cliente é gravado na própria transação, com feed, auditoria e evento como no cadastro avulso.
+// OnBatch, quando informado, recebe o resumo parcial ao fim de cada lote.
+// Com Indexes o dry-run também prevê os conflitos entre linhas do próprio arquivo.
+type ImportCustomersUsecase struct {
+	Create    *CreateCustomerUsecase
+	BatchSize int
+	OnBatch   func(dtos.ImportCustomersSummaryDto)
+	Indexes   gateways.CustomerIndexes
+	Tracer    gateways.Tracer
+}
+
+// dryRunSeen guarda os índices cegos das linhas já aceitas no dry-run: sem gravar, o banco não
+// acusa o documento ou o email repetido no arquivo, então a repetição é conferida aqui
+type dryRunSeen struct {
+	documents map[string]bool
+	// emails aponta o índice do email para o do documento do cliente que fica com ele
+	emails map[string]string
+}
+
+// Execute importa as linhas de source. report recebe, na ordem do arquivo, as linhas ignoradas e as
+// que falharam; um erro dele interrompe a importação. Erros inesperados do banco também interrompem,
+// e as linhas gravadas até ali continuam gravadas: o resumo devolvido junto com o erro diz até onde foi.
+func (r *ImportCustomersUsecase) Execute(ctx context.Context, source gateways.CustomerImportSource, inputDto dtos.ImportCustomersDto, report func(dtos.ImportRowResultDto) error) (_ *dtos.ImportCustomersSummaryDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ImportCustomersUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	summary := &dtos.ImportCustomersSummaryDto{DryRun: inputDto.DryRun}
+	defer func() {
+		span.SetAttributes(
+			attribute.Bool("import.dry_run", summary.DryRun),
+			attribute.Int("import.rows", summary.Rows),
+			attribute.Int("import.failed", summary.Failed),
+		)
+	}()
+
+	var seen *dryRunSeen
+	if inputDto.DryRun && r.Indexes != nil {
+		seen = &dryRunSeen{documents: map[string]bool{}, emails: map[string]string{}}
+	}
+
+	batch := make([]dtos.ImportCustomerRowDto, 0, r.batchSize())
+
+	for eof := false; !eof; {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		batch = batch[:0]
+		for len(batch) < r.batchSize() {
+			row, err := source.Next()
+			if errors.Is(err, io.EOF) {
+				eof = true
+				break
+			}
+			if err != nil {
+				return summary, err
+			}
+			batch = append(batch, row)
+		}
+
+		for _, row := range batch {
+			result, err := r.importRow(ctx, row, inputDto, seen)
+			if err != nil {
+				return summary, fmt.Errorf("linha %d: %w", row.Line, err)
+			}
+
+			summary.Rows++
+			switch result.Outcome {
+			case dtos.ImportRowCreated:
+				summary.Created++
+			case dtos.ImportRowUpdated:
+				summary.Updated++
+			case dtos.ImportRowSkipped:
+				summary.Skipped++
+			case dtos.ImportRowFailed:
+				summary.Failed++
+			}
+
+			if result.Outcome == dtos.ImportRowSkipped || result.Outcome == dtos.ImportRowFailed {
+				if err := report(result); err != nil {
+					return summary, err
+				}
+			}
+		}
+
+		if r.OnBatch != nil && len(batch) > 0 {
+			r.OnBatch(*summary)
+		}
+	}
+
+	return summary, nil
+}
+
+// importRow decide o destino de uma linha. Só devolve erro quando a importação deve parar;
+// problemas da própria linha voltam como resultado failed.
+func (r *ImportCustomersUsecase) importRow(ctx context.Context, row dtos.ImportCustomerRowDto, inputDto dtos.ImportCustomersDto, seen *dryRunSeen) (dtos.ImportRowResultDto, error) {
+	result := dtos.ImportRowResultDto{Line: row.Line}
+
+	fail := func(err error) (dtos.ImportRowResultDto, error) {
+		result.Outcome = dtos.ImportRowFailed
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	if row.Err != nil {
+		return fail(row.Err)
+	}
+
+	customer, document, err := r.Create.newCustomer(row.Customer)
+	if err != nil {
+		return fail(err)
+	}
+
+	repository := r.Create.CustomerRepository
+
+	existing, err := repository.FindByDocument(ctx, *document)
+	if err != nil && !errors.Is(err, entities.ErrCustomerNotFound) {
+		return result, err
+	}
+
+	var documentIndex, emailIndex string
+	if seen != nil {
+		if documentIndex, err = r.Indexes.DocumentIndex(*document); err != nil {
+			return result, err
+		}
+		if emailIndex, err = r.Indexes.EmailIndex(customer.Email); err != nil {
+			return result, err
+		}
+	}
+
+	// no dry-run, a linha que repete o documento de uma anterior encontraria o cliente criado por ela
+	if existing != nil || (seen != nil && seen.documents[documentIndex]) {
+		if existing != nil {
+			result.CustomerID = existing.ID
+		}
+
+		if inputDto.OnConflict != dtos.ImportConflictUpsert {
+			// o cliente ignorado mantém o email do cadastro, que as linhas seguintes não podem usar
+			if seen != nil && existing != nil {
+				existingEmail, err := r.Indexes.EmailIndex(existing.Email)
+				if err != nil {
+					return result, err
+				}
+				seen.documents[documentIndex] = true
+				seen.emails[existingEmail] = documentIndex
+			}
+
+			result.Outcome = dtos.ImportRowSkipped
+			result.Error = entities.ErrCustomerAlreadyExists.Error()
+			return result, nil
+		}
+
+		result.Outcome = dtos.ImportRowUpdated
+		if inputDto.DryRun {
+			if seen != nil {
+				if owner, ok := seen.emails[emailIndex]; ok && owner != documentIndex {
+					return fail(entities.ErrCustomerAlreadyExists)
+				}
+				seen.documents[documentIndex] = true
+				seen.emails[emailIndex] = documentIndex
+			}
+			return result, nil
+		}
+
+		// como no PATCH: o mesmo número mantém a verificação, o status não muda
+		existing.Name = customer.Name
+		existing.Email = customer.Email
+		if customer.Phone != nil && (existing.Phone == nil || existing.Phone.Number != customer.Phone.Number) {
+			existing.Phone = customer.Phone
+		}
+
+		if _, err := repository.Update(ctx, existing); err != nil {
+			if errors.Is(err, entities.ErrCustomerAlreadyExists) || errors.Is(err, entities.ErrPhoneAlreadyInUse) {
+				return fail(err)
+			}
+			return result, err
+		}
+
+		return result, nil
+	}
+
+	result.Outcome = dtos.ImportRowCreated
+	if inputDto.DryRun {
+		if seen != nil {
+			// o email de uma linha anterior faria o Create falhar como abaixo
+			if _, ok := seen.emails[emailIndex]; ok {
+				if inputDto.OnConflict == dtos.ImportConflictUpsert {
+					return fail(entities.ErrCustomerAlreadyExists)
+				}
+				result.Outcome = dtos.ImportRowSkipped
+				result.Error = entities.ErrCustomerAlreadyExists.Error()
+				return result, nil
+			}
+			seen.documents[documentIndex] = true
+			seen.emails[emailIndex] = documentIndex
+		}
+		return result, nil
+	}
+
+	created, err := repository.Create(ctx, customer)
+	if errors.Is(err, entities.ErrCustomerAlreadyExists) {
+		// outro cliente já usa o email, ou a linha repete um documento de uma linha anterior
+		if inputDto.OnConflict == dtos.ImportConflictUpsert {
+			return fail(err)
+		}
+		result.Outcome = dtos.ImportRowSkipped
+		result.Error = err.Error()
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.CustomerID = created.ID
+
+	return result, nil
+}
+
+func (r *ImportCustomersUsecase) batchSize() int {
+	if r.BatchSize <= 0 {
+		return DefaultImportBatchSize
+	}
+	return r.BatchSize
+}
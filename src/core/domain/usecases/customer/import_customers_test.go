@@ -0,0 +1,216 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sliceImportSource struct {
+	rows []dtos.ImportCustomerRowDto
+}
+
+func (s *sliceImportSource) Next() (dtos.ImportCustomerRowDto, error) {
+	if len(s.rows) == 0 {
+		return dtos.ImportCustomerRowDto{}, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+// mockImportRepository guarda os clientes por documento; emails repetidos violam a unicidade
+type mockImportRepository struct {
+	gateways.CustomerRepository
+	customers map[string]entities.Customer
+	created   int
+	updated   int
+	failWith  error
+}
+
+func (m *mockImportRepository) FindByDocument(ctx context.Context, document entities.IdentityDocument) (*entities.Customer, error) {
+	if m.failWith != nil {
+		return nil, m.failWith
+	}
+	customer, ok := m.customers[document.Number]
+	if !ok {
+		return nil, entities.ErrCustomerNotFound
+	}
+	return &customer, nil
+}
+
+func (m *mockImportRepository) Create(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	for _, existing := range m.customers {
+		if existing.Email == customer.Email {
+			return nil, entities.ErrCustomerAlreadyExists
+		}
+	}
+	m.created++
+	customer.ID = uint(100 + m.created)
+	m.customers[customer.CPF] = *customer
+	return customer, nil
+}
+
+func (m *mockImportRepository) Update(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	m.updated++
+	m.customers[customer.CPF] = *customer
+	return customer, nil
+}
+
+func importRows() []dtos.ImportCustomerRowDto {
+	return []dtos.ImportCustomerRowDto{
+		{Line: 2, Customer: dtos.CreateCustomerDto{Name: "Novo", CPF: "11111111111", Email: "novo@example.com"}},
+		{Line: 3, Customer: dtos.CreateCustomerDto{Name: "Nome Atualizado", CPF: "22222222222", Email: "existente@example.com"}},
+		{Line: 4, Err: entities.ErrInvalidImportRow},
+		{Line: 5, Customer: dtos.CreateCustomerDto{Name: "Telefone Fixo", CPF: "33333333333", Email: "fixo@example.com", Phone: "11 3333-4444"}},
+		{Line: 6, Customer: dtos.CreateCustomerDto{Name: "Email Repetido", CPF: "44444444444", Email: "existente@example.com"}},
+	}
+}
+
+func newImportRepository() *mockImportRepository {
+	return &mockImportRepository{customers: map[string]entities.Customer{
+		"22222222222": {ID: 7, Name: "Existente", CPF: "22222222222", Email: "existente@example.com", Status: entities.CustomerActive},
+	}}
+}
+
+func TestImportCustomersUsecase_Skip(t *testing.T) {
+	repo := newImportRepository()
+	var batches []dtos.ImportCustomersSummaryDto
+	usecase := ImportCustomersUsecase{
+		Create:    &CreateCustomerUsecase{CustomerRepository: repo, Tracer: tracing.UsecaseTracer{}},
+		BatchSize: 2,
+		OnBatch:   func(summary dtos.ImportCustomersSummaryDto) { batches = append(batches, summary) },
+		Tracer:    tracing.UsecaseTracer{},
+	}
+
+	var reported []dtos.ImportRowResultDto
+	summary, err := usecase.Execute(context.Background(), &sliceImportSource{rows: importRows()}, dtos.ImportCustomersDto{}, func(row dtos.ImportRowResultDto) error {
+		reported = append(reported, row)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, dtos.ImportCustomersSummaryDto{Rows: 5, Created: 1, Skipped: 2, Failed: 2}, *summary)
+	assert.Len(t, batches, 3)
+	assert.Equal(t, 4, batches[1].Rows)
+	assert.Equal(t, "Existente", repo.customers["22222222222"].Name)
+
+	require.Len(t, reported, 4)
+	assert.Equal(t, dtos.ImportRowResultDto{Line: 3, Outcome: dtos.ImportRowSkipped, CustomerID: 7, Error: entities.ErrCustomerAlreadyExists.Error()}, reported[0])
+	assert.Equal(t, dtos.ImportRowFailed, reported[1].Outcome)
+	assert.Equal(t, entities.ErrInvalidPhone.Error(), reported[2].Error)
+	assert.Equal(t, 6, reported[3].Line)
+	assert.Equal(t, dtos.ImportRowSkipped, reported[3].Outcome)
+}
+
+func TestImportCustomersUsecase_Upsert(t *testing.T) {
+	repo := newImportRepository()
+	usecase := ImportCustomersUsecase{Create: &CreateCustomerUsecase{CustomerRepository: repo, RequireVerification: true, Tracer: tracing.UsecaseTracer{}}, Tracer: tracing.UsecaseTracer{}}
+
+	var reported []dtos.ImportRowResultDto
+	summary, err := usecase.Execute(context.Background(), &sliceImportSource{rows: importRows()}, dtos.ImportCustomersDto{OnConflict: dtos.ImportConflictUpsert}, func(row dtos.ImportRowResultDto) error {
+		reported = append(reported, row)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, dtos.ImportCustomersSummaryDto{Rows: 5, Created: 1, Updated: 1, Failed: 3}, *summary)
+
+	// a atualização não mexe no status; o cadastro novo segue a verificação exigida
+	updated := repo.customers["22222222222"]
+	assert.Equal(t, "Nome Atualizado", updated.Name)
+	assert.Equal(t, entities.CustomerActive, updated.Status)
+	assert.Equal(t, entities.CustomerPendingVerification, repo.customers["11111111111"].Status)
+
+	require.Len(t, reported, 3)
+	assert.Equal(t, entities.ErrCustomerAlreadyExists.Error(), reported[2].Error)
+}
+
+// prefixIndexes faz as vezes dos índices cegos; basta que o mesmo valor dê o mesmo índice
+type prefixIndexes struct{}
+
+func (prefixIndexes) DocumentIndex(document entities.IdentityDocument) (string, error) {
+	return "document:" + document.Key(), nil
+}
+
+func (prefixIndexes) EmailIndex(email string) (string, error) {
+	return "email:" + email, nil
+}
+
+func TestImportCustomersUsecase_DryRun(t *testing.T) {
+	for _, onConflict := range []string{dtos.ImportConflictSkip, dtos.ImportConflictUpsert} {
+		t.Run(onConflict, func(t *testing.T) {
+			newUsecase := func(repo *mockImportRepository) ImportCustomersUsecase {
+				return ImportCustomersUsecase{Create: &CreateCustomerUsecase{CustomerRepository: repo, Tracer: tracing.UsecaseTracer{}}, Indexes: prefixIndexes{}, Tracer: tracing.UsecaseTracer{}}
+			}
+			run := func(repo *mockImportRepository, dryRun bool) (*dtos.ImportCustomersSummaryDto, []dtos.ImportRowResultDto) {
+				usecase := newUsecase(repo)
+				var reported []dtos.ImportRowResultDto
+				summary, err := usecase.Execute(context.Background(), &sliceImportSource{rows: importRows()}, dtos.ImportCustomersDto{OnConflict: onConflict, DryRun: dryRun}, func(row dtos.ImportRowResultDto) error {
+					reported = append(reported, row)
+					return nil
+				})
+				require.NoError(t, err)
+				return summary, reported
+			}
+
+			repo := newImportRepository()
+			summary, reported := run(repo, true)
+			assert.Zero(t, repo.created)
+			assert.Zero(t, repo.updated)
+
+			// o dry-run prevê o mesmo resultado da importação de verdade, inclusive o email repetido da linha 6
+			expected, expectedReport := run(newImportRepository(), false)
+			expected.DryRun = true
+			assert.Equal(t, *expected, *summary)
+			require.Len(t, reported, len(expectedReport))
+			for i := range reported {
+				assert.Equal(t, expectedReport[i].Line, reported[i].Line)
+				assert.Equal(t, expectedReport[i].Outcome, reported[i].Outcome)
+				assert.Equal(t, expectedReport[i].Error, reported[i].Error)
+			}
+		})
+	}
+}
+
+func TestImportCustomersUsecase_DryRunReportsRepeatedRows(t *testing.T) {
+	usecase := ImportCustomersUsecase{Create: &CreateCustomerUsecase{CustomerRepository: newImportRepository(), Tracer: tracing.UsecaseTracer{}}, Indexes: prefixIndexes{}, Tracer: tracing.UsecaseTracer{}}
+	rows := []dtos.ImportCustomerRowDto{
+		{Line: 2, Customer: dtos.CreateCustomerDto{Name: "Primeiro", CPF: "55555555555", Email: "primeiro@example.com"}},
+		{Line: 3, Customer: dtos.CreateCustomerDto{Name: "Mesmo Documento", CPF: "55555555555", Email: "outro@example.com"}},
+		{Line: 4, Customer: dtos.CreateCustomerDto{Name: "Mesmo Email", CPF: "66666666666", Email: "primeiro@example.com"}},
+	}
+
+	var reported []dtos.ImportRowResultDto
+	summary, err := usecase.Execute(context.Background(), &sliceImportSource{rows: rows}, dtos.ImportCustomersDto{DryRun: true}, func(row dtos.ImportRowResultDto) error {
+		reported = append(reported, row)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, dtos.ImportCustomersSummaryDto{DryRun: true, Rows: 3, Created: 1, Skipped: 2}, *summary)
+	assert.Equal(t, []dtos.ImportRowResultDto{
+		{Line: 3, Outcome: dtos.ImportRowSkipped, Error: entities.ErrCustomerAlreadyExists.Error()},
+		{Line: 4, Outcome: dtos.ImportRowSkipped, Error: entities.ErrCustomerAlreadyExists.Error()},
+	}, reported)
+}
+
+func TestImportCustomersUsecase_StopsOnRepositoryError(t *testing.T) {
+	repo := newImportRepository()
+	repo.failWith = errors.New("conexão recusada")
+	usecase := ImportCustomersUsecase{Create: &CreateCustomerUsecase{CustomerRepository: repo, Tracer: tracing.UsecaseTracer{}}, Tracer: tracing.UsecaseTracer{}}
+
+	summary, err := usecase.Execute(context.Background(), &sliceImportSource{rows: importRows()}, dtos.ImportCustomersDto{}, func(dtos.ImportRowResultDto) error {
+		return nil
+	})
+	assert.ErrorContains(t, err, "linha 2: conexão recusada")
+	assert.Zero(t, summary.Rows)
+}
@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+const (
+	DefaultChangesLimit = 500
+	cursorPrefix        = "v1:"
+	// o cursor do feed guarda também a transação; cursores v1 do feed continuam aceitos
+	positionCursorPrefix = "v2:"
+)
+
+type ListCustomerChangesUsecase struct {
+	ChangeFeed gateways.CustomerChangeFeed
+	AuditLog   gateways.AuditLog
+	Tracer     gateways.Tracer
+}
+
+// Execute devolve uma página do feed. O cursor é opaco para o cliente: basta repetir a chamada
+// com o nextCursor recebido, mesmo quando a página veio vazia.
+func (r *ListCustomerChangesUsecase) Execute(ctx context.Context, inputDto dtos.ListCustomerChangesDto) (_ *dtos.CustomerChangesPageDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ListCustomerChangesUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	after, err := DecodeChangePositionCursor(inputDto.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := inputDto.Limit
+	if limit == 0 {
+		limit = DefaultChangesLimit
+	}
+
+	changes, last, hasMore, err := r.ChangeFeed.ListChanges(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// só as entradas com dados do cliente contam como leitura; tombstones não expõem nada
+	entries := []entities.AuditEntry{}
+	for _, change := range changes {
+		if change.Customer != nil {
+			entries = append(entries, entities.CustomerAuditEntry(entities.AuditActionCustomerRead, change.CustomerID))
+		}
+	}
+
+	if len(entries) > 0 {
+		if err := r.AuditLog.Record(ctx, entries...); err != nil {
+			return nil, err
+		}
+	}
+
+	if last.Before(after) {
+		last = after
+	}
+
+	return &dtos.CustomerChangesPageDto{
+		Changes:    changes,
+		NextCursor: EncodeChangePositionCursor(last),
+		HasMore:    hasMore,
+	}, nil
+}
+
+func EncodeChangeCursor(sequence uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatUint(sequence, 10)))
+}
+
+// DecodeChangeCursor aceita cursor vazio como início do feed
+func DecodeChangeCursor(cursor string) (uint64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, entities.ErrInvalidCursor
+	}
+
+	value, found := strings.CutPrefix(string(raw), cursorPrefix)
+	if !found {
+		return 0, entities.ErrInvalidCursor
+	}
+
+	sequence, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, entities.ErrInvalidCursor
+	}
+
+	return sequence, nil
+}
+
+func EncodeChangePositionCursor(position entities.CustomerChangePosition) string {
+	value := strconv.FormatUint(position.TransactionID, 10) + "." + strconv.FormatUint(position.Sequence, 10)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(positionCursorPrefix + value))
+}
+
+// DecodeChangePositionCursor aceita cursor vazio como início do feed. Um cursor v1 continua valendo:
+// as alterações gravadas antes de o log registrar a transação ficaram com a transação zerada.
+func DecodeChangePositionCursor(cursor string) (entities.CustomerChangePosition, error) {
+	var position entities.CustomerChangePosition
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return position, entities.ErrInvalidCursor
+	}
+
+	value, found := strings.CutPrefix(string(raw), positionCursorPrefix)
+	if !found {
+		position.Sequence, err = DecodeChangeCursor(cursor)
+		return position, err
+	}
+
+	transactionID, sequence, found := strings.Cut(value, ".")
+	if !found {
+		return position, entities.ErrInvalidCursor
+	}
+
+	if position.TransactionID, err = strconv.ParseUint(transactionID, 10, 64); err != nil {
+		return position, entities.ErrInvalidCursor
+	}
+
+	if position.Sequence, err = strconv.ParseUint(sequence, 10, 64); err != nil {
+		return position, entities.ErrInvalidCursor
+	}
+
+	return position, nil
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockChangeFeed struct {
+	after entities.CustomerChangePosition
+	limit int
+}
+
+func (m *mockChangeFeed) ListChanges(ctx context.Context, after entities.CustomerChangePosition, limit int) ([]entities.CustomerChange, entities.CustomerChangePosition, bool, error) {
+	m.after, m.limit = after, limit
+	last := entities.CustomerChangePosition{TransactionID: after.TransactionID + 1, Sequence: after.Sequence + 1}
+	return []entities.CustomerChange{{Sequence: last.Sequence}}, last, false, nil
+}
+
+func TestListCustomerChangesUsecase_Execute(t *testing.T) {
+	feed := &mockChangeFeed{}
+	usecase := ListCustomerChangesUsecase{ChangeFeed: feed, AuditLog: &mockAuditLog{}, Tracer: tracing.UsecaseTracer{}}
+
+	t.Run("sem cursor começa do início", func(t *testing.T) {
+		page, err := usecase.Execute(context.Background(), dtos.ListCustomerChangesDto{})
+		require.NoError(t, err)
+		assert.Equal(t, entities.CustomerChangePosition{}, feed.after)
+		assert.Equal(t, DefaultChangesLimit, feed.limit)
+		assert.Equal(t, EncodeChangePositionCursor(entities.CustomerChangePosition{TransactionID: 1, Sequence: 1}), page.NextCursor)
+	})
+
+	t.Run("retoma do cursor", func(t *testing.T) {
+		position := entities.CustomerChangePosition{TransactionID: 900, Sequence: 41}
+		_, err := usecase.Execute(context.Background(), dtos.ListCustomerChangesDto{Since: EncodeChangePositionCursor(position), Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, position, feed.after)
+		assert.Equal(t, 10, feed.limit)
+	})
+
+	t.Run("cursor v1 retoma pela sequência", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.ListCustomerChangesDto{Since: EncodeChangeCursor(41)})
+		require.NoError(t, err)
+		assert.Equal(t, entities.CustomerChangePosition{Sequence: 41}, feed.after)
+	})
+
+	t.Run("cursor inválido", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.ListCustomerChangesDto{Since: "42"})
+		assert.ErrorIs(t, err, entities.ErrInvalidCursor)
+	})
+}
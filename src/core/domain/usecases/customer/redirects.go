@@ -0,0 +1,29 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// FindCustomer busca o cliente pelo id e, se ele foi incorporado numa fusão, devolve o sobrevivente.
+// O redirecionamento só é consultado quando o id não é encontrado, então o caminho comum não custa nada a mais.
+func FindCustomer(ctx context.Context, customers gateways.CustomerRepository, redirects gateways.CustomerRedirects, id uint) (*entities.Customer, error) {
+	customer, err := customers.FindByID(ctx, id)
+	if !errors.Is(err, entities.ErrCustomerNotFound) {
+		return customer, err
+	}
+
+	survivorID, resolveErr := redirects.Resolve(ctx, id)
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+
+	if survivorID == id {
+		return nil, err
+	}
+
+	return customers.FindByID(ctx, survivorID)
+}
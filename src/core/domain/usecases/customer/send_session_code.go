@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	DefaultSessionCodeTTL        = 10 * time.Minute
+	DefaultSessionResendInterval = time.Minute
+	DefaultSessionMaxAttempts    = 5
+)
+
+// SendSessionCodeUsecase envia ao cliente da sessão o código que a confirma: por SMS ao telefone
+// verificado ou por email. O CPF sozinho identifica o cliente, mas só quem recebe o código chega aos
+// dados pessoais. Um novo pedido substitui o código anterior, em qualquer canal, só depois de ResendInterval.
+// Sem SMSSender (SMS desligado) o código padrão vai por email e o pedido explícito de SMS devolve ErrSMSUnavailable.
+type SendSessionCodeUsecase struct {
+	CustomerRepository            gateways.CustomerRepository
+	SessionVerificationRepository gateways.SessionVerificationRepository
+	SMSSender                     gateways.SMSSender
+	EmailSender                   gateways.EmailSender
+	CodeTTL                       time.Duration
+	ResendInterval                time.Duration
+	Tracer                        gateways.Tracer
+}
+
+func (r *SendSessionCodeUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.SendSessionCodeDto) (_ *dtos.SessionCodeSentDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "SendSessionCodeUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	customer, err := r.CustomerRepository.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, destination, err := sessionChannel(customer, inputDto.Channel, r.SMSSender != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("session.channel", channel))
+
+	now := time.Now()
+	resendInterval := sessionDurationOrDefault(r.ResendInterval, DefaultSessionResendInterval)
+
+	pending, err := r.SessionVerificationRepository.Find(ctx, customerID)
+	switch {
+	case err == nil:
+		if now.Before(pending.SentAt.Add(resendInterval)) {
+			return nil, entities.ErrSessionCodeResendTooSoon
+		}
+	case !errors.Is(err, entities.ErrSessionVerificationNotFound):
+		return nil, err
+	}
+
+	code, err := newVerificationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	codeTTL := sessionDurationOrDefault(r.CodeTTL, DefaultSessionCodeTTL)
+	verification := entities.SessionVerification{
+		CustomerID:  customerID,
+		Channel:     channel,
+		Destination: destination,
+		SentAt:      now,
+		ExpiresAt:   now.Add(codeTTL),
+	}
+
+	if err := r.SessionVerificationRepository.Save(ctx, verification, code); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Seu código de acesso é %s. Ele vale por %d minutos; não o compartilhe.", code, int(codeTTL.Minutes()))
+	if channel == entities.SessionChannelSMS {
+		err = r.SMSSender.Send(ctx, destination, message)
+	} else {
+		err = r.EmailSender.Send(ctx, destination, "Código de acesso", message)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.SessionCodeSentDto{
+		Channel:   channel,
+		ExpiresAt: verification.ExpiresAt,
+		ResendAt:  now.Add(resendInterval),
+	}, nil
+}
+
+// sessionChannel resolve o canal pedido, ou o padrão quando vazio, e o destino do código.
+// Telefone não verificado não serve: ele é só o que o cliente digitou no cadastro.
+func sessionChannel(customer *entities.Customer, requested string, smsEnabled bool) (string, string, error) {
+	hasPhone := customer.Phone != nil && customer.Phone.Verified
+
+	if requested == "" {
+		requested = entities.SessionChannelEmail
+		if hasPhone && smsEnabled {
+			requested = entities.SessionChannelSMS
+		}
+	}
+
+	switch {
+	case requested == entities.SessionChannelSMS && !smsEnabled:
+		return "", "", entities.ErrSMSUnavailable
+	case requested == entities.SessionChannelSMS && hasPhone:
+		return requested, customer.Phone.Number, nil
+	case requested == entities.SessionChannelEmail && customer.Email != "":
+		return requested, customer.Email, nil
+	default:
+		return "", "", entities.ErrSessionChannelUnavailable
+	}
+}
+
+// newVerificationCode sorteia o código de seis dígitos com crypto/rand
+func newVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func sessionDurationOrDefault(value time.Duration, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,192 @@
+package usecases
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSessionCustomerRepository struct {
+	gateways.CustomerRepository
+	customer entities.Customer
+}
+
+func (m *mockSessionCustomerRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	if id != m.customer.ID {
+		return nil, entities.ErrCustomerNotFound
+	}
+	customer := m.customer
+	return &customer, nil
+}
+
+// memorySessionVerifications guarda o código em texto puro, o que basta para os testes
+type memorySessionVerifications struct {
+	verifications map[uint]entities.SessionVerification
+	codes         map[uint]string
+}
+
+func (m *memorySessionVerifications) Find(ctx context.Context, customerID uint) (*entities.SessionVerification, error) {
+	verification, ok := m.verifications[customerID]
+	if !ok {
+		return nil, entities.ErrSessionVerificationNotFound
+	}
+	return &verification, nil
+}
+
+func (m *memorySessionVerifications) Save(ctx context.Context, verification entities.SessionVerification, code string) error {
+	if m.verifications == nil {
+		m.verifications, m.codes = map[uint]entities.SessionVerification{}, map[uint]string{}
+	}
+	m.verifications[verification.CustomerID] = verification
+	m.codes[verification.CustomerID] = code
+	return nil
+}
+
+func (m *memorySessionVerifications) Consume(ctx context.Context, customerID uint, code string, maxAttempts int, now time.Time) error {
+	verification, ok := m.verifications[customerID]
+	switch {
+	case !ok:
+		return entities.ErrSessionVerificationNotFound
+	case !now.Before(verification.ExpiresAt) || verification.Attempts >= maxAttempts:
+		delete(m.verifications, customerID)
+		return entities.ErrSessionCodeExpired
+	case m.codes[customerID] != code:
+		verification.Attempts++
+		m.verifications[customerID] = verification
+		return entities.ErrInvalidSessionCode
+	}
+	delete(m.verifications, customerID)
+	return nil
+}
+
+// recordingSender grava os SMS e os emails enviados
+type recordingSender struct {
+	destinations []string
+	messages     []string
+}
+
+func (s *recordingSender) Send(ctx context.Context, phone string, message string) error {
+	s.destinations = append(s.destinations, phone)
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+type recordingEmailSender struct {
+	*recordingSender
+}
+
+func (s recordingEmailSender) Send(ctx context.Context, to string, subject string, body string) error {
+	return s.recordingSender.Send(ctx, to, body)
+}
+
+func newSessionCodeFixture(customer entities.Customer) (*SendSessionCodeUsecase, *memorySessionVerifications, *recordingSender) {
+	verifications := &memorySessionVerifications{}
+	sender := &recordingSender{}
+
+	return &SendSessionCodeUsecase{
+		CustomerRepository:            &mockSessionCustomerRepository{customer: customer},
+		SessionVerificationRepository: verifications,
+		SMSSender:                     sender,
+		EmailSender:                   recordingEmailSender{sender},
+		Tracer:                        tracing.UsecaseTracer{},
+	}, verifications, sender
+}
+
+func TestSendSessionCodeUsecase_Execute(t *testing.T) {
+	usecase, verifications, sender := newSessionCodeFixture(entities.Customer{
+		ID:    7,
+		Email: "john@example.com",
+		Phone: &entities.CustomerPhone{Number: "+5511987654321", Verified: true},
+	})
+
+	result, err := usecase.Execute(context.Background(), 7, dtos.SendSessionCodeDto{})
+	require.NoError(t, err)
+
+	code := verifications.codes[7]
+	assert.Regexp(t, regexp.MustCompile(`^[0-9]{6}$`), code)
+	assert.Equal(t, entities.SessionChannelSMS, result.Channel)
+	assert.Equal(t, []string{"+5511987654321"}, sender.destinations)
+	assert.Contains(t, sender.messages[0], code)
+	assert.WithinDuration(t, time.Now().Add(DefaultSessionCodeTTL), result.ExpiresAt, time.Second)
+
+	t.Run("reenvio antes do intervalo, mesmo em outro canal", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), 7, dtos.SendSessionCodeDto{Channel: entities.SessionChannelEmail})
+		assert.ErrorIs(t, err, entities.ErrSessionCodeResendTooSoon)
+		assert.Len(t, sender.messages, 1)
+	})
+}
+
+func TestSendSessionCodeUsecase_Channels(t *testing.T) {
+	tests := map[string]struct {
+		customer    entities.Customer
+		channel     string
+		destination string
+		err         error
+	}{
+		"email quando o telefone não foi verificado": {
+			customer:    entities.Customer{ID: 7, Email: "john@example.com", Phone: &entities.CustomerPhone{Number: "+5511987654321"}},
+			destination: "john@example.com",
+		},
+		"email pedido com telefone verificado": {
+			customer:    entities.Customer{ID: 7, Email: "john@example.com", Phone: &entities.CustomerPhone{Number: "+5511987654321", Verified: true}},
+			channel:     entities.SessionChannelEmail,
+			destination: "john@example.com",
+		},
+		"SMS sem telefone verificado": {
+			customer: entities.Customer{ID: 7, Email: "john@example.com", Phone: &entities.CustomerPhone{Number: "+5511987654321"}},
+			channel:  entities.SessionChannelSMS,
+			err:      entities.ErrSessionChannelUnavailable,
+		},
+		"sem telefone nem email": {
+			customer: entities.Customer{ID: 7},
+			err:      entities.ErrSessionChannelUnavailable,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			usecase, _, sender := newSessionCodeFixture(test.customer)
+
+			_, err := usecase.Execute(context.Background(), 7, dtos.SendSessionCodeDto{Channel: test.channel})
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Empty(t, sender.messages)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{test.destination}, sender.destinations)
+		})
+	}
+}
+
+func TestSendSessionCodeUsecase_WithoutSMS(t *testing.T) {
+	customer := entities.Customer{ID: 7, Email: "john@example.com", Phone: &entities.CustomerPhone{Number: "+5511987654321", Verified: true}}
+
+	t.Run("canal padrão vai por email", func(t *testing.T) {
+		usecase, _, sender := newSessionCodeFixture(customer)
+		usecase.SMSSender = nil
+
+		result, err := usecase.Execute(context.Background(), 7, dtos.SendSessionCodeDto{})
+		require.NoError(t, err)
+		assert.Equal(t, entities.SessionChannelEmail, result.Channel)
+		assert.Equal(t, []string{"john@example.com"}, sender.destinations)
+	})
+
+	t.Run("SMS pedido", func(t *testing.T) {
+		usecase, _, sender := newSessionCodeFixture(customer)
+		usecase.SMSSender = nil
+
+		_, err := usecase.Execute(context.Background(), 7, dtos.SendSessionCodeDto{Channel: entities.SessionChannelSMS})
+		assert.ErrorIs(t, err, entities.ErrSMSUnavailable)
+		assert.Empty(t, sender.messages)
+	})
+}
@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type UpdateCustomerUsecase struct {
+	CustomerRepository gateways.CustomerRepository
+	Tracer             gateways.Tracer
+}
+
+func (r *UpdateCustomerUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.UpdateCustomerDto) (_ *entities.Customer, err error) {
+	ctx, span := r.Tracer.Start(ctx, "UpdateCustomerUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	customer, err := r.CustomerRepository.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if inputDto.Name != "" {
+		customer.Name = inputDto.Name
+	}
+
+	if inputDto.Email != "" {
+		customer.Email = inputDto.Email
+	}
+
+	// o mesmo número mantém a verificação; um número novo precisa ser verificado
+	if inputDto.Phone != "" {
+		phone, err := entities.NormalizePhone(inputDto.Phone)
+		if err != nil {
+			return nil, err
+		}
+		if customer.Phone == nil || customer.Phone.Number != phone {
+			customer.Phone = &entities.CustomerPhone{Number: phone}
+		}
+	}
+
+	return r.CustomerRepository.Update(ctx, customer)
+}
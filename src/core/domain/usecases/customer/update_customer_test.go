@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockUpdateCustomerRepository struct {
+	gateways.CustomerRepository
+	stored  *entities.Customer
+	updated *entities.Customer
+}
+
+func (m *mockUpdateCustomerRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	if m.stored == nil || m.stored.ID != id {
+		return nil, entities.ErrCustomerNotFound
+	}
+	customer := *m.stored
+	return &customer, nil
+}
+
+func (m *mockUpdateCustomerRepository) Update(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	m.updated = customer
+	return customer, nil
+}
+
+func TestUpdateCustomerUsecase_Execute(t *testing.T) {
+	t.Run("mantém campos não informados", func(t *testing.T) {
+		mockCustomerRepo := &mockUpdateCustomerRepository{
+			stored: &entities.Customer{ID: 1, Name: "John Doe", CPF: "12345678901", Email: "john@example.com"},
+		}
+		usecase := UpdateCustomerUsecase{CustomerRepository: mockCustomerRepo, Tracer: tracing.UsecaseTracer{}}
+
+		result, err := usecase.Execute(context.Background(), 1, dtos.UpdateCustomerDto{Email: "johnny@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, "John Doe", result.Name)
+		assert.Equal(t, "johnny@example.com", result.Email)
+		assert.Equal(t, "12345678901", mockCustomerRepo.updated.CPF)
+	})
+
+	t.Run("cliente inexistente", func(t *testing.T) {
+		mockCustomerRepo := &mockUpdateCustomerRepository{}
+		usecase := UpdateCustomerUsecase{CustomerRepository: mockCustomerRepo, Tracer: tracing.UsecaseTracer{}}
+
+		_, err := usecase.Execute(context.Background(), 1, dtos.UpdateCustomerDto{Name: "Jane"})
+		assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+		assert.Nil(t, mockCustomerRepo.updated)
+	})
+}
+
+func TestUpdateCustomerUsecase_Phone(t *testing.T) {
+	stored := &entities.Customer{ID: 1, Name: "John Doe", Email: "john@example.com", Phone: &entities.CustomerPhone{Number: "+5511987654321", Verified: true}}
+
+	t.Run("mesmo número mantém a verificação", func(t *testing.T) {
+		mockCustomerRepo := &mockUpdateCustomerRepository{stored: stored}
+		usecase := UpdateCustomerUsecase{CustomerRepository: mockCustomerRepo, Tracer: tracing.UsecaseTracer{}}
+
+		result, err := usecase.Execute(context.Background(), 1, dtos.UpdateCustomerDto{Phone: "(11) 98765-4321"})
+		assert.NoError(t, err)
+		assert.True(t, result.Phone.Verified)
+	})
+
+	t.Run("número novo precisa ser verificado", func(t *testing.T) {
+		mockCustomerRepo := &mockUpdateCustomerRepository{stored: stored}
+		usecase := UpdateCustomerUsecase{CustomerRepository: mockCustomerRepo, Tracer: tracing.UsecaseTracer{}}
+
+		result, err := usecase.Execute(context.Background(), 1, dtos.UpdateCustomerDto{Phone: "21987654321"})
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.CustomerPhone{Number: "+5521987654321"}, result.Phone)
+	})
+
+	t.Run("número inválido", func(t *testing.T) {
+		mockCustomerRepo := &mockUpdateCustomerRepository{stored: stored}
+		usecase := UpdateCustomerUsecase{CustomerRepository: mockCustomerRepo, Tracer: tracing.UsecaseTracer{}}
+
+		_, err := usecase.Execute(context.Background(), 1, dtos.UpdateCustomerDto{Phone: "1133334444"})
+		assert.ErrorIs(t, err, entities.ErrInvalidPhone)
+		assert.Nil(t, mockCustomerRepo.updated)
+	})
+}
@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// VerifySessionCodeUsecase confere o código enviado por SendSessionCodeUsecase e emite o token de
+// sessão confirmada, de validade curta (TokenTTL), exigido pelas rotas de /v2/customers/me
+type VerifySessionCodeUsecase struct {
+	SessionVerificationRepository gateways.SessionVerificationRepository
+	Metrics                       gateways.Metrics
+	MaxAttempts                   int
+	TokenTTL                      time.Duration
+	Tracer                        gateways.Tracer
+}
+
+func (r *VerifySessionCodeUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.VerifySessionCodeDto) (_ *entities.Session, err error) {
+	ctx, span := r.Tracer.Start(ctx, "VerifySessionCodeUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	maxAttempts := r.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultSessionMaxAttempts
+	}
+
+	now := time.Now()
+	if err := r.SessionVerificationRepository.Consume(ctx, customerID, inputDto.Code, maxAttempts, now); err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(sessionDurationOrDefault(r.TokenTTL, utils.VerifiedTokenTTL))
+	token, err := utils.NewVerifiedJWT(customerID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Metrics.TokenIssued(entities.SessionTypeVerified)
+
+	return &entities.Session{
+		Token:      token,
+		Identified: true,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySessionCodeUsecase_Execute(t *testing.T) {
+	send, verifications, _ := newSessionCodeFixture(entities.Customer{ID: 7, Email: "john@example.com"})
+	metrics := &mockMetrics{}
+	verify := VerifySessionCodeUsecase{SessionVerificationRepository: verifications, Metrics: metrics, MaxAttempts: 2, Tracer: tracing.UsecaseTracer{}}
+
+	_, err := send.Execute(context.Background(), 7, dtos.SendSessionCodeDto{})
+	require.NoError(t, err)
+	code := verifications.codes[7]
+
+	_, err = verify.Execute(context.Background(), 7, dtos.VerifySessionCodeDto{Code: otherCode(code)})
+	assert.ErrorIs(t, err, entities.ErrInvalidSessionCode)
+
+	session, err := verify.Execute(context.Background(), 7, dtos.VerifySessionCodeDto{Code: code})
+	require.NoError(t, err)
+
+	claims, err := utils.ParseJWT(session.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "7", claims.CustomerId)
+	assert.Equal(t, utils.ScopeVerifiedCustomer, claims.Scope)
+	assert.Equal(t, 1, metrics.tokens[entities.SessionTypeVerified])
+
+	// o código só vale uma vez
+	_, err = verify.Execute(context.Background(), 7, dtos.VerifySessionCodeDto{Code: code})
+	assert.ErrorIs(t, err, entities.ErrSessionVerificationNotFound)
+}
+
+func TestVerifySessionCodeUsecase_AttemptsExhausted(t *testing.T) {
+	send, verifications, _ := newSessionCodeFixture(entities.Customer{ID: 7, Email: "john@example.com"})
+	verify := VerifySessionCodeUsecase{SessionVerificationRepository: verifications, Metrics: &mockMetrics{}, MaxAttempts: 2, Tracer: tracing.UsecaseTracer{}}
+
+	_, err := send.Execute(context.Background(), 7, dtos.SendSessionCodeDto{})
+	require.NoError(t, err)
+	code := verifications.codes[7]
+
+	for i := 0; i < 2; i++ {
+		_, err = verify.Execute(context.Background(), 7, dtos.VerifySessionCodeDto{Code: otherCode(code)})
+		assert.ErrorIs(t, err, entities.ErrInvalidSessionCode)
+	}
+
+	// mesmo o código certo não vale depois de esgotadas as tentativas
+	_, err = verify.Execute(context.Background(), 7, dtos.VerifySessionCodeDto{Code: code})
+	assert.ErrorIs(t, err, entities.ErrSessionCodeExpired)
+}
+
+// otherCode devolve um código de seis dígitos diferente de code
+func otherCode(code string) string {
+	if code == "000000" {
+		return "000001"
+	}
+	return "000000"
+}
@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+type VerifyTokenUsecase struct {
+	CustomerRepository gateways.CustomerRepository
+	Redirects          gateways.CustomerRedirects
+	Tracer             gateways.Tracer
+}
+
+func (r *VerifyTokenUsecase) Execute(ctx context.Context, token string) (_ *entities.VerifiedToken, err error) {
+	ctx, span := r.Tracer.Start(ctx, "VerifyTokenUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	claims, err := utils.ParseJWT(token)
+	if err != nil {
+		return nil, entities.ErrInvalidToken
+	}
+
+	// tokens de um cliente incorporado numa fusão passam a identificar o sobrevivente;
+	// tokens anônimos não têm id numérico e seguem como estão
+	customerID := claims.CustomerId
+	if id, parseErr := strconv.ParseUint(customerID, 10, 64); parseErr == nil {
+		resolvedID, err := r.Redirects.Resolve(ctx, uint(id))
+		if err != nil {
+			return nil, err
+		}
+		customerID = strconv.FormatUint(uint64(resolvedID), 10)
+
+		// um cliente suspenso ou encerrado depois da emissão invalida os tokens que já circulam
+		customer, err := r.CustomerRepository.FindByID(ctx, resolvedID)
+		if err != nil && !errors.Is(err, entities.ErrCustomerNotFound) {
+			return nil, err
+		}
+		if customer != nil && customer.Status != entities.CustomerActive {
+			return nil, entities.ErrInvalidToken
+		}
+	}
+
+	return &entities.VerifiedToken{
+		CustomerID:      customerID,
+		ExpiresAt:       claims.ExpiresAt.Time,
+		PreferencesHash: claims.PreferencesHash,
+	}, nil
+}
@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	DefaultMinScore     = 0.65
+	DefaultMaxBlockSize = 50
+	DefaultBatchSize    = 1000
+)
+
+// DetectDuplicatesUsecase procura clientes cadastrados mais de uma vez. Comparar todos com todos
+// não escala, então os clientes são agrupados por nome (primeiro e último), parte local do email e
+// telefone, e só quem divide um grupo é comparado. Grupos maiores que MaxBlockSize, como os de
+// nomes muito comuns, são ignorados: neles o par só é encontrado pelo email ou pelo telefone.
+type DetectDuplicatesUsecase struct {
+	DuplicateRepository gateways.DuplicateRepository
+	MinScore            float64
+	MaxBlockSize        int
+	BatchSize           int
+	Tracer              gateways.Tracer
+}
+
+type DetectionResult struct {
+	Customers  int
+	Candidates int
+}
+
+// Execute lê todos os clientes ativos, grava os pares com score a partir de MinScore e remove os
+// pares pendentes que deixaram de ser encontrados. Pares descartados pelo admin não voltam.
+func (r *DetectDuplicatesUsecase) Execute(ctx context.Context) (_ DetectionResult, err error) {
+	ctx, span := r.Tracer.Start(ctx, "DetectDuplicatesUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	// o banco guarda microssegundos; sem truncar, os pares recém-gravados seriam mais antigos que detectedAt
+	detectedAt := time.Now().UTC().Truncate(time.Second)
+
+	profiles, blocks, err := r.load(ctx)
+	if err != nil {
+		return DetectionResult{}, err
+	}
+
+	maxBlockSize := r.MaxBlockSize
+	if maxBlockSize <= 0 {
+		maxBlockSize = DefaultMaxBlockSize
+	}
+
+	minScore := r.MinScore
+	if minScore <= 0 {
+		minScore = DefaultMinScore
+	}
+
+	compared := map[[2]int]bool{}
+	candidates := []entities.DuplicateCandidate{}
+
+	for _, members := range blocks {
+		if len(members) < 2 || len(members) > maxBlockSize {
+			continue
+		}
+
+		// os índices entram em ordem de id, então a é sempre o menor id do par
+		for i := range members {
+			for _, other := range members[i+1:] {
+				pair := [2]int{members[i], other}
+				if compared[pair] {
+					continue
+				}
+				compared[pair] = true
+
+				a, b := profiles[pair[0]], profiles[pair[1]]
+				if a.distinctDocuments(b) {
+					continue
+				}
+
+				score, reasons := scorePair(a, b)
+				if score < minScore {
+					continue
+				}
+
+				candidates = append(candidates, entities.DuplicateCandidate{
+					CustomerID:      a.id,
+					OtherCustomerID: b.id,
+					Score:           score,
+					Reasons:         reasons,
+					Status:          entities.DuplicatePending,
+					DetectedAt:      detectedAt,
+				})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].CustomerID != candidates[j].CustomerID {
+			return candidates[i].CustomerID < candidates[j].CustomerID
+		}
+		return candidates[i].OtherCustomerID < candidates[j].OtherCustomerID
+	})
+
+	span.SetAttributes(attribute.Int("duplicates.customers", len(profiles)), attribute.Int("duplicates.candidates", len(candidates)))
+
+	for start := 0; start < len(candidates); start += r.batchSize() {
+		end := min(start+r.batchSize(), len(candidates))
+		if err := r.DuplicateRepository.SaveCandidates(ctx, candidates[start:end]); err != nil {
+			return DetectionResult{}, err
+		}
+	}
+
+	if err := r.DuplicateRepository.DeleteStaleCandidates(ctx, detectedAt); err != nil {
+		return DetectionResult{}, err
+	}
+
+	return DetectionResult{Customers: len(profiles), Candidates: len(candidates)}, nil
+}
+
+// load percorre os clientes em lotes e monta os grupos com os índices dos perfis
+func (r *DetectDuplicatesUsecase) load(ctx context.Context) ([]profile, map[string][]int, error) {
+	var (
+		profiles []profile
+		blocks   = map[string][]int{}
+		afterID  uint
+	)
+
+	for {
+		customers, err := r.DuplicateRepository.ScanCustomers(ctx, afterID, r.batchSize())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, customer := range customers {
+			p := newProfile(customer)
+			for _, key := range p.blockKeys() {
+				blocks[key] = append(blocks[key], len(profiles))
+			}
+			profiles = append(profiles, p)
+			afterID = customer.ID
+		}
+
+		if len(customers) < r.batchSize() {
+			return profiles, blocks, nil
+		}
+	}
+}
+
+func (r *DetectDuplicatesUsecase) batchSize() int {
+	if r.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+
+	return r.BatchSize
+}
@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryDuplicateRepository struct {
+	customers  []entities.Customer
+	saved      []entities.DuplicateCandidate
+	staleSince time.Time
+}
+
+func (m *memoryDuplicateRepository) ScanCustomers(ctx context.Context, afterID uint, limit int) ([]entities.Customer, error) {
+	var page []entities.Customer
+	for _, customer := range m.customers {
+		if customer.ID > afterID && len(page) < limit {
+			page = append(page, customer)
+		}
+	}
+	return page, nil
+}
+
+func (m *memoryDuplicateRepository) SaveCandidates(ctx context.Context, candidates []entities.DuplicateCandidate) error {
+	m.saved = append(m.saved, candidates...)
+	return nil
+}
+
+func (m *memoryDuplicateRepository) DeleteStaleCandidates(ctx context.Context, before time.Time) error {
+	m.staleSince = before
+	return nil
+}
+
+func (m *memoryDuplicateRepository) ListCandidates(ctx context.Context, status string, limit int) ([]entities.DuplicateCandidate, error) {
+	return m.saved, nil
+}
+
+func (m *memoryDuplicateRepository) DismissCandidate(ctx context.Context, id uint) (*entities.DuplicateCandidate, error) {
+	return nil, entities.ErrDuplicateCandidateNotFound
+}
+
+func TestDetectDuplicatesUsecase_Execute(t *testing.T) {
+	repo := &memoryDuplicateRepository{customers: []entities.Customer{
+		{ID: 1, Name: "João da Silva", CPF: "52998224725", Email: "joao.silva@gmail.com"},
+		{ID: 2, Name: "JOAO SILVA", Document: &entities.IdentityDocument{Type: entities.DocumentPassport, Number: "AB123456", Country: "PT"}, Email: "joaosilva@gmial.com"},
+		{ID: 3, Name: "Maria Souza", CPF: "11144477735", Email: "maria@example.com", Phone: &entities.CustomerPhone{Number: "+5511987654321", Verified: true}},
+		{ID: 4, Name: "Maria S. Souza", Email: "msouza@example.org", Phone: &entities.CustomerPhone{Number: "+5511987654321"}},
+		// mesmo nome e email de 1, mas outro CPF: são pessoas diferentes
+		{ID: 5, Name: "João Silva", CPF: "39053344705", Email: "joao.silva@hotmail.com"},
+		{ID: 6, Name: "Pedro Alves", CPF: "15350946056", Email: "pedro@example.com"},
+	}}
+	usecase := DetectDuplicatesUsecase{DuplicateRepository: repo, BatchSize: 2, Tracer: tracing.UsecaseTracer{}}
+
+	result, err := usecase.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, DetectionResult{Customers: 6, Candidates: 3}, result)
+
+	require.Len(t, repo.saved, 3)
+
+	pairs := [][2]uint{}
+	for _, candidate := range repo.saved {
+		pairs = append(pairs, [2]uint{candidate.CustomerID, candidate.OtherCustomerID})
+		assert.Equal(t, entities.DuplicatePending, candidate.Status)
+		assert.GreaterOrEqual(t, candidate.Score, DefaultMinScore)
+		assert.Equal(t, repo.staleSince, candidate.DetectedAt)
+	}
+	assert.Equal(t, [][2]uint{{1, 2}, {2, 5}, {3, 4}}, pairs)
+
+	assert.Equal(t, []string{entities.DuplicateReasonName, entities.DuplicateReasonEmail}, repo.saved[0].Reasons)
+	assert.Contains(t, repo.saved[2].Reasons, entities.DuplicateReasonPhone)
+}
+
+func TestDetectDuplicatesUsecase_SkipsLargeBlocks(t *testing.T) {
+	repo := &memoryDuplicateRepository{}
+	for id := uint(1); id <= 4; id++ {
+		repo.customers = append(repo.customers, entities.Customer{ID: id, Name: "Ana Lima", Email: "ana.lima@example.com"})
+	}
+
+	result, err := (&DetectDuplicatesUsecase{DuplicateRepository: repo, MaxBlockSize: 3, Tracer: tracing.UsecaseTracer{}}).Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Candidates)
+	assert.Empty(t, repo.saved)
+}
+
+func TestScorePair(t *testing.T) {
+	joao := newProfile(entities.Customer{ID: 1, Name: "João da Silva", Email: "joao.silva+pedidos@gmail.com"})
+
+	t.Run("só o nome não basta", func(t *testing.T) {
+		score, reasons := scorePair(joao, newProfile(entities.Customer{ID: 2, Name: "Joao Silva", Email: "jsilva@empresa.com"}))
+		assert.Less(t, score, DefaultMinScore)
+		assert.Equal(t, []string{entities.DuplicateReasonName}, reasons)
+	})
+
+	t.Run("email com erro de digitação", func(t *testing.T) {
+		score, reasons := scorePair(joao, newProfile(entities.Customer{ID: 2, Name: "Joao Silva", Email: "joao.slva@gmail.com"}))
+		assert.Equal(t, 0.74, score)
+		assert.Equal(t, []string{entities.DuplicateReasonName, entities.DuplicateReasonEmail}, reasons)
+	})
+
+	t.Run("partes locais curtas não contam como erro de digitação", func(t *testing.T) {
+		_, reasons := scorePair(
+			newProfile(entities.Customer{Name: "Ana", Email: "ana@example.com"}),
+			newProfile(entities.Customer{Name: "Ane", Email: "ane@example.com"}),
+		)
+		assert.NotContains(t, reasons, entities.DuplicateReasonEmail)
+	})
+}
+
+func TestNormalization(t *testing.T) {
+	assert.Equal(t, "joao silva santos", normalizeName("  JOÃO  da Silva-Santos "))
+	assert.Equal(t, "joaosilva", emailLocalPart("Joao.Silva+Promo@Gmail.com"))
+	assert.Empty(t, emailLocalPart("sem-arroba"))
+
+	assert.InDelta(t, 0.961, jaroWinkler("martha", "marhta"), 0.001)
+	assert.Equal(t, 1.0, jaroWinkler("joao silva", "joao silva"))
+
+	assert.True(t, oneEditApart("joaosilva", "joaoslva"))
+	assert.True(t, oneEditApart("joaosilva", "joaosilvb"))
+	assert.False(t, oneEditApart("joaosilva", "joaosilva"))
+	assert.False(t, oneEditApart("joaosilva", "jaosilvb"))
+}
@@ -0,0 +1,21 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// DismissDuplicateUsecase marca o par como pessoas diferentes; as próximas detecções não o reabrem
+type DismissDuplicateUsecase struct {
+	DuplicateRepository gateways.DuplicateRepository
+	Tracer              gateways.Tracer
+}
+
+func (r *DismissDuplicateUsecase) Execute(ctx context.Context, candidateID uint) (_ *entities.DuplicateCandidate, err error) {
+	ctx, span := r.Tracer.Start(ctx, "DismissDuplicateUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	return r.DuplicateRepository.DismissCandidate(ctx, candidateID)
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+const defaultDuplicatesLimit = 100
+
+type ListDuplicatesUsecase struct {
+	DuplicateRepository gateways.DuplicateRepository
+	CustomerRepository  gateways.CustomerRepository
+	AuditLog            gateways.AuditLog
+	Tracer              gateways.Tracer
+}
+
+// Execute devolve os pares com os dois clientes, para o admin escolher o sobrevivente. Cada
+// cliente exibido entra na auditoria como leitura. Pares pendentes em que um dos clientes já
+// foi eliminado ou incorporado ficam de fora até a próxima detecção removê-los.
+func (r *ListDuplicatesUsecase) Execute(ctx context.Context, inputDto dtos.ListDuplicatesDto) (_ []entities.DuplicateCandidate, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ListDuplicatesUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	status := inputDto.Status
+	if status == "" {
+		status = entities.DuplicatePending
+	}
+
+	limit := inputDto.Limit
+	if limit == 0 {
+		limit = defaultDuplicatesLimit
+	}
+
+	candidates, err := r.DuplicateRepository.ListCandidates(ctx, status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []entities.DuplicateCandidate{}
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uint, 0, 2*len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.CustomerID, candidate.OtherCustomerID)
+	}
+
+	customers, err := r.CustomerRepository.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]entities.Customer, len(customers))
+	for _, customer := range customers {
+		byID[customer.ID] = customer
+	}
+
+	read := map[uint]bool{}
+	entries := []entities.AuditEntry{}
+
+	for _, candidate := range candidates {
+		for _, id := range []uint{candidate.CustomerID, candidate.OtherCustomerID} {
+			if customer, ok := byID[id]; ok {
+				candidate.Customers = append(candidate.Customers, customer)
+			}
+		}
+
+		if candidate.Status == entities.DuplicatePending && len(candidate.Customers) < 2 {
+			continue
+		}
+
+		for _, customer := range candidate.Customers {
+			if !read[customer.ID] {
+				read[customer.ID] = true
+				entries = append(entries, entities.CustomerAuditEntry(entities.AuditActionCustomerRead, customer.ID))
+			}
+		}
+
+		result = append(result, candidate)
+	}
+
+	if len(entries) > 0 {
+		if err := r.AuditLog.Record(ctx, entries...); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	addressusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/address"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// MergeCustomersUsecase incorpora um cliente a outro, escolhido pelo admin como sobrevivente.
+// Não precisa haver um par detectado: o admin pode fundir cadastros que a detecção não encontrou.
+// MaxAddresses é o mesmo limite do cadastro de endereços, que a fusão não pode ultrapassar.
+type MergeCustomersUsecase struct {
+	CustomerRepository gateways.CustomerRepository
+	MaxAddresses       int
+	Tracer             gateways.Tracer
+}
+
+func (r *MergeCustomersUsecase) Execute(ctx context.Context, survivorID uint, inputDto dtos.MergeCustomersDto) (_ *entities.Customer, err error) {
+	ctx, span := r.Tracer.Start(ctx, "MergeCustomersUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(survivorID)), attribute.Int("customer.merged_id", int(inputDto.MergedCustomerID)))
+
+	if survivorID == inputDto.MergedCustomerID {
+		return nil, entities.ErrSelfMerge
+	}
+
+	maxAddresses := r.MaxAddresses
+	if maxAddresses <= 0 {
+		maxAddresses = addressusecases.DefaultMaxAddresses
+	}
+
+	return r.CustomerRepository.Merge(ctx, survivorID, inputDto.MergedCustomerID, maxAddresses)
+}
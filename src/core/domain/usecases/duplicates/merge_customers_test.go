@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	addressusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/address"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mergeCustomerRepository struct {
+	gateways.CustomerRepository
+	customers map[uint]entities.Customer
+	merges    [][2]uint
+	limits    []int
+}
+
+func (m *mergeCustomerRepository) FindByIDs(ctx context.Context, ids []uint) ([]entities.Customer, error) {
+	var found []entities.Customer
+	for _, id := range ids {
+		if customer, ok := m.customers[id]; ok {
+			found = append(found, customer)
+		}
+	}
+	return found, nil
+}
+
+func (m *mergeCustomerRepository) Merge(ctx context.Context, survivorID uint, mergedID uint, maxAddresses int) (*entities.Customer, error) {
+	m.merges = append(m.merges, [2]uint{survivorID, mergedID})
+	m.limits = append(m.limits, maxAddresses)
+	survivor := m.customers[survivorID]
+	delete(m.customers, mergedID)
+	return &survivor, nil
+}
+
+type recordingAuditLog struct {
+	gateways.AuditLog
+	entries []entities.AuditEntry
+}
+
+func (l *recordingAuditLog) Record(ctx context.Context, entries ...entities.AuditEntry) error {
+	l.entries = append(l.entries, entries...)
+	return nil
+}
+
+func TestMergeCustomersUsecase_Execute(t *testing.T) {
+	repo := &mergeCustomerRepository{customers: map[uint]entities.Customer{
+		1: {ID: 1, Name: "João da Silva"},
+		2: {ID: 2, Name: "Joao Silva"},
+	}}
+	usecase := MergeCustomersUsecase{CustomerRepository: repo, Tracer: tracing.UsecaseTracer{}}
+
+	_, err := usecase.Execute(context.Background(), 2, dtos.MergeCustomersDto{MergedCustomerID: 2})
+	assert.ErrorIs(t, err, entities.ErrSelfMerge)
+	assert.Empty(t, repo.merges)
+
+	survivor, err := usecase.Execute(context.Background(), 2, dtos.MergeCustomersDto{MergedCustomerID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), survivor.ID)
+	assert.Equal(t, [][2]uint{{2, 1}}, repo.merges)
+	assert.Equal(t, []int{addressusecases.DefaultMaxAddresses}, repo.limits)
+
+	usecase.MaxAddresses = 3
+	_, err = usecase.Execute(context.Background(), 2, dtos.MergeCustomersDto{MergedCustomerID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []int{addressusecases.DefaultMaxAddresses, 3}, repo.limits)
+}
+
+func TestListDuplicatesUsecase_HidesStalePairs(t *testing.T) {
+	customers := &mergeCustomerRepository{customers: map[uint]entities.Customer{
+		1: {ID: 1, Name: "João da Silva"},
+		2: {ID: 2, Name: "Joao Silva"},
+		3: {ID: 3, Name: "Maria Souza"},
+	}}
+	duplicates := &memoryDuplicateRepository{saved: []entities.DuplicateCandidate{
+		{ID: 1, CustomerID: 1, OtherCustomerID: 2, Status: entities.DuplicatePending},
+		// o cliente 4 foi eliminado depois da detecção
+		{ID: 2, CustomerID: 3, OtherCustomerID: 4, Status: entities.DuplicatePending},
+	}}
+	auditLog := &recordingAuditLog{}
+	usecase := ListDuplicatesUsecase{DuplicateRepository: duplicates, CustomerRepository: customers, AuditLog: auditLog, Tracer: tracing.UsecaseTracer{}}
+
+	result, err := usecase.Execute(context.Background(), dtos.ListDuplicatesDto{})
+	require.NoError(t, err)
+
+	require.Len(t, result, 1)
+	assert.Equal(t, []entities.Customer{customers.customers[1], customers.customers[2]}, result[0].Customers)
+
+	require.Len(t, auditLog.entries, 2)
+	assert.Equal(t, entities.AuditActionCustomerRead, auditLog.entries[0].Action)
+}
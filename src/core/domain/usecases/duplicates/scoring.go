@@ -0,0 +1,225 @@
+package usecases
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// Pesos de cada sinal no score. O nome sozinho não alcança DefaultMinScore: homônimos são
+// comuns, então é preciso que email ou telefone confirmem.
+const (
+	nameWeight  = 0.5
+	emailWeight = 0.3
+	phoneWeight = 0.2
+
+	// nameReasonSimilarity é a similaridade a partir da qual o nome aparece entre os motivos
+	nameReasonSimilarity = 0.9
+	// emailTypoFactor é quanto vale, do peso do email, uma parte local a uma edição de distância
+	emailTypoFactor = 0.8
+	// emailTypoMinLength evita tratar como erro de digitação partes locais curtas como "ana" e "ane"
+	emailTypoMinLength = 5
+)
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// nameParticles são as preposições dos sobrenomes, que muita gente omite no cadastro
+var nameParticles = map[string]bool{"da": true, "de": true, "do": true, "das": true, "dos": true, "e": true}
+
+// profile é o que a detecção guarda de cada cliente, já normalizado
+type profile struct {
+	id         uint
+	name       string
+	emailLocal string
+	phone      string
+	document   entities.IdentityDocument
+}
+
+func newProfile(customer entities.Customer) profile {
+	p := profile{
+		id:         customer.ID,
+		name:       normalizeName(customer.Name),
+		emailLocal: emailLocalPart(customer.Email),
+		document:   customer.IdentityDocument(),
+	}
+
+	if customer.Phone != nil {
+		p.phone = customer.Phone.Number
+	}
+
+	return p
+}
+
+// blockKeys são as chaves dos grupos em que o cliente entra; só clientes que dividem um grupo são comparados
+func (p profile) blockKeys() []string {
+	var keys []string
+
+	if tokens := strings.Fields(p.name); len(tokens) > 0 {
+		keys = append(keys, "name:"+tokens[0]+" "+tokens[len(tokens)-1])
+	}
+
+	if p.emailLocal != "" {
+		keys = append(keys, "email:"+p.emailLocal)
+	}
+
+	if p.phone != "" {
+		keys = append(keys, "phone:"+p.phone)
+	}
+
+	return keys
+}
+
+// distinctDocuments indica dois documentos do mesmo tipo e país com números diferentes,
+// ou seja, duas pessoas diferentes: o CPF tem dígitos verificadores, então não é erro de digitação
+func (p profile) distinctDocuments(other profile) bool {
+	a, b := p.document, other.document
+
+	return a.Number != "" && b.Number != "" && a.Type == b.Type && a.Country == b.Country && a.Number != b.Number
+}
+
+// scorePair pontua o par de 0 a 1, com duas casas, e devolve os sinais que contribuíram
+func scorePair(a, b profile) (float64, []string) {
+	var (
+		score   float64
+		reasons []string
+	)
+
+	if a.name != "" && b.name != "" {
+		similarity := jaroWinkler(a.name, b.name)
+		score += nameWeight * similarity
+		if similarity >= nameReasonSimilarity {
+			reasons = append(reasons, entities.DuplicateReasonName)
+		}
+	}
+
+	switch {
+	case a.emailLocal == "" || b.emailLocal == "":
+	case a.emailLocal == b.emailLocal:
+		score += emailWeight
+		reasons = append(reasons, entities.DuplicateReasonEmail)
+	case min(len(a.emailLocal), len(b.emailLocal)) >= emailTypoMinLength && oneEditApart(a.emailLocal, b.emailLocal):
+		score += emailWeight * emailTypoFactor
+		reasons = append(reasons, entities.DuplicateReasonEmail)
+	}
+
+	if a.phone != "" && a.phone == b.phone {
+		score += phoneWeight
+		reasons = append(reasons, entities.DuplicateReasonPhone)
+	}
+
+	return math.Round(score*100) / 100, reasons
+}
+
+// normalizeName deixa o nome em minúsculas, sem acentos, pontuação e preposições
+func normalizeName(name string) string {
+	fields := strings.FieldsFunc(accentReplacer.Replace(strings.ToLower(name)), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	kept := fields[:0]
+	for _, field := range fields {
+		if !nameParticles[field] {
+			kept = append(kept, field)
+		}
+	}
+
+	return strings.Join(kept, " ")
+}
+
+// emailLocalPart é a parte antes do @, sem o sufixo +tag e sem pontos: o domínio é ignorado
+// porque erros como gmial.com são justamente o que gera o segundo cadastro
+func emailLocalPart(email string) string {
+	local, _, found := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !found {
+		return ""
+	}
+
+	local, _, _ = strings.Cut(local, "+")
+
+	return strings.ReplaceAll(local, ".", "")
+}
+
+// jaroWinkler é a similaridade de Jaro-Winkler, de 0 a 1, que favorece prefixos comuns
+func jaroWinkler(a, b string) float64 {
+	s, t := []rune(a), []rune(b)
+	if len(s) == 0 || len(t) == 0 {
+		return 0
+	}
+
+	window := max(len(s), len(t))/2 - 1
+	window = max(window, 0)
+
+	sMatched := make([]bool, len(s))
+	tMatched := make([]bool, len(t))
+
+	matches := 0
+	for i := range s {
+		for j := max(0, i-window); j < min(len(t), i+window+1); j++ {
+			if !tMatched[j] && s[i] == t[j] {
+				sMatched[i], tMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range s {
+		if !sMatched[i] {
+			continue
+		}
+		for !tMatched[j] {
+			j++
+		}
+		if s[i] != t[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s)) + m/float64(len(t)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s), len(t)) && s[prefix] == t[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// oneEditApart indica se a e b diferem por exatamente uma inserção, remoção ou troca de caractere
+func oneEditApart(a, b string) bool {
+	s, t := []rune(a), []rune(b)
+	if len(s) > len(t) {
+		s, t = t, s
+	}
+
+	if len(t)-len(s) > 1 {
+		return false
+	}
+
+	i := 0
+	for i < len(s) && s[i] == t[i] {
+		i++
+	}
+
+	if len(s) == len(t) {
+		return i < len(s) && string(s[i+1:]) == string(t[i+1:])
+	}
+
+	return string(s[i:]) == string(t[i+1:])
+}
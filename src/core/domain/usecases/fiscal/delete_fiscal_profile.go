@@ -0,0 +1,22 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type DeleteFiscalProfileUsecase struct {
+	FiscalProfileRepository gateways.FiscalProfileRepository
+	Tracer                  gateways.Tracer
+}
+
+func (r *DeleteFiscalProfileUsecase) Execute(ctx context.Context, customerID uint) (err error) {
+	ctx, span := r.Tracer.Start(ctx, "DeleteFiscalProfileUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	return r.FiscalProfileRepository.Delete(ctx, customerID)
+}
@@ -0,0 +1,34 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetFiscalProfileUsecase lê o perfil fiscal do cliente. Com CPF é dado pessoal, então a leitura entra na auditoria.
+type GetFiscalProfileUsecase struct {
+	FiscalProfileRepository gateways.FiscalProfileRepository
+	AuditLog                gateways.AuditLog
+	Tracer                  gateways.Tracer
+}
+
+func (r *GetFiscalProfileUsecase) Execute(ctx context.Context, customerID uint) (_ *entities.FiscalProfile, err error) {
+	ctx, span := r.Tracer.Start(ctx, "GetFiscalProfileUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	profile, err := r.FiscalProfileRepository.Find(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.AuditLog.Record(ctx, entities.CustomerAuditEntry(entities.AuditActionFiscalProfileRead, customerID)); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+// ResolveFiscalProfileUsecase atende o serviço de faturamento: a partir do token de sessão do pedido
+// devolve os dados da nota. Sessão anônima não tem perfil fiscal, e a nota sai sem identificação.
+type ResolveFiscalProfileUsecase struct {
+	GetFiscalProfile *GetFiscalProfileUsecase
+	Tracer           gateways.Tracer
+}
+
+func (r *ResolveFiscalProfileUsecase) Execute(ctx context.Context, inputDto dtos.ResolveFiscalProfileDto) (_ *entities.FiscalProfile, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ResolveFiscalProfileUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	claims, err := utils.ParseJWT(inputDto.Token)
+	if err != nil {
+		return nil, entities.ErrInvalidToken
+	}
+
+	if claims.CustomerId == "" {
+		return nil, entities.ErrFiscalProfileNotFound
+	}
+
+	customerID, err := strconv.ParseUint(claims.CustomerId, 10, 64)
+	if err != nil {
+		return nil, entities.ErrInvalidToken
+	}
+
+	return r.GetFiscalProfile.Execute(ctx, uint(customerID))
+}
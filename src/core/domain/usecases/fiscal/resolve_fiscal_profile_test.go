@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuditLog struct {
+	recorded []entities.AuditEntry
+}
+
+func (m *mockAuditLog) Record(ctx context.Context, entries ...entities.AuditEntry) error {
+	m.recorded = append(m.recorded, entries...)
+	return nil
+}
+
+func (m *mockAuditLog) List(ctx context.Context, filter entities.AuditFilter, afterSequence uint64, limit int) ([]entities.AuditEntry, error) {
+	return nil, nil
+}
+
+func TestResolveFiscalProfileUsecase_Execute(t *testing.T) {
+	auditLog := &mockAuditLog{}
+	repo := &memoryFiscalProfileRepository{profiles: map[uint]entities.FiscalProfile{
+		7: {CustomerID: 7, DocumentType: entities.FiscalDocumentCPF, Document: "52998224725"},
+	}}
+	usecase := ResolveFiscalProfileUsecase{GetFiscalProfile: &GetFiscalProfileUsecase{FiscalProfileRepository: repo, AuditLog: auditLog, Tracer: tracing.UsecaseTracer{}}, Tracer: tracing.UsecaseTracer{}}
+
+	token := func(t *testing.T, customerID interface{}) string {
+		token, err := utils.NewJWT(customerID, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("cliente com perfil fiscal", func(t *testing.T) {
+		profile, err := usecase.Execute(context.Background(), dtos.ResolveFiscalProfileDto{Token: token(t, 7)})
+		require.NoError(t, err)
+		assert.Equal(t, "52998224725", profile.Document)
+
+		require.Len(t, auditLog.recorded, 1)
+		assert.Equal(t, entities.AuditActionFiscalProfileRead, auditLog.recorded[0].Action)
+		assert.Equal(t, "7", auditLog.recorded[0].TargetID)
+	})
+
+	t.Run("cliente sem perfil fiscal", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.ResolveFiscalProfileDto{Token: token(t, 8)})
+		assert.ErrorIs(t, err, entities.ErrFiscalProfileNotFound)
+	})
+
+	t.Run("sessão anônima", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.ResolveFiscalProfileDto{Token: token(t, nil)})
+		assert.ErrorIs(t, err, entities.ErrFiscalProfileNotFound)
+	})
+
+	t.Run("token inválido", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), dtos.ResolveFiscalProfileDto{Token: "abc"})
+		assert.ErrorIs(t, err, entities.ErrInvalidToken)
+	})
+}
@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type SaveFiscalProfileUsecase struct {
+	FiscalProfileRepository gateways.FiscalProfileRepository
+	Tracer                  gateways.Tracer
+}
+
+func (r *SaveFiscalProfileUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.SaveFiscalProfileDto) (_ *entities.FiscalProfile, err error) {
+	ctx, span := r.Tracer.Start(ctx, "SaveFiscalProfileUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	profile, err := entities.NewFiscalProfile(
+		customerID,
+		entities.FiscalDocumentType(strings.ToUpper(inputDto.DocumentType)),
+		inputDto.Document,
+		inputDto.LegalName,
+		inputDto.StateRegistration,
+		inputDto.FiscalEmail,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("fiscal.document_type", string(profile.DocumentType)))
+
+	return r.FiscalProfileRepository.Save(ctx, profile)
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryFiscalProfileRepository struct {
+	gateways.FiscalProfileRepository
+	profiles map[uint]entities.FiscalProfile
+}
+
+func (m *memoryFiscalProfileRepository) Find(ctx context.Context, customerID uint) (*entities.FiscalProfile, error) {
+	profile, ok := m.profiles[customerID]
+	if !ok {
+		return nil, entities.ErrFiscalProfileNotFound
+	}
+	return &profile, nil
+}
+
+func (m *memoryFiscalProfileRepository) Save(ctx context.Context, profile entities.FiscalProfile) (*entities.FiscalProfile, error) {
+	if m.profiles == nil {
+		m.profiles = map[uint]entities.FiscalProfile{}
+	}
+	m.profiles[profile.CustomerID] = profile
+	return &profile, nil
+}
+
+func TestSaveFiscalProfileUsecase_Documents(t *testing.T) {
+	usecase := SaveFiscalProfileUsecase{FiscalProfileRepository: &memoryFiscalProfileRepository{}, Tracer: tracing.UsecaseTracer{}}
+
+	valid := map[string]dtos.SaveFiscalProfileDto{
+		"CPF com pontuação":     {DocumentType: "cpf", Document: "529.982.247-25"},
+		"CNPJ numérico":         {DocumentType: "CNPJ", Document: "11.222.333/0001-81", LegalName: "Lanches Ltda"},
+		"CNPJ alfanumérico":     {DocumentType: "CNPJ", Document: "12.abc.345/01de-35", LegalName: "Lanches Ltda"},
+		"inscrição estadual":    {DocumentType: "CNPJ", Document: "11222333000181", LegalName: "Lanches Ltda", StateRegistration: "110.042.490.114"},
+		"produtor rural isento": {DocumentType: "CPF", Document: "52998224725", StateRegistration: "Isento"},
+	}
+
+	for name, input := range valid {
+		t.Run(name, func(t *testing.T) {
+			profile, err := usecase.Execute(context.Background(), 7, input)
+			require.NoError(t, err)
+			assert.NotContains(t, profile.Document, ".")
+			assert.Equal(t, uint(7), profile.CustomerID)
+		})
+	}
+
+	invalid := map[string]struct {
+		input dtos.SaveFiscalProfileDto
+		err   error
+	}{
+		"dígito do CPF":           {dtos.SaveFiscalProfileDto{DocumentType: "CPF", Document: "52998224726"}, entities.ErrInvalidCPF},
+		"CPF repetido":            {dtos.SaveFiscalProfileDto{DocumentType: "CPF", Document: "111.111.111-11"}, entities.ErrInvalidCPF},
+		"CNPJ como CPF":           {dtos.SaveFiscalProfileDto{DocumentType: "CPF", Document: "11222333000181"}, entities.ErrInvalidCPF},
+		"dígito do CNPJ":          {dtos.SaveFiscalProfileDto{DocumentType: "CNPJ", Document: "11222333000182", LegalName: "Lanches Ltda"}, entities.ErrInvalidCNPJ},
+		"letra no dígito":         {dtos.SaveFiscalProfileDto{DocumentType: "CNPJ", Document: "12ABC34501DE3A", LegalName: "Lanches Ltda"}, entities.ErrInvalidCNPJ},
+		"CNPJ sem razão social":   {dtos.SaveFiscalProfileDto{DocumentType: "CNPJ", Document: "11222333000181"}, entities.ErrInvalidFiscalProfile},
+		"tipo desconhecido":       {dtos.SaveFiscalProfileDto{DocumentType: "RG", Document: "123456789"}, entities.ErrInvalidFiscalProfile},
+		"inscrição estadual ruim": {dtos.SaveFiscalProfileDto{DocumentType: "CPF", Document: "52998224725", StateRegistration: "IE-??"}, entities.ErrInvalidFiscalProfile},
+	}
+
+	for name, test := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := usecase.Execute(context.Background(), 7, test.input)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// AdjustPointsUsecase lança uma correção manual. Créditos seguem a validade da política,
+// a não ser que ExpiresAt seja informado; débitos podem deixar o saldo negativo.
+type AdjustPointsUsecase struct {
+	CustomerRepository gateways.CustomerRepository
+	Ledger             gateways.LoyaltyLedger
+	Policy             LoyaltyPolicy
+	Metrics            gateways.Metrics
+	Tracer             gateways.Tracer
+}
+
+func (r *AdjustPointsUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.AdjustPointsDto) (_ *dtos.LoyaltyPostingDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "AdjustPointsUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	if inputDto.ExpiresAt != nil && (inputDto.Points < 0 || !inputDto.ExpiresAt.After(time.Now())) {
+		return nil, entities.ErrInvalidPoints
+	}
+
+	if _, err := r.CustomerRepository.FindByID(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	return post(ctx, r.Ledger, r.Policy, r.Metrics, posting{
+		customerID: customerID,
+		kind:       entities.LoyaltyAdjust,
+		reference:  inputDto.Reference,
+		sameRequest: func(existing entities.LoyaltyTransaction) bool {
+			return existing.Points == inputDto.Points
+		},
+		build: func(_ LoyaltyState, now time.Time) (entities.LoyaltyTransaction, error) {
+			transaction := entities.LoyaltyTransaction{
+				Points:      inputDto.Points,
+				Description: inputDto.Description,
+			}
+
+			if inputDto.Points > 0 {
+				transaction.ExpiresAt = r.Policy.creditExpiresAt(now)
+				if inputDto.ExpiresAt != nil {
+					expiresAt := inputDto.ExpiresAt.UTC()
+					transaction.ExpiresAt = &expiresAt
+				}
+			}
+
+			return transaction, nil
+		},
+	})
+}
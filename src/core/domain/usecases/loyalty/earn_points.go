@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	customerusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// EarnPointsUsecase credita os pontos de um pedido pago, com o multiplicador da faixa atual do cliente
+type EarnPointsUsecase struct {
+	CustomerRepository gateways.CustomerRepository
+	Redirects          gateways.CustomerRedirects
+	Ledger             gateways.LoyaltyLedger
+	Policy             LoyaltyPolicy
+	Metrics            gateways.Metrics
+	Tracer             gateways.Tracer
+}
+
+func (r *EarnPointsUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.EarnPointsDto) (_ *dtos.LoyaltyPostingDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "EarnPointsUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	// pedidos de um cliente incorporado numa fusão pontuam no sobrevivente
+	customer, err := customerusecases.FindCustomer(ctx, r.CustomerRepository, r.Redirects, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return post(ctx, r.Ledger, r.Policy, r.Metrics, posting{
+		customerID: customer.ID,
+		kind:       entities.LoyaltyEarn,
+		reference:  inputDto.Reference,
+		sameRequest: func(existing entities.LoyaltyTransaction) bool {
+			return existing.AmountCents == inputDto.AmountCents
+		},
+		build: func(state LoyaltyState, now time.Time) (entities.LoyaltyTransaction, error) {
+			tier, _ := r.Policy.Tier(state.TierPoints)
+
+			return entities.LoyaltyTransaction{
+				Points:      r.Policy.EarnPoints(inputDto.AmountCents, tier),
+				AmountCents: inputDto.AmountCents,
+				ExpiresAt:   r.Policy.creditExpiresAt(now),
+			}, nil
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// ExpirePointsUsecase grava os lançamentos de expiração dos créditos vencidos. O saldo já
+// desconta os pontos vencidos antes disso; o lançamento deixa a baixa visível no extrato.
+type ExpirePointsUsecase struct {
+	Ledger    gateways.LoyaltyLedger
+	Policy    LoyaltyPolicy
+	Metrics   gateways.Metrics
+	BatchSize int
+	Tracer    gateways.Tracer
+}
+
+// Execute percorre todos os clientes com lançamentos e devolve quantos pontos expirou.
+// A referência de cada expiração é o crédito vencido, então rodar em várias réplicas não duplica nada.
+func (r *ExpirePointsUsecase) Execute(ctx context.Context) (_ int64, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ExpirePointsUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	var (
+		expired int64
+		afterID uint
+	)
+
+	for {
+		customerIDs, err := r.Ledger.CustomerIDs(ctx, afterID, r.BatchSize)
+		if err != nil {
+			return expired, err
+		}
+
+		for _, customerID := range customerIDs {
+			points, err := r.expireCustomer(ctx, customerID)
+			if err != nil {
+				return expired, err
+			}
+			expired += points
+			afterID = customerID
+		}
+
+		if len(customerIDs) < r.BatchSize {
+			return expired, nil
+		}
+	}
+}
+
+func (r *ExpirePointsUsecase) expireCustomer(ctx context.Context, customerID uint) (int64, error) {
+	var expired int64
+
+	_, err := r.Ledger.Post(ctx, customerID, func(history []entities.LoyaltyTransaction) ([]entities.LoyaltyTransaction, error) {
+		now := time.Now().UTC()
+		state := r.Policy.Replay(history, now)
+
+		transactions := make([]entities.LoyaltyTransaction, 0, len(state.Pending))
+		for _, pending := range state.Pending {
+			lotID := pending.LotID
+			transactions = append(transactions, entities.LoyaltyTransaction{
+				CustomerID:  customerID,
+				Type:        entities.LoyaltyExpire,
+				Points:      -pending.Points,
+				Reference:   fmt.Sprintf("lot:%d", lotID),
+				Description: "pontos vencidos em " + pending.ExpiredAt.Format(time.DateOnly),
+				LotID:       &lotID,
+				CreatedAt:   now,
+			})
+			expired += pending.Points
+		}
+
+		return transactions, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.Metrics.LoyaltyPoints(entities.LoyaltyExpire, expired)
+
+	return expired, nil
+}
@@ -0,0 +1,21 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+)
+
+type GetLoyaltyBalanceUsecase struct {
+	Ledger gateways.LoyaltyLedger
+	Policy LoyaltyPolicy
+	Tracer gateways.Tracer
+}
+
+func (r *GetLoyaltyBalanceUsecase) Execute(ctx context.Context, customerID uint) (_ *dtos.LoyaltyBalanceDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "GetLoyaltyBalanceUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	return loadBalance(ctx, r.Ledger, r.Policy, customerID)
+}
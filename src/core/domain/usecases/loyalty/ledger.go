@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"sort"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// LoyaltyState é o extrato consolidado num instante. O saldo não é guardado em lugar nenhum:
+// é sempre recalculado a partir dos lançamentos.
+type LoyaltyState struct {
+	Balance int64
+	// TierPoints são os pontos ganhos dentro da janela da faixa
+	TierPoints int64
+	// Expiring são os créditos com saldo e vencimento, do mais próximo ao mais distante
+	Expiring []entities.ExpiringPoints
+	// Pending são os créditos vencidos que ainda não têm lançamento de expiração
+	Pending []PendingExpiration
+}
+
+type PendingExpiration struct {
+	LotID     uint
+	Points    int64
+	ExpiredAt time.Time
+}
+
+// lot é um crédito com o que ainda resta dele
+type lot struct {
+	id        uint
+	remaining int64
+	createdAt time.Time
+	expiresAt *time.Time
+	expiredAt *time.Time
+	recorded  bool
+}
+
+// Replay refaz o extrato em ordem. Débitos consomem primeiro os créditos que vencem antes
+// (FIFO); créditos vencidos não são consumidos, ficam à espera do lançamento de expiração.
+// Débitos sem crédito que os cubra, como um ajuste negativo, viram déficit abatido dos
+// próximos créditos.
+func (p LoyaltyPolicy) Replay(history []entities.LoyaltyTransaction, now time.Time) LoyaltyState {
+	var (
+		lots         []*lot
+		lotsByID     = map[uint]*lot{}
+		deficit      int64
+		lastActivity time.Time
+		state        LoyaltyState
+	)
+
+	expireUntil := func(at time.Time) {
+		for _, credit := range lots {
+			if credit.expiredAt != nil || credit.remaining <= 0 {
+				continue
+			}
+
+			if deadline := p.deadline(credit, lastActivity); deadline != nil && !at.Before(*deadline) {
+				credit.expiredAt = deadline
+			}
+		}
+	}
+
+	for _, transaction := range history {
+		expireUntil(transaction.CreatedAt)
+
+		switch {
+		case transaction.Type == entities.LoyaltyExpire:
+			if credit, ok := lotsByID[derefID(transaction.LotID)]; ok {
+				credit.remaining += transaction.Points
+				credit.recorded = true
+			}
+		case transaction.Points > 0:
+			points := transaction.Points
+			paid := min(deficit, points)
+			deficit -= paid
+
+			credit := &lot{
+				id:        transaction.ID,
+				remaining: points - paid,
+				createdAt: transaction.CreatedAt,
+				expiresAt: transaction.ExpiresAt,
+			}
+			lots = append(lots, credit)
+			lotsByID[credit.id] = credit
+		case transaction.Points < 0:
+			deficit += consume(lots, -transaction.Points, p, lastActivity)
+		}
+
+		if transaction.Type == entities.LoyaltyEarn || transaction.Type == entities.LoyaltyRedeem {
+			lastActivity = transaction.CreatedAt
+		}
+
+		if transaction.Type == entities.LoyaltyEarn && transaction.CreatedAt.After(now.Add(-p.TierWindow)) {
+			state.TierPoints += transaction.Points
+		}
+	}
+
+	expireUntil(now)
+
+	state.Balance = -deficit
+	for _, credit := range lots {
+		if credit.remaining <= 0 {
+			continue
+		}
+
+		if credit.expiredAt != nil {
+			if !credit.recorded {
+				state.Pending = append(state.Pending, PendingExpiration{LotID: credit.id, Points: credit.remaining, ExpiredAt: *credit.expiredAt})
+			}
+			continue
+		}
+
+		state.Balance += credit.remaining
+		if deadline := p.deadline(credit, lastActivity); deadline != nil {
+			state.Expiring = append(state.Expiring, entities.ExpiringPoints{Points: credit.remaining, ExpiresAt: *deadline})
+		}
+	}
+
+	sort.SliceStable(state.Expiring, func(i, j int) bool { return state.Expiring[i].ExpiresAt.Before(state.Expiring[j].ExpiresAt) })
+
+	return state
+}
+
+// consume debita points dos créditos vivos, do vencimento mais próximo ao mais distante,
+// e devolve o que faltou
+func consume(lots []*lot, points int64, p LoyaltyPolicy, lastActivity time.Time) int64 {
+	live := make([]*lot, 0, len(lots))
+	for _, credit := range lots {
+		if credit.expiredAt == nil && credit.remaining > 0 {
+			live = append(live, credit)
+		}
+	}
+
+	sort.SliceStable(live, func(i, j int) bool {
+		a, b := p.deadline(live[i], lastActivity), p.deadline(live[j], lastActivity)
+		return a != nil && (b == nil || a.Before(*b))
+	})
+
+	for _, credit := range live {
+		if points == 0 {
+			break
+		}
+
+		used := min(credit.remaining, points)
+		credit.remaining -= used
+		points -= used
+	}
+
+	return points
+}
+
+// deadline é quando o crédito vence; nil se não vence. O vencimento gravado no crédito sempre vale
+// e a política de inatividade pode antecipá-lo.
+func (p LoyaltyPolicy) deadline(credit *lot, lastActivity time.Time) *time.Time {
+	if p.Expiry != ExpiryInactivity || p.PointsTTL <= 0 {
+		return credit.expiresAt
+	}
+
+	deadline := credit.createdAt
+	if lastActivity.After(deadline) {
+		deadline = lastActivity
+	}
+	deadline = deadline.Add(p.PointsTTL)
+
+	if credit.expiresAt != nil && credit.expiresAt.Before(deadline) {
+		return credit.expiresAt
+	}
+
+	return &deadline
+}
+
+func derefID(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+
+	return *id
+}
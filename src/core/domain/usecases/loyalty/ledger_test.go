@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var day = 24 * time.Hour
+
+func testPolicy() LoyaltyPolicy {
+	tiers, _ := ParseLoyaltyTiers("bronze:0:1,prata:100:2")
+
+	return LoyaltyPolicy{
+		PointsPerReal: 1,
+		Expiry:        ExpiryPerLot,
+		PointsTTL:     30 * day,
+		ExpiryWarning: 7 * day,
+		Tiers:         tiers,
+		TierWindow:    365 * day,
+	}
+}
+
+func credit(id uint, points int64, at time.Time, expiresAt *time.Time) entities.LoyaltyTransaction {
+	return entities.LoyaltyTransaction{ID: id, Type: entities.LoyaltyEarn, Points: points, CreatedAt: at, ExpiresAt: expiresAt}
+}
+
+func at(t time.Time) *time.Time {
+	return &t
+}
+
+func TestReplay_RedeemConsumesEarliestExpiringCredits(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	history := []entities.LoyaltyTransaction{
+		credit(1, 100, start, at(start.Add(30*day))),
+		credit(2, 50, start.Add(10*day), at(start.Add(40*day))),
+		{ID: 3, Type: entities.LoyaltyRedeem, Points: -120, CreatedAt: start.Add(20 * day)},
+	}
+
+	state := testPolicy().Replay(history, start.Add(35*day))
+
+	// o primeiro crédito foi todo consumido antes de vencer; sobram 30 do segundo
+	assert.Equal(t, int64(30), state.Balance)
+	assert.Empty(t, state.Pending)
+	require.Len(t, state.Expiring, 1)
+	assert.Equal(t, entities.ExpiringPoints{Points: 30, ExpiresAt: start.Add(40 * day)}, state.Expiring[0])
+}
+
+func TestReplay_ExpiredCreditsAreNotRedeemedAndWaitForExpiry(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	lotID := uint(1)
+	history := []entities.LoyaltyTransaction{
+		credit(1, 100, start, at(start.Add(30*day))),
+		credit(2, 50, start.Add(10*day), at(start.Add(40*day))),
+		// depois do vencimento do primeiro crédito o resgate só pode usar o segundo
+		{ID: 3, Type: entities.LoyaltyRedeem, Points: -20, CreatedAt: start.Add(31 * day)},
+	}
+
+	state := testPolicy().Replay(history, start.Add(32*day))
+	assert.Equal(t, int64(30), state.Balance)
+	require.Len(t, state.Pending, 1)
+	assert.Equal(t, PendingExpiration{LotID: 1, Points: 100, ExpiredAt: start.Add(30 * day)}, state.Pending[0])
+
+	history = append(history, entities.LoyaltyTransaction{ID: 4, Type: entities.LoyaltyExpire, Points: -100, LotID: &lotID, CreatedAt: start.Add(32 * day)})
+	state = testPolicy().Replay(history, start.Add(33*day))
+	assert.Equal(t, int64(30), state.Balance)
+	assert.Empty(t, state.Pending)
+}
+
+func TestReplay_NegativeAdjustmentBecomesDeficit(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	history := []entities.LoyaltyTransaction{
+		credit(1, 10, start, nil),
+		{ID: 2, Type: entities.LoyaltyAdjust, Points: -25, CreatedAt: start.Add(day)},
+		credit(3, 40, start.Add(2*day), nil),
+	}
+
+	state := testPolicy().Replay(history, start.Add(3*day))
+	assert.Equal(t, int64(25), state.Balance)
+	assert.Equal(t, int64(50), state.TierPoints)
+}
+
+func TestReplay_InactivityPolicyExpiresWholeBalance(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	policy := testPolicy()
+	policy.Expiry = ExpiryInactivity
+	history := []entities.LoyaltyTransaction{
+		credit(1, 10, start, nil),
+		credit(2, 20, start.Add(20*day), nil),
+	}
+
+	// a atividade no dia 20 adia o vencimento do primeiro crédito também
+	state := policy.Replay(history, start.Add(40*day))
+	assert.Equal(t, int64(30), state.Balance)
+
+	state = policy.Replay(history, start.Add(50*day))
+	assert.Zero(t, state.Balance)
+	assert.Len(t, state.Pending, 2)
+}
+
+func TestLoyaltyPolicy_TierAndEarnPoints(t *testing.T) {
+	policy := testPolicy()
+
+	tier, next := policy.Tier(99)
+	assert.Equal(t, "bronze", tier.Name)
+	require.NotNil(t, next)
+	assert.Equal(t, "prata", next.Name)
+
+	tier, next = policy.Tier(100)
+	assert.Equal(t, "prata", tier.Name)
+	assert.Nil(t, next)
+
+	assert.Equal(t, int64(91), policy.EarnPoints(4590, tier))
+}
+
+func TestParseLoyaltyTiers_RequiresBaseTier(t *testing.T) {
+	_, err := ParseLoyaltyTiers("prata:100:1.5")
+	assert.Error(t, err)
+
+	_, err = ParseLoyaltyTiers("bronze:0")
+	assert.Error(t, err)
+}
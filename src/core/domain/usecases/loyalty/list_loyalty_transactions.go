@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	customerusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+)
+
+const DefaultLoyaltyTransactionsLimit = 50
+
+type ListLoyaltyTransactionsUsecase struct {
+	Ledger gateways.LoyaltyLedger
+	Tracer gateways.Tracer
+}
+
+// Execute devolve uma página do extrato em ordem de lançamento; o cursor segue o formato do feed de alterações
+func (r *ListLoyaltyTransactionsUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.ListLoyaltyTransactionsDto) (_ *dtos.LoyaltyTransactionsPageDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ListLoyaltyTransactionsUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	after, err := customerusecases.DecodeChangeCursor(inputDto.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := inputDto.Limit
+	if limit == 0 {
+		limit = DefaultLoyaltyTransactionsLimit
+	}
+
+	transactions, err := r.Ledger.List(ctx, customerID, uint(after), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	last := after
+	if len(transactions) > 0 {
+		last = uint64(transactions[len(transactions)-1].ID)
+	}
+
+	return &dtos.LoyaltyTransactionsPageDto{
+		Transactions: transactions,
+		NextCursor:   customerusecases.EncodeChangeCursor(last),
+		HasMore:      len(transactions) == limit,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+// Políticas de validade dos pontos
+const (
+	// ExpiryPerLot: cada crédito vence PointsTTL depois de lançado
+	ExpiryPerLot = "lot"
+	// ExpiryInactivity: o saldo inteiro vence PointsTTL depois do último ganho ou resgate
+	ExpiryInactivity = "inactivity"
+	// ExpiryNever: pontos não vencem
+	ExpiryNever = "never"
+)
+
+const defaultLoyaltyTiers = "bronze:0:1,prata:1000:1.25,ouro:5000:1.5"
+
+// LoyaltyPolicy reúne as regras do programa de fidelidade
+type LoyaltyPolicy struct {
+	// PointsPerReal é quantos pontos cada real pago rende, antes do multiplicador da faixa
+	PointsPerReal float64
+	Expiry        string
+	PointsTTL     time.Duration
+	// ExpiryWarning é a antecedência com que os pontos a vencer aparecem no saldo
+	ExpiryWarning time.Duration
+	// Tiers em ordem crescente de MinPoints; a primeira deve começar em zero
+	Tiers []entities.LoyaltyTier
+	// TierWindow é o período em que os pontos ganhos contam para a faixa
+	TierWindow time.Duration
+}
+
+// LoadLoyaltyPolicy lê as regras do programa das variáveis de ambiente
+func LoadLoyaltyPolicy() (LoyaltyPolicy, error) {
+	pointsPerReal, err := strconv.ParseFloat(utils.GetEnv("LOYALTY_POINTS_PER_REAL", "1"), 64)
+	if err != nil || pointsPerReal < 0 {
+		return LoyaltyPolicy{}, fmt.Errorf("LOYALTY_POINTS_PER_REAL inválido")
+	}
+
+	expiry := utils.GetEnv("LOYALTY_EXPIRY_POLICY", ExpiryPerLot)
+	if expiry != ExpiryPerLot && expiry != ExpiryInactivity && expiry != ExpiryNever {
+		return LoyaltyPolicy{}, fmt.Errorf("LOYALTY_EXPIRY_POLICY desconhecida: %s", expiry)
+	}
+
+	tiers, err := ParseLoyaltyTiers(utils.GetEnv("LOYALTY_TIERS", defaultLoyaltyTiers))
+	if err != nil {
+		return LoyaltyPolicy{}, err
+	}
+
+	return LoyaltyPolicy{
+		PointsPerReal: pointsPerReal,
+		Expiry:        expiry,
+		PointsTTL:     utils.GetEnvDuration("LOYALTY_POINTS_TTL", 365*24*time.Hour),
+		ExpiryWarning: utils.GetEnvDuration("LOYALTY_EXPIRY_WARNING", 30*24*time.Hour),
+		Tiers:         tiers,
+		TierWindow:    utils.GetEnvDuration("LOYALTY_TIER_WINDOW", 365*24*time.Hour),
+	}, nil
+}
+
+// ParseLoyaltyTiers lê faixas no formato "nome:pontos mínimos:multiplicador,..."
+func ParseLoyaltyTiers(value string) ([]entities.LoyaltyTier, error) {
+	var tiers []entities.LoyaltyTier
+
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("faixa de fidelidade inválida: %q", item)
+		}
+
+		minPoints, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || minPoints < 0 {
+			return nil, fmt.Errorf("pontos mínimos inválidos na faixa %s", parts[0])
+		}
+
+		multiplier, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || multiplier <= 0 {
+			return nil, fmt.Errorf("multiplicador inválido na faixa %s", parts[0])
+		}
+
+		tiers = append(tiers, entities.LoyaltyTier{Name: parts[0], MinPoints: minPoints, Multiplier: multiplier})
+	}
+
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinPoints < tiers[j].MinPoints })
+
+	if tiers[0].MinPoints != 0 {
+		return nil, fmt.Errorf("a primeira faixa de fidelidade deve começar em 0 pontos")
+	}
+
+	return tiers, nil
+}
+
+// Tier devolve a faixa de quem acumulou tierPoints e a próxima, se houver
+func (p LoyaltyPolicy) Tier(tierPoints int64) (entities.LoyaltyTier, *entities.LoyaltyTier) {
+	current := p.Tiers[0]
+	for i, tier := range p.Tiers {
+		if tierPoints < tier.MinPoints {
+			return current, &p.Tiers[i]
+		}
+		current = tier
+	}
+
+	return current, nil
+}
+
+// EarnPoints converte o valor pago em pontos, arredondando para baixo
+func (p LoyaltyPolicy) EarnPoints(amountCents int64, tier entities.LoyaltyTier) int64 {
+	return int64(math.Floor(float64(amountCents) / 100 * p.PointsPerReal * tier.Multiplier))
+}
+
+// creditExpiresAt é o vencimento gravado no crédito; só a política por lançamento grava
+func (p LoyaltyPolicy) creditExpiresAt(now time.Time) *time.Time {
+	if p.Expiry != ExpiryPerLot || p.PointsTTL <= 0 {
+		return nil
+	}
+
+	expiresAt := now.Add(p.PointsTTL)
+	return &expiresAt
+}
@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// posting descreve um lançamento idempotente: uma repetição com o mesmo tipo e referência
+// devolve o lançamento original se sameRequest aceitá-lo, ou ErrLoyaltyReferenceConflict
+type posting struct {
+	customerID  uint
+	kind        string
+	reference   string
+	sameRequest func(existing entities.LoyaltyTransaction) bool
+	// build monta o lançamento a partir do extrato atual
+	build func(state LoyaltyState, now time.Time) (entities.LoyaltyTransaction, error)
+}
+
+func post(ctx context.Context, ledger gateways.LoyaltyLedger, policy LoyaltyPolicy, metrics gateways.Metrics, p posting) (*dtos.LoyaltyPostingDto, error) {
+	var replayed *entities.LoyaltyTransaction
+
+	created, err := ledger.Post(ctx, p.customerID, func(history []entities.LoyaltyTransaction) ([]entities.LoyaltyTransaction, error) {
+		for _, existing := range history {
+			if existing.Type == p.kind && existing.Reference == p.reference {
+				if !p.sameRequest(existing) {
+					return nil, entities.ErrLoyaltyReferenceConflict
+				}
+
+				replayed = &existing
+				return nil, nil
+			}
+		}
+
+		now := time.Now().UTC()
+		transaction, err := p.build(policy.Replay(history, now), now)
+		if err != nil {
+			return nil, err
+		}
+
+		transaction.CustomerID = p.customerID
+		transaction.Type = p.kind
+		transaction.Reference = p.reference
+		transaction.CreatedAt = now
+
+		return []entities.LoyaltyTransaction{transaction}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := replayed
+	if transaction == nil {
+		transaction = &created[0]
+		metrics.LoyaltyPoints(transaction.Type, abs(transaction.Points))
+	}
+
+	balance, err := loadBalance(ctx, ledger, policy, p.customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.LoyaltyPostingDto{
+		Transaction: *transaction,
+		Replayed:    replayed != nil,
+		Balance:     *balance,
+	}, nil
+}
+
+func loadBalance(ctx context.Context, ledger gateways.LoyaltyLedger, policy LoyaltyPolicy, customerID uint) (*dtos.LoyaltyBalanceDto, error) {
+	history, err := ledger.History(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	state := policy.Replay(history, now)
+	tier, next := policy.Tier(state.TierPoints)
+
+	balance := &dtos.LoyaltyBalanceDto{
+		CustomerID: customerID,
+		Points:     state.Balance,
+		Tier:       tier.Name,
+		TierPoints: state.TierPoints,
+		Expiring:   []entities.ExpiringPoints{},
+	}
+
+	if next != nil {
+		balance.NextTier = next.Name
+		balance.PointsToNextTier = next.MinPoints - state.TierPoints
+	}
+
+	for _, expiring := range state.Expiring {
+		if expiring.ExpiresAt.Before(now.Add(policy.ExpiryWarning)) {
+			balance.Expiring = append(balance.Expiring, expiring)
+		}
+	}
+
+	return balance, nil
+}
+
+func abs(points int64) int64 {
+	if points < 0 {
+		return -points
+	}
+
+	return points
+}
@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryLedger struct {
+	transactions []entities.LoyaltyTransaction
+}
+
+func (m *memoryLedger) History(ctx context.Context, customerID uint) ([]entities.LoyaltyTransaction, error) {
+	history := []entities.LoyaltyTransaction{}
+	for _, transaction := range m.transactions {
+		if transaction.CustomerID == customerID {
+			history = append(history, transaction)
+		}
+	}
+	return history, nil
+}
+
+func (m *memoryLedger) List(ctx context.Context, customerID uint, afterID uint, limit int) ([]entities.LoyaltyTransaction, error) {
+	return nil, nil
+}
+
+func (m *memoryLedger) Post(ctx context.Context, customerID uint, build func(history []entities.LoyaltyTransaction) ([]entities.LoyaltyTransaction, error)) ([]entities.LoyaltyTransaction, error) {
+	history, _ := m.History(ctx, customerID)
+	transactions, err := build(history)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range transactions {
+		transactions[i].ID = uint(len(m.transactions) + 1)
+		m.transactions = append(m.transactions, transactions[i])
+	}
+	return transactions, nil
+}
+
+func (m *memoryLedger) CustomerIDs(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	ids := []uint{}
+	for _, transaction := range m.transactions {
+		if transaction.CustomerID > afterID && (len(ids) == 0 || ids[len(ids)-1] != transaction.CustomerID) && len(ids) < limit {
+			ids = append(ids, transaction.CustomerID)
+		}
+	}
+	return ids, nil
+}
+
+type loyaltyCustomerRepository struct {
+	gateways.CustomerRepository
+}
+
+func (m *loyaltyCustomerRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	if id != 7 {
+		return nil, entities.ErrCustomerNotFound
+	}
+	return &entities.Customer{ID: 7}, nil
+}
+
+// loyaltyRedirects liga ids incorporados numa fusão aos sobreviventes
+type loyaltyRedirects map[uint]uint
+
+func (r loyaltyRedirects) Resolve(ctx context.Context, id uint) (uint, error) {
+	if to, ok := r[id]; ok {
+		return to, nil
+	}
+	return id, nil
+}
+
+func (r loyaltyRedirects) ResolveMany(ctx context.Context, ids []uint) (map[uint]uint, error) {
+	result := map[uint]uint{}
+	for _, id := range ids {
+		if to, ok := r[id]; ok {
+			result[id] = to
+		}
+	}
+	return result, nil
+}
+
+// loyaltyMetrics soma os pontos lançados por tipo
+type loyaltyMetrics map[string]int64
+
+func (m loyaltyMetrics) TokenIssued(string) {}
+
+func (m loyaltyMetrics) LoyaltyPoints(transactionType string, points int64) {
+	m[transactionType] += points
+}
+
+func TestEarnAndRedeemPoints(t *testing.T) {
+	ledger := &memoryLedger{}
+	policy := testPolicy()
+	redirects := loyaltyRedirects{3: 7}
+	metrics := loyaltyMetrics{}
+	earn := EarnPointsUsecase{CustomerRepository: &loyaltyCustomerRepository{}, Redirects: redirects, Ledger: ledger, Policy: policy, Metrics: metrics, Tracer: tracing.UsecaseTracer{}}
+	redeem := RedeemPointsUsecase{Redirects: redirects, Ledger: ledger, Policy: policy, Metrics: metrics, Tracer: tracing.UsecaseTracer{}}
+	ctx := context.Background()
+
+	t.Run("credita o pedido uma única vez", func(t *testing.T) {
+		first, err := earn.Execute(ctx, 7, dtos.EarnPointsDto{Reference: "order:1", AmountCents: 9000})
+		require.NoError(t, err)
+		assert.Equal(t, int64(90), first.Transaction.Points)
+		assert.NotNil(t, first.Transaction.ExpiresAt)
+		assert.Equal(t, int64(90), first.Balance.Points)
+		assert.Equal(t, "bronze", first.Balance.Tier)
+		assert.Equal(t, int64(10), first.Balance.PointsToNextTier)
+
+		again, err := earn.Execute(ctx, 7, dtos.EarnPointsDto{Reference: "order:1", AmountCents: 9000})
+		require.NoError(t, err)
+		assert.Equal(t, first.Transaction.ID, again.Transaction.ID)
+		assert.True(t, again.Replayed)
+		assert.Len(t, ledger.transactions, 1)
+		assert.Equal(t, int64(90), metrics[entities.LoyaltyEarn])
+
+		_, err = earn.Execute(ctx, 7, dtos.EarnPointsDto{Reference: "order:1", AmountCents: 100})
+		assert.ErrorIs(t, err, entities.ErrLoyaltyReferenceConflict)
+	})
+
+	t.Run("aplica o multiplicador da faixa", func(t *testing.T) {
+		_, err := earn.Execute(ctx, 7, dtos.EarnPointsDto{Reference: "order:2", AmountCents: 1000})
+		require.NoError(t, err)
+
+		posting, err := earn.Execute(ctx, 7, dtos.EarnPointsDto{Reference: "order:3", AmountCents: 1000})
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), posting.Transaction.Points)
+		assert.Equal(t, "prata", posting.Balance.Tier)
+	})
+
+	t.Run("resgate limitado ao saldo", func(t *testing.T) {
+		_, err := redeem.Execute(ctx, 7, dtos.RedeemPointsDto{Reference: "r-1", Points: 500})
+		assert.ErrorIs(t, err, entities.ErrInsufficientPoints)
+
+		posting, err := redeem.Execute(ctx, 7, dtos.RedeemPointsDto{Reference: "r-1", Points: 100})
+		require.NoError(t, err)
+		assert.Equal(t, int64(-100), posting.Transaction.Points)
+		assert.Equal(t, int64(20), posting.Balance.Points)
+	})
+
+	t.Run("cliente incorporado numa fusão usa o saldo do sobrevivente", func(t *testing.T) {
+		earned, err := earn.Execute(ctx, 3, dtos.EarnPointsDto{Reference: "order:4", AmountCents: 1000})
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), earned.Transaction.CustomerID)
+
+		redeemed, err := redeem.Execute(ctx, 3, dtos.RedeemPointsDto{Reference: "r-2", Points: 10})
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), redeemed.Transaction.CustomerID)
+		assert.Equal(t, earned.Balance.Points-10, redeemed.Balance.Points)
+	})
+
+	t.Run("cliente inexistente", func(t *testing.T) {
+		_, err := earn.Execute(ctx, 9, dtos.EarnPointsDto{Reference: "order:9", AmountCents: 1000})
+		assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+	})
+}
+
+func TestExpirePointsUsecase_RecordsExpiredCreditsOnce(t *testing.T) {
+	past := time.Now().UTC().Add(-40 * day)
+	ledger := &memoryLedger{transactions: []entities.LoyaltyTransaction{
+		{ID: 1, CustomerID: 7, Type: entities.LoyaltyEarn, Points: 80, Reference: "order:1", CreatedAt: past, ExpiresAt: at(past.Add(30 * day))},
+		{ID: 2, CustomerID: 7, Type: entities.LoyaltyRedeem, Points: -30, Reference: "r-1", CreatedAt: past.Add(day)},
+		{ID: 3, CustomerID: 8, Type: entities.LoyaltyEarn, Points: 10, Reference: "order:2", CreatedAt: past},
+	}}
+	metrics := loyaltyMetrics{}
+	usecase := ExpirePointsUsecase{Ledger: ledger, Policy: testPolicy(), Metrics: metrics, BatchSize: 1, Tracer: tracing.UsecaseTracer{}}
+
+	expired, err := usecase.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), expired)
+	assert.Equal(t, int64(50), metrics[entities.LoyaltyExpire])
+
+	last := ledger.transactions[len(ledger.transactions)-1]
+	assert.Equal(t, entities.LoyaltyExpire, last.Type)
+	assert.Equal(t, int64(-50), last.Points)
+	assert.Equal(t, uint(1), *last.LotID)
+
+	expired, err = usecase.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, expired)
+}
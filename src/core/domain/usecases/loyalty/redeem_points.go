@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RedeemPointsUsecase debita pontos do saldo; pontos vencidos não podem ser resgatados
+type RedeemPointsUsecase struct {
+	Redirects gateways.CustomerRedirects
+	Ledger    gateways.LoyaltyLedger
+	Policy    LoyaltyPolicy
+	Metrics   gateways.Metrics
+	Tracer    gateways.Tracer
+}
+
+func (r *RedeemPointsUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.RedeemPointsDto) (_ *dtos.LoyaltyPostingDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "RedeemPointsUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	// o saldo de um cliente incorporado numa fusão foi levado para o sobrevivente
+	customerID, err = r.Redirects.Resolve(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return post(ctx, r.Ledger, r.Policy, r.Metrics, posting{
+		customerID: customerID,
+		kind:       entities.LoyaltyRedeem,
+		reference:  inputDto.Reference,
+		sameRequest: func(existing entities.LoyaltyTransaction) bool {
+			return existing.Points == -inputDto.Points
+		},
+		build: func(state LoyaltyState, _ time.Time) (entities.LoyaltyTransaction, error) {
+			if state.Balance < inputDto.Points {
+				return entities.LoyaltyTransaction{}, entities.ErrInsufficientPoints
+			}
+
+			return entities.LoyaltyTransaction{
+				Points:      -inputDto.Points,
+				Description: inputDto.Description,
+			}, nil
+		},
+	})
+}
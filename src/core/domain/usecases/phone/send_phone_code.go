@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	DefaultCodeTTL        = 10 * time.Minute
+	DefaultResendInterval = time.Minute
+	DefaultMaxAttempts    = 5
+	DefaultDailySendLimit = 5
+)
+
+// SendPhoneCodeUsecase envia por SMS o código que verifica o telefone do cliente.
+// Um novo pedido substitui o código anterior, mas só depois de ResendInterval, e o cliente recebe
+// no máximo DailySendLimit códigos em 24 horas. Os dois limites são do cliente, não do telefone:
+// trocar o número não libera mais SMS. Sem SMSSender (SMS desligado) devolve ErrSMSUnavailable.
+type SendPhoneCodeUsecase struct {
+	CustomerRepository          gateways.CustomerRepository
+	PhoneVerificationRepository gateways.PhoneVerificationRepository
+	SMSSender                   gateways.SMSSender
+	CodeTTL                     time.Duration
+	ResendInterval              time.Duration
+	DailySendLimit              int
+	Tracer                      gateways.Tracer
+}
+
+func (r *SendPhoneCodeUsecase) Execute(ctx context.Context, customerID uint) (_ *dtos.PhoneCodeSentDto, err error) {
+	ctx, span := r.Tracer.Start(ctx, "SendPhoneCodeUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	if r.SMSSender == nil {
+		return nil, entities.ErrSMSUnavailable
+	}
+
+	customer, err := r.CustomerRepository.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.Phone == nil {
+		return nil, entities.ErrPhoneNotFound
+	}
+
+	if customer.Phone.Verified {
+		return nil, entities.ErrPhoneAlreadyVerified
+	}
+
+	now := time.Now()
+	resendInterval := durationOrDefault(r.ResendInterval, DefaultResendInterval)
+
+	pending, err := r.PhoneVerificationRepository.Find(ctx, customerID)
+	switch {
+	case err == nil:
+		if now.Before(pending.SentAt.Add(resendInterval)) {
+			return nil, entities.ErrPhoneCodeResendTooSoon
+		}
+	case !errors.Is(err, entities.ErrPhoneVerificationNotFound):
+		return nil, err
+	}
+
+	sent, err := r.PhoneVerificationRepository.CountSent(ctx, customerID, now.Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	dailySendLimit := r.DailySendLimit
+	if dailySendLimit <= 0 {
+		dailySendLimit = DefaultDailySendLimit
+	}
+
+	if sent >= dailySendLimit {
+		return nil, entities.ErrPhoneCodeDailyLimit
+	}
+
+	code, err := newCode()
+	if err != nil {
+		return nil, err
+	}
+
+	codeTTL := durationOrDefault(r.CodeTTL, DefaultCodeTTL)
+	verification := entities.PhoneVerification{
+		CustomerID: customerID,
+		Phone:      customer.Phone.Number,
+		SentAt:     now,
+		ExpiresAt:  now.Add(codeTTL),
+	}
+
+	if err := r.PhoneVerificationRepository.Save(ctx, verification, code); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Seu código de verificação é %s. Ele vale por %d minutos; não o compartilhe.", code, int(codeTTL.Minutes()))
+	if err := r.SMSSender.Send(ctx, customer.Phone.Number, message); err != nil {
+		return nil, err
+	}
+
+	return &dtos.PhoneCodeSentDto{
+		ExpiresAt: verification.ExpiresAt,
+		ResendAt:  now.Add(resendInterval),
+	}, nil
+}
+
+// newCode sorteia o código de seis dígitos com crypto/rand
+func newCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func durationOrDefault(value time.Duration, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,207 @@
+package usecases
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryCustomerRepository struct {
+	gateways.CustomerRepository
+	customers map[uint]entities.Customer
+}
+
+func (m *memoryCustomerRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	customer, ok := m.customers[id]
+	if !ok {
+		return nil, entities.ErrCustomerNotFound
+	}
+	if customer.Phone != nil {
+		phone := *customer.Phone
+		customer.Phone = &phone
+	}
+	return &customer, nil
+}
+
+func (m *memoryCustomerRepository) FindByPhone(ctx context.Context, phone string) (*entities.Customer, error) {
+	for _, customer := range m.customers {
+		if customer.Phone != nil && customer.Phone.Verified && customer.Phone.Number == phone {
+			return &customer, nil
+		}
+	}
+	return nil, entities.ErrCustomerNotFound
+}
+
+func (m *memoryCustomerRepository) Update(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	m.customers[customer.ID] = *customer
+	return customer, nil
+}
+
+// memoryPhoneVerifications guarda o código em texto puro, o que basta para os testes
+type memoryPhoneVerifications struct {
+	verifications map[uint]entities.PhoneVerification
+	codes         map[uint]string
+	sent          map[uint][]time.Time
+}
+
+func (m *memoryPhoneVerifications) Find(ctx context.Context, customerID uint) (*entities.PhoneVerification, error) {
+	verification, ok := m.verifications[customerID]
+	if !ok {
+		return nil, entities.ErrPhoneVerificationNotFound
+	}
+	return &verification, nil
+}
+
+func (m *memoryPhoneVerifications) Save(ctx context.Context, verification entities.PhoneVerification, code string) error {
+	if m.verifications == nil {
+		m.verifications, m.codes, m.sent = map[uint]entities.PhoneVerification{}, map[uint]string{}, map[uint][]time.Time{}
+	}
+	m.verifications[verification.CustomerID] = verification
+	m.codes[verification.CustomerID] = code
+	m.sent[verification.CustomerID] = append(m.sent[verification.CustomerID], verification.SentAt)
+	return nil
+}
+
+func (m *memoryPhoneVerifications) CountSent(ctx context.Context, customerID uint, since time.Time) (int, error) {
+	count := 0
+	for _, sentAt := range m.sent[customerID] {
+		if !sentAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryPhoneVerifications) Consume(ctx context.Context, customerID uint, phone string, code string, maxAttempts int, now time.Time) error {
+	verification, ok := m.verifications[customerID]
+	switch {
+	case !ok || verification.Phone != phone:
+		return entities.ErrPhoneVerificationNotFound
+	case !now.Before(verification.ExpiresAt) || verification.Attempts >= maxAttempts:
+		delete(m.verifications, customerID)
+		return entities.ErrPhoneCodeExpired
+	case m.codes[customerID] != code:
+		verification.Attempts++
+		m.verifications[customerID] = verification
+		return entities.ErrInvalidPhoneCode
+	}
+	delete(m.verifications, customerID)
+	return nil
+}
+
+type recordingSMSSender struct {
+	phones   []string
+	messages []string
+}
+
+func (s *recordingSMSSender) Send(ctx context.Context, phone string, message string) error {
+	s.phones = append(s.phones, phone)
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func newPhoneFixture(phone *entities.CustomerPhone) (*memoryCustomerRepository, *memoryPhoneVerifications, *recordingSMSSender) {
+	customers := &memoryCustomerRepository{customers: map[uint]entities.Customer{
+		7: {ID: 7, Name: "João da Silva", CPF: "12345678900", Phone: phone},
+	}}
+
+	return customers, &memoryPhoneVerifications{}, &recordingSMSSender{}
+}
+
+func TestSendPhoneCodeUsecase_Execute(t *testing.T) {
+	customers, verifications, sender := newPhoneFixture(&entities.CustomerPhone{Number: "+5511987654321"})
+	usecase := SendPhoneCodeUsecase{CustomerRepository: customers, PhoneVerificationRepository: verifications, SMSSender: sender, Tracer: tracing.UsecaseTracer{}}
+
+	result, err := usecase.Execute(context.Background(), 7)
+	require.NoError(t, err)
+
+	code := verifications.codes[7]
+	assert.Regexp(t, regexp.MustCompile(`^[0-9]{6}$`), code)
+	assert.Equal(t, []string{"+5511987654321"}, sender.phones)
+	assert.Contains(t, sender.messages[0], code)
+	assert.WithinDuration(t, time.Now().Add(DefaultCodeTTL), result.ExpiresAt, time.Second)
+	assert.WithinDuration(t, time.Now().Add(DefaultResendInterval), result.ResendAt, time.Second)
+
+	t.Run("reenvio antes do intervalo", func(t *testing.T) {
+		_, err := usecase.Execute(context.Background(), 7)
+		assert.ErrorIs(t, err, entities.ErrPhoneCodeResendTooSoon)
+		assert.Len(t, sender.messages, 1)
+	})
+
+	t.Run("troca do telefone não libera o reenvio", func(t *testing.T) {
+		customers.customers[7] = entities.Customer{ID: 7, Phone: &entities.CustomerPhone{Number: "+5521987654321"}}
+
+		_, err := usecase.Execute(context.Background(), 7)
+		assert.ErrorIs(t, err, entities.ErrPhoneCodeResendTooSoon)
+		assert.Len(t, sender.messages, 1)
+	})
+
+	t.Run("reenvio depois do intervalo", func(t *testing.T) {
+		pending := verifications.verifications[7]
+		pending.SentAt = pending.SentAt.Add(-DefaultResendInterval)
+		verifications.verifications[7] = pending
+
+		_, err := usecase.Execute(context.Background(), 7)
+		require.NoError(t, err)
+		assert.Equal(t, "+5521987654321", sender.phones[1])
+	})
+}
+
+func TestSendPhoneCodeUsecase_DailyLimitAcrossPhones(t *testing.T) {
+	customers, verifications, sender := newPhoneFixture(&entities.CustomerPhone{Number: "+5511987654321"})
+	usecase := SendPhoneCodeUsecase{CustomerRepository: customers, PhoneVerificationRepository: verifications, SMSSender: sender, DailySendLimit: 2, Tracer: tracing.UsecaseTracer{}}
+
+	send := func(phone string) error {
+		customers.customers[7] = entities.Customer{ID: 7, Phone: &entities.CustomerPhone{Number: phone}}
+		// o intervalo de reenvio já passou
+		if pending, ok := verifications.verifications[7]; ok {
+			pending.SentAt = pending.SentAt.Add(-DefaultResendInterval)
+			verifications.verifications[7] = pending
+		}
+
+		_, err := usecase.Execute(context.Background(), 7)
+		return err
+	}
+
+	require.NoError(t, send("+5511987654321"))
+	require.NoError(t, send("+5521987654321"))
+	assert.ErrorIs(t, send("+5531987654321"), entities.ErrPhoneCodeDailyLimit)
+	assert.Equal(t, []string{"+5511987654321", "+5521987654321"}, sender.phones)
+}
+
+func TestSendPhoneCodeUsecase_WithoutSMS(t *testing.T) {
+	customers, verifications, _ := newPhoneFixture(&entities.CustomerPhone{Number: "+5511987654321"})
+	usecase := SendPhoneCodeUsecase{CustomerRepository: customers, PhoneVerificationRepository: verifications, Tracer: tracing.UsecaseTracer{}}
+
+	_, err := usecase.Execute(context.Background(), 7)
+	assert.ErrorIs(t, err, entities.ErrSMSUnavailable)
+	assert.Empty(t, verifications.verifications)
+}
+
+func TestSendPhoneCodeUsecase_Errors(t *testing.T) {
+	tests := map[string]struct {
+		phone *entities.CustomerPhone
+		err   error
+	}{
+		"sem telefone":           {nil, entities.ErrPhoneNotFound},
+		"telefone já verificado": {&entities.CustomerPhone{Number: "+5511987654321", Verified: true}, entities.ErrPhoneAlreadyVerified},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			customers, verifications, sender := newPhoneFixture(test.phone)
+			usecase := SendPhoneCodeUsecase{CustomerRepository: customers, PhoneVerificationRepository: verifications, SMSSender: sender, Tracer: tracing.UsecaseTracer{}}
+
+			_, err := usecase.Execute(context.Background(), 7)
+			assert.ErrorIs(t, err, test.err)
+			assert.Empty(t, sender.messages)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// VerifyPhoneUsecase confere o código enviado por SMS e marca o telefone como verificado.
+// Um telefone só pode estar verificado em um cliente.
+type VerifyPhoneUsecase struct {
+	CustomerRepository          gateways.CustomerRepository
+	PhoneVerificationRepository gateways.PhoneVerificationRepository
+	MaxAttempts                 int
+	Tracer                      gateways.Tracer
+}
+
+func (r *VerifyPhoneUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.VerifyPhoneDto) (_ *entities.Customer, err error) {
+	ctx, span := r.Tracer.Start(ctx, "VerifyPhoneUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	customer, err := r.CustomerRepository.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.Phone == nil {
+		return nil, entities.ErrPhoneNotFound
+	}
+
+	if customer.Phone.Verified {
+		return nil, entities.ErrPhoneAlreadyVerified
+	}
+
+	owner, err := r.CustomerRepository.FindByPhone(ctx, customer.Phone.Number)
+	switch {
+	case err == nil && owner.ID != customerID:
+		return nil, entities.ErrPhoneAlreadyInUse
+	case err != nil && !errors.Is(err, entities.ErrCustomerNotFound):
+		return nil, err
+	}
+
+	maxAttempts := r.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	err = r.PhoneVerificationRepository.Consume(ctx, customerID, customer.Phone.Number, inputDto.Code, maxAttempts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	customer.Phone.Verified = true
+
+	return r.CustomerRepository.Update(ctx, customer)
+}
@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyPhoneUsecase_Execute(t *testing.T) {
+	customers, verifications, sender := newPhoneFixture(&entities.CustomerPhone{Number: "+5511987654321"})
+	send := SendPhoneCodeUsecase{CustomerRepository: customers, PhoneVerificationRepository: verifications, SMSSender: sender, Tracer: tracing.UsecaseTracer{}}
+	verify := VerifyPhoneUsecase{CustomerRepository: customers, PhoneVerificationRepository: verifications, MaxAttempts: 2, Tracer: tracing.UsecaseTracer{}}
+
+	_, err := send.Execute(context.Background(), 7)
+	require.NoError(t, err)
+
+	_, err = verify.Execute(context.Background(), 7, dtos.VerifyPhoneDto{Code: wrongCode(verifications.codes[7])})
+	assert.ErrorIs(t, err, entities.ErrInvalidPhoneCode)
+
+	customer, err := verify.Execute(context.Background(), 7, dtos.VerifyPhoneDto{Code: verifications.codes[7]})
+	require.NoError(t, err)
+	assert.True(t, customer.Phone.Verified)
+	assert.True(t, customers.customers[7].Phone.Verified)
+
+	_, err = verify.Execute(context.Background(), 7, dtos.VerifyPhoneDto{Code: verifications.codes[7]})
+	assert.ErrorIs(t, err, entities.ErrPhoneAlreadyVerified)
+}
+
+func TestVerifyPhoneUsecase_AttemptsExhausted(t *testing.T) {
+	customers, verifications, sender := newPhoneFixture(&entities.CustomerPhone{Number: "+5511987654321"})
+	send := SendPhoneCodeUsecase{CustomerRepository: customers, PhoneVerificationRepository: verifications, SMSSender: sender, Tracer: tracing.UsecaseTracer{}}
+	verify := VerifyPhoneUsecase{CustomerRepository: customers, PhoneVerificationRepository: verifications, MaxAttempts: 2, Tracer: tracing.UsecaseTracer{}}
+
+	_, err := send.Execute(context.Background(), 7)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = verify.Execute(context.Background(), 7, dtos.VerifyPhoneDto{Code: wrongCode(verifications.codes[7])})
+		assert.ErrorIs(t, err, entities.ErrInvalidPhoneCode)
+	}
+
+	// mesmo o código certo não vale depois de esgotadas as tentativas
+	_, err = verify.Execute(context.Background(), 7, dtos.VerifyPhoneDto{Code: verifications.codes[7]})
+	assert.ErrorIs(t, err, entities.ErrPhoneCodeExpired)
+	assert.False(t, customers.customers[7].Phone.Verified)
+}
+
+func TestVerifyPhoneUsecase_PhoneVerifiedByAnotherCustomer(t *testing.T) {
+	customers, verifications, _ := newPhoneFixture(&entities.CustomerPhone{Number: "+5511987654321"})
+	customers.customers[8] = entities.Customer{ID: 8, Phone: &entities.CustomerPhone{Number: "+5511987654321", Verified: true}}
+	require.NoError(t, verifications.Save(context.Background(), entities.PhoneVerification{
+		CustomerID: 7, Phone: "+5511987654321", ExpiresAt: time.Now().Add(time.Minute),
+	}, "123456"))
+
+	verify := VerifyPhoneUsecase{CustomerRepository: customers, PhoneVerificationRepository: verifications, Tracer: tracing.UsecaseTracer{}}
+
+	_, err := verify.Execute(context.Background(), 7, dtos.VerifyPhoneDto{Code: "123456"})
+	assert.ErrorIs(t, err, entities.ErrPhoneAlreadyInUse)
+}
+
+// wrongCode devolve um código de seis dígitos diferente do enviado
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	customerusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetPreferencesUsecase lê as preferências alimentares. Por serem dados de saúde,
+// toda leitura, do próprio cliente ou de um serviço, entra na auditoria.
+type GetPreferencesUsecase struct {
+	CustomerRepository    gateways.CustomerRepository
+	PreferencesRepository gateways.PreferencesRepository
+	AuditLog              gateways.AuditLog
+	Redirects             gateways.CustomerRedirects
+	Tracer                gateways.Tracer
+}
+
+func (r *GetPreferencesUsecase) Execute(ctx context.Context, customerID uint) (_ *entities.DietaryPreferences, err error) {
+	ctx, span := r.Tracer.Start(ctx, "GetPreferencesUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	customer, err := customerusecases.FindCustomer(ctx, r.CustomerRepository, r.Redirects, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := r.PreferencesRepository.Find(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.AuditLog.Record(ctx, entities.CustomerAuditEntry(entities.AuditActionPreferencesRead, customer.ID)); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
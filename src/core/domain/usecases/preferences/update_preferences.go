@@ -0,0 +1,29 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type UpdatePreferencesUsecase struct {
+	PreferencesRepository gateways.PreferencesRepository
+	Tracer                gateways.Tracer
+}
+
+func (r *UpdatePreferencesUsecase) Execute(ctx context.Context, customerID uint, inputDto dtos.UpdatePreferencesDto) (_ *entities.DietaryPreferences, err error) {
+	ctx, span := r.Tracer.Start(ctx, "UpdatePreferencesUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	span.SetAttributes(attribute.Int("customer.id", int(customerID)))
+
+	preferences, err := entities.NewDietaryPreferences(customerID, inputDto.Allergens, inputDto.DietaryStyles, inputDto.DislikedIngredients)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.PreferencesRepository.Save(ctx, preferences)
+}
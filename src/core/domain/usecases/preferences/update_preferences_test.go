@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPreferencesRepository struct {
+	gateways.PreferencesRepository
+	saved *entities.DietaryPreferences
+}
+
+func (m *mockPreferencesRepository) Save(ctx context.Context, preferences entities.DietaryPreferences) (*entities.DietaryPreferences, error) {
+	m.saved = &preferences
+	return &preferences, nil
+}
+
+func TestUpdatePreferencesUsecase_Normalizes(t *testing.T) {
+	repo := &mockPreferencesRepository{}
+	usecase := UpdatePreferencesUsecase{PreferencesRepository: repo, Tracer: tracing.UsecaseTracer{}}
+
+	result, err := usecase.Execute(context.Background(), 7, dtos.UpdatePreferencesDto{
+		Allergens:           []string{"sesame", "peanuts", "sesame"},
+		DietaryStyles:       []string{"vegan"},
+		DislikedIngredients: []string{"  Cebola   Roxa ", "coentro", "COENTRO"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), result.CustomerID)
+	assert.Equal(t, []string{"peanuts", "sesame"}, result.Allergens)
+	assert.Equal(t, []string{"cebola roxa", "coentro"}, result.DislikedIngredients)
+
+	// listas omitidas apagam a preferência e nunca voltam nulas
+	result, err = usecase.Execute(context.Background(), 7, dtos.UpdatePreferencesDto{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, result.Allergens)
+	assert.Equal(t, "a=;d=;i=", result.Canonical())
+}
+
+func TestUpdatePreferencesUsecase_RejectsUnknownCodes(t *testing.T) {
+	repo := &mockPreferencesRepository{}
+	usecase := UpdatePreferencesUsecase{PreferencesRepository: repo, Tracer: tracing.UsecaseTracer{}}
+
+	tests := map[string]dtos.UpdatePreferencesDto{
+		"alérgeno fora do vocabulário": {Allergens: []string{"Peanuts"}},
+		"estilo fora do vocabulário":   {DietaryStyles: []string{"keto"}},
+		"ingrediente vazio":            {DislikedIngredients: []string{"   "}},
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := usecase.Execute(context.Background(), 7, input)
+			assert.ErrorIs(t, err, entities.ErrInvalidPreferences)
+		})
+	}
+
+	assert.Nil(t, repo.saved)
+}
@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+)
+
+const minSecretLength = 16
+
+type CreateSubscriptionUsecase struct {
+	WebhookRepository gateways.WebhookRepository
+	// AllowInsecureURL aceita http:// (apenas fora de produção)
+	AllowInsecureURL bool
+	Tracer           gateways.Tracer
+}
+
+// Execute registra a assinatura. Sem segredo informado um é gerado; ele só é devolvido nesta resposta.
+func (r *CreateSubscriptionUsecase) Execute(ctx context.Context, inputDto dtos.CreateWebhookSubscriptionDto) (_ *entities.WebhookSubscription, err error) {
+	ctx, span := r.Tracer.Start(ctx, "CreateSubscriptionUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	if !r.validURL(inputDto.URL) {
+		return nil, entities.ErrInvalidWebhookURL
+	}
+
+	for _, eventType := range inputDto.EventTypes {
+		if !slices.Contains(events.CustomerEventTypes, eventType) {
+			return nil, entities.ErrInvalidEventType
+		}
+	}
+
+	secret := inputDto.Secret
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			return nil, err
+		}
+	} else if len(secret) < minSecretLength {
+		return nil, entities.ErrWeakWebhookSecret
+	}
+
+	subscription := entities.WebhookSubscription{
+		URL:        inputDto.URL,
+		EventTypes: uniqueEventTypes(inputDto.EventTypes),
+		Secret:     secret,
+		Active:     true,
+	}
+
+	return r.WebhookRepository.CreateSubscription(ctx, &subscription)
+}
+
+func (r *CreateSubscriptionUsecase) validURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+
+	return parsed.Scheme == "https" || (r.AllowInsecureURL && parsed.Scheme == "http")
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+func uniqueEventTypes(eventTypes []string) []string {
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(result, eventType) {
+			result = append(result, eventType)
+		}
+	}
+
+	return result
+}
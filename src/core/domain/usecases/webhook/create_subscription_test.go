@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWebhookRepository struct {
+	gateways.WebhookRepository
+	created *entities.WebhookSubscription
+}
+
+func (m *mockWebhookRepository) CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	m.created = subscription
+	subscription.ID = 1
+	return subscription, nil
+}
+
+func TestCreateSubscriptionUsecase_Execute(t *testing.T) {
+	t.Run("gera segredo quando não informado", func(t *testing.T) {
+		mockRepo := &mockWebhookRepository{}
+		usecase := CreateSubscriptionUsecase{WebhookRepository: mockRepo, Tracer: tracing.UsecaseTracer{}}
+
+		result, err := usecase.Execute(context.Background(), dtos.CreateWebhookSubscriptionDto{
+			URL:        "https://crm.example.com/hooks",
+			EventTypes: []string{events.CustomerCreated, events.CustomerCreated, events.CustomerErased},
+		})
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result.Secret, "whsec_"))
+		assert.Equal(t, []string{events.CustomerCreated, events.CustomerErased}, mockRepo.created.EventTypes)
+		assert.True(t, result.Active)
+	})
+
+	t.Run("recusa http em produção", func(t *testing.T) {
+		usecase := CreateSubscriptionUsecase{WebhookRepository: &mockWebhookRepository{}, Tracer: tracing.UsecaseTracer{}}
+
+		_, err := usecase.Execute(context.Background(), dtos.CreateWebhookSubscriptionDto{
+			URL:        "http://crm.example.com/hooks",
+			EventTypes: []string{events.CustomerCreated},
+		})
+		assert.ErrorIs(t, err, entities.ErrInvalidWebhookURL)
+	})
+
+	t.Run("recusa tipo de evento desconhecido", func(t *testing.T) {
+		usecase := CreateSubscriptionUsecase{WebhookRepository: &mockWebhookRepository{}, Tracer: tracing.UsecaseTracer{}}
+
+		_, err := usecase.Execute(context.Background(), dtos.CreateWebhookSubscriptionDto{
+			URL:        "https://crm.example.com/hooks",
+			EventTypes: []string{"OrderPaid"},
+		})
+		assert.ErrorIs(t, err, entities.ErrInvalidEventType)
+	})
+
+	t.Run("recusa segredo curto", func(t *testing.T) {
+		usecase := CreateSubscriptionUsecase{WebhookRepository: &mockWebhookRepository{}, Tracer: tracing.UsecaseTracer{}}
+
+		_, err := usecase.Execute(context.Background(), dtos.CreateWebhookSubscriptionDto{
+			URL:        "https://crm.example.com/hooks",
+			EventTypes: []string{events.CustomerCreated},
+			Secret:     "curto",
+		})
+		assert.ErrorIs(t, err, entities.ErrWeakWebhookSecret)
+	})
+}
@@ -0,0 +1,19 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+)
+
+type DeleteSubscriptionUsecase struct {
+	WebhookRepository gateways.WebhookRepository
+	Tracer            gateways.Tracer
+}
+
+func (r *DeleteSubscriptionUsecase) Execute(ctx context.Context, subscriptionID uint) (err error) {
+	ctx, span := r.Tracer.Start(ctx, "DeleteSubscriptionUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	return r.WebhookRepository.DeleteSubscription(ctx, subscriptionID)
+}
@@ -0,0 +1,29 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+const defaultDeliveriesLimit = 100
+
+type ListDeliveriesUsecase struct {
+	WebhookRepository gateways.WebhookRepository
+	Tracer            gateways.Tracer
+}
+
+// Execute devolve o log de entregas da assinatura, das mais recentes para as mais antigas
+func (r *ListDeliveriesUsecase) Execute(ctx context.Context, subscriptionID uint, inputDto dtos.ListWebhookDeliveriesDto) (_ []entities.WebhookDelivery, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ListDeliveriesUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	limit := inputDto.Limit
+	if limit == 0 {
+		limit = defaultDeliveriesLimit
+	}
+
+	return r.WebhookRepository.ListDeliveries(ctx, subscriptionID, inputDto.Status, limit)
+}
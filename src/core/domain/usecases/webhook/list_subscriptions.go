@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type ListSubscriptionsUsecase struct {
+	WebhookRepository gateways.WebhookRepository
+	Tracer            gateways.Tracer
+}
+
+func (r *ListSubscriptionsUsecase) Execute(ctx context.Context) (_ []entities.WebhookSubscription, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ListSubscriptionsUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	subscriptions, err := r.WebhookRepository.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// o segredo só é exibido na criação
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return subscriptions, nil
+}
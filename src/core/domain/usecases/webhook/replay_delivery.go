@@ -0,0 +1,22 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+type ReplayDeliveryUsecase struct {
+	WebhookRepository gateways.WebhookRepository
+	Tracer            gateways.Tracer
+}
+
+// Execute recoloca a entrega na fila, inclusive as que foram para a dead-letter ou já tiveram sucesso.
+// O histórico de tentativas é mantido.
+func (r *ReplayDeliveryUsecase) Execute(ctx context.Context, subscriptionID uint, deliveryID uint) (_ *entities.WebhookDelivery, err error) {
+	ctx, span := r.Tracer.Start(ctx, "ReplayDeliveryUsecase.Execute")
+	defer func() { span.End(err) }()
+
+	return r.WebhookRepository.ReplayDelivery(ctx, subscriptionID, deliveryID)
+}
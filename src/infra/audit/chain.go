@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// Hash calcula o hash da entrada a partir do hash anterior e dos campos gravados.
+// A sequência fica de fora: é atribuída pelo banco, e a ordem já é garantida pelo PrevHash.
+func Hash(entry entities.AuditEntry) string {
+	canonical, _ := json.Marshal([]string{
+		entry.PrevHash,
+		entry.OccurredAt.UTC().Format(time.RFC3339Nano),
+		entry.Actor,
+		entry.Role,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		entry.IP,
+		entry.RequestID,
+	})
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// Chain encadeia as entradas a partir do hash da última entrada gravada
+func Chain(prevHash string, entries []entities.AuditEntry) []entities.AuditEntry {
+	chained := make([]entities.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.PrevHash = prevHash
+		entry.Hash = Hash(entry)
+		prevHash = entry.Hash
+		chained = append(chained, entry)
+	}
+
+	return chained
+}
+
+// EntrySource lê o log completo em ordem de sequência
+type EntrySource interface {
+	ListAll(ctx context.Context, afterSequence uint64, limit int) ([]entities.AuditEntry, error)
+}
+
+type VerifyResult struct {
+	Checked uint64
+	// HeadSequence e HeadHash identificam a última entrada; guardados fora do banco,
+	// permitem detectar também a remoção das entradas mais recentes
+	HeadSequence uint64
+	HeadHash     string
+	// BrokenAt é a sequência da primeira entrada inválida, zero se a cadeia estiver íntegra
+	BrokenAt uint64
+}
+
+// Verify percorre o log recalculando os hashes e conferindo o encadeamento
+func Verify(ctx context.Context, source EntrySource, batchSize int) (VerifyResult, error) {
+	var result VerifyResult
+	var after uint64
+	prevHash := ""
+
+	for {
+		entries, err := source.ListAll(ctx, after, batchSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, entry := range entries {
+			if entry.PrevHash != prevHash || Hash(entry) != entry.Hash {
+				result.BrokenAt = entry.Sequence
+				return result, nil
+			}
+
+			prevHash = entry.Hash
+			after = entry.Sequence
+			result.Checked++
+			result.HeadSequence = entry.Sequence
+			result.HeadHash = entry.Hash
+		}
+
+		if len(entries) < batchSize {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sliceSource []entities.AuditEntry
+
+func (s sliceSource) ListAll(_ context.Context, afterSequence uint64, limit int) ([]entities.AuditEntry, error) {
+	result := []entities.AuditEntry{}
+	for _, entry := range s {
+		if entry.Sequence > afterSequence && len(result) < limit {
+			result = append(result, entry)
+		}
+	}
+
+	return result, nil
+}
+
+func newChain() sliceSource {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	entries := Chain("", []entities.AuditEntry{
+		{OccurredAt: now, Actor: "anonymous", Role: "public", Action: entities.AuditActionCustomerCreated, TargetType: "customer", TargetID: "1"},
+		{OccurredAt: now, Actor: "anonymous", Role: "public", Action: entities.AuditActionCPFLookup, TargetType: "customer", TargetID: "1"},
+		{OccurredAt: now, Actor: "ops", Role: "admin", Action: entities.AuditActionCustomerRead, TargetType: "customer", TargetID: "1"},
+	})
+
+	for i := range entries {
+		entries[i].Sequence = uint64(i + 1)
+	}
+
+	return entries
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cadeia íntegra", func(t *testing.T) {
+		chain := newChain()
+
+		result, err := Verify(ctx, chain, 2)
+		require.NoError(t, err)
+		assert.Zero(t, result.BrokenAt)
+		assert.Equal(t, uint64(3), result.Checked)
+		assert.Equal(t, chain[2].Hash, result.HeadHash)
+	})
+
+	t.Run("entrada alterada", func(t *testing.T) {
+		chain := newChain()
+		chain[1].Actor = "outro"
+
+		result, err := Verify(ctx, chain, 2)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), result.BrokenAt)
+	})
+
+	t.Run("entrada removida", func(t *testing.T) {
+		chain := newChain()
+		chain = append(chain[:1], chain[2:]...)
+
+		result, err := Verify(ctx, chain, 10)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), result.BrokenAt)
+	})
+}
+
+func TestStamp(t *testing.T) {
+	ctx := WithActor(context.Background(), CustomerActor(7))
+	ctx = WithClientIP(ctx, "10.0.0.1")
+
+	entry := Stamp(ctx, entities.CustomerAuditEntry(entities.AuditActionCustomerRead, 7))
+	assert.Equal(t, "customer:7", entry.Actor)
+	assert.Equal(t, "customer", entry.Role)
+	assert.Equal(t, "10.0.0.1", entry.IP)
+	assert.Equal(t, "7", entry.TargetID)
+	assert.Zero(t, entry.OccurredAt.Nanosecond()%1000)
+
+	anonymous := Stamp(context.Background(), entities.CustomerAuditEntry(entities.AuditActionSessionIssued, 0))
+	assert.Equal(t, AnonymousActor.ID, anonymous.Actor)
+	assert.Empty(t, anonymous.TargetID)
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/auth"
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+)
+
+// Actor identifica quem fez a requisição
+type Actor struct {
+	ID   string
+	Role string
+}
+
+// AnonymousActor é usado quando a requisição não traz credencial
+var AnonymousActor = Actor{ID: "anonymous", Role: auth.RolePublic}
+
+type actorKey struct{}
+
+type clientIPKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func CustomerActor(customerID uint) Actor {
+	return Actor{ID: "customer:" + strconv.FormatUint(uint64(customerID), 10), Role: auth.RoleCustomer}
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+
+	return AnonymousActor
+}
+
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// Stamp preenche com os dados da requisição os campos que vierem vazios.
+// O horário é truncado em microssegundos, a precisão do Postgres, para o hash continuar válido após a leitura.
+func Stamp(ctx context.Context, entry entities.AuditEntry) entities.AuditEntry {
+	actor := ActorFromContext(ctx)
+	if entry.Actor == "" {
+		entry.Actor = actor.ID
+	}
+	if entry.Role == "" {
+		entry.Role = actor.Role
+	}
+	if entry.IP == "" {
+		entry.IP = ClientIP(ctx)
+	}
+	if entry.RequestID == "" {
+		entry.RequestID = logging.RequestID(ctx)
+	}
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = time.Now()
+	}
+	entry.OccurredAt = entry.OccurredAt.UTC().Truncate(time.Microsecond)
+
+	return entry
+}
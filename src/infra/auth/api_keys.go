@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"strings"
+)
+
+const (
+	RoleAdmin   = "admin"
+	RoleService = "service"
+	// papéis sem chave de API: cliente autenticado pelo token de sessão e chamador anônimo
+	RoleCustomer = "customer"
+	RolePublic   = "public"
+)
+
+// APIKeys associa a chave de API ao ator que a usa
+type APIKeys map[string]string
+
+// ParseAPIKeys lê chaves no formato "ator:chave,ator2:chave2"
+func ParseAPIKeys(value string) APIKeys {
+	keys := APIKeys{}
+
+	for _, pair := range strings.Split(value, ",") {
+		actor, key, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if found && actor != "" && key != "" {
+			keys[key] = actor
+		}
+	}
+
+	return keys
+}
+
+// Lookup compara a chave em tempo constante e devolve o ator dono dela
+func (k APIKeys) Lookup(candidate string) (string, bool) {
+	if candidate == "" {
+		return "", false
+	}
+
+	for key, actor := range k {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(candidate)) == 1 {
+			return actor, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,267 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"golang.org/x/sync/singleflight"
+)
+
+// propósitos dos índices cegos das chaves, diferentes dos índices das colunas para que uma chave do
+// cache não possa ser cruzada com o banco
+const (
+	cpfKeyPurpose      = "customers.cache_cpf"
+	documentKeyPurpose = "customers.cache_document"
+)
+
+// cachedCustomer é o valor guardado; Customer nulo é um cache negativo (cliente inexistente)
+type cachedCustomer struct {
+	Customer *entities.Customer `json:"customer"`
+}
+
+// CustomerRepository decora o gateways.CustomerRepository com cache read-through nas buscas
+// por documento e por id. Buscas idênticas e simultâneas são agrupadas (singleflight) numa única
+// consulta ao banco, e documentos desconhecidos ficam em cache por NegativeTTL.
+// Escritas passam direto e invalidam as chaves afetadas.
+// As chaves por documento são índices cegos do Keyring, então não dá para testar um CPF contra elas, e os
+// valores são cifrados pelo Keyring com a chave como dado associado, então o Redis não guarda dados pessoais
+// legíveis e um valor copiado para outra chave não decifra.
+type CustomerRepository struct {
+	gateways.CustomerRepository
+	Store       Store
+	Keyring     *encryption.Keyring
+	TTL         time.Duration
+	NegativeTTL time.Duration
+
+	group singleflight.Group
+}
+
+func NewCustomerRepository(next gateways.CustomerRepository, store Store, keyring *encryption.Keyring, ttl time.Duration, negativeTTL time.Duration) *CustomerRepository {
+	return &CustomerRepository{
+		CustomerRepository: next,
+		Store:              store,
+		Keyring:            keyring,
+		TTL:                ttl,
+		NegativeTTL:        negativeTTL,
+	}
+}
+
+func (r *CustomerRepository) FindFirstByCpf(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	return r.lookup(ctx, "find_first_by_cpf", r.cpfKey(customer.CPF), func(ctx context.Context) (*entities.Customer, error) {
+		return r.CustomerRepository.FindFirstByCpf(ctx, customer)
+	})
+}
+
+func (r *CustomerRepository) FindByDocument(ctx context.Context, document entities.IdentityDocument) (*entities.Customer, error) {
+	return r.lookup(ctx, "find_by_document", r.documentKey(document), func(ctx context.Context) (*entities.Customer, error) {
+		return r.CustomerRepository.FindByDocument(ctx, document)
+	})
+}
+
+func (r *CustomerRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	return r.lookup(ctx, "find_by_id", idKey(id), func(ctx context.Context) (*entities.Customer, error) {
+		return r.CustomerRepository.FindByID(ctx, id)
+	})
+}
+
+func (r *CustomerRepository) Create(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	result, err := r.CustomerRepository.Create(ctx, customer)
+	if err == nil {
+		// remove o cache negativo do documento que acabou de ser cadastrado
+		r.invalidate(ctx, r.documentKey(result.IdentityDocument()), idKey(result.ID))
+	}
+
+	return result, err
+}
+
+func (r *CustomerRepository) Update(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	result, err := r.CustomerRepository.Update(ctx, customer)
+	if err == nil {
+		r.invalidate(ctx, r.documentKey(result.IdentityDocument()), idKey(result.ID))
+	}
+
+	return result, err
+}
+
+func (r *CustomerRepository) Erase(ctx context.Context, id uint) error {
+	keys := []string{idKey(id)}
+
+	// o documento é anonimizado na remoção, então precisa ser lido antes para invalidar a chave
+	if current, err := r.CustomerRepository.FindByID(ctx, id); err == nil {
+		keys = append(keys, r.documentKey(current.IdentityDocument()))
+	}
+
+	err := r.CustomerRepository.Erase(ctx, id)
+	if err == nil {
+		r.invalidate(ctx, keys...)
+	}
+
+	return err
+}
+
+// Merge invalida os dois clientes; o documento do incorporado é lido antes, como em Erase
+func (r *CustomerRepository) Merge(ctx context.Context, survivorID uint, mergedID uint, maxAddresses int) (*entities.Customer, error) {
+	keys := []string{idKey(survivorID), idKey(mergedID)}
+
+	if current, err := r.CustomerRepository.FindByID(ctx, mergedID); err == nil {
+		keys = append(keys, r.documentKey(current.IdentityDocument()))
+	}
+
+	result, err := r.CustomerRepository.Merge(ctx, survivorID, mergedID, maxAddresses)
+	if err == nil {
+		r.invalidate(ctx, append(keys, r.documentKey(result.IdentityDocument()))...)
+	}
+
+	return result, err
+}
+
+// ChangeStatus invalida o cliente pelo id e pelo documento: a emissão de sessão lê o status pela
+// busca por documento, e um cliente suspenso não pode continuar recebendo sessão identificada do cache
+func (r *CustomerRepository) ChangeStatus(ctx context.Context, id uint, from entities.CustomerStatus, to entities.CustomerStatus, reason string) (*entities.CustomerStatusChange, error) {
+	keys := []string{idKey(id)}
+
+	if current, err := r.CustomerRepository.FindByID(ctx, id); err == nil {
+		keys = append(keys, r.documentKey(current.IdentityDocument()))
+	}
+
+	result, err := r.CustomerRepository.ChangeStatus(ctx, id, from, to, reason)
+	if err == nil {
+		r.invalidate(ctx, keys...)
+	}
+
+	return result, err
+}
+
+func (r *CustomerRepository) lookup(ctx context.Context, operation string, key string, load func(context.Context) (*entities.Customer, error)) (*entities.Customer, error) {
+	if cached, ok := r.get(ctx, key); ok {
+		if cached.Customer == nil {
+			metrics.CacheLookupsTotal.WithLabelValues(operation, "negative_hit").Inc()
+			return nil, entities.ErrCustomerNotFound
+		}
+
+		metrics.CacheLookupsTotal.WithLabelValues(operation, "hit").Inc()
+		customer := *cached.Customer
+		return &customer, nil
+	}
+
+	value, err, shared := r.group.Do(key, func() (interface{}, error) {
+		// a consulta é compartilhada: o cancelamento de quem chegou primeiro não deve derrubar os demais
+		ctx := context.WithoutCancel(ctx)
+		customer, err := load(ctx)
+
+		switch {
+		case err == nil:
+			r.set(ctx, key, cachedCustomer{Customer: customer}, r.TTL)
+		case errors.Is(err, entities.ErrCustomerNotFound):
+			r.set(ctx, key, cachedCustomer{}, r.NegativeTTL)
+		}
+
+		return customer, err
+	})
+
+	if shared {
+		metrics.CacheLookupsTotal.WithLabelValues(operation, "coalesced").Inc()
+	} else {
+		metrics.CacheLookupsTotal.WithLabelValues(operation, "miss").Inc()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	customer := *value.(*entities.Customer)
+
+	return &customer, nil
+}
+
+func (r *CustomerRepository) get(ctx context.Context, key string) (cachedCustomer, bool) {
+	var cached cachedCustomer
+
+	sealed, ok, err := r.Store.Get(ctx, key)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Erro ao ler o cache de clientes", "error", err)
+		return cached, false
+	}
+
+	// entradas em texto puro, de antes da criptografia, são ignoradas
+	if !ok || !encryption.IsEncrypted(string(sealed)) {
+		return cached, false
+	}
+
+	raw, err := r.Keyring.Decrypt(ctx, key, string(sealed))
+	if err != nil {
+		logging.FromContext(ctx).Warn("Erro ao decifrar o cache de clientes", "error", err)
+		return cached, false
+	}
+
+	if json.Unmarshal([]byte(raw), &cached) != nil {
+		return cached, false
+	}
+
+	// entradas gravadas antes do status da conta são descartadas: sem status o cliente pareceria inativo
+	if cached.Customer != nil && cached.Customer.Status == "" {
+		return cached, false
+	}
+
+	return cached, true
+}
+
+func (r *CustomerRepository) set(ctx context.Context, key string, value cachedCustomer, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	sealed, err := r.Keyring.Encrypt(ctx, key, string(raw))
+	if err != nil {
+		logging.FromContext(ctx).Warn("Erro ao cifrar o cache de clientes", "error", err)
+		return
+	}
+
+	if err := r.Store.Set(ctx, key, []byte(sealed), ttl); err != nil {
+		logging.FromContext(ctx).Warn("Erro ao gravar o cache de clientes", "error", err)
+	}
+}
+
+func (r *CustomerRepository) invalidate(ctx context.Context, keys ...string) {
+	// uma busca em andamento poderia regravar o valor antigo logo após a invalidação
+	for _, key := range keys {
+		r.group.Forget(key)
+	}
+
+	if err := r.Store.Delete(ctx, keys...); err != nil {
+		logging.FromContext(ctx).Warn("Erro ao invalidar o cache de clientes", "error", err)
+	}
+}
+
+// cpfKey usa o índice cego do CPF para que o CPF não apareça nem possa ser testado por força bruta
+// nas chaves do cache compartilhado
+func (r *CustomerRepository) cpfKey(cpf string) string {
+	return "customer:cpf:" + r.Keyring.BlindIndex(cpfKeyPurpose, cpf)[:32]
+}
+
+// documentKey de um CPF é a mesma chave de cpfKey, para que a busca por CPF e por documento
+// compartilhem o cache e a invalidação
+func (r *CustomerRepository) documentKey(document entities.IdentityDocument) string {
+	if document.Type == entities.DocumentCPF {
+		return r.cpfKey(document.Number)
+	}
+
+	return "customer:document:" + r.Keyring.BlindIndex(documentKeyPurpose, document.Key())[:32]
+}
+
+func idKey(id uint) string {
+	return "customer:id:" + strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,268 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingRepository struct {
+	gateways.CustomerRepository
+	calls     atomic.Int32
+	release   chan struct{}
+	customers map[string]entities.Customer
+}
+
+func (m *countingRepository) FindFirstByCpf(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	m.calls.Add(1)
+	if m.release != nil {
+		<-m.release
+	}
+
+	found, ok := m.customers[customer.CPF]
+	if !ok {
+		return nil, entities.ErrCustomerNotFound
+	}
+	return &found, nil
+}
+
+func (m *countingRepository) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	for _, customer := range m.customers {
+		if customer.ID == id {
+			return &customer, nil
+		}
+	}
+	return nil, entities.ErrCustomerNotFound
+}
+
+func (m *countingRepository) Create(ctx context.Context, customer *entities.Customer) (*entities.Customer, error) {
+	customer.ID = uint(len(m.customers) + 1)
+	m.customers[customer.CPF] = *customer
+	return customer, nil
+}
+
+func (m *countingRepository) Erase(ctx context.Context, id uint) error {
+	for cpf, customer := range m.customers {
+		if customer.ID == id {
+			delete(m.customers, cpf)
+		}
+	}
+	return nil
+}
+
+func (m *countingRepository) Merge(ctx context.Context, survivorID uint, mergedID uint, maxAddresses int) (*entities.Customer, error) {
+	if err := m.Erase(ctx, mergedID); err != nil {
+		return nil, err
+	}
+	return m.FindByID(ctx, survivorID)
+}
+
+func (m *countingRepository) ChangeStatus(ctx context.Context, id uint, from, to entities.CustomerStatus, reason string) (*entities.CustomerStatusChange, error) {
+	for cpf, customer := range m.customers {
+		if customer.ID == id {
+			customer.Status = to
+			m.customers[cpf] = customer
+		}
+	}
+	return &entities.CustomerStatusChange{CustomerID: id, From: from, To: to, Reason: reason}, nil
+}
+
+type singleKeyStore struct {
+	keys []encryption.WrappedKey
+}
+
+func (s *singleKeyStore) ListKeys(context.Context) ([]encryption.WrappedKey, error) {
+	return s.keys, nil
+}
+
+func (s *singleKeyStore) SaveKey(_ context.Context, key encryption.WrappedKey) error {
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *singleKeyStore) ActivateKey(_ context.Context, id string) error {
+	for i := range s.keys {
+		s.keys[i].Active = s.keys[i].ID == id
+	}
+	return nil
+}
+
+func newTestKeyring(t *testing.T) *encryption.Keyring {
+	key := sha256.Sum256([]byte("kek"))
+	kek, err := encryption.ParseLocalKeys("kek:" + base64.StdEncoding.EncodeToString(key[:]))
+	require.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(context.Background(), &singleKeyStore{}, kek, []byte(strings.Repeat("k", 32)), 0)
+	require.NoError(t, err)
+
+	return keyring
+}
+
+func newTestRepository(t *testing.T, next *countingRepository) *CustomerRepository {
+	return NewCustomerRepository(next, NewLRUStore(100), newTestKeyring(t), time.Minute, time.Minute)
+}
+
+func TestCustomerRepository_CachesLookups(t *testing.T) {
+	next := &countingRepository{customers: map[string]entities.Customer{"12345678901": {ID: 1, CPF: "12345678901", Status: entities.CustomerActive}}}
+	repo := newTestRepository(t, next)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		customer, err := repo.FindFirstByCpf(ctx, &entities.Customer{CPF: "12345678901"})
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), customer.ID)
+	}
+	assert.Equal(t, int32(1), next.calls.Load())
+
+	t.Run("cache negativo para CPF desconhecido", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_, err := repo.FindFirstByCpf(ctx, &entities.Customer{CPF: "99999999999"})
+			assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+		}
+		assert.Equal(t, int32(2), next.calls.Load())
+	})
+
+	t.Run("cadastro invalida o cache negativo", func(t *testing.T) {
+		_, err := repo.Create(ctx, &entities.Customer{CPF: "99999999999", Status: entities.CustomerActive})
+		require.NoError(t, err)
+
+		customer, err := repo.FindFirstByCpf(ctx, &entities.Customer{CPF: "99999999999"})
+		require.NoError(t, err)
+		assert.Equal(t, "99999999999", customer.CPF)
+	})
+
+	t.Run("remoção invalida o cache", func(t *testing.T) {
+		require.NoError(t, repo.Erase(ctx, 1))
+
+		_, err := repo.FindFirstByCpf(ctx, &entities.Customer{CPF: "12345678901"})
+		assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+	})
+}
+
+func TestCustomerRepository_MergeInvalidatesMergedCustomer(t *testing.T) {
+	next := &countingRepository{customers: map[string]entities.Customer{
+		"12345678901": {ID: 1, CPF: "12345678901"},
+		"10987654321": {ID: 2, CPF: "10987654321"},
+	}}
+	repo := newTestRepository(t, next)
+	ctx := context.Background()
+
+	_, err := repo.FindFirstByCpf(ctx, &entities.Customer{CPF: "10987654321"})
+	require.NoError(t, err)
+	_, err = repo.FindByID(ctx, 2)
+	require.NoError(t, err)
+
+	survivor, err := repo.Merge(ctx, 1, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), survivor.ID)
+
+	_, err = repo.FindFirstByCpf(ctx, &entities.Customer{CPF: "10987654321"})
+	assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+	_, err = repo.FindByID(ctx, 2)
+	assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+}
+
+func TestCustomerRepository_ChangeStatusInvalidatesCustomer(t *testing.T) {
+	next := &countingRepository{customers: map[string]entities.Customer{
+		"12345678901": {ID: 1, CPF: "12345678901", Status: entities.CustomerActive},
+	}}
+	repo := newTestRepository(t, next)
+	ctx := context.Background()
+
+	_, err := repo.FindFirstByCpf(ctx, &entities.Customer{CPF: "12345678901"})
+	require.NoError(t, err)
+	_, err = repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+
+	_, err = repo.ChangeStatus(ctx, 1, entities.CustomerActive, entities.CustomerSuspended, "fraude")
+	require.NoError(t, err)
+
+	// a próxima sessão precisa ver a suspensão, não a cópia em cache
+	customer, err := repo.FindFirstByCpf(ctx, &entities.Customer{CPF: "12345678901"})
+	require.NoError(t, err)
+	assert.Equal(t, entities.CustomerSuspended, customer.Status)
+	customer, err = repo.FindByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CustomerSuspended, customer.Status)
+}
+
+func TestCustomerRepository_CoalescesConcurrentLookups(t *testing.T) {
+	next := &countingRepository{
+		release:   make(chan struct{}),
+		customers: map[string]entities.Customer{"12345678901": {ID: 1, CPF: "12345678901"}},
+	}
+	repo := newTestRepository(t, next)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			customer, err := repo.FindFirstByCpf(context.Background(), &entities.Customer{CPF: "12345678901"})
+			assert.NoError(t, err)
+			assert.Equal(t, uint(1), customer.ID)
+		}()
+	}
+
+	// dá tempo para as goroutines chegarem ao singleflight antes de liberar a consulta
+	time.Sleep(50 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), next.calls.Load())
+}
+
+func TestCustomerRepository_DocumentKeysAreBlindIndexes(t *testing.T) {
+	repo := newTestRepository(t, &countingRepository{})
+	unsalted := sha256.Sum256([]byte("12345678901"))
+
+	key := repo.cpfKey("12345678901")
+	assert.NotContains(t, key, "12345678901")
+	assert.NotContains(t, key, hex.EncodeToString(unsalted[:16]))
+	assert.Equal(t, key, repo.documentKey(entities.IdentityDocument{Type: entities.DocumentCPF, Number: "12345678901"}))
+}
+
+func TestCustomerRepository_EncryptsCachedValues(t *testing.T) {
+	next := &countingRepository{customers: map[string]entities.Customer{
+		"12345678901": {ID: 1, Name: "Jane Doe", CPF: "12345678901", Email: "jane@example.com", Status: entities.CustomerActive},
+	}}
+	store := NewLRUStore(100)
+	repo := NewCustomerRepository(next, store, newTestKeyring(t), time.Minute, time.Minute)
+	ctx := context.Background()
+
+	_, err := repo.FindFirstByCpf(ctx, &entities.Customer{CPF: "12345678901"})
+	require.NoError(t, err)
+
+	raw, ok, err := store.Get(ctx, repo.cpfKey("12345678901"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, encryption.IsEncrypted(string(raw)))
+	assert.NotContains(t, string(raw), "Jane")
+	assert.NotContains(t, string(raw), "jane@example.com")
+
+	t.Run("valor movido para outra chave não decifra", func(t *testing.T) {
+		_ = store.Set(ctx, idKey(1), raw, time.Minute)
+
+		_, ok := repo.get(ctx, idKey(1))
+		assert.False(t, ok)
+	})
+
+	t.Run("entrada em texto puro é ignorada", func(t *testing.T) {
+		_ = store.Set(ctx, idKey(2), []byte(`{"customer":{"id":2,"status":"active"}}`), time.Minute)
+
+		_, ok := repo.get(ctx, idKey(2))
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRUStore é um cache em memória com limite de entradas e expiração por entrada.
+// Com MaxTTL maior que zero nenhuma entrada vive mais que ele, seja qual for o TTL pedido.
+type LRUStore struct {
+	MaxTTL time.Duration
+
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewLRUStore(capacity int) *LRUStore {
+	return &LRUStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (s *LRUStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !s.now().Before(entry.expiresAt) {
+		s.remove(element)
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+func (s *LRUStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.MaxTTL > 0 {
+		ttl = min(ttl, s.MaxTTL)
+	}
+
+	expiresAt := s.now().Add(ttl)
+
+	if element, ok := s.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		s.order.MoveToFront(element)
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+func (s *LRUStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := s.entries[key]; ok {
+			s.remove(element)
+		}
+	}
+
+	return nil
+}
+
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+func (s *LRUStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewLRUStore(2)
+	store.now = func() time.Time { return now }
+
+	_ = store.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = store.Set(ctx, "b", []byte("2"), time.Minute)
+
+	// "a" passa a ser o mais recente, então "b" é removido ao inserir "c"
+	_, ok, _ := store.Get(ctx, "a")
+	assert.True(t, ok)
+	_ = store.Set(ctx, "c", []byte("3"), time.Minute)
+
+	_, ok, _ = store.Get(ctx, "b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, store.Len())
+
+	t.Run("expira pelo TTL", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+		_, ok, _ := store.Get(ctx, "a")
+		assert.False(t, ok)
+	})
+
+	t.Run("remove chaves", func(t *testing.T) {
+		_ = store.Set(ctx, "d", []byte("4"), time.Minute)
+		_ = store.Delete(ctx, "d")
+		_, ok, _ := store.Get(ctx, "d")
+		assert.False(t, ok)
+	})
+}
+
+func TestLRUStore_MaxTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewLRUStore(10)
+	store.MaxTTL = 15 * time.Second
+	store.now = func() time.Time { return now }
+
+	_ = store.Set(ctx, "a", []byte("1"), 5*time.Minute)
+
+	now = now.Add(10 * time.Second)
+	_, ok, _ := store.Get(ctx, "a")
+	assert.True(t, ok)
+
+	now = now.Add(10 * time.Second)
+	_, ok, _ = store.Get(ctx, "a")
+	assert.False(t, ok)
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// Store é o backend do cache. Erros do backend são tratados como miss por quem usa o cache,
+// então uma indisponibilidade do cache compartilhado só aumenta a carga no banco.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// NewStoreFromEnv monta o cache local (LRU) e, com CACHE_REDIS_URL, coloca o Redis como segundo nível.
+// O TTL local fica sempre limitado a CACHE_LOCAL_TTL, que é o atraso máximo para uma invalidação
+// feita em outra réplica chegar a esta; sem o Redis ele limita também o TTL do cache como um todo.
+func NewStoreFromEnv() Store {
+	localTTL := utils.GetEnvDuration("CACHE_LOCAL_TTL", 15*time.Second)
+	local := NewLRUStore(utils.GetEnvInt("CACHE_LOCAL_SIZE", 10000))
+	local.MaxTTL = localTTL
+
+	redisURL := utils.GetEnv("CACHE_REDIS_URL", "")
+	if redisURL == "" {
+		return local
+	}
+
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		slog.Warn("CACHE_REDIS_URL inválida; usando apenas o cache local", "error", err)
+		return local
+	}
+
+	return &TieredStore{
+		Local:    local,
+		Shared:   NewRedisStore(redis.NewClient(options), "customer-service:"),
+		LocalTTL: localTTL,
+	}
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TieredStore consulta o cache local antes do compartilhado e preenche o local nos hits do compartilhado
+type TieredStore struct {
+	Local    Store
+	Shared   Store
+	LocalTTL time.Duration
+}
+
+func (s *TieredStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if value, ok, _ := s.Local.Get(ctx, key); ok {
+		return value, true, nil
+	}
+
+	value, ok, err := s.Shared.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	_ = s.Local.Set(ctx, key, value, s.LocalTTL)
+
+	return value, true, nil
+}
+
+func (s *TieredStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_ = s.Local.Set(ctx, key, value, min(ttl, s.LocalTTL))
+
+	return s.Shared.Set(ctx, key, value, ttl)
+}
+
+func (s *TieredStore) Delete(ctx context.Context, keys ...string) error {
+	return errors.Join(s.Local.Delete(ctx, keys...), s.Shared.Delete(ctx, keys...))
+}
@@ -0,0 +1,65 @@
+package cep
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+)
+
+//go:embed fixtures/ceps.json
+var defaultFixtures []byte
+
+// FixtureProvider responde a partir de uma lista fixa de CEPs, sem rede.
+// Serve para testes e para rodar o serviço offline.
+type FixtureProvider struct {
+	addresses map[string]entities.CEPAddress
+}
+
+func NewFixtureProvider(addresses ...entities.CEPAddress) *FixtureProvider {
+	provider := &FixtureProvider{addresses: make(map[string]entities.CEPAddress, len(addresses))}
+	for _, address := range addresses {
+		provider.addresses[address.CEP] = address
+	}
+
+	return provider
+}
+
+// DefaultFixtureProvider usa os CEPs de fixtures/ceps.json
+func DefaultFixtureProvider() (*FixtureProvider, error) {
+	return parseFixtures(defaultFixtures)
+}
+
+// LoadFixtureProvider lê um arquivo no formato de fixtures/ceps.json
+func LoadFixtureProvider(path string) (*FixtureProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFixtures(data)
+}
+
+func parseFixtures(data []byte) (*FixtureProvider, error) {
+	var addresses []entities.CEPAddress
+	if err := json.Unmarshal(data, &addresses); err != nil {
+		return nil, fmt.Errorf("fixtures de CEP inválidos: %w", err)
+	}
+
+	return NewFixtureProvider(addresses...), nil
+}
+
+func (p *FixtureProvider) Lookup(_ context.Context, cep string) (*entities.CEPAddress, error) {
+	address, ok := p.addresses[cep]
+	if !ok {
+		metrics.CEPLookupsTotal.WithLabelValues("fixture", "not_found").Inc()
+		return nil, entities.ErrCEPNotFound
+	}
+
+	metrics.CEPLookupsTotal.WithLabelValues("fixture", "found").Inc()
+	return &address, nil
+}
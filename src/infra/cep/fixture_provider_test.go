@@ -0,0 +1,23 @@
+package cep
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultFixtureProvider(t *testing.T) {
+	provider, err := DefaultFixtureProvider()
+	require.NoError(t, err)
+
+	address, err := provider.Lookup(context.Background(), "01310100")
+	require.NoError(t, err)
+	assert.Equal(t, "Avenida Paulista", address.Street)
+	assert.Equal(t, "SP", address.State)
+
+	_, err = provider.Lookup(context.Background(), "99999999")
+	assert.ErrorIs(t, err, entities.ErrCEPNotFound)
+}
@@ -0,0 +1,30 @@
+package cep
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+// NewProviderFromEnv escolhe o provedor de CEP por CEP_PROVIDER: "viacep" (padrão) consulta
+// CEP_PROVIDER_URL com timeout CEP_PROVIDER_TIMEOUT; "fixture" responde offline com os CEPs
+// de CEP_FIXTURES_PATH ou, sem ele, com os fixtures embutidos.
+func NewProviderFromEnv() (gateways.CEPProvider, error) {
+	switch provider := utils.GetEnv("CEP_PROVIDER", "viacep"); provider {
+	case "viacep":
+		return NewViaCEPProvider(
+			utils.GetEnv("CEP_PROVIDER_URL", DefaultViaCEPURL),
+			utils.GetEnvDuration("CEP_PROVIDER_TIMEOUT", 3*time.Second),
+		), nil
+	case "fixture":
+		if path := os.Getenv("CEP_FIXTURES_PATH"); path != "" {
+			return LoadFixtureProvider(path)
+		}
+		return DefaultFixtureProvider()
+	default:
+		return nil, fmt.Errorf("provedor de CEP desconhecido: %s", provider)
+	}
+}
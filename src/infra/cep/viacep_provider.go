@@ -0,0 +1,94 @@
+package cep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+)
+
+const DefaultViaCEPURL = "https://viacep.com.br/ws"
+
+// ViaCEPProvider consulta a API pública do ViaCEP
+type ViaCEPProvider struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewViaCEPProvider(baseURL string, timeout time.Duration) *ViaCEPProvider {
+	return &ViaCEPProvider{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client: &http.Client{
+			Timeout:   timeout,
+			Transport: &tracing.Transport{},
+		},
+	}
+}
+
+// viaCEPResponse é a resposta do ViaCEP. Um CEP inexistente volta com status 200 e
+// "erro": true (ou "true", nas versões mais novas da API).
+type viaCEPResponse struct {
+	CEP          string          `json:"cep"`
+	Street       string          `json:"logradouro"`
+	Neighborhood string          `json:"bairro"`
+	City         string          `json:"localidade"`
+	State        string          `json:"uf"`
+	Error        json.RawMessage `json:"erro"`
+}
+
+func (p *ViaCEPProvider) Lookup(ctx context.Context, cep string) (*entities.CEPAddress, error) {
+	result := "error"
+	defer func() { metrics.CEPLookupsTotal.WithLabelValues("viacep", result).Inc() }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"/"+cep+"/json/", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, unavailable(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	// 400 é a resposta do ViaCEP para um CEP fora do formato
+	if resp.StatusCode == http.StatusBadRequest {
+		return nil, entities.ErrInvalidCEP
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unavailable(ctx, fmt.Errorf("status %d", resp.StatusCode))
+	}
+
+	var body viaCEPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, unavailable(ctx, fmt.Errorf("resposta inválida: %w", err))
+	}
+
+	if len(body.Error) > 0 && string(body.Error) != "false" {
+		result = "not_found"
+		return nil, entities.ErrCEPNotFound
+	}
+
+	result = "found"
+	return &entities.CEPAddress{
+		CEP:          strings.ReplaceAll(body.CEP, "-", ""),
+		Street:       body.Street,
+		Neighborhood: body.Neighborhood,
+		City:         body.City,
+		State:        body.State,
+	}, nil
+}
+
+// unavailable registra o motivo da falha e devolve o erro de domínio, que chega ao cliente sem detalhes
+func unavailable(ctx context.Context, err error) error {
+	slog.WarnContext(ctx, "Erro ao consultar o ViaCEP", "error", err)
+	return entities.ErrCEPProviderUnavailable
+}
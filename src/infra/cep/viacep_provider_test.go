@@ -0,0 +1,52 @@
+package cep
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViaCEPProvider_Found(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/01310100/json/", r.URL.Path)
+		w.Write([]byte(`{"cep":"01310-100","logradouro":"Avenida Paulista","bairro":"Bela Vista","localidade":"São Paulo","uf":"SP"}`))
+	}))
+	defer server.Close()
+
+	address, err := NewViaCEPProvider(server.URL+"/", time.Second).Lookup(context.Background(), "01310100")
+	require.NoError(t, err)
+	assert.Equal(t, entities.CEPAddress{CEP: "01310100", Street: "Avenida Paulista", Neighborhood: "Bela Vista", City: "São Paulo", State: "SP"}, *address)
+}
+
+func TestViaCEPProvider_NotFound(t *testing.T) {
+	for _, body := range []string{`{"erro": true}`, `{"erro": "true"}`} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+
+		_, err := NewViaCEPProvider(server.URL, time.Second).Lookup(context.Background(), "99999999")
+		assert.ErrorIs(t, err, entities.ErrCEPNotFound, body)
+
+		server.Close()
+	}
+}
+
+func TestViaCEPProvider_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := NewViaCEPProvider(server.URL, time.Second).Lookup(context.Background(), "01310100")
+	assert.ErrorIs(t, err, entities.ErrCEPProviderUnavailable)
+
+	server.Close()
+	_, err = NewViaCEPProvider(server.URL, time.Second).Lookup(context.Background(), "01310100")
+	assert.ErrorIs(t, err, entities.ErrCEPProviderUnavailable)
+}
@@ -0,0 +1,23 @@
+package database
+
+import "gorm.io/gorm"
+
+// AuditEntriesLockKey serializa as escritas na auditoria (pg_advisory_xact_lock): cada entrada
+// precisa do hash da anterior, então duas transações não podem ler o mesmo "último hash"
+const AuditEntriesLockKey = 720_310_040
+
+const auditEntriesMigrationSQL = `
+CREATE OR REPLACE FUNCTION audit_entries_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_entries é append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_entries_append_only ON audit_entries;
+CREATE TRIGGER audit_entries_append_only BEFORE UPDATE OR DELETE ON audit_entries
+	FOR EACH ROW EXECUTE FUNCTION audit_entries_append_only();
+`
+
+func migrateAuditEntries(db *gorm.DB) error {
+	return db.Exec(auditEntriesMigrationSQL).Error
+}
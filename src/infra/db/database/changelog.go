@@ -0,0 +1,26 @@
+package database
+
+import "gorm.io/gorm"
+
+const customerChangesMigrationSQL = `
+CREATE OR REPLACE FUNCTION customer_changes_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'customer_changes é append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS customer_changes_append_only ON customer_changes;
+CREATE TRIGGER customer_changes_append_only BEFORE UPDATE OR DELETE ON customer_changes
+	FOR EACH ROW EXECUTE FUNCTION customer_changes_append_only();
+
+INSERT INTO customer_changes (customer_id, operation, changed_fields, changed_at)
+SELECT id, 'created', '', created_at FROM customers
+WHERE deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM customer_changes)
+ORDER BY id;
+`
+
+// migrateCustomerChanges protege o log contra alterações e, na primeira execução,
+// registra os clientes já existentes como criados para que o feed comece completo
+func migrateCustomerChanges(db *gorm.DB) error {
+	return db.Exec(customerChangesMigrationSQL).Error
+}
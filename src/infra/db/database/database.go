@@ -1,11 +1,16 @@
 package database
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -16,15 +21,26 @@ var (
 
 // Database is an interface that defines methods for interacting with a database.
 type Database interface {
+	WithContext(ctx context.Context) Database
 	Create(data interface{}) error
 	Where(query interface{}, args ...interface{}) *gorm.DB
 	First(dest interface{}, conds ...interface{}) error
+	Find(dest interface{}, conds ...interface{}) error
+	Save(data interface{}) error
+	Delete(value interface{}, conds ...interface{}) error
+	Exec(sql string, values ...interface{}) error
+	Raw(dest interface{}, sql string, values ...interface{}) error
+	Transaction(fn func(tx Database) error) error
 }
 
 type RealDatabase struct {
 	db *gorm.DB
 }
 
+func (rdb *RealDatabase) WithContext(ctx context.Context) Database {
+	return &RealDatabase{db: rdb.db.WithContext(ctx)}
+}
+
 func (rdb *RealDatabase) Create(data interface{}) error {
 	return rdb.db.Create(data).Error
 }
@@ -37,21 +53,119 @@ func (rdb *RealDatabase) First(dest interface{}, conds ...interface{}) error {
 	return rdb.db.First(dest, conds...).Error
 }
 
+func (rdb *RealDatabase) Find(dest interface{}, conds ...interface{}) error {
+	return rdb.db.Find(dest, conds...).Error
+}
+
+func (rdb *RealDatabase) Save(data interface{}) error {
+	return rdb.db.Save(data).Error
+}
+
+func (rdb *RealDatabase) Delete(value interface{}, conds ...interface{}) error {
+	return rdb.db.Delete(value, conds...).Error
+}
+
+func (rdb *RealDatabase) Exec(sql string, values ...interface{}) error {
+	return rdb.db.Exec(sql, values...).Error
+}
+
+func (rdb *RealDatabase) Raw(dest interface{}, sql string, values ...interface{}) error {
+	return rdb.db.Raw(sql, values...).Scan(dest).Error
+}
+
+// Transaction executa fn numa transação; qualquer erro retornado faz rollback
+func (rdb *RealDatabase) Transaction(fn func(tx Database) error) error {
+	return rdb.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&RealDatabase{db: tx})
+	})
+}
+
+// IsUniqueViolation indica se o erro do Postgres veio de uma restrição de unicidade
+func IsUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "duplicate key value violates unique constraint")
+}
+
 func ConnectDB() {
 	conectionString := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=5432 sslmode=require TimeZone=America/Fortaleza",
 		os.Getenv("POSTGRES_HOST"), os.Getenv("POSTGRES_USER"), os.Getenv("POSTGRES_PASSWORD"), os.Getenv("POSTGRES_DB"))
 
-	db, err := gorm.Open(postgres.Open(conectionString))
+	db, err := gorm.Open(postgres.Open(conectionString), &gorm.Config{
+		Logger: logging.NewGormLogger(),
+	})
 	if err != nil {
-		log.Panic("Erro ao conectar com banco de dados")
+		logging.Fatal("Erro ao conectar com banco de dados", err)
+	}
+
+	if err := registerTracingCallbacks(db); err != nil {
+		logging.Fatal("Erro ao registrar callbacks de tracing", err)
 	}
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		logging.Fatal("Erro ao obter pool de conexões", err)
+	}
+
+	sqlDB.SetMaxOpenConns(utils.GetEnvInt("DB_MAX_OPEN_CONNS", 10))
+	sqlDB.SetMaxIdleConns(utils.GetEnvInt("DB_MAX_IDLE_CONNS", 5))
+	sqlDB.SetConnMaxLifetime(utils.GetEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute))
+	metrics.RegisterDBStats(sqlDB, os.Getenv("POSTGRES_DB"))
+
 	DB = &RealDatabase{
 		db: db,
 	}
 
-	err = db.AutoMigrate(&models.Customer{})
+	err = db.AutoMigrate(
+		&models.Customer{},
+		&models.IdempotencyKey{},
+		&models.OutboxEvent{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryAttempt{},
+		&models.CustomerChange{},
+		&models.EncryptionKey{},
+		&models.AuditEntry{},
+		&models.LoyaltyTransaction{},
+		&models.CustomerAddress{},
+		&models.CustomerPreferences{},
+		&models.CustomerFiscalProfile{},
+		&models.CustomerPhoneVerification{},
+		&models.CustomerSessionVerification{},
+		&models.CustomerRedirect{},
+		&models.CustomerDuplicateCandidate{},
+		&models.CustomerStatusChange{},
+	)
+	if err != nil {
+		logging.Fatal("Erro ao fazer auto migrate", err)
+	}
+
+	if err := migrateCustomerChanges(db); err != nil {
+		logging.Fatal("Erro ao preparar o log de alterações de clientes", err)
+	}
+
+	if err := migrateAuditEntries(db); err != nil {
+		logging.Fatal("Erro ao preparar o log de auditoria", err)
+	}
+
+	if err := migrateLoyaltyTransactions(db); err != nil {
+		logging.Fatal("Erro ao preparar o extrato de pontos", err)
+	}
+
+	if err := migrateCustomerEncryption(db); err != nil {
+		logging.Fatal("Erro ao preparar a criptografia de clientes", err)
+	}
+}
+
+// CloseDB fecha o pool de conexões do GORM; deve ser chamado após drenar as requisições
+func CloseDB() error {
+	realDB, ok := DB.(*RealDatabase)
+	if !ok || realDB == nil {
+		return nil
+	}
+
+	sqlDB, err := realDB.db.DB()
 	if err != nil {
-		log.Panic("Erro ao fazer auto migrate")
+		return err
 	}
+
+	return sqlDB.Close()
 }
@@ -0,0 +1,16 @@
+package database
+
+import "gorm.io/gorm"
+
+// as restrições antigas eram sobre o texto puro; com os valores cifrados a unicidade
+// passa para os índices cegos (cpf_index, email_index)
+const customerEncryptionMigrationSQL = `
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_cpf_key;
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_email_key;
+DROP INDEX IF EXISTS idx_customers_cpf;
+DROP INDEX IF EXISTS idx_customers_email;
+`
+
+func migrateCustomerEncryption(db *gorm.DB) error {
+	return db.Exec(customerEncryptionMigrationSQL).Error
+}
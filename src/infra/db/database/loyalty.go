@@ -0,0 +1,33 @@
+package database
+
+import "gorm.io/gorm"
+
+// LoyaltyLockKey é a primeira chave do lock por cliente do extrato de pontos
+// (pg_advisory_xact_lock(LoyaltyLockKey, id do cliente)): o saldo lido antes de um resgate
+// não pode mudar até o resgate ser gravado
+const LoyaltyLockKey = 720_310_042
+
+// CustomerMergeSetting é a variável de sessão que a fusão de clientes liga com SET LOCAL para
+// transferir o extrato do cliente incorporado. É a única alteração aceita pelo trigger, e só
+// se nada além do customer_id mudar: os lançamentos, com os lotes e vencimentos, ficam intactos.
+const CustomerMergeSetting = "app.customer_merge"
+
+const loyaltyTransactionsMigrationSQL = `
+CREATE OR REPLACE FUNCTION loyalty_transactions_append_only() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'UPDATE' AND current_setting('` + CustomerMergeSetting + `', true) = 'on'
+		AND to_jsonb(NEW) - 'customer_id' = to_jsonb(OLD) - 'customer_id' THEN
+		RETURN NEW;
+	END IF;
+	RAISE EXCEPTION 'loyalty_transactions é append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS loyalty_transactions_append_only ON loyalty_transactions;
+CREATE TRIGGER loyalty_transactions_append_only BEFORE UPDATE OR DELETE ON loyalty_transactions
+	FOR EACH ROW EXECUTE FUNCTION loyalty_transactions_append_only();
+`
+
+func migrateLoyaltyTransactions(db *gorm.DB) error {
+	return db.Exec(loyaltyTransactionsMigrationSQL).Error
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanInstanceKey = "otel:span"
+
+// registerTracingCallbacks cria um span por query do GORM.
+// O SQL é registrado com os placeholders ($1, $2...), nunca com os valores, para não vazar CPF ou email.
+func registerTracingCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("otel:before_create", startSpan("create")),
+		callbacks.Create().After("gorm:create").Register("otel:after_create", endSpan),
+		callbacks.Query().Before("gorm:query").Register("otel:before_query", startSpan("select")),
+		callbacks.Query().After("gorm:query").Register("otel:after_query", endSpan),
+		callbacks.Update().Before("gorm:update").Register("otel:before_update", startSpan("update")),
+		callbacks.Update().After("gorm:update").Register("otel:after_update", endSpan),
+		callbacks.Delete().Before("gorm:delete").Register("otel:before_delete", startSpan("delete")),
+		callbacks.Delete().After("gorm:delete").Register("otel:after_delete", endSpan),
+		callbacks.Row().Before("gorm:row").Register("otel:before_row", startSpan("row")),
+		callbacks.Row().After("gorm:row").Register("otel:after_row", endSpan),
+		callbacks.Raw().Before("gorm:raw").Register("otel:before_raw", startSpan("raw")),
+		callbacks.Raw().After("gorm:raw").Register("otel:after_raw", endSpan),
+	)
+}
+
+func startSpan(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+
+		ctx, span := tracing.Tracer().Start(ctx, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperation(operation),
+			),
+		)
+
+		tx.Statement.Context = ctx
+		tx.InstanceSet(spanInstanceKey, span)
+	}
+}
+
+func endSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(
+		semconv.DBSQLTable(tx.Statement.Table),
+		semconv.DBStatement(tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.End()
+		return
+	}
+
+	tracing.EndSpan(span, tx.Error)
+}
@@ -10,8 +10,10 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
+	database "github.com/CAVAh/api-tech-challenge/src/infra/db/database"
 	gomock "go.uber.org/mock/gomock"
 	gorm "gorm.io/gorm"
 )
@@ -53,6 +55,63 @@ func (mr *MockDatabaseMockRecorder) Create(data any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDatabase)(nil).Create), data)
 }
 
+// Delete mocks base method.
+func (m *MockDatabase) Delete(value any, conds ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []any{value}
+	for _, a := range conds {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDatabaseMockRecorder) Delete(value any, conds ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{value}, conds...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDatabase)(nil).Delete), varargs...)
+}
+
+// Exec mocks base method.
+func (m *MockDatabase) Exec(sql string, values ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []any{sql}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exec", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Exec indicates an expected call of Exec.
+func (mr *MockDatabaseMockRecorder) Exec(sql any, values ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{sql}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockDatabase)(nil).Exec), varargs...)
+}
+
+// Find mocks base method.
+func (m *MockDatabase) Find(dest any, conds ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []any{dest}
+	for _, a := range conds {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Find", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockDatabaseMockRecorder) Find(dest any, conds ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{dest}, conds...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockDatabase)(nil).Find), varargs...)
+}
+
 // First mocks base method.
 func (m *MockDatabase) First(dest any, conds ...any) error {
 	m.ctrl.T.Helper()
@@ -72,6 +131,53 @@ func (mr *MockDatabaseMockRecorder) First(dest any, conds ...any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "First", reflect.TypeOf((*MockDatabase)(nil).First), varargs...)
 }
 
+// Raw mocks base method.
+func (m *MockDatabase) Raw(dest any, sql string, values ...any) error {
+	m.ctrl.T.Helper()
+	varargs := []any{dest, sql}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Raw", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Raw indicates an expected call of Raw.
+func (mr *MockDatabaseMockRecorder) Raw(dest, sql any, values ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{dest, sql}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Raw", reflect.TypeOf((*MockDatabase)(nil).Raw), varargs...)
+}
+
+// Save mocks base method.
+func (m *MockDatabase) Save(data any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockDatabaseMockRecorder) Save(data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockDatabase)(nil).Save), data)
+}
+
+// Transaction mocks base method.
+func (m *MockDatabase) Transaction(fn func(database.Database) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockDatabaseMockRecorder) Transaction(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockDatabase)(nil).Transaction), fn)
+}
+
 // Where mocks base method.
 func (m *MockDatabase) Where(query any, args ...any) *gorm.DB {
 	m.ctrl.T.Helper()
@@ -90,3 +196,17 @@ func (mr *MockDatabaseMockRecorder) Where(query any, args ...any) *gomock.Call {
 	varargs := append([]any{query}, args...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Where", reflect.TypeOf((*MockDatabase)(nil).Where), varargs...)
 }
+
+// WithContext mocks base method.
+func (m *MockDatabase) WithContext(ctx context.Context) database.Database {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithContext", ctx)
+	ret0, _ := ret[0].(database.Database)
+	return ret0
+}
+
+// WithContext indicates an expected call of WithContext.
+func (mr *MockDatabaseMockRecorder) WithContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithContext", reflect.TypeOf((*MockDatabase)(nil).WithContext), ctx)
+}
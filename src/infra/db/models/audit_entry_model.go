@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// AuditEntry é o log de auditoria encadeado por hash.
+// UPDATE e DELETE são bloqueados por trigger (database.migrateAuditEntries).
+type AuditEntry struct {
+	Sequence   uint64    `gorm:"primaryKey;autoIncrement"`
+	OccurredAt time.Time `gorm:"index;not null"`
+	Actor      string    `gorm:"size:100;index;not null"`
+	Role       string    `gorm:"size:20;not null"`
+	Action     string    `gorm:"size:50;index;not null"`
+	TargetType string    `gorm:"size:50;index:idx_audit_entries_target"`
+	TargetID   string    `gorm:"size:50;index:idx_audit_entries_target"`
+	IP         string    `gorm:"size:45"`
+	RequestID  string    `gorm:"size:128"`
+	PrevHash   string    `gorm:"size:64;not null"`
+	Hash       string    `gorm:"size:64;not null"`
+}
+
+func NewAuditEntry(entry entities.AuditEntry) AuditEntry {
+	return AuditEntry{
+		OccurredAt: entry.OccurredAt,
+		Actor:      entry.Actor,
+		Role:       entry.Role,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		IP:         entry.IP,
+		RequestID:  entry.RequestID,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+}
+
+func (a AuditEntry) ToDomain() entities.AuditEntry {
+	return entities.AuditEntry{
+		Sequence:   a.Sequence,
+		OccurredAt: a.OccurredAt,
+		Actor:      a.Actor,
+		Role:       a.Role,
+		Action:     a.Action,
+		TargetType: a.TargetType,
+		TargetID:   a.TargetID,
+		IP:         a.IP,
+		RequestID:  a.RequestID,
+		PrevHash:   a.PrevHash,
+		Hash:       a.Hash,
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// CustomerAddress é um endereço do caderno do cliente. O índice parcial garante no máximo
+// um endereço padrão por cliente. A eliminação do cliente apaga os endereços de vez.
+type CustomerAddress struct {
+	ID           uint   `gorm:"primaryKey"`
+	CustomerID   uint   `gorm:"not null;index;uniqueIndex:idx_customer_addresses_default,where:is_default"`
+	Label        string `gorm:"size:50;not null"`
+	CEP          string `gorm:"size:8;not null"`
+	Street       string `gorm:"size:200;not null"`
+	Number       string `gorm:"size:20;not null"`
+	Complement   string `gorm:"size:100"`
+	Neighborhood string `gorm:"size:100"`
+	City         string `gorm:"size:100;not null"`
+	State        string `gorm:"size:2;not null"`
+	IsDefault    bool   `gorm:"not null;default:false"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func NewCustomerAddress(customerID uint, address entities.Address) CustomerAddress {
+	return CustomerAddress{
+		ID:           address.ID,
+		CustomerID:   customerID,
+		Label:        address.Label,
+		CEP:          address.CEP,
+		Street:       address.Street,
+		Number:       address.Number,
+		Complement:   address.Complement,
+		Neighborhood: address.Neighborhood,
+		City:         address.City,
+		State:        address.State,
+		IsDefault:    address.Default,
+		CreatedAt:    address.CreatedAt,
+		UpdatedAt:    address.UpdatedAt,
+	}
+}
+
+func (a CustomerAddress) ToDomain() entities.Address {
+	return entities.Address{
+		ID:           a.ID,
+		Label:        a.Label,
+		CEP:          a.CEP,
+		Street:       a.Street,
+		Number:       a.Number,
+		Complement:   a.Complement,
+		Neighborhood: a.Neighborhood,
+		City:         a.City,
+		State:        a.State,
+		Default:      a.IsDefault,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// CustomerChange é o log append-only que alimenta o feed de alterações.
+// UPDATE e DELETE são bloqueados por trigger (database.migrateCustomerChanges).
+// TransactionID é o pg_current_xact_id() de quem gravou; as linhas anteriores a ele ficaram com zero.
+type CustomerChange struct {
+	Sequence      uint64 `gorm:"primaryKey;autoIncrement;index:idx_customer_changes_position,priority:2"`
+	TransactionID uint64 `gorm:"not null;default:0;index:idx_customer_changes_position,priority:1"`
+	CustomerID    uint   `gorm:"index;not null"`
+	Operation     string `gorm:"size:20;not null"`
+	ChangedFields string `gorm:"size:200"`
+	ChangedAt     time.Time
+}
+
+func (c CustomerChange) Position() entities.CustomerChangePosition {
+	return entities.CustomerChangePosition{TransactionID: c.TransactionID, Sequence: c.Sequence}
+}
+
+func (c CustomerChange) ToDomain() entities.CustomerChange {
+	changedFields := []string{}
+	if c.ChangedFields != "" {
+		changedFields = strings.Split(c.ChangedFields, ",")
+	}
+
+	return entities.CustomerChange{
+		Sequence:      c.Sequence,
+		Operation:     c.Operation,
+		CustomerID:    c.CustomerID,
+		ChangedAt:     c.ChangedAt,
+		ChangedFields: changedFields,
+	}
+}
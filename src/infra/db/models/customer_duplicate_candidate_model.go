@@ -0,0 +1,34 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// CustomerDuplicateCandidate é um par suspeito encontrado pela detecção de duplicidades.
+// O par é único com o menor id em CustomerID, para que cada execução atualize o mesmo registro.
+type CustomerDuplicateCandidate struct {
+	ID              uint    `gorm:"primaryKey"`
+	CustomerID      uint    `gorm:"not null;uniqueIndex:idx_customer_duplicate_candidates_pair"`
+	OtherCustomerID uint    `gorm:"not null;index;uniqueIndex:idx_customer_duplicate_candidates_pair"`
+	Score           float64 `gorm:"not null"`
+	Reasons         string  `gorm:"size:50;not null"`
+	Status          string  `gorm:"size:20;not null;index"`
+	DetectedAt      time.Time
+	ResolvedAt      *time.Time
+}
+
+func (c CustomerDuplicateCandidate) ToDomain() entities.DuplicateCandidate {
+	return entities.DuplicateCandidate{
+		ID:              c.ID,
+		CustomerID:      c.CustomerID,
+		OtherCustomerID: c.OtherCustomerID,
+		Score:           c.Score,
+		Reasons:         strings.Split(c.Reasons, ","),
+		Status:          c.Status,
+		DetectedAt:      c.DetectedAt,
+		ResolvedAt:      c.ResolvedAt,
+	}
+}
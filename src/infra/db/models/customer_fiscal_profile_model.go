@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// CustomerFiscalProfile guarda os dados da nota fiscal do cliente. Documento, razão social,
+// inscrição estadual e email ficam cifrados: com CPF são dados pessoais.
+type CustomerFiscalProfile struct {
+	CustomerID        uint   `gorm:"primaryKey;autoIncrement:false"`
+	DocumentType      string `gorm:"size:4;not null"`
+	Document          string `gorm:"serializer:encrypted;not null"`
+	LegalName         string `gorm:"serializer:encrypted"`
+	StateRegistration string `gorm:"serializer:encrypted"`
+	FiscalEmail       string `gorm:"serializer:encrypted"`
+	UpdatedAt         time.Time
+}
+
+func NewCustomerFiscalProfile(profile entities.FiscalProfile) CustomerFiscalProfile {
+	return CustomerFiscalProfile{
+		CustomerID:        profile.CustomerID,
+		DocumentType:      string(profile.DocumentType),
+		Document:          profile.Document,
+		LegalName:         profile.LegalName,
+		StateRegistration: profile.StateRegistration,
+		FiscalEmail:       profile.FiscalEmail,
+	}
+}
+
+func (p CustomerFiscalProfile) ToDomain() entities.FiscalProfile {
+	return entities.FiscalProfile{
+		CustomerID:        p.CustomerID,
+		DocumentType:      entities.FiscalDocumentType(p.DocumentType),
+		Document:          p.Document,
+		LegalName:         p.LegalName,
+		StateRegistration: p.StateRegistration,
+		FiscalEmail:       p.FiscalEmail,
+		UpdatedAt:         p.UpdatedAt,
+	}
+}
@@ -1,24 +1,200 @@
 package models
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
 	"github.com/CAVAh/api-tech-challenge/src/utils"
 	"gorm.io/gorm"
 )
 
+// propósitos dos índices cegos; mudar invalida os índices gravados
+const (
+	CustomerCPFIndexPurpose      = "customers.cpf"
+	CustomerDocumentIndexPurpose = "customers.document"
+	CustomerEmailIndexPurpose    = "customers.email"
+	CustomerPhoneIndexPurpose    = "customers.phone"
+	PhoneCodeHashPurpose         = "customers.phone_code"
+	SessionCodeHashPurpose       = "customers.session_code"
+)
+
+// CPF e email ficam cifrados; busca e unicidade usam os índices cegos.
+// As colunas cpf e cpf_index guardam o documento de qualquer tipo: DocumentType vazio é CPF,
+// como nos clientes anteriores aos documentos estrangeiros. O índice de um CPF continua sendo
+// o HMAC só do número; o dos demais documentos inclui tipo e país (propósito diferente),
+// então a unicidade fica por tipo e país sem colidir com os CPFs.
+// O telefone só recebe índice depois de verificado: a unicidade vale entre os telefones verificados,
+// para que cadastrar o número de outra pessoa não impeça o titular de usá-lo.
+// Status tem default active para que os clientes anteriores à máquina de estados continuem ativos.
 type Customer struct {
 	gorm.Model
-	Name  string
-	CPF   string `gorm:"unique;index"`
-	Email string `gorm:"unique;index"`
+	Name            string
+	CPF             string `gorm:"serializer:encrypted"`
+	DocumentType    string `gorm:"size:8"`
+	DocumentCountry string `gorm:"size:2"`
+	Email           string `gorm:"serializer:encrypted"`
+	Phone           string `gorm:"serializer:encrypted"`
+	PhoneVerifiedAt *time.Time
+	Status          string  `gorm:"size:30;not null;default:active;index"`
+	CPFIndex        *string `gorm:"size:64;uniqueIndex"`
+	EmailIndex      *string `gorm:"size:64;uniqueIndex"`
+	PhoneIndex      *string `gorm:"size:64;uniqueIndex"`
+}
+
+// NewCustomer monta o registro a partir da entidade; o CPF fica com o DocumentType vazio
+func NewCustomer(entity entities.Customer) Customer {
+	customer := Customer{
+		Name:   entity.Name,
+		CPF:    entity.CPF,
+		Email:  entity.Email,
+		Status: string(entity.Status),
+	}
+
+	if customer.Status == "" {
+		customer.Status = string(entities.CustomerActive)
+	}
+
+	if entity.Document != nil && entity.Document.Type != entities.DocumentCPF {
+		customer.CPF = entity.Document.Number
+		customer.DocumentType = string(entity.Document.Type)
+		customer.DocumentCountry = entity.Document.Country
+	}
+
+	// o telefone de um cadastro novo ainda não foi verificado
+	if entity.Phone != nil {
+		customer.Phone = entity.Phone.Number
+	}
+
+	return customer
+}
+
+// Document é o documento gravado, seja CPF ou estrangeiro
+func (c Customer) Document() entities.IdentityDocument {
+	if c.DocumentType == "" {
+		return entities.CPFDocument(c.CPF)
+	}
+
+	return entities.IdentityDocument{
+		Type:    entities.DocumentType(c.DocumentType),
+		Number:  c.CPF,
+		Country: c.DocumentCountry,
+	}
+}
+
+// BeforeSave recalcula os índices cegos a cada gravação
+func (c *Customer) BeforeSave(*gorm.DB) error {
+	cpfIndex, err := CustomerDocumentIndex(c.Document())
+	if err != nil {
+		return err
+	}
+
+	emailIndex, err := CustomerEmailIndex(c.Email)
+	if err != nil {
+		return err
+	}
+
+	c.CPFIndex = &cpfIndex
+	c.EmailIndex = &emailIndex
+	c.PhoneIndex = nil
+
+	if c.PhoneVerifiedAt != nil && c.Phone != "" {
+		phoneIndex, err := CustomerPhoneIndex(c.Phone)
+		if err != nil {
+			return err
+		}
+		c.PhoneIndex = &phoneIndex
+	}
+
+	return nil
+}
+
+func CustomerCPFIndex(cpf string) (string, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return "", encryption.ErrNotConfigured
+	}
+
+	return keyring.BlindIndex(CustomerCPFIndexPurpose, cpf), nil
+}
+
+// CustomerDocumentIndex é o índice cego do documento com o keyring padrão
+func CustomerDocumentIndex(document entities.IdentityDocument) (string, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return "", encryption.ErrNotConfigured
+	}
+
+	return DocumentBlindIndex(keyring, document), nil
+}
+
+// DocumentBlindIndex calcula o índice do documento: para CPF, o mesmo de CustomerCPFIndex
+func DocumentBlindIndex(keyring *encryption.Keyring, document entities.IdentityDocument) string {
+	if document.Type == entities.DocumentCPF {
+		return keyring.BlindIndex(CustomerCPFIndexPurpose, document.Number)
+	}
+
+	return keyring.BlindIndex(CustomerDocumentIndexPurpose, document.Key())
+}
+
+func CustomerEmailIndex(email string) (string, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return "", encryption.ErrNotConfigured
+	}
+
+	return keyring.BlindIndex(CustomerEmailIndexPurpose, email), nil
+}
+
+func CustomerPhoneIndex(phone string) (string, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return "", encryption.ErrNotConfigured
+	}
+
+	return keyring.BlindIndex(CustomerPhoneIndexPurpose, phone), nil
+}
+
+// PhoneCodeHash é o HMAC do código de verificação, amarrado ao cliente e ao telefone
+func PhoneCodeHash(customerID uint, phone string, code string) (string, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return "", encryption.ErrNotConfigured
+	}
+
+	return keyring.BlindIndex(PhoneCodeHashPurpose, strconv.FormatUint(uint64(customerID), 10)+":"+phone+":"+code), nil
+}
+
+// SessionCodeHash é o HMAC do código que confirma a sessão, amarrado ao cliente, ao canal e ao destino
+func SessionCodeHash(customerID uint, channel string, destination string, code string) (string, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return "", encryption.ErrNotConfigured
+	}
+
+	return keyring.BlindIndex(SessionCodeHashPurpose, strconv.FormatUint(uint64(customerID), 10)+":"+channel+":"+destination+":"+code), nil
 }
 
 func (c Customer) ToDomain() entities.Customer {
-	return entities.Customer{
+	customer := entities.Customer{
 		ID:        c.ID,
 		Name:      c.Name,
-		CPF:       c.CPF,
 		Email:     c.Email,
+		Status:    entities.CustomerStatus(c.Status),
 		CreatedAt: c.CreatedAt.Format(utils.CompleteEnglishDateFormat),
 	}
+
+	if c.DocumentType == "" {
+		customer.CPF = c.CPF
+	} else {
+		document := c.Document()
+		customer.Document = &document
+	}
+
+	if c.Phone != "" {
+		customer.Phone = &entities.CustomerPhone{Number: c.Phone, Verified: c.PhoneVerifiedAt != nil}
+	}
+
+	return customer
 }
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// CustomerPhoneVerification é o código de verificação pendente do telefone, um por cliente.
+// O código fica só como HMAC (PhoneCodeHash); o telefone fica cifrado como no cadastro.
+type CustomerPhoneVerification struct {
+	CustomerID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Phone      string `gorm:"serializer:encrypted;not null"`
+	CodeHash   string `gorm:"size:64;not null"`
+	Attempts   int    `gorm:"not null;default:0"`
+	SentAt     time.Time
+	ExpiresAt  time.Time
+}
+
+func (v CustomerPhoneVerification) ToDomain() entities.PhoneVerification {
+	return entities.PhoneVerification{
+		CustomerID: v.CustomerID,
+		Phone:      v.Phone,
+		Attempts:   v.Attempts,
+		SentAt:     v.SentAt,
+		ExpiresAt:  v.ExpiresAt,
+	}
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+)
+
+// CustomerPreferencesHashPurpose é o propósito do HMAC do hash das preferências; mudar muda todos os hashes
+const CustomerPreferencesHashPurpose = "customer_preferences.hash"
+
+// o hash vai no token de sessão, então é curto; 64 bits bastam para detectar mudanças
+const preferencesHashLength = 16
+
+// CustomerPreferences guarda as preferências alimentares cifradas, por serem dados de saúde.
+// O hash é um HMAC com a chave dos índices cegos: sem a chave não dá para testar
+// combinações do vocabulário contra o hash exposto no token.
+type CustomerPreferences struct {
+	CustomerID  uint   `gorm:"primaryKey;autoIncrement:false"`
+	Preferences string `gorm:"serializer:encrypted;not null"`
+	Hash        string `gorm:"size:16;not null"`
+	UpdatedAt   time.Time
+}
+
+type storedPreferences struct {
+	Allergens           []string `json:"allergens"`
+	DietaryStyles       []string `json:"dietaryStyles"`
+	DislikedIngredients []string `json:"dislikedIngredients"`
+}
+
+func NewCustomerPreferences(preferences entities.DietaryPreferences) (CustomerPreferences, error) {
+	data, err := json.Marshal(storedPreferences{
+		Allergens:           preferences.Allergens,
+		DietaryStyles:       preferences.DietaryStyles,
+		DislikedIngredients: preferences.DislikedIngredients,
+	})
+	if err != nil {
+		return CustomerPreferences{}, err
+	}
+
+	hash, err := PreferencesHash(preferences)
+	if err != nil {
+		return CustomerPreferences{}, err
+	}
+
+	return CustomerPreferences{
+		CustomerID:  preferences.CustomerID,
+		Preferences: string(data),
+		Hash:        hash,
+	}, nil
+}
+
+func PreferencesHash(preferences entities.DietaryPreferences) (string, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return "", encryption.ErrNotConfigured
+	}
+
+	return keyring.BlindIndex(CustomerPreferencesHashPurpose, preferences.Canonical())[:preferencesHashLength], nil
+}
+
+func (p CustomerPreferences) ToDomain() (entities.DietaryPreferences, error) {
+	var stored storedPreferences
+	if err := json.Unmarshal([]byte(p.Preferences), &stored); err != nil {
+		return entities.DietaryPreferences{}, err
+	}
+
+	updatedAt := p.UpdatedAt
+
+	return entities.DietaryPreferences{
+		CustomerID:          p.CustomerID,
+		Allergens:           stored.Allergens,
+		DietaryStyles:       stored.DietaryStyles,
+		DislikedIngredients: stored.DislikedIngredients,
+		Hash:                p.Hash,
+		UpdatedAt:           &updatedAt,
+	}, nil
+}
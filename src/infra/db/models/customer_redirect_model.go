@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// CustomerRedirect aponta o id de um cliente incorporado numa fusão para o sobrevivente.
+// Fusões encadeadas reapontam os redirecionamentos antigos, então ToID é sempre um cliente ativo.
+type CustomerRedirect struct {
+	FromID    uint `gorm:"primaryKey;autoIncrement:false"`
+	ToID      uint `gorm:"not null;index"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// CustomerSessionVerification é o código pendente que confirma a sessão, um por cliente.
+// O código fica só como HMAC (SessionCodeHash); o destino, telefone ou email, fica cifrado.
+type CustomerSessionVerification struct {
+	CustomerID  uint   `gorm:"primaryKey;autoIncrement:false"`
+	Channel     string `gorm:"size:8;not null"`
+	Destination string `gorm:"serializer:encrypted;not null"`
+	CodeHash    string `gorm:"size:64;not null"`
+	Attempts    int    `gorm:"not null;default:0"`
+	SentAt      time.Time
+	ExpiresAt   time.Time
+}
+
+func (v CustomerSessionVerification) ToDomain() entities.SessionVerification {
+	return entities.SessionVerification{
+		CustomerID:  v.CustomerID,
+		Channel:     v.Channel,
+		Destination: v.Destination,
+		Attempts:    v.Attempts,
+		SentAt:      v.SentAt,
+		ExpiresAt:   v.ExpiresAt,
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// CustomerStatusChange é o histórico das transições de status, com o motivo e o ator de cada uma
+type CustomerStatusChange struct {
+	ID         uint   `gorm:"primaryKey"`
+	CustomerID uint   `gorm:"not null;index"`
+	FromStatus string `gorm:"size:30;not null"`
+	ToStatus   string `gorm:"size:30;not null"`
+	Reason     string `gorm:"size:500;not null"`
+	ChangedBy  string `gorm:"size:100;not null"`
+	ChangedAt  time.Time
+}
+
+func (c CustomerStatusChange) ToDomain() entities.CustomerStatusChange {
+	return entities.CustomerStatusChange{
+		CustomerID: c.CustomerID,
+		From:       entities.CustomerStatus(c.FromStatus),
+		To:         entities.CustomerStatus(c.ToStatus),
+		Reason:     c.Reason,
+		ChangedBy:  c.ChangedBy,
+		ChangedAt:  c.ChangedAt,
+	}
+}
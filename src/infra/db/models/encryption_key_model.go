@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+)
+
+// EncryptionKey é uma chave de dados embrulhada pela chave mestra
+type EncryptionKey struct {
+	ID         string `gorm:"primaryKey;size:36"`
+	WrappedKey []byte `gorm:"not null"`
+	KEKID      string `gorm:"column:kek_id;not null"`
+	Active     bool   `gorm:"not null;default:false"`
+	CreatedAt  time.Time
+}
+
+func NewEncryptionKey(key encryption.WrappedKey) EncryptionKey {
+	return EncryptionKey{
+		ID:         key.ID,
+		WrappedKey: key.WrappedKey,
+		KEKID:      key.KEKID,
+		Active:     key.Active,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func (k EncryptionKey) ToWrappedKey() encryption.WrappedKey {
+	return encryption.WrappedKey{
+		ID:         k.ID,
+		WrappedKey: k.WrappedKey,
+		KEKID:      k.KEKID,
+		Active:     k.Active,
+		CreatedAt:  k.CreatedAt,
+	}
+}
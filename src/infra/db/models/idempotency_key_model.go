@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type IdempotencyKey struct {
+	Key         string `gorm:"primaryKey;size:300"`
+	Fingerprint string `gorm:"size:64;not null"`
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
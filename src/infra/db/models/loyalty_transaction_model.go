@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+)
+
+// LoyaltyTransaction é o extrato de pontos, append-only: UPDATE e DELETE são bloqueados
+// por trigger (database.migrateLoyaltyTransactions), a não ser a troca de cliente feita pela
+// fusão (database.CustomerMergeSetting). O par tipo e referência é único.
+type LoyaltyTransaction struct {
+	ID          uint   `gorm:"primaryKey"`
+	CustomerID  uint   `gorm:"index;not null"`
+	Type        string `gorm:"size:20;not null;uniqueIndex:idx_loyalty_transactions_reference"`
+	Points      int64  `gorm:"not null"`
+	Reference   string `gorm:"size:100;not null;uniqueIndex:idx_loyalty_transactions_reference"`
+	Description string `gorm:"size:200"`
+	AmountCents int64
+	ExpiresAt   *time.Time
+	LotID       *uint
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func NewLoyaltyTransaction(transaction entities.LoyaltyTransaction) LoyaltyTransaction {
+	return LoyaltyTransaction{
+		CustomerID:  transaction.CustomerID,
+		Type:        transaction.Type,
+		Points:      transaction.Points,
+		Reference:   transaction.Reference,
+		Description: transaction.Description,
+		AmountCents: transaction.AmountCents,
+		ExpiresAt:   transaction.ExpiresAt,
+		LotID:       transaction.LotID,
+		CreatedAt:   transaction.CreatedAt,
+	}
+}
+
+func (t LoyaltyTransaction) ToDomain() entities.LoyaltyTransaction {
+	return entities.LoyaltyTransaction{
+		ID:          t.ID,
+		CustomerID:  t.CustomerID,
+		Type:        t.Type,
+		Points:      t.Points,
+		Reference:   t.Reference,
+		Description: t.Description,
+		AmountCents: t.AmountCents,
+		ExpiresAt:   t.ExpiresAt,
+		LotID:       t.LotID,
+		CreatedAt:   t.CreatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"gorm.io/gorm"
+)
+
+// OutboxEvent guarda o evento na mesma transação da alteração do cliente.
+// O ID sequencial define a ordem de publicação; EventID é o id do envelope usado para deduplicação.
+// O payload traz dados do cliente e é gravado cifrado (BeforeCreate).
+type OutboxEvent struct {
+	ID            uint   `gorm:"primaryKey"`
+	EventID       string `gorm:"size:36;uniqueIndex;not null"`
+	Type          string `gorm:"size:100;not null"`
+	SchemaVersion int
+	AggregateType string `gorm:"size:50;not null"`
+	AggregateID   string `gorm:"size:50;index;not null"`
+	Payload       []byte `gorm:"not null"`
+	OccurredAt    time.Time
+	PublishedAt   *time.Time `gorm:"index"`
+	Attempts      int
+	LastError     string
+}
+
+func NewOutboxEvent(envelope events.Envelope) (*OutboxEvent, error) {
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		EventID:       envelope.ID,
+		Type:          envelope.Type,
+		SchemaVersion: envelope.SchemaVersion,
+		AggregateType: envelope.AggregateType,
+		AggregateID:   envelope.AggregateID,
+		Payload:       payload,
+		OccurredAt:    envelope.OccurredAt,
+	}, nil
+}
+
+// BeforeCreate cifra o payload com o keyring padrão
+func (e *OutboxEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	e.Payload, err = SealPayload(tx.Statement.Context, OutboxPayloadField, e.Payload)
+
+	return err
+}
+
+func (e OutboxEvent) ToEnvelope(ctx context.Context) (events.Envelope, error) {
+	var envelope events.Envelope
+
+	payload, err := OpenPayload(ctx, OutboxPayloadField, e.Payload)
+	if err != nil {
+		return envelope, err
+	}
+
+	err = json.Unmarshal(payload, &envelope)
+
+	return envelope, err
+}
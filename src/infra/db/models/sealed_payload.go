@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+)
+
+// Nomes usados como dado associado dos payloads cifrados, para que um payload copiado para outra tabela não decifre
+const (
+	OutboxPayloadField          = "outbox_events.payload"
+	WebhookDeliveryPayloadField = "webhook_deliveries.payload"
+	IdempotencyBodyField        = "idempotency_keys.body"
+)
+
+// SealPayload cifra com o keyring padrão um payload que traz dados de clientes
+func SealPayload(ctx context.Context, field string, payload []byte) ([]byte, error) {
+	keyring := encryption.Default()
+	if keyring == nil {
+		return nil, encryption.ErrNotConfigured
+	}
+
+	sealed, err := keyring.Encrypt(ctx, field, string(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(sealed), nil
+}
+
+// OpenPayload decifra um payload de SealPayload. Payloads gravados antes da criptografia voltam como estão.
+func OpenPayload(ctx context.Context, field string, payload []byte) ([]byte, error) {
+	if !encryption.IsEncrypted(string(payload)) {
+		return payload, nil
+	}
+
+	keyring := encryption.Default()
+	if keyring == nil {
+		return nil, encryption.ErrNotConfigured
+	}
+
+	plaintext, err := keyring.Decrypt(ctx, field, string(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(plaintext), nil
+}
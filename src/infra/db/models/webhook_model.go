@@ -0,0 +1,87 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"gorm.io/gorm"
+)
+
+type WebhookSubscription struct {
+	gorm.Model
+	URL        string `gorm:"size:2048;not null"`
+	EventTypes string `gorm:"size:500;not null"`
+	Secret     string `gorm:"size:100;not null"`
+	Active     bool   `gorm:"not null"`
+}
+
+// WebhookDelivery é uma entrega de um evento para uma assinatura; o índice único evita
+// duplicar a entrega quando o relay republica o mesmo evento. AggregateID permite apagar as
+// entregas de um cliente eliminado.
+type WebhookDelivery struct {
+	ID             uint   `gorm:"primaryKey"`
+	SubscriptionID uint   `gorm:"not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventID        string `gorm:"size:36;not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventType      string `gorm:"size:100;not null"`
+	AggregateType  string `gorm:"size:50;index:idx_webhook_deliveries_aggregate"`
+	AggregateID    string `gorm:"size:50;index:idx_webhook_deliveries_aggregate"`
+	Payload        []byte `gorm:"not null"`
+	Status         string `gorm:"size:20;not null;index:idx_webhook_deliveries_due"`
+	Attempts       int
+	NextAttemptAt  time.Time `gorm:"index:idx_webhook_deliveries_due"`
+	LastStatusCode int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type WebhookDeliveryAttempt struct {
+	ID         uint `gorm:"primaryKey"`
+	DeliveryID uint `gorm:"index;not null"`
+	Attempt    int
+	StatusCode int
+	Error      string
+	DurationMs int64
+	CreatedAt  time.Time
+}
+
+func (s WebhookSubscription) ToDomain() entities.WebhookSubscription {
+	return entities.WebhookSubscription{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: strings.Split(s.EventTypes, ","),
+		Secret:     s.Secret,
+		Active:     s.Active,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+func (d WebhookDelivery) ToDomain() entities.WebhookDelivery {
+	return entities.WebhookDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		AttemptLog:     []entities.WebhookDeliveryAttempt{},
+	}
+}
+
+func (a WebhookDeliveryAttempt) ToDomain() entities.WebhookDeliveryAttempt {
+	return entities.WebhookDeliveryAttempt{
+		Attempt:    a.Attempt,
+		StatusCode: a.StatusCode,
+		Error:      a.Error,
+		DurationMs: a.DurationMs,
+		CreatedAt:  a.CreatedAt,
+	}
+}
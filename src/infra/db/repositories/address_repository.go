@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+)
+
+const (
+	// travar a linha do cliente serializa as alterações dos dados ligados a ele e a eliminação do cliente
+	lockActiveCustomerSQL = `SELECT id FROM customers WHERE id = ? AND deleted_at IS NULL FOR UPDATE`
+	selectAddressesSQL    = `SELECT * FROM customer_addresses WHERE customer_id = ? ORDER BY id`
+)
+
+// lockActiveCustomer trava a linha do cliente na transação; cliente eliminado ou inexistente é ErrCustomerNotFound
+func lockActiveCustomer(tx database.Database, customerID uint) error {
+	var ids []uint
+	if err := tx.Raw(&ids, lockActiveCustomerSQL, customerID); err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return entities.ErrCustomerNotFound
+	}
+
+	return nil
+}
+
+type AddressRepository struct {
+	DB database.Database
+}
+
+func (r AddressRepository) FindByCustomer(ctx context.Context, customerID uint) (_ *entities.AddressBook, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_addresses", start, err) }(time.Now())
+
+	var addresses []models.CustomerAddress
+	if err := r.DB.WithContext(ctx).Raw(&addresses, selectAddressesSQL, customerID); err != nil {
+		return nil, err
+	}
+
+	return addressBook(customerID, addresses), nil
+}
+
+// Update aplica change ao caderno atual e grava a diferença: endereços que sumiram são apagados,
+// os sem id são criados e os alterados são salvos, cada um com sua entrada de auditoria
+func (r AddressRepository) Update(ctx context.Context, customerID uint, change func(book *entities.AddressBook) error) (_ *entities.AddressBook, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("update_addresses", start, err) }(time.Now())
+
+	var book *entities.AddressBook
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := lockActiveCustomer(tx, customerID); err != nil {
+			return err
+		}
+
+		var current []models.CustomerAddress
+		if err := tx.Raw(&current, selectAddressesSQL, customerID); err != nil {
+			return err
+		}
+
+		book = addressBook(customerID, current)
+		original := make(map[uint]entities.Address, len(book.Addresses))
+		for _, address := range book.Addresses {
+			original[address.ID] = address
+		}
+
+		if err := change(book); err != nil {
+			return err
+		}
+
+		kept := make(map[uint]bool, len(book.Addresses))
+		for _, address := range book.Addresses {
+			kept[address.ID] = true
+		}
+
+		var entries []entities.AuditEntry
+
+		for _, address := range current {
+			if kept[address.ID] {
+				continue
+			}
+
+			if err := tx.Delete(&models.CustomerAddress{}, address.ID); err != nil {
+				return err
+			}
+			entries = append(entries, entities.CustomerAuditEntry(entities.AuditActionAddressDeleted, customerID))
+		}
+
+		// o novo padrão é gravado depois do antigo ser desmarcado, senão o índice parcial acusa dois padrões
+		for _, isDefault := range []bool{false, true} {
+			for i := range book.Addresses {
+				address := &book.Addresses[i]
+				if address.Default != isDefault {
+					continue
+				}
+
+				model := models.NewCustomerAddress(customerID, *address)
+
+				switch {
+				case address.ID == 0:
+					if err := tx.Create(&model); err != nil {
+						return err
+					}
+					entries = append(entries, entities.CustomerAuditEntry(entities.AuditActionAddressCreated, customerID))
+				case *address != original[address.ID]:
+					if err := tx.Save(&model); err != nil {
+						return err
+					}
+					entries = append(entries, entities.CustomerAuditEntry(entities.AuditActionAddressUpdated, customerID))
+				default:
+					continue
+				}
+
+				*address = model.ToDomain()
+			}
+		}
+
+		if len(entries) == 0 {
+			return nil
+		}
+
+		return appendAudit(ctx, tx, entries...)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+func addressBook(customerID uint, addresses []models.CustomerAddress) *entities.AddressBook {
+	book := &entities.AddressBook{CustomerID: customerID, Addresses: make([]entities.Address, 0, len(addresses))}
+	for _, address := range addresses {
+		book.Addresses = append(book.Addresses, address.ToDomain())
+	}
+
+	return book
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestUpdateAddresses_NewDefaultIsWrittenAfterOldOneIsCleared(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := AddressRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	expectLockedCustomer(mockDB, 7)
+	mockDB.EXPECT().Raw(gomock.Any(), selectAddressesSQL, uint(7)).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.CustomerAddress) = []models.CustomerAddress{{ID: 1, CustomerID: 7, Label: "Casa", IsDefault: true}}
+		return nil
+	})
+
+	saved := mockDB.EXPECT().Save(gomock.AssignableToTypeOf(&models.CustomerAddress{})).DoAndReturn(func(data interface{}) error {
+		assert.False(t, data.(*models.CustomerAddress).IsDefault)
+		return nil
+	})
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.CustomerAddress{})).DoAndReturn(func(data interface{}) error {
+		model := data.(*models.CustomerAddress)
+		assert.True(t, model.IsDefault)
+		assert.Equal(t, uint(7), model.CustomerID)
+		model.ID = 2
+		return nil
+	}).After(saved)
+	auditEntry := expectAudit(mockDB)
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.AuditEntry{})).Return(nil)
+
+	book, err := repo.Update(context.Background(), 7, func(book *entities.AddressBook) error {
+		_, err := book.Add(entities.Address{Label: "Trabalho", Default: true}, 10)
+		return err
+	})
+	require.NoError(t, err)
+	require.Len(t, book.Addresses, 2)
+	assert.False(t, book.Addresses[0].Default)
+	assert.Equal(t, uint(2), book.Addresses[1].ID)
+	assert.True(t, book.Addresses[1].Default)
+	assert.Equal(t, entities.AuditActionAddressUpdated, auditEntry.Action)
+	assert.Equal(t, "7", auditEntry.TargetID)
+}
+
+func TestUpdateAddresses_RemovedAddressIsDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := AddressRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	expectLockedCustomer(mockDB, 7)
+	mockDB.EXPECT().Raw(gomock.Any(), selectAddressesSQL, uint(7)).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.CustomerAddress) = []models.CustomerAddress{
+			{ID: 1, CustomerID: 7, Label: "Casa", IsDefault: true},
+			{ID: 2, CustomerID: 7, Label: "Trabalho"},
+		}
+		return nil
+	})
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerAddress{}), uint(1)).Return(nil)
+	mockDB.EXPECT().Save(gomock.AssignableToTypeOf(&models.CustomerAddress{})).DoAndReturn(func(data interface{}) error {
+		assert.Equal(t, uint(2), data.(*models.CustomerAddress).ID)
+		assert.True(t, data.(*models.CustomerAddress).IsDefault)
+		return nil
+	})
+	auditEntry := expectAudit(mockDB)
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.AuditEntry{})).Return(nil)
+
+	book, err := repo.Update(context.Background(), 7, func(book *entities.AddressBook) error {
+		return book.Remove(1)
+	})
+	require.NoError(t, err)
+	require.Len(t, book.Addresses, 1)
+	assert.True(t, book.Addresses[0].Default)
+	assert.Equal(t, entities.AuditActionAddressDeleted, auditEntry.Action)
+}
+
+func TestUpdateAddresses_ErasedCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := AddressRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), lockActiveCustomerSQL, uint(7)).Return(nil)
+
+	_, err := repo.Update(context.Background(), 7, func(book *entities.AddressBook) error {
+		t.Fatal("change não deve ser chamado para cliente inexistente")
+		return nil
+	})
+	assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+}
+
+func expectLockedCustomer(mockDB *mocks.MockDatabase, customerID uint) {
+	mockDB.EXPECT().Raw(gomock.Any(), lockActiveCustomerSQL, customerID).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]uint) = []uint{customerID}
+		return nil
+	})
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/audit"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+)
+
+const selectLastAuditEntrySQL = `SELECT * FROM audit_entries ORDER BY sequence DESC LIMIT 1`
+
+const selectAuditEntriesSQL = `SELECT * FROM audit_entries WHERE sequence > ? ORDER BY sequence LIMIT ?`
+
+type AuditRepository struct {
+	DB database.Database
+}
+
+func (r AuditRepository) Record(ctx context.Context, entries ...entities.AuditEntry) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("record_audit", start, err) }(time.Now())
+
+	return r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		return appendAudit(ctx, tx, entries...)
+	})
+}
+
+func (r AuditRepository) List(ctx context.Context, filter entities.AuditFilter, afterSequence uint64, limit int) (_ []entities.AuditEntry, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("list_audit", start, err) }(time.Now())
+
+	conditions := []string{"sequence > ?"}
+	values := []interface{}{afterSequence}
+
+	for _, condition := range []struct{ column, value string }{
+		{"actor", filter.Actor},
+		{"action", filter.Action},
+		{"target_type", filter.TargetType},
+		{"target_id", filter.TargetID},
+	} {
+		if condition.value != "" {
+			conditions = append(conditions, condition.column+" = ?")
+			values = append(values, condition.value)
+		}
+	}
+
+	if filter.From != nil {
+		conditions = append(conditions, "occurred_at >= ?")
+		values = append(values, *filter.From)
+	}
+
+	if filter.To != nil {
+		conditions = append(conditions, "occurred_at < ?")
+		values = append(values, *filter.To)
+	}
+
+	sql := "SELECT * FROM audit_entries WHERE " + strings.Join(conditions, " AND ") + " ORDER BY sequence LIMIT ?"
+	values = append(values, limit)
+
+	var entries []models.AuditEntry
+	if err := r.DB.WithContext(ctx).Raw(&entries, sql, values...); err != nil {
+		return nil, err
+	}
+
+	return auditEntriesToDomain(entries), nil
+}
+
+// ListAll lê o log sem filtros, para a verificação da cadeia
+func (r AuditRepository) ListAll(ctx context.Context, afterSequence uint64, limit int) (_ []entities.AuditEntry, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("list_all_audit", start, err) }(time.Now())
+
+	var entries []models.AuditEntry
+	if err := r.DB.WithContext(ctx).Raw(&entries, selectAuditEntriesSQL, afterSequence, limit); err != nil {
+		return nil, err
+	}
+
+	return auditEntriesToDomain(entries), nil
+}
+
+func auditEntriesToDomain(entries []models.AuditEntry) []entities.AuditEntry {
+	result := make([]entities.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.ToDomain())
+	}
+
+	return result
+}
+
+// appendAudit grava as entradas encadeadas à última entrada do log. O advisory lock vale até
+// o fim da transação, então a entrada fica no log se e somente se a alteração auditada for gravada.
+func appendAudit(ctx context.Context, tx database.Database, entries ...entities.AuditEntry) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", database.AuditEntriesLockKey); err != nil {
+		return err
+	}
+
+	var last models.AuditEntry
+	if err := tx.Raw(&last, selectLastAuditEntrySQL); err != nil {
+		return err
+	}
+
+	stamped := make([]entities.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		stamped = append(stamped, audit.Stamp(ctx, entry))
+	}
+
+	for _, entry := range audit.Chain(last.Hash, stamped) {
+		model := models.NewAuditEntry(entry)
+		if err := tx.Create(&model); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
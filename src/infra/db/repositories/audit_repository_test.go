@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/audit"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestRecordAudit_ChainsEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := AuditRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Exec("SELECT pg_advisory_xact_lock(?)", database.AuditEntriesLockKey).Return(nil)
+	mockDB.EXPECT().Raw(gomock.Any(), selectLastAuditEntrySQL).Return(nil)
+
+	var created []models.AuditEntry
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.AuditEntry{})).Times(2).DoAndReturn(func(data interface{}) error {
+		created = append(created, *data.(*models.AuditEntry))
+		return nil
+	})
+
+	ctx := audit.WithActor(context.Background(), audit.Actor{ID: "ops", Role: "admin"})
+	err := repo.Record(ctx,
+		entities.CustomerAuditEntry(entities.AuditActionCustomerRead, 1),
+		entities.CustomerAuditEntry(entities.AuditActionCustomerRead, 2),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, created, 2)
+	assert.Equal(t, "ops", created[0].Actor)
+	assert.Empty(t, created[0].PrevHash)
+	assert.Equal(t, created[0].Hash, created[1].PrevHash)
+	assert.Equal(t, audit.Hash(created[1].ToDomain()), created[1].Hash)
+}
+
+func TestListAudit_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := AuditRepository{DB: mockDB}
+
+	from := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(),
+		"SELECT * FROM audit_entries WHERE sequence > ? AND action = ? AND target_id = ? AND occurred_at >= ? ORDER BY sequence LIMIT ?",
+		uint64(3), entities.AuditActionCustomerRead, "9", from, 50,
+	).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.AuditEntry) = []models.AuditEntry{{Sequence: 4, Action: entities.AuditActionCustomerRead, TargetID: "9"}}
+		return nil
+	})
+
+	entries, err := repo.List(context.Background(), entities.AuditFilter{
+		Action:   entities.AuditActionCustomerRead,
+		TargetID: "9",
+		From:     &from,
+	}, 3, 50)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint64(4), entries[0].Sequence)
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+)
+
+// só entram transações abaixo do xmin do snapshot, isto é, já encerradas: qualquer alteração gravada
+// depois vem de uma transação com id maior e não pode aparecer atrás do cursor
+const selectChangesSQL = `SELECT * FROM customer_changes
+	WHERE (transaction_id, sequence) > (?, ?) AND transaction_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+	ORDER BY transaction_id, sequence LIMIT ?`
+
+type CustomerChangeRepository struct {
+	DB database.Database
+}
+
+// ListChanges lê o log de alterações e junta o estado atual de cada cliente.
+// Criações e atualizações de clientes que já foram apagados são omitidas: o tombstone
+// que vem depois basta para o consumidor remover a cópia, e os dados já foram anonimizados.
+// O tombstone de uma fusão aponta o sobrevivente atual, mesmo depois de fusões encadeadas.
+func (r CustomerChangeRepository) ListChanges(ctx context.Context, after entities.CustomerChangePosition, limit int) (_ []entities.CustomerChange, _ entities.CustomerChangePosition, _ bool, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("list_customer_changes", start, err) }(time.Now())
+
+	db := r.DB.WithContext(ctx)
+
+	var changes []models.CustomerChange
+	if err := db.Raw(&changes, selectChangesSQL, after.TransactionID, after.Sequence, limit); err != nil {
+		return nil, after, false, err
+	}
+
+	result := []entities.CustomerChange{}
+	if len(changes) == 0 {
+		return result, after, false, nil
+	}
+
+	ids := make([]uint, 0, len(changes))
+	var mergedIDs []uint
+	for _, change := range changes {
+		ids = append(ids, change.CustomerID)
+		if change.Operation == entities.CustomerChangeMerged {
+			mergedIDs = append(mergedIDs, change.CustomerID)
+		}
+	}
+
+	// inclui os registros com soft delete para saber quem foi apagado
+	var customers []models.Customer
+	if err := db.Raw(&customers, "SELECT * FROM customers WHERE id IN ?", ids); err != nil {
+		return nil, after, false, err
+	}
+
+	byID := make(map[uint]models.Customer, len(customers))
+	for _, customer := range customers {
+		byID[customer.ID] = customer
+	}
+
+	mergedInto := map[uint]uint{}
+	if len(mergedIDs) > 0 {
+		var redirects []models.CustomerRedirect
+		if err := db.Raw(&redirects, selectRedirectsSQL, mergedIDs); err != nil {
+			return nil, after, false, err
+		}
+
+		for _, redirect := range redirects {
+			mergedInto[redirect.FromID] = redirect.ToID
+		}
+	}
+
+	for _, change := range changes {
+		entry := change.ToDomain()
+
+		switch change.Operation {
+		case entities.CustomerChangeErased:
+			// tombstone sem dados pessoais
+		case entities.CustomerChangeMerged:
+			if to, ok := mergedInto[change.CustomerID]; ok {
+				entry.MergedInto = &to
+			}
+		default:
+			customer, ok := byID[change.CustomerID]
+			if !ok || customer.DeletedAt.Valid {
+				continue
+			}
+
+			domain := customer.ToDomain()
+			entry.Customer = &domain
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, changes[len(changes)-1].Position(), len(changes) == limit, nil
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestListChanges_TombstonesHideErasedCustomers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerChangeRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), selectChangesSQL, uint64(900), uint64(10), 3).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.CustomerChange) = []models.CustomerChange{
+			{TransactionID: 900, Sequence: 11, CustomerID: 1, Operation: entities.CustomerChangeCreated},
+			{TransactionID: 901, Sequence: 14, CustomerID: 2, Operation: entities.CustomerChangeUpdated, ChangedFields: "name,email"},
+			{TransactionID: 903, Sequence: 12, CustomerID: 1, Operation: entities.CustomerChangeErased},
+		}
+		return nil
+	})
+	mockDB.EXPECT().Raw(gomock.Any(), "SELECT * FROM customers WHERE id IN ?", []uint{1, 2, 1}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.Customer) = []models.Customer{
+			{Model: gorm.Model{ID: 1, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}, Name: "apagado-1"},
+			{Model: gorm.Model{ID: 2}, Name: "Jane Doe"},
+		}
+		return nil
+	})
+
+	changes, last, hasMore, err := repo.ListChanges(context.Background(), entities.CustomerChangePosition{TransactionID: 900, Sequence: 10}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CustomerChangePosition{TransactionID: 903, Sequence: 12}, last)
+	assert.True(t, hasMore)
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, "Jane Doe", changes[0].Customer.Name)
+	assert.Equal(t, []string{"name", "email"}, changes[0].ChangedFields)
+	assert.Equal(t, entities.CustomerChangeErased, changes[1].Operation)
+	assert.Nil(t, changes[1].Customer)
+}
+
+func TestListChanges_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerChangeRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), selectChangesSQL, uint64(0), uint64(7), 500).Return(nil)
+
+	changes, last, hasMore, err := repo.ListChanges(context.Background(), entities.CustomerChangePosition{Sequence: 7}, 500)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, entities.CustomerChangePosition{Sequence: 7}, last)
+	assert.False(t, hasMore)
+}
+
+func TestListChanges_MergeTombstonePointsToSurvivor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerChangeRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), selectChangesSQL, uint64(0), uint64(0), 500).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.CustomerChange) = []models.CustomerChange{
+			{Sequence: 1, CustomerID: 2, Operation: entities.CustomerChangeMerged},
+		}
+		return nil
+	})
+	mockDB.EXPECT().Raw(gomock.Any(), "SELECT * FROM customers WHERE id IN ?", []uint{2}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.Customer) = []models.Customer{
+			{Model: gorm.Model{ID: 2, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}, Name: "apagado-2"},
+		}
+		return nil
+	})
+	mockDB.EXPECT().Raw(gomock.Any(), selectRedirectsSQL, []uint{2}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.CustomerRedirect) = []models.CustomerRedirect{{FromID: 2, ToID: 1}}
+		return nil
+	})
+
+	changes, _, _, err := repo.ListChanges(context.Background(), entities.CustomerChangePosition{}, 500)
+	require.NoError(t, err)
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, entities.CustomerChangeMerged, changes[0].Operation)
+	assert.Nil(t, changes[0].Customer)
+	require.NotNil(t, changes[0].MergedInto)
+	assert.Equal(t, uint(1), *changes[0].MergedInto)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+)
+
+// CustomerIndexes expõe os índices cegos de models.Customer com o keyring padrão
+type CustomerIndexes struct{}
+
+func (CustomerIndexes) DocumentIndex(document entities.IdentityDocument) (string, error) {
+	return models.CustomerDocumentIndex(document)
+}
+
+func (CustomerIndexes) EmailIndex(email string) (string, error) {
+	return models.CustomerEmailIndex(email)
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+)
+
+const selectRedirectsSQL = `SELECT * FROM customer_redirects WHERE from_id IN ?`
+
+// CustomerRedirectRepository lê os redirecionamentos gravados por CustomerRepository.Merge.
+// Como a fusão reaponta as fusões anteriores, um único salto basta.
+type CustomerRedirectRepository struct {
+	DB database.Database
+}
+
+func (r CustomerRedirectRepository) Resolve(ctx context.Context, id uint) (uint, error) {
+	redirects, err := r.ResolveMany(ctx, []uint{id})
+	if err != nil {
+		return 0, err
+	}
+
+	if to, ok := redirects[id]; ok {
+		return to, nil
+	}
+
+	return id, nil
+}
+
+func (r CustomerRedirectRepository) ResolveMany(ctx context.Context, ids []uint) (_ map[uint]uint, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("resolve_redirects", start, err) }(time.Now())
+
+	var redirects []models.CustomerRedirect
+	if err := r.DB.WithContext(ctx).Raw(&redirects, selectRedirectsSQL, ids); err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]uint, len(redirects))
+	for _, redirect := range redirects {
+		result[redirect.FromID] = redirect.ToID
+	}
+
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+)
+
+// lê os valores como estão no banco, sem passar pelo serializer
+const selectCustomersForReencryptionSQL = `SELECT id, cpf, document_type, document_country, email, phone, cpf_index, email_index FROM customers WHERE id > ? ORDER BY id LIMIT ?`
+
+// a condição nos valores antigos evita sobrescrever uma gravação concorrente; esse registro
+// já foi gravado com a chave ativa e só é revisitado se o job rodar de novo. O índice do telefone
+// não depende da chave de dados e fica como está.
+const reencryptCustomerSQL = `UPDATE customers SET cpf = ?, email = ?, phone = ?, cpf_index = ?, email_index = ? WHERE id = ? AND cpf = ? AND email = ? AND phone IS NOT DISTINCT FROM ?`
+
+type storedCustomer struct {
+	ID              uint
+	CPF             string
+	DocumentType    *string
+	DocumentCountry *string
+	Email           string
+	Phone           *string
+	CPFIndex        *string
+	EmailIndex      *string
+}
+
+// CustomerReencryptionRepository migra CPF, email e telefone para a chave de dados ativa e preenche
+// os índices cegos. Roda online, em lotes por id, incluindo clientes com soft delete.
+type CustomerReencryptionRepository struct {
+	DB      database.Database
+	Keyring *encryption.Keyring
+}
+
+// ReencryptBatch processa até limit clientes com id maior que afterID e devolve o último id lido
+// (zero quando não há mais clientes) e quantos foram regravados
+func (r CustomerReencryptionRepository) ReencryptBatch(ctx context.Context, afterID uint, limit int) (_ uint, _ int, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("reencrypt_customers", start, err) }(time.Now())
+
+	db := r.DB.WithContext(ctx)
+
+	var customers []storedCustomer
+	if err := db.Raw(&customers, selectCustomersForReencryptionSQL, afterID, limit); err != nil {
+		return 0, 0, err
+	}
+
+	if len(customers) == 0 {
+		return 0, 0, nil
+	}
+
+	updated := 0
+	for _, customer := range customers {
+		if !r.needsUpdate(customer) {
+			continue
+		}
+
+		cpf, err := r.Keyring.Decrypt(ctx, "cpf", customer.CPF)
+		if err != nil {
+			return 0, updated, err
+		}
+
+		email, err := r.Keyring.Decrypt(ctx, "email", customer.Email)
+		if err != nil {
+			return 0, updated, err
+		}
+
+		encryptedCPF, err := r.Keyring.Encrypt(ctx, "cpf", cpf)
+		if err != nil {
+			return 0, updated, err
+		}
+
+		encryptedEmail, err := r.Keyring.Encrypt(ctx, "email", email)
+		if err != nil {
+			return 0, updated, err
+		}
+
+		// clientes anteriores ao telefone têm a coluna nula, que continua nula
+		var encryptedPhone *string
+		if customer.Phone != nil {
+			phone, err := r.Keyring.Decrypt(ctx, "phone", *customer.Phone)
+			if err != nil {
+				return 0, updated, err
+			}
+
+			encrypted, err := r.Keyring.Encrypt(ctx, "phone", phone)
+			if err != nil {
+				return 0, updated, err
+			}
+			encryptedPhone = &encrypted
+		}
+
+		// a coluna cpf guarda o documento de qualquer tipo; o índice depende do tipo
+		stored := models.Customer{CPF: cpf}
+		if customer.DocumentType != nil && customer.DocumentCountry != nil {
+			stored.DocumentType, stored.DocumentCountry = *customer.DocumentType, *customer.DocumentCountry
+		}
+		cpfIndex := models.DocumentBlindIndex(r.Keyring, stored.Document())
+		emailIndex := r.Keyring.BlindIndex(models.CustomerEmailIndexPurpose, email)
+
+		err = db.Exec(reencryptCustomerSQL, encryptedCPF, encryptedEmail, encryptedPhone, cpfIndex, emailIndex,
+			customer.ID, customer.CPF, customer.Email, customer.Phone)
+		if err != nil {
+			return 0, updated, err
+		}
+		updated++
+	}
+
+	return customers[len(customers)-1].ID, updated, nil
+}
+
+func (r CustomerReencryptionRepository) needsUpdate(customer storedCustomer) bool {
+	return customer.CPFIndex == nil || customer.EmailIndex == nil ||
+		r.Keyring.NeedsReencryption(customer.CPF) || r.Keyring.NeedsReencryption(customer.Email) ||
+		(customer.Phone != nil && r.Keyring.NeedsReencryption(*customer.Phone))
+}
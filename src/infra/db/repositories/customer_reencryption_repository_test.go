@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+type singleKeyStore struct {
+	keys []encryption.WrappedKey
+}
+
+func (s *singleKeyStore) ListKeys(context.Context) ([]encryption.WrappedKey, error) {
+	return s.keys, nil
+}
+
+func (s *singleKeyStore) SaveKey(_ context.Context, key encryption.WrappedKey) error {
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *singleKeyStore) ActivateKey(_ context.Context, id string) error {
+	for i := range s.keys {
+		s.keys[i].Active = s.keys[i].ID == id
+	}
+	return nil
+}
+
+func newTestKeyring(t *testing.T) *encryption.Keyring {
+	key := sha256.Sum256([]byte("kek"))
+	kek, err := encryption.ParseLocalKeys("kek:" + base64.StdEncoding.EncodeToString(key[:]))
+	require.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(context.Background(), &singleKeyStore{}, kek, []byte(strings.Repeat("k", 32)), 0)
+	require.NoError(t, err)
+
+	return keyring
+}
+
+func TestReencryptBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	keyring := newTestKeyring(t)
+
+	encryptedCPF, err := keyring.Encrypt(ctx, "cpf", "11111111111")
+	require.NoError(t, err)
+	encryptedEmail, err := keyring.Encrypt(ctx, "email", "current@example.com")
+	require.NoError(t, err)
+	cpfIndex := keyring.BlindIndex(models.CustomerCPFIndexPurpose, "11111111111")
+	emailIndex := keyring.BlindIndex(models.CustomerEmailIndexPurpose, "current@example.com")
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerReencryptionRepository{DB: mockDB, Keyring: keyring}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), selectCustomersForReencryptionSQL, uint(0), 2).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]storedCustomer) = []storedCustomer{
+			{ID: 1, CPF: "12345678901", Email: "legacy@example.com"},
+			{ID: 2, CPF: encryptedCPF, Email: encryptedEmail, CPFIndex: &cpfIndex, EmailIndex: &emailIndex},
+		}
+		return nil
+	})
+
+	// só o cliente legado é regravado
+	mockDB.EXPECT().Exec(reencryptCustomerSQL, gomock.Any(), gomock.Any(), gomock.Nil(),
+		keyring.BlindIndex(models.CustomerCPFIndexPurpose, "12345678901"),
+		keyring.BlindIndex(models.CustomerEmailIndexPurpose, "legacy@example.com"),
+		uint(1), "12345678901", "legacy@example.com", gomock.Nil(),
+	).DoAndReturn(func(sql string, values ...interface{}) error {
+		cpf, err := keyring.Decrypt(ctx, "cpf", values[0].(string))
+		require.NoError(t, err)
+		assert.Equal(t, "12345678901", cpf)
+		assert.True(t, encryption.IsEncrypted(values[1].(string)))
+		return nil
+	})
+
+	lastID, updated, err := repo.ReencryptBatch(ctx, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), lastID)
+	assert.Equal(t, 1, updated)
+}
+
+func TestReencryptBatch_Phone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	keyring := newTestKeyring(t)
+
+	encryptedCPF, err := keyring.Encrypt(ctx, "cpf", "11111111111")
+	require.NoError(t, err)
+	encryptedEmail, err := keyring.Encrypt(ctx, "email", "current@example.com")
+	require.NoError(t, err)
+	cpfIndex := keyring.BlindIndex(models.CustomerCPFIndexPurpose, "11111111111")
+	emailIndex := keyring.BlindIndex(models.CustomerEmailIndexPurpose, "current@example.com")
+	phone := "+5511987654321"
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerReencryptionRepository{DB: mockDB, Keyring: keyring}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), selectCustomersForReencryptionSQL, uint(0), 1).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]storedCustomer) = []storedCustomer{
+			{ID: 3, CPF: encryptedCPF, Email: encryptedEmail, Phone: &phone, CPFIndex: &cpfIndex, EmailIndex: &emailIndex},
+		}
+		return nil
+	})
+
+	// só o telefone está em texto puro, e é ele que força a regravação
+	mockDB.EXPECT().Exec(reencryptCustomerSQL, gomock.Any(), gomock.Any(), gomock.Any(), cpfIndex, emailIndex,
+		uint(3), encryptedCPF, encryptedEmail, &phone,
+	).DoAndReturn(func(sql string, values ...interface{}) error {
+		decrypted, err := keyring.Decrypt(ctx, "phone", *values[2].(*string))
+		require.NoError(t, err)
+		assert.Equal(t, phone, decrypted)
+		return nil
+	})
+
+	_, updated, err := repo.ReencryptBatch(ctx, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+}
+
+func TestReencryptBatch_Done(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerReencryptionRepository{DB: mockDB, Keyring: newTestKeyring(t)}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), selectCustomersForReencryptionSQL, uint(5), 2).Return(nil)
+
+	lastID, updated, err := repo.ReencryptBatch(context.Background(), 5, 2)
+	require.NoError(t, err)
+	assert.Zero(t, lastID)
+	assert.Zero(t, updated)
+}
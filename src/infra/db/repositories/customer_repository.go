@@ -1,28 +1,83 @@
 package repositories
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/CAVAh/api-tech-challenge/src/infra/audit"
 	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
 	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"gorm.io/gorm"
+)
+
+const (
+	lockCustomerSQL        = `SELECT * FROM customers WHERE id = ? AND deleted_at IS NULL FOR UPDATE`
+	lockMergeCustomersSQL  = `SELECT * FROM customers WHERE id IN ? AND deleted_at IS NULL ORDER BY id FOR UPDATE`
+	countMergeAddressesSQL = `SELECT COUNT(*) FROM customer_addresses WHERE customer_id IN ?`
+	// os endereços do incorporado perdem o padrão quando o sobrevivente já tem um
+	moveAddressesSQL = `UPDATE customer_addresses SET customer_id = ?,
+		is_default = is_default AND NOT EXISTS (SELECT 1 FROM customer_addresses WHERE customer_id = ? AND is_default)
+		WHERE customer_id = ?`
+	movePreferencesSQL = `UPDATE customer_preferences SET customer_id = ?
+		WHERE customer_id = ? AND NOT EXISTS (SELECT 1 FROM customer_preferences WHERE customer_id = ?)`
+	moveFiscalProfileSQL = `UPDATE customer_fiscal_profiles SET customer_id = ?
+		WHERE customer_id = ? AND NOT EXISTS (SELECT 1 FROM customer_fiscal_profiles WHERE customer_id = ?)`
+	moveLoyaltyTransactionsSQL = `UPDATE loyalty_transactions SET customer_id = ? WHERE customer_id = ?`
+	repointRedirectsSQL        = `UPDATE customer_redirects SET to_id = ? WHERE to_id = ?`
+	resolveMergedCandidateSQL  = `UPDATE customer_duplicate_candidates SET status = ?, resolved_at = ? WHERE customer_id = ? AND other_customer_id = ?`
+	deletePendingCandidatesSQL = `DELETE FROM customer_duplicate_candidates WHERE status = ? AND (customer_id = ? OR other_customer_id = ?)`
+	// os payloads dos eventos anteriores trazem os dados pessoais; a eliminação apaga o outbox e as entregas de webhook do cliente
+	deleteCustomerWebhookAttemptsSQL = `DELETE FROM webhook_delivery_attempts WHERE delivery_id IN
+		(SELECT id FROM webhook_deliveries WHERE (aggregate_type = ? AND aggregate_id = ?)
+			OR event_id IN (SELECT event_id FROM outbox_events WHERE aggregate_type = ? AND aggregate_id = ?))`
+	deleteCustomerWebhookDeliveriesSQL = `DELETE FROM webhook_deliveries WHERE (aggregate_type = ? AND aggregate_id = ?)
+		OR event_id IN (SELECT event_id FROM outbox_events WHERE aggregate_type = ? AND aggregate_id = ?)`
+	deleteCustomerOutboxSQL = `DELETE FROM outbox_events WHERE aggregate_type = ? AND aggregate_id = ?`
+	insertChangeSQL         = `INSERT INTO customer_changes (customer_id, operation, changed_fields, changed_at, transaction_id)
+		VALUES (?, ?, ?, ?, pg_current_xact_id()::text::bigint)`
 )
 
 type CustomerRepository struct {
 	DB database.Database
 }
 
-func (r CustomerRepository) Create(entity *entities.Customer) (*entities.Customer, error) {
-	customer := models.Customer{
-		Name:  entity.Name,
-		CPF:   entity.CPF,
-		Email: entity.Email,
-	}
+func (r CustomerRepository) Create(ctx context.Context, entity *entities.Customer) (_ *entities.Customer, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("create", start, err) }(time.Now())
+
+	customer := models.NewCustomer(*entity)
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := tx.Create(&customer); err != nil {
+			return err
+		}
+
+		if err := appendChange(tx, customer.ID, entities.CustomerChangeCreated, nil); err != nil {
+			return err
+		}
 
-	if err := r.DB.Create(&customer); err != nil {
-		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
-			return nil, errors.New("cliente já existe no sistema")
+		if err := appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionCustomerCreated, customer.ID)); err != nil {
+			return err
+		}
+
+		event, err := events.NewCustomerCreated(customer.ToDomain())
+		if err != nil {
+			return err
+		}
+
+		return appendOutbox(tx, event)
+	})
+
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			metrics.DuplicateCustomerConflictsTotal.Inc()
+			return nil, entities.ErrCustomerAlreadyExists
 		} else {
 			return nil, errors.New("ocorreu um erro desconhecido ao criar o cliente")
 		}
@@ -33,17 +88,538 @@ func (r CustomerRepository) Create(entity *entities.Customer) (*entities.Custome
 	return &result, nil
 }
 
-func (r CustomerRepository) FindFirstByCpf(entity *entities.Customer) (*entities.Customer, error) {
+func (r CustomerRepository) FindFirstByCpf(ctx context.Context, entity *entities.Customer) (_ *entities.Customer, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_first_by_cpf", start, err) }(time.Now())
+
+	var customer models.Customer
+
+	cpfIndex, err := models.CustomerCPFIndex(entity.CPF)
+	if err != nil {
+		return nil, err
+	}
+
+	// registros ainda não migrados pelo reencrypt têm o CPF em texto puro e o índice nulo
+	db := r.DB.WithContext(ctx).Where("cpf_index = ? OR (cpf_index IS NULL AND cpf = ?)", cpfIndex, entity.CPF)
+	err = db.First(&customer).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+
+	result := customer.ToDomain()
+
+	return &result, nil
+}
+
+// FindByDocument busca o cliente pelo documento; CPF segue pela busca de FindFirstByCpf
+func (r CustomerRepository) FindByDocument(ctx context.Context, document entities.IdentityDocument) (_ *entities.Customer, err error) {
+	if document.Type == entities.DocumentCPF {
+		return r.FindFirstByCpf(ctx, &entities.Customer{CPF: document.Number})
+	}
+
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_by_document", start, err) }(time.Now())
+
+	documentIndex, err := models.CustomerDocumentIndex(document)
+	if err != nil {
+		return nil, err
+	}
+
 	var customer models.Customer
+	if err := r.DB.WithContext(ctx).First(&customer, "cpf_index = ?", documentIndex); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+
+	result := customer.ToDomain()
 
-	db := r.DB.Where("cpf = ?", entity.CPF)
-	err := db.First(&customer).Error
+	return &result, nil
+}
 
+// FindByPhone busca pelo índice do telefone, que só existe nos telefones verificados
+func (r CustomerRepository) FindByPhone(ctx context.Context, phone string) (_ *entities.Customer, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_by_phone", start, err) }(time.Now())
+
+	phoneIndex, err := models.CustomerPhoneIndex(phone)
 	if err != nil {
 		return nil, err
 	}
 
+	var customer models.Customer
+	if err := r.DB.WithContext(ctx).First(&customer, "phone_index = ?", phoneIndex); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+
+	result := customer.ToDomain()
+
+	return &result, nil
+}
+
+func (r CustomerRepository) FindByID(ctx context.Context, id uint) (_ *entities.Customer, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_by_id", start, err) }(time.Now())
+
+	var customer models.Customer
+
+	if err := r.DB.WithContext(ctx).First(&customer, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+
 	result := customer.ToDomain()
 
 	return &result, nil
 }
+
+func (r CustomerRepository) FindByIDs(ctx context.Context, ids []uint) (_ []entities.Customer, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_by_ids", start, err) }(time.Now())
+
+	var customers []models.Customer
+
+	if err := r.DB.WithContext(ctx).Find(&customers, "id IN ?", ids); err != nil {
+		return nil, err
+	}
+
+	result := make([]entities.Customer, 0, len(customers))
+	for _, customer := range customers {
+		result = append(result, customer.ToDomain())
+	}
+
+	return result, nil
+}
+
+// Update grava nome, email e telefone do cliente e registra um CustomerUpdated com os campos alterados.
+// Trocar o telefone desfaz a verificação. Se nada mudou nenhum evento é gerado.
+func (r CustomerRepository) Update(ctx context.Context, entity *entities.Customer) (_ *entities.Customer, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("update", start, err) }(time.Now())
+
+	var customer models.Customer
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := tx.First(&customer, entity.ID); err != nil {
+			return err
+		}
+
+		var changedFields []string
+		if entity.Name != customer.Name {
+			customer.Name = entity.Name
+			changedFields = append(changedFields, "name")
+		}
+		if entity.Email != customer.Email {
+			customer.Email = entity.Email
+			changedFields = append(changedFields, "email")
+		}
+		if phone := phoneNumber(entity.Phone); phone != customer.Phone {
+			customer.Phone = phone
+			customer.PhoneVerifiedAt = nil
+			changedFields = append(changedFields, "phone")
+		} else if verified := entity.Phone != nil && entity.Phone.Verified; verified != (customer.PhoneVerifiedAt != nil) {
+			customer.PhoneVerifiedAt = nil
+			if verified {
+				now := time.Now()
+				customer.PhoneVerifiedAt = &now
+			}
+			changedFields = append(changedFields, "phoneVerified")
+		}
+
+		if len(changedFields) == 0 {
+			return nil
+		}
+
+		if err := tx.Save(&customer); err != nil {
+			return err
+		}
+
+		if err := appendChange(tx, customer.ID, entities.CustomerChangeUpdated, changedFields); err != nil {
+			return err
+		}
+
+		if err := appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionCustomerUpdated, customer.ID)); err != nil {
+			return err
+		}
+
+		event, err := events.NewCustomerUpdated(customer.ToDomain(), changedFields)
+		if err != nil {
+			return err
+		}
+
+		return appendOutbox(tx, event)
+	})
+
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, entities.ErrCustomerNotFound
+		case database.IsUniqueViolation(err) && entity.Phone != nil && entity.Phone.Verified && customer.Phone == entity.Phone.Number:
+			// outro cliente verificou o mesmo telefone entre a checagem do caso de uso e a gravação
+			return nil, entities.ErrPhoneAlreadyInUse
+		case database.IsUniqueViolation(err):
+			metrics.DuplicateCustomerConflictsTotal.Inc()
+			return nil, entities.ErrCustomerAlreadyExists
+		default:
+			return nil, err
+		}
+	}
+
+	result := customer.ToDomain()
+
+	return &result, nil
+}
+
+func phoneNumber(phone *entities.CustomerPhone) string {
+	if phone == nil {
+		return ""
+	}
+
+	return phone.Number
+}
+
+// Erase anonimiza os dados pessoais, apaga endereços, preferências, os códigos de verificação, os
+// eventos do cliente no outbox e as entregas de webhook desses eventos e faz o soft delete do cliente.
+// Só o CustomerErased, gravado em seguida, fica para avisar os consumidores.
+// Os valores substitutos são únicos por id para não colidirem nos índices de documento e email,
+// o que também libera o documento e o email originais para um novo cadastro.
+func (r CustomerRepository) Erase(ctx context.Context, id uint) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("erase", start, err) }(time.Now())
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		var customer models.Customer
+		if err := tx.First(&customer, id); err != nil {
+			return err
+		}
+
+		anonymize(&customer)
+
+		if err := tx.Save(&customer); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&customer); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.CustomerAddress{}, "customer_id = ?", id); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.CustomerPreferences{}, "customer_id = ?", id); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.CustomerFiscalProfile{}, "customer_id = ?", id); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.CustomerPhoneVerification{}, "customer_id = ?", id); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.CustomerSessionVerification{}, "customer_id = ?", id); err != nil {
+			return err
+		}
+
+		if err := scrubCustomerEvents(tx, id); err != nil {
+			return err
+		}
+
+		if err := appendChange(tx, id, entities.CustomerChangeErased, nil); err != nil {
+			return err
+		}
+
+		if err := appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionCustomerErased, id)); err != nil {
+			return err
+		}
+
+		event, err := events.NewCustomerErased(id)
+		if err != nil {
+			return err
+		}
+
+		return appendOutbox(tx, event)
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.ErrCustomerNotFound
+	}
+
+	return err
+}
+
+// Merge roda numa transação com os dois clientes travados. Endereços, preferências, perfil fiscal e
+// extrato de pontos passam para o sobrevivente; preferências e perfil fiscal só quando ele não tem os
+// seus, e os endereços chegam sem o padrão se ele já tiver um. Se os dois cadernos juntos passarem de
+// maxAddresses, nada é fundido e a fusão devolve ErrAddressLimitReached. O sobrevivente herda o telefone
+// se não tiver um. O incorporado é anonimizado e tem os eventos apagados como na eliminação, e o id
+// dele vira um redirecionamento.
+func (r CustomerRepository) Merge(ctx context.Context, survivorID uint, mergedID uint, maxAddresses int) (_ *entities.Customer, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("merge", start, err) }(time.Now())
+
+	var survivor models.Customer
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		var locked []models.Customer
+		if err := tx.Raw(&locked, lockMergeCustomersSQL, []uint{survivorID, mergedID}); err != nil {
+			return err
+		}
+
+		if len(locked) != 2 {
+			return entities.ErrCustomerNotFound
+		}
+
+		merged := locked[0]
+		survivor = locked[1]
+		if survivor.ID != survivorID {
+			survivor, merged = merged, survivor
+		}
+
+		// o status é conferido com as linhas travadas, para uma suspensão concorrente não escapar
+		if !entities.CustomerStatus(survivor.Status).CanMerge() || !entities.CustomerStatus(merged.Status).CanMerge() {
+			return entities.ErrMergeNotAllowed
+		}
+
+		// as travas dos clientes também seguram o caderno de endereços (ver AddressRepository.Update)
+		var addresses int64
+		if err := tx.Raw(&addresses, countMergeAddressesSQL, []uint{survivorID, mergedID}); err != nil {
+			return err
+		}
+
+		if addresses > int64(maxAddresses) {
+			return entities.ErrAddressLimitReached
+		}
+
+		// o extrato é travado como em LoyaltyRepository.Post, na ordem dos ids
+		for _, customer := range locked {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", database.LoyaltyLockKey, customer.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec(moveAddressesSQL, survivorID, survivorID, mergedID); err != nil {
+			return err
+		}
+
+		if err := tx.Exec(movePreferencesSQL, survivorID, mergedID, survivorID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.CustomerPreferences{}, "customer_id = ?", mergedID); err != nil {
+			return err
+		}
+
+		if err := tx.Exec(moveFiscalProfileSQL, survivorID, mergedID, survivorID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.CustomerFiscalProfile{}, "customer_id = ?", mergedID); err != nil {
+			return err
+		}
+
+		if err := tx.Exec("SET LOCAL " + database.CustomerMergeSetting + " = 'on'"); err != nil {
+			return err
+		}
+
+		if err := tx.Exec(moveLoyaltyTransactionsSQL, survivorID, mergedID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.CustomerPhoneVerification{}, "customer_id = ?", mergedID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.CustomerSessionVerification{}, "customer_id = ?", mergedID); err != nil {
+			return err
+		}
+
+		var changedFields []string
+		if survivor.Phone == "" && merged.Phone != "" {
+			survivor.Phone = merged.Phone
+			survivor.PhoneVerifiedAt = merged.PhoneVerifiedAt
+			changedFields = append(changedFields, "phone")
+		}
+
+		// o incorporado é gravado antes para liberar o índice do telefone que o sobrevivente herda
+		anonymize(&merged)
+
+		if err := tx.Save(&merged); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&merged); err != nil {
+			return err
+		}
+
+		if len(changedFields) > 0 {
+			if err := tx.Save(&survivor); err != nil {
+				return err
+			}
+		}
+
+		if err := scrubCustomerEvents(tx, mergedID); err != nil {
+			return err
+		}
+
+		if err := tx.Exec(repointRedirectsSQL, survivorID, mergedID); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&models.CustomerRedirect{FromID: mergedID, ToID: survivorID}); err != nil {
+			return err
+		}
+
+		if err := tx.Exec(resolveMergedCandidateSQL, entities.DuplicateMerged, time.Now(), min(survivorID, mergedID), max(survivorID, mergedID)); err != nil {
+			return err
+		}
+
+		if err := tx.Exec(deletePendingCandidatesSQL, entities.DuplicatePending, mergedID, mergedID); err != nil {
+			return err
+		}
+
+		if err := appendChange(tx, mergedID, entities.CustomerChangeMerged, nil); err != nil {
+			return err
+		}
+
+		if len(changedFields) > 0 {
+			if err := appendChange(tx, survivorID, entities.CustomerChangeUpdated, changedFields); err != nil {
+				return err
+			}
+		}
+
+		if err := appendAudit(ctx, tx,
+			entities.CustomerAuditEntry(entities.AuditActionCustomerMerged, survivorID),
+			entities.CustomerAuditEntry(entities.AuditActionCustomerMerged, mergedID),
+		); err != nil {
+			return err
+		}
+
+		event, err := events.NewCustomersMerged(survivor.ToDomain(), mergedID)
+		if err != nil {
+			return err
+		}
+
+		return appendOutbox(tx, event)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := survivor.ToDomain()
+
+	return &result, nil
+}
+
+// ChangeStatus trava o cliente para que duas transições simultâneas não partam do mesmo status.
+// A troca sai no feed e no evento como uma atualização do campo status; o motivo fica só no histórico.
+func (r CustomerRepository) ChangeStatus(ctx context.Context, id uint, from entities.CustomerStatus, to entities.CustomerStatus, reason string) (_ *entities.CustomerStatusChange, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("change_status", start, err) }(time.Now())
+
+	var change models.CustomerStatusChange
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		var locked []models.Customer
+		if err := tx.Raw(&locked, lockCustomerSQL, id); err != nil {
+			return err
+		}
+
+		if len(locked) == 0 {
+			return entities.ErrCustomerNotFound
+		}
+
+		customer := locked[0]
+		if customer.Status != string(from) {
+			return entities.ErrInvalidStatusChange
+		}
+
+		customer.Status = string(to)
+		if err := tx.Save(&customer); err != nil {
+			return err
+		}
+
+		change = models.CustomerStatusChange{
+			CustomerID: id,
+			FromStatus: string(from),
+			ToStatus:   string(to),
+			Reason:     reason,
+			ChangedBy:  audit.ActorFromContext(ctx).ID,
+			ChangedAt:  time.Now(),
+		}
+		if err := tx.Create(&change); err != nil {
+			return err
+		}
+
+		changedFields := []string{"status"}
+		if err := appendChange(tx, id, entities.CustomerChangeUpdated, changedFields); err != nil {
+			return err
+		}
+
+		if err := appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionStatusChanged, id)); err != nil {
+			return err
+		}
+
+		event, err := events.NewCustomerUpdated(customer.ToDomain(), changedFields)
+		if err != nil {
+			return err
+		}
+
+		return appendOutbox(tx, event)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := change.ToDomain()
+
+	return &result, nil
+}
+
+// anonymize troca os dados pessoais por valores únicos por id
+func anonymize(customer *models.Customer) {
+	customer.Name = erasedValue(customer.ID)
+	customer.CPF = erasedValue(customer.ID)
+	customer.Email = erasedValue(customer.ID) + "@apagado.invalid"
+	customer.Phone = ""
+	customer.PhoneVerifiedAt = nil
+}
+
+func erasedValue(id uint) string {
+	return fmt.Sprintf("apagado-%d", id)
+}
+
+func appendOutbox(tx database.Database, event events.Envelope) error {
+	outboxEvent, err := models.NewOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(outboxEvent)
+}
+
+// scrubCustomerEvents apaga os eventos do cliente no outbox e as entregas de webhook geradas por eles
+func scrubCustomerEvents(tx database.Database, id uint) error {
+	aggregateID := strconv.FormatUint(uint64(id), 10)
+
+	if err := tx.Exec(deleteCustomerWebhookAttemptsSQL, events.CustomerAggregate, aggregateID, events.CustomerAggregate, aggregateID); err != nil {
+		return err
+	}
+
+	if err := tx.Exec(deleteCustomerWebhookDeliveriesSQL, events.CustomerAggregate, aggregateID, events.CustomerAggregate, aggregateID); err != nil {
+		return err
+	}
+
+	return tx.Exec(deleteCustomerOutboxSQL, events.CustomerAggregate, aggregateID)
+}
+
+// appendChange registra a alteração no log do feed com o id da transação, que ordena o feed sem
+// serializar as escritas (ver selectChangesSQL)
+func appendChange(tx database.Database, customerID uint, operation string, changedFields []string) error {
+	return tx.Exec(insertChangeSQL, customerID, operation, strings.Join(changedFields, ","), time.Now())
+}
@@ -1,13 +1,19 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
 	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
 	"github.com/stretchr/testify/assert"
 	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestCreateCustomer(t *testing.T) {
@@ -23,12 +29,28 @@ func TestCreateCustomer(t *testing.T) {
 		Email: "john@example.com",
 	}
 
-	mockDB.EXPECT().Create(gomock.Any()).Return(nil)
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.Customer{})).Return(nil)
 
-	result, err := repo.Create(entity)
+	change := expectChange(mockDB)
+	auditEntry := expectAudit(mockDB)
+
+	var outboxEvent *models.OutboxEvent
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.OutboxEvent{})).DoAndReturn(func(data interface{}) error {
+		outboxEvent = data.(*models.OutboxEvent)
+		return nil
+	})
+
+	result, err := repo.Create(context.Background(), entity)
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, "John Doe", result.Name)
+	assert.Equal(t, entities.CustomerChangeCreated, change.Operation)
+	assert.Equal(t, entities.AuditActionCustomerCreated, auditEntry.Action)
+	assert.Equal(t, "anterior", auditEntry.PrevHash)
+	assert.Equal(t, events.CustomerCreated, outboxEvent.Type)
+	assert.NotContains(t, string(outboxEvent.Payload), "12345678901")
 }
 
 func TestCreateCustomer_Duplicate(t *testing.T) {
@@ -44,9 +66,11 @@ func TestCreateCustomer_Duplicate(t *testing.T) {
 		Email: "john@example.com",
 	}
 
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
 	mockDB.EXPECT().Create(gomock.Any()).Return(errors.New("duplicate key value violates unique constraint"))
 
-	result, err := repo.Create(entity)
+	result, err := repo.Create(context.Background(), entity)
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Equal(t, "cliente já existe no sistema", err.Error())
@@ -65,14 +89,50 @@ func TestCreateCustomer_Error(t *testing.T) {
 		Email: "john@example.com",
 	}
 
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
 	mockDB.EXPECT().Create(gomock.Any()).Return(errors.New("some error"))
 
-	result, err := repo.Create(entity)
+	result, err := repo.Create(context.Background(), entity)
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Equal(t, "ocorreu um erro desconhecido ao criar o cliente", err.Error())
 }
 
+func TestFindByID_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().First(gomock.Any(), uint(7)).Return(gorm.ErrRecordNotFound)
+
+	result, err := repo.FindByID(context.Background(), 7)
+	assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+	assert.Nil(t, result)
+}
+
+func TestFindByIDs_SingleQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Find(gomock.Any(), "id IN ?", []uint{1, 2}).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
+		*dest.(*[]models.Customer) = []models.Customer{{Name: "John Doe"}}
+		return nil
+	}).Times(1)
+
+	result, err := repo.FindByIDs(context.Background(), []uint{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "John Doe", result[0].Name)
+}
+
 // FIXME: This test is not working
 // func TestFindFirstByCpf_Success(t *testing.T) {
 // 	ctrl := gomock.NewController(t)
@@ -142,3 +202,447 @@ func TestCreateCustomer_Error(t *testing.T) {
 // 	assert.Nil(t, result)
 // 	assert.Equal(t, "database error", err.Error())
 // }
+
+func TestUpdateCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().First(gomock.Any(), uint(1)).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
+		*dest.(*models.Customer) = models.Customer{Model: gorm.Model{ID: 1}, Name: "John Doe", CPF: "12345678901", Email: "john@example.com"}
+		return nil
+	})
+	mockDB.EXPECT().Save(gomock.Any()).Return(nil)
+
+	change := expectChange(mockDB)
+	auditEntry := expectAudit(mockDB)
+
+	var outboxEvent *models.OutboxEvent
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.OutboxEvent{})).DoAndReturn(func(data interface{}) error {
+		outboxEvent = data.(*models.OutboxEvent)
+		return nil
+	})
+
+	result, err := repo.Update(context.Background(), &entities.Customer{ID: 1, Name: "John Doe", Email: "johnny@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "johnny@example.com", result.Email)
+	assert.Equal(t, "email", change.ChangedFields)
+	assert.Equal(t, entities.AuditActionCustomerUpdated, auditEntry.Action)
+	assert.Equal(t, events.CustomerUpdated, outboxEvent.Type)
+	assert.Contains(t, string(outboxEvent.Payload), `"changedFields":["email"]`)
+}
+
+func TestUpdateCustomer_Phone(t *testing.T) {
+	verifiedAt := time.Now()
+
+	tests := map[string]struct {
+		phone         *entities.CustomerPhone
+		changedFields string
+		verified      bool
+	}{
+		"troca desfaz a verificação": {&entities.CustomerPhone{Number: "+5521987654321", Verified: true}, "phone", false},
+		"remoção":                    {nil, "phone", false},
+		"verificação":                {&entities.CustomerPhone{Number: "+5511987654321", Verified: true}, "phoneVerified", true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			repo := CustomerRepository{DB: mockDB}
+
+			stored := models.Customer{Model: gorm.Model{ID: 1}, Name: "John Doe", CPF: "12345678901", Email: "john@example.com", Phone: "+5511987654321"}
+			if !test.verified {
+				stored.PhoneVerifiedAt = &verifiedAt
+			}
+
+			mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+			expectTransaction(mockDB)
+			mockDB.EXPECT().First(gomock.Any(), uint(1)).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
+				*dest.(*models.Customer) = stored
+				return nil
+			})
+
+			var saved models.Customer
+			mockDB.EXPECT().Save(gomock.Any()).DoAndReturn(func(data interface{}) error {
+				saved = *data.(*models.Customer)
+				return nil
+			})
+
+			change := expectChange(mockDB)
+			expectAudit(mockDB)
+
+			var outboxEvent *models.OutboxEvent
+			mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.OutboxEvent{})).DoAndReturn(func(data interface{}) error {
+				outboxEvent = data.(*models.OutboxEvent)
+				return nil
+			})
+
+			_, err := repo.Update(context.Background(), &entities.Customer{ID: 1, Name: "John Doe", Email: "john@example.com", Phone: test.phone})
+			assert.NoError(t, err)
+			assert.Equal(t, test.changedFields, change.ChangedFields)
+			assert.Equal(t, test.verified, saved.PhoneVerifiedAt != nil)
+			assert.NotContains(t, string(outboxEvent.Payload), "98765")
+		})
+	}
+}
+
+func TestUpdateCustomer_NoChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().First(gomock.Any(), uint(1)).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
+		*dest.(*models.Customer) = models.Customer{Model: gorm.Model{ID: 1}, Name: "John Doe", Email: "john@example.com"}
+		return nil
+	})
+
+	result, err := repo.Update(context.Background(), &entities.Customer{ID: 1, Name: "John Doe", Email: "john@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", result.Name)
+}
+
+func TestEraseCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	verifiedAt := time.Now()
+	mockDB.EXPECT().First(gomock.Any(), uint(7)).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
+		*dest.(*models.Customer) = models.Customer{Model: gorm.Model{ID: 7}, Name: "John Doe", CPF: "12345678901", Email: "john@example.com", Phone: "+5511987654321", PhoneVerifiedAt: &verifiedAt}
+		return nil
+	})
+
+	var saved models.Customer
+	mockDB.EXPECT().Save(gomock.Any()).DoAndReturn(func(data interface{}) error {
+		saved = *data.(*models.Customer)
+		return nil
+	})
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.Customer{})).Return(nil)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerAddress{}), "customer_id = ?", uint(7)).Return(nil)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerPreferences{}), "customer_id = ?", uint(7)).Return(nil)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerFiscalProfile{}), "customer_id = ?", uint(7)).Return(nil)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerPhoneVerification{}), "customer_id = ?", uint(7)).Return(nil)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerSessionVerification{}), "customer_id = ?", uint(7)).Return(nil)
+	gomock.InOrder(
+		mockDB.EXPECT().Exec(deleteCustomerWebhookAttemptsSQL, events.CustomerAggregate, "7", events.CustomerAggregate, "7").Return(nil),
+		mockDB.EXPECT().Exec(deleteCustomerWebhookDeliveriesSQL, events.CustomerAggregate, "7", events.CustomerAggregate, "7").Return(nil),
+		mockDB.EXPECT().Exec(deleteCustomerOutboxSQL, events.CustomerAggregate, "7").Return(nil),
+	)
+
+	change := expectChange(mockDB)
+	auditEntry := expectAudit(mockDB)
+
+	var outboxEvent *models.OutboxEvent
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.OutboxEvent{})).DoAndReturn(func(data interface{}) error {
+		outboxEvent = data.(*models.OutboxEvent)
+		return nil
+	})
+
+	err := repo.Erase(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "apagado-7", saved.CPF)
+	assert.Equal(t, "apagado-7@apagado.invalid", saved.Email)
+	assert.Empty(t, saved.Phone)
+	assert.Nil(t, saved.PhoneVerifiedAt)
+	assert.Equal(t, entities.CustomerChangeErased, change.Operation)
+	assert.Equal(t, entities.AuditActionCustomerErased, auditEntry.Action)
+	assert.Equal(t, "7", auditEntry.TargetID)
+	assert.Equal(t, events.CustomerErased, outboxEvent.Type)
+	assert.NotContains(t, string(outboxEvent.Payload), "John")
+}
+
+func TestEraseCustomer_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().First(gomock.Any(), uint(7)).Return(gorm.ErrRecordNotFound)
+
+	err := repo.Erase(context.Background(), 7)
+	assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+}
+
+func TestMergeCustomers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	verifiedAt := time.Now()
+	mockDB.EXPECT().Raw(gomock.Any(), lockMergeCustomersSQL, []uint{9, 4}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.Customer) = []models.Customer{
+			{Model: gorm.Model{ID: 4}, Name: "Joao Silva", CPF: "12345678901", Email: "joao.silva@gmial.com", Phone: "+5511987654321", PhoneVerifiedAt: &verifiedAt, Status: "pending_verification"},
+			{Model: gorm.Model{ID: 9}, Name: "João da Silva", CPF: "98765432100", Email: "joao.silva@gmail.com", Status: "active"},
+		}
+		return nil
+	})
+
+	mockDB.EXPECT().Raw(gomock.Any(), countMergeAddressesSQL, []uint{9, 4}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*int64) = 10
+		return nil
+	})
+
+	gomock.InOrder(
+		mockDB.EXPECT().Exec("SELECT pg_advisory_xact_lock(?, ?)", database.LoyaltyLockKey, uint(4)).Return(nil),
+		mockDB.EXPECT().Exec("SELECT pg_advisory_xact_lock(?, ?)", database.LoyaltyLockKey, uint(9)).Return(nil),
+	)
+	mockDB.EXPECT().Exec(moveAddressesSQL, uint(9), uint(9), uint(4)).Return(nil)
+	mockDB.EXPECT().Exec(movePreferencesSQL, uint(9), uint(4), uint(9)).Return(nil)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerPreferences{}), "customer_id = ?", uint(4)).Return(nil)
+	mockDB.EXPECT().Exec(moveFiscalProfileSQL, uint(9), uint(4), uint(9)).Return(nil)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerFiscalProfile{}), "customer_id = ?", uint(4)).Return(nil)
+	gomock.InOrder(
+		mockDB.EXPECT().Exec("SET LOCAL app.customer_merge = 'on'").Return(nil),
+		mockDB.EXPECT().Exec(moveLoyaltyTransactionsSQL, uint(9), uint(4)).Return(nil),
+	)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerPhoneVerification{}), "customer_id = ?", uint(4)).Return(nil)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerSessionVerification{}), "customer_id = ?", uint(4)).Return(nil)
+
+	var saved []models.Customer
+	mockDB.EXPECT().Save(gomock.AssignableToTypeOf(&models.Customer{})).DoAndReturn(func(data interface{}) error {
+		saved = append(saved, *data.(*models.Customer))
+		return nil
+	}).Times(2)
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.Customer{})).DoAndReturn(func(value interface{}, conds ...interface{}) error {
+		assert.Equal(t, uint(4), value.(*models.Customer).ID)
+		return nil
+	})
+
+	// os eventos anteriores do incorporado trazem os dados pessoais dele e são apagados como na eliminação
+	gomock.InOrder(
+		mockDB.EXPECT().Exec(deleteCustomerWebhookAttemptsSQL, events.CustomerAggregate, "4", events.CustomerAggregate, "4").Return(nil),
+		mockDB.EXPECT().Exec(deleteCustomerWebhookDeliveriesSQL, events.CustomerAggregate, "4", events.CustomerAggregate, "4").Return(nil),
+		mockDB.EXPECT().Exec(deleteCustomerOutboxSQL, events.CustomerAggregate, "4").Return(nil),
+		mockDB.EXPECT().Exec(repointRedirectsSQL, uint(9), uint(4)).Return(nil),
+		mockDB.EXPECT().Create(&models.CustomerRedirect{FromID: 4, ToID: 9}).Return(nil),
+	)
+	mockDB.EXPECT().Exec(resolveMergedCandidateSQL, entities.DuplicateMerged, gomock.Any(), uint(4), uint(9)).Return(nil)
+	mockDB.EXPECT().Exec(deletePendingCandidatesSQL, entities.DuplicatePending, uint(4), uint(4)).Return(nil)
+
+	mergedChange := expectChange(mockDB)
+	survivorChange := expectChange(mockDB)
+
+	var auditEntries []models.AuditEntry
+	mockDB.EXPECT().Exec("SELECT pg_advisory_xact_lock(?)", database.AuditEntriesLockKey).Return(nil)
+	mockDB.EXPECT().Raw(gomock.Any(), selectLastAuditEntrySQL).Return(nil)
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.AuditEntry{})).DoAndReturn(func(data interface{}) error {
+		auditEntries = append(auditEntries, *data.(*models.AuditEntry))
+		return nil
+	}).Times(2)
+
+	var outboxEvent *models.OutboxEvent
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.OutboxEvent{})).DoAndReturn(func(data interface{}) error {
+		outboxEvent = data.(*models.OutboxEvent)
+		return nil
+	})
+
+	survivor, err := repo.Merge(context.Background(), 9, 4, 10)
+	assert.NoError(t, err)
+
+	// o incorporado é anonimizado antes de o sobrevivente herdar o telefone verificado
+	if assert.Len(t, saved, 2) {
+		assert.Equal(t, "apagado-4", saved[0].CPF)
+		assert.Empty(t, saved[0].Phone)
+		assert.Equal(t, uint(9), saved[1].ID)
+		assert.Equal(t, "+5511987654321", saved[1].Phone)
+	}
+	assert.Equal(t, &entities.CustomerPhone{Number: "+5511987654321", Verified: true}, survivor.Phone)
+
+	assert.Equal(t, entities.CustomerChangeMerged, mergedChange.Operation)
+	assert.Equal(t, uint(4), mergedChange.CustomerID)
+	assert.Equal(t, entities.CustomerChangeUpdated, survivorChange.Operation)
+	assert.Equal(t, "phone", survivorChange.ChangedFields)
+
+	if assert.Len(t, auditEntries, 2) {
+		assert.Equal(t, entities.AuditActionCustomerMerged, auditEntries[0].Action)
+		assert.Equal(t, "9", auditEntries[0].TargetID)
+		assert.Equal(t, "4", auditEntries[1].TargetID)
+	}
+
+	assert.Equal(t, events.CustomersMerged, outboxEvent.Type)
+	assert.Equal(t, "9", outboxEvent.AggregateID)
+	assert.Contains(t, string(outboxEvent.Payload), `"mergedCustomerId":4`)
+}
+
+func TestMergeCustomers_InactiveCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), lockMergeCustomersSQL, []uint{9, 4}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.Customer) = []models.Customer{{Model: gorm.Model{ID: 9}, Name: "João da Silva"}}
+		return nil
+	})
+
+	_, err := repo.Merge(context.Background(), 9, 4, 10)
+	assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+}
+
+func TestMergeCustomers_RejectsSuspendedOrClosedCustomer(t *testing.T) {
+	for _, status := range []string{"suspended", "closed"} {
+		t.Run(status, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			repo := CustomerRepository{DB: mockDB}
+
+			mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+			expectTransaction(mockDB)
+			mockDB.EXPECT().Raw(gomock.Any(), lockMergeCustomersSQL, []uint{9, 4}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+				*dest.(*[]models.Customer) = []models.Customer{
+					{Model: gorm.Model{ID: 4}, Name: "Joao Silva", Status: status},
+					{Model: gorm.Model{ID: 9}, Name: "João da Silva", Status: "active"},
+				}
+				return nil
+			})
+
+			// nada além da trava é executado: sem Exec, Save ou Delete esperados
+			_, err := repo.Merge(context.Background(), 9, 4, 10)
+			assert.ErrorIs(t, err, entities.ErrMergeNotAllowed)
+		})
+	}
+}
+
+func TestMergeCustomers_AddressLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), lockMergeCustomersSQL, []uint{9, 4}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.Customer) = []models.Customer{
+			{Model: gorm.Model{ID: 4}, Name: "Joao Silva", Status: "active"},
+			{Model: gorm.Model{ID: 9}, Name: "João da Silva", Status: "active"},
+		}
+		return nil
+	})
+	mockDB.EXPECT().Raw(gomock.Any(), countMergeAddressesSQL, []uint{9, 4}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*int64) = 11
+		return nil
+	})
+
+	// com os endereços dos dois acima do limite nada é movido: sem Exec, Save ou Delete esperados
+	_, err := repo.Merge(context.Background(), 9, 4, 10)
+	assert.ErrorIs(t, err, entities.ErrAddressLimitReached)
+}
+
+func TestChangeCustomerStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), lockCustomerSQL, uint(3)).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.Customer) = []models.Customer{{Model: gorm.Model{ID: 3}, Name: "John Doe", Status: "active"}}
+		return nil
+	})
+
+	var saved *models.Customer
+	mockDB.EXPECT().Save(gomock.Any()).DoAndReturn(func(value interface{}) error {
+		saved = value.(*models.Customer)
+		return nil
+	})
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.CustomerStatusChange{})).Return(nil)
+
+	change := expectChange(mockDB)
+	auditEntry := expectAudit(mockDB)
+
+	var outboxEvent *models.OutboxEvent
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.OutboxEvent{})).DoAndReturn(func(data interface{}) error {
+		outboxEvent = data.(*models.OutboxEvent)
+		return nil
+	})
+
+	result, err := repo.ChangeStatus(context.Background(), 3, entities.CustomerActive, entities.CustomerSuspended, "chargeback")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.CustomerSuspended, result.To)
+	assert.Equal(t, "chargeback", result.Reason)
+	assert.Equal(t, "suspended", saved.Status)
+	assert.Equal(t, "status", change.ChangedFields)
+	assert.Equal(t, entities.AuditActionStatusChanged, auditEntry.Action)
+	assert.Contains(t, string(outboxEvent.Payload), `"status":"suspended"`)
+	assert.NotContains(t, string(outboxEvent.Payload), "chargeback")
+}
+
+func TestChangeCustomerStatus_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRepository{DB: mockDB}
+
+	// outra transição já tirou o cliente do status lido pelo caso de uso
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), lockCustomerSQL, uint(3)).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.Customer) = []models.Customer{{Model: gorm.Model{ID: 3}, Status: "closed"}}
+		return nil
+	})
+
+	_, err := repo.ChangeStatus(context.Background(), 3, entities.CustomerSuspended, entities.CustomerClosed, "encerramento")
+	assert.ErrorIs(t, err, entities.ErrInvalidStatusChange)
+}
+
+// expectTransaction executa a função da transação no próprio mock
+func expectTransaction(mockDB *mocks.MockDatabase) {
+	mockDB.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(tx database.Database) error) error {
+		return fn(mockDB)
+	})
+}
+
+// expectChange espera o lock e a gravação no log de alterações e devolve a entrada gravada
+func expectChange(mockDB *mocks.MockDatabase) *models.CustomerChange {
+	change := &models.CustomerChange{}
+	mockDB.EXPECT().Exec(insertChangeSQL, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(sql string, values ...interface{}) error {
+		*change = models.CustomerChange{CustomerID: values[0].(uint), Operation: values[1].(string), ChangedFields: values[2].(string)}
+		return nil
+	})
+	return change
+}
+
+func expectAudit(mockDB *mocks.MockDatabase) *models.AuditEntry {
+	entry := &models.AuditEntry{}
+	mockDB.EXPECT().Exec("SELECT pg_advisory_xact_lock(?)", database.AuditEntriesLockKey).Return(nil)
+	mockDB.EXPECT().Raw(gomock.Any(), selectLastAuditEntrySQL).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*models.AuditEntry) = models.AuditEntry{Hash: "anterior"}
+		return nil
+	})
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.AuditEntry{})).DoAndReturn(func(data interface{}) error {
+		*entry = *data.(*models.AuditEntry)
+		return nil
+	})
+	return entry
+}
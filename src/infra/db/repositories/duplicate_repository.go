@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"gorm.io/gorm"
+)
+
+const (
+	scanCustomersSQL = `SELECT * FROM customers WHERE deleted_at IS NULL AND id > ? ORDER BY id LIMIT ?`
+	// só os pares pendentes são atualizados; descartados e fundidos ficam como estão
+	upsertCandidateSQL = `INSERT INTO customer_duplicate_candidates (customer_id, other_customer_id, score, reasons, status, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (customer_id, other_customer_id) DO UPDATE
+		SET score = EXCLUDED.score, reasons = EXCLUDED.reasons, detected_at = EXCLUDED.detected_at
+		WHERE customer_duplicate_candidates.status = EXCLUDED.status`
+	deleteStaleCandidatesSQL = `DELETE FROM customer_duplicate_candidates WHERE status = ? AND detected_at < ?`
+	selectCandidatesSQL      = `SELECT * FROM customer_duplicate_candidates WHERE status = ? ORDER BY score DESC, id LIMIT ?`
+)
+
+type DuplicateRepository struct {
+	DB database.Database
+}
+
+func (r DuplicateRepository) ScanCustomers(ctx context.Context, afterID uint, limit int) (_ []entities.Customer, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("scan_customers", start, err) }(time.Now())
+
+	var customers []models.Customer
+	if err := r.DB.WithContext(ctx).Raw(&customers, scanCustomersSQL, afterID, limit); err != nil {
+		return nil, err
+	}
+
+	result := make([]entities.Customer, 0, len(customers))
+	for _, customer := range customers {
+		result = append(result, customer.ToDomain())
+	}
+
+	return result, nil
+}
+
+func (r DuplicateRepository) SaveCandidates(ctx context.Context, candidates []entities.DuplicateCandidate) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("save_duplicate_candidates", start, err) }(time.Now())
+
+	return r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		for _, candidate := range candidates {
+			if err := tx.Exec(upsertCandidateSQL,
+				candidate.CustomerID, candidate.OtherCustomerID, candidate.Score,
+				strings.Join(candidate.Reasons, ","), entities.DuplicatePending, candidate.DetectedAt,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r DuplicateRepository) DeleteStaleCandidates(ctx context.Context, before time.Time) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("delete_stale_duplicate_candidates", start, err) }(time.Now())
+
+	return r.DB.WithContext(ctx).Exec(deleteStaleCandidatesSQL, entities.DuplicatePending, before)
+}
+
+func (r DuplicateRepository) ListCandidates(ctx context.Context, status string, limit int) (_ []entities.DuplicateCandidate, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("list_duplicate_candidates", start, err) }(time.Now())
+
+	var candidates []models.CustomerDuplicateCandidate
+	if err := r.DB.WithContext(ctx).Raw(&candidates, selectCandidatesSQL, status, limit); err != nil {
+		return nil, err
+	}
+
+	result := make([]entities.DuplicateCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		result = append(result, candidate.ToDomain())
+	}
+
+	return result, nil
+}
+
+func (r DuplicateRepository) DismissCandidate(ctx context.Context, id uint) (_ *entities.DuplicateCandidate, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("dismiss_duplicate_candidate", start, err) }(time.Now())
+
+	var candidate models.CustomerDuplicateCandidate
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := tx.First(&candidate, id); err != nil {
+			return err
+		}
+
+		if candidate.Status != entities.DuplicatePending {
+			return entities.ErrDuplicateAlreadyResolved
+		}
+
+		now := time.Now()
+		candidate.Status = entities.DuplicateDismissed
+		candidate.ResolvedAt = &now
+
+		return tx.Save(&candidate)
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrDuplicateCandidateNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := candidate.ToDomain()
+
+	return &result, nil
+}
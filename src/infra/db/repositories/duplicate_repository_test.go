@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestSaveDuplicateCandidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := DuplicateRepository{DB: mockDB}
+
+	detectedAt := time.Now()
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Exec(upsertCandidateSQL, uint(4), uint(9), 0.82, "name,email", entities.DuplicatePending, detectedAt).Return(nil)
+
+	err := repo.SaveCandidates(context.Background(), []entities.DuplicateCandidate{
+		{CustomerID: 4, OtherCustomerID: 9, Score: 0.82, Reasons: []string{entities.DuplicateReasonName, entities.DuplicateReasonEmail}, DetectedAt: detectedAt},
+	})
+	assert.NoError(t, err)
+}
+
+func TestDismissDuplicateCandidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := DuplicateRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().First(gomock.Any(), uint(3)).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
+		*dest.(*models.CustomerDuplicateCandidate) = models.CustomerDuplicateCandidate{ID: 3, CustomerID: 4, OtherCustomerID: 9, Reasons: "phone", Status: entities.DuplicatePending}
+		return nil
+	})
+	mockDB.EXPECT().Save(gomock.AssignableToTypeOf(&models.CustomerDuplicateCandidate{})).Return(nil)
+
+	candidate, err := repo.DismissCandidate(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, entities.DuplicateDismissed, candidate.Status)
+	assert.NotNil(t, candidate.ResolvedAt)
+	assert.Equal(t, []string{entities.DuplicateReasonPhone}, candidate.Reasons)
+}
+
+func TestDismissDuplicateCandidate_Resolved(t *testing.T) {
+	for name, tc := range map[string]struct {
+		first  error
+		stored models.CustomerDuplicateCandidate
+		want   error
+	}{
+		"par inexistente": {first: gorm.ErrRecordNotFound, want: entities.ErrDuplicateCandidateNotFound},
+		"par já fundido":  {stored: models.CustomerDuplicateCandidate{ID: 3, Status: entities.DuplicateMerged}, want: entities.ErrDuplicateAlreadyResolved},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDB := mocks.NewMockDatabase(ctrl)
+			repo := DuplicateRepository{DB: mockDB}
+
+			mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+			expectTransaction(mockDB)
+			mockDB.EXPECT().First(gomock.Any(), uint(3)).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
+				*dest.(*models.CustomerDuplicateCandidate) = tc.stored
+				return tc.first
+			})
+
+			_, err := repo.DismissCandidate(context.Background(), 3)
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestResolveCustomerRedirect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := CustomerRedirectRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB).Times(2)
+	mockDB.EXPECT().Raw(gomock.Any(), selectRedirectsSQL, []uint{4}).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.CustomerRedirect) = []models.CustomerRedirect{{FromID: 4, ToID: 9}}
+		return nil
+	})
+	mockDB.EXPECT().Raw(gomock.Any(), selectRedirectsSQL, []uint{9}).Return(nil)
+
+	id, err := repo.Resolve(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint(9), id)
+
+	id, err = repo.Resolve(context.Background(), 9)
+	require.NoError(t, err)
+	assert.Equal(t, uint(9), id)
+}
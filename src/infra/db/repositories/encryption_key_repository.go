@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+)
+
+// EncryptionKeyRepository implementa encryption.KeyStore
+type EncryptionKeyRepository struct {
+	DB database.Database
+}
+
+func (r EncryptionKeyRepository) ListKeys(ctx context.Context) (_ []encryption.WrappedKey, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("list_encryption_keys", start, err) }(time.Now())
+
+	var keys []models.EncryptionKey
+	if err := r.DB.WithContext(ctx).Raw(&keys, "SELECT * FROM encryption_keys ORDER BY created_at"); err != nil {
+		return nil, err
+	}
+
+	result := make([]encryption.WrappedKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, key.ToWrappedKey())
+	}
+
+	return result, nil
+}
+
+func (r EncryptionKeyRepository) SaveKey(ctx context.Context, key encryption.WrappedKey) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("save_encryption_key", start, err) }(time.Now())
+
+	model := models.NewEncryptionKey(key)
+
+	return r.DB.WithContext(ctx).Save(&model)
+}
+
+func (r EncryptionKeyRepository) ActivateKey(ctx context.Context, id string) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("activate_encryption_key", start, err) }(time.Now())
+
+	return r.DB.WithContext(ctx).Exec("UPDATE encryption_keys SET active = (id = ?)", id)
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"gorm.io/gorm"
+)
+
+type FiscalProfileRepository struct {
+	DB database.Database
+}
+
+func (r FiscalProfileRepository) Find(ctx context.Context, customerID uint) (_ *entities.FiscalProfile, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_fiscal_profile", start, err) }(time.Now())
+
+	var model models.CustomerFiscalProfile
+	if err := r.DB.WithContext(ctx).First(&model, "customer_id = ?", customerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrFiscalProfileNotFound
+		}
+		return nil, err
+	}
+
+	profile := model.ToDomain()
+
+	return &profile, nil
+}
+
+// Save cria ou substitui o perfil fiscal, com a linha do cliente travada como nas preferências
+func (r FiscalProfileRepository) Save(ctx context.Context, profile entities.FiscalProfile) (_ *entities.FiscalProfile, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("save_fiscal_profile", start, err) }(time.Now())
+
+	model := models.NewCustomerFiscalProfile(profile)
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := lockActiveCustomer(tx, profile.CustomerID); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&model); err != nil {
+			return err
+		}
+
+		return appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionFiscalProfileUpdated, profile.CustomerID))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := model.ToDomain()
+
+	return &result, nil
+}
+
+func (r FiscalProfileRepository) Delete(ctx context.Context, customerID uint) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("delete_fiscal_profile", start, err) }(time.Now())
+
+	return r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := lockActiveCustomer(tx, customerID); err != nil {
+			return err
+		}
+
+		var model models.CustomerFiscalProfile
+		if err := tx.First(&model, "customer_id = ?", customerID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrFiscalProfileNotFound
+			}
+			return err
+		}
+
+		if err := tx.Delete(&model); err != nil {
+			return err
+		}
+
+		return appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionFiscalProfileDeleted, customerID))
+	})
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestSaveFiscalProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := FiscalProfileRepository{DB: mockDB}
+
+	profile, err := entities.NewFiscalProfile(7, entities.FiscalDocumentCNPJ, "11.222.333/0001-81", "Lanches Ltda", "isento", "")
+	require.NoError(t, err)
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	expectLockedCustomer(mockDB, 7)
+
+	var saved models.CustomerFiscalProfile
+	mockDB.EXPECT().Save(gomock.AssignableToTypeOf(&models.CustomerFiscalProfile{})).DoAndReturn(func(data interface{}) error {
+		saved = *data.(*models.CustomerFiscalProfile)
+		return nil
+	})
+	auditEntry := expectAudit(mockDB)
+
+	result, err := repo.Save(context.Background(), profile)
+	require.NoError(t, err)
+	assert.Equal(t, "CNPJ", saved.DocumentType)
+	assert.Equal(t, "11222333000181", saved.Document)
+	assert.Equal(t, entities.StateRegistrationExempt, result.StateRegistration)
+	assert.Equal(t, entities.AuditActionFiscalProfileUpdated, auditEntry.Action)
+}
+
+func TestDeleteFiscalProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := FiscalProfileRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	expectLockedCustomer(mockDB, 7)
+	mockDB.EXPECT().First(gomock.AssignableToTypeOf(&models.CustomerFiscalProfile{}), "customer_id = ?", uint(7)).DoAndReturn(func(dest interface{}, conds ...interface{}) error {
+		dest.(*models.CustomerFiscalProfile).CustomerID = 7
+		return nil
+	})
+	mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerFiscalProfile{})).Return(nil)
+	auditEntry := expectAudit(mockDB)
+
+	require.NoError(t, repo.Delete(context.Background(), 7))
+	assert.Equal(t, entities.AuditActionFiscalProfileDeleted, auditEntry.Action)
+}
+
+func TestDeleteFiscalProfile_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := FiscalProfileRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	expectLockedCustomer(mockDB, 7)
+	mockDB.EXPECT().First(gomock.AssignableToTypeOf(&models.CustomerFiscalProfile{}), "customer_id = ?", uint(7)).Return(gorm.ErrRecordNotFound)
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), 7), entities.ErrFiscalProfileNotFound)
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+)
+
+const (
+	selectLoyaltyHistorySQL      = `SELECT * FROM loyalty_transactions WHERE customer_id = ? ORDER BY id`
+	selectLoyaltyTransactionsSQL = `SELECT * FROM loyalty_transactions WHERE customer_id = ? AND id > ? ORDER BY id LIMIT ?`
+	selectLoyaltyCustomerIDsSQL  = `SELECT DISTINCT customer_id FROM loyalty_transactions WHERE customer_id > ? ORDER BY customer_id LIMIT ?`
+)
+
+type LoyaltyRepository struct {
+	DB database.Database
+}
+
+func (r LoyaltyRepository) History(ctx context.Context, customerID uint) (_ []entities.LoyaltyTransaction, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("loyalty_history", start, err) }(time.Now())
+
+	var transactions []models.LoyaltyTransaction
+	if err := r.DB.WithContext(ctx).Raw(&transactions, selectLoyaltyHistorySQL, customerID); err != nil {
+		return nil, err
+	}
+
+	return loyaltyTransactionsToDomain(transactions), nil
+}
+
+func (r LoyaltyRepository) List(ctx context.Context, customerID uint, afterID uint, limit int) (_ []entities.LoyaltyTransaction, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("list_loyalty_transactions", start, err) }(time.Now())
+
+	var transactions []models.LoyaltyTransaction
+	if err := r.DB.WithContext(ctx).Raw(&transactions, selectLoyaltyTransactionsSQL, customerID, afterID, limit); err != nil {
+		return nil, err
+	}
+
+	return loyaltyTransactionsToDomain(transactions), nil
+}
+
+func (r LoyaltyRepository) Post(ctx context.Context, customerID uint, build func(history []entities.LoyaltyTransaction) ([]entities.LoyaltyTransaction, error)) (_ []entities.LoyaltyTransaction, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("post_loyalty_transactions", start, err) }(time.Now())
+
+	created := []entities.LoyaltyTransaction{}
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", database.LoyaltyLockKey, customerID); err != nil {
+			return err
+		}
+
+		var history []models.LoyaltyTransaction
+		if err := tx.Raw(&history, selectLoyaltyHistorySQL, customerID); err != nil {
+			return err
+		}
+
+		transactions, err := build(loyaltyTransactionsToDomain(history))
+		if err != nil {
+			return err
+		}
+
+		for _, transaction := range transactions {
+			model := models.NewLoyaltyTransaction(transaction)
+			if err := tx.Create(&model); err != nil {
+				return err
+			}
+			created = append(created, model.ToDomain())
+		}
+
+		return nil
+	})
+
+	// a mesma referência já foi usada para outro cliente
+	if database.IsUniqueViolation(err) {
+		return nil, entities.ErrLoyaltyReferenceConflict
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r LoyaltyRepository) CustomerIDs(ctx context.Context, afterID uint, limit int) (_ []uint, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("loyalty_customer_ids", start, err) }(time.Now())
+
+	var ids []uint
+	if err := r.DB.WithContext(ctx).Raw(&ids, selectLoyaltyCustomerIDsSQL, afterID, limit); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func loyaltyTransactionsToDomain(transactions []models.LoyaltyTransaction) []entities.LoyaltyTransaction {
+	result := make([]entities.LoyaltyTransaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		result = append(result, transaction.ToDomain())
+	}
+
+	return result
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestPostLoyalty_LocksCustomerAndBuildsFromHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := LoyaltyRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Exec("SELECT pg_advisory_xact_lock(?, ?)", database.LoyaltyLockKey, uint(7)).Return(nil)
+	mockDB.EXPECT().Raw(gomock.Any(), selectLoyaltyHistorySQL, uint(7)).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.LoyaltyTransaction) = []models.LoyaltyTransaction{{ID: 1, CustomerID: 7, Type: entities.LoyaltyEarn, Points: 50}}
+		return nil
+	})
+	mockDB.EXPECT().Create(gomock.AssignableToTypeOf(&models.LoyaltyTransaction{})).DoAndReturn(func(data interface{}) error {
+		data.(*models.LoyaltyTransaction).ID = 2
+		return nil
+	})
+
+	created, err := repo.Post(context.Background(), 7, func(history []entities.LoyaltyTransaction) ([]entities.LoyaltyTransaction, error) {
+		require.Len(t, history, 1)
+		assert.Equal(t, int64(50), history[0].Points)
+
+		return []entities.LoyaltyTransaction{{CustomerID: 7, Type: entities.LoyaltyRedeem, Points: -20, Reference: "r-1"}}, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, uint(2), created[0].ID)
+	assert.Equal(t, int64(-20), created[0].Points)
+}
+
+func TestPostLoyalty_BuildErrorWritesNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := LoyaltyRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Exec("SELECT pg_advisory_xact_lock(?, ?)", database.LoyaltyLockKey, uint(7)).Return(nil)
+	mockDB.EXPECT().Raw(gomock.Any(), selectLoyaltyHistorySQL, uint(7)).Return(nil)
+
+	_, err := repo.Post(context.Background(), 7, func(history []entities.LoyaltyTransaction) ([]entities.LoyaltyTransaction, error) {
+		return nil, entities.ErrInsufficientPoints
+	})
+	assert.ErrorIs(t, err, entities.ErrInsufficientPoints)
+}
+
+func TestPostLoyalty_ReferenceUsedByAnotherCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := LoyaltyRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Exec("SELECT pg_advisory_xact_lock(?, ?)", database.LoyaltyLockKey, uint(7)).Return(nil)
+	mockDB.EXPECT().Raw(gomock.Any(), selectLoyaltyHistorySQL, uint(7)).Return(nil)
+	mockDB.EXPECT().Create(gomock.Any()).Return(errors.New(`ERROR: duplicate key value violates unique constraint "idx_loyalty_transactions_reference"`))
+
+	_, err := repo.Post(context.Background(), 7, func(history []entities.LoyaltyTransaction) ([]entities.LoyaltyTransaction, error) {
+		return []entities.LoyaltyTransaction{{CustomerID: 7, Type: entities.LoyaltyEarn, Points: 10, Reference: "order:1"}}, nil
+	})
+	assert.ErrorIs(t, err, entities.ErrLoyaltyReferenceConflict)
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"gorm.io/gorm"
+)
+
+const lockPhoneVerificationSQL = `SELECT * FROM customer_phone_verifications WHERE customer_id = ? FOR UPDATE`
+
+// countPhoneCodesSentSQL conta os envios pela auditoria, que guarda todos eles: a verificação pendente
+// é substituída a cada envio e apagada quando o código é usado ou descartado
+const countPhoneCodesSentSQL = `SELECT COUNT(*) FROM audit_entries WHERE target_type = ? AND target_id = ? AND action = ? AND occurred_at >= ?`
+
+type PhoneVerificationRepository struct {
+	DB database.Database
+}
+
+func (r PhoneVerificationRepository) Find(ctx context.Context, customerID uint) (_ *entities.PhoneVerification, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_phone_verification", start, err) }(time.Now())
+
+	var model models.CustomerPhoneVerification
+	if err := r.DB.WithContext(ctx).First(&model, "customer_id = ?", customerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrPhoneVerificationNotFound
+		}
+		return nil, err
+	}
+
+	verification := model.ToDomain()
+
+	return &verification, nil
+}
+
+// Save substitui o código pendente, com a linha do cliente travada como nas preferências
+func (r PhoneVerificationRepository) Save(ctx context.Context, verification entities.PhoneVerification, code string) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("save_phone_verification", start, err) }(time.Now())
+
+	codeHash, err := models.PhoneCodeHash(verification.CustomerID, verification.Phone, code)
+	if err != nil {
+		return err
+	}
+
+	model := models.CustomerPhoneVerification{
+		CustomerID: verification.CustomerID,
+		Phone:      verification.Phone,
+		CodeHash:   codeHash,
+		SentAt:     verification.SentAt,
+		ExpiresAt:  verification.ExpiresAt,
+	}
+
+	return r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := lockActiveCustomer(tx, verification.CustomerID); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&model); err != nil {
+			return err
+		}
+
+		return appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionPhoneCodeSent, verification.CustomerID))
+	})
+}
+
+func (r PhoneVerificationRepository) CountSent(ctx context.Context, customerID uint, since time.Time) (_ int, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("count_phone_codes_sent", start, err) }(time.Now())
+
+	entry := entities.CustomerAuditEntry(entities.AuditActionPhoneCodeSent, customerID)
+
+	var counts []int64
+	if err := r.DB.WithContext(ctx).Raw(&counts, countPhoneCodesSentSQL, entry.TargetType, entry.TargetID, entry.Action, since); err != nil {
+		return 0, err
+	}
+
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	return int(counts[0]), nil
+}
+
+// Consume confere o código com a verificação travada. As tentativas erradas precisam ficar gravadas,
+// então a transação é confirmada mesmo quando o código não confere e o erro é devolvido depois.
+func (r PhoneVerificationRepository) Consume(ctx context.Context, customerID uint, phone string, code string, maxAttempts int, now time.Time) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("consume_phone_verification", start, err) }(time.Now())
+
+	codeHash, err := models.PhoneCodeHash(customerID, phone, code)
+	if err != nil {
+		return err
+	}
+
+	var outcome error
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		var pending []models.CustomerPhoneVerification
+		if err := tx.Raw(&pending, lockPhoneVerificationSQL, customerID); err != nil {
+			return err
+		}
+
+		// sem código, ou com código enviado para o telefone anterior a uma troca
+		if len(pending) == 0 || pending[0].Phone != phone {
+			outcome = entities.ErrPhoneVerificationNotFound
+			return nil
+		}
+
+		verification := pending[0]
+
+		if !now.Before(verification.ExpiresAt) || verification.Attempts >= maxAttempts {
+			outcome = entities.ErrPhoneCodeExpired
+			return tx.Delete(&models.CustomerPhoneVerification{}, "customer_id = ?", customerID)
+		}
+
+		if subtle.ConstantTimeCompare([]byte(verification.CodeHash), []byte(codeHash)) != 1 {
+			outcome = entities.ErrInvalidPhoneCode
+			verification.Attempts++
+			return tx.Save(&verification)
+		}
+
+		if err := tx.Delete(&models.CustomerPhoneVerification{}, "customer_id = ?", customerID); err != nil {
+			return err
+		}
+
+		return appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionPhoneVerified, customerID))
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return outcome
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+const testPhone = "+5511987654321"
+
+func TestSavePhoneVerification(t *testing.T) {
+	encryption.SetDefault(newTestKeyring(t))
+	t.Cleanup(func() { encryption.SetDefault(nil) })
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := PhoneVerificationRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	expectLockedCustomer(mockDB, 7)
+
+	var saved models.CustomerPhoneVerification
+	mockDB.EXPECT().Save(gomock.AssignableToTypeOf(&models.CustomerPhoneVerification{})).DoAndReturn(func(data interface{}) error {
+		saved = *data.(*models.CustomerPhoneVerification)
+		return nil
+	})
+	auditEntry := expectAudit(mockDB)
+
+	err := repo.Save(context.Background(), entities.PhoneVerification{CustomerID: 7, Phone: testPhone, ExpiresAt: time.Now().Add(time.Minute)}, "123456")
+	require.NoError(t, err)
+
+	codeHash, err := models.PhoneCodeHash(7, testPhone, "123456")
+	require.NoError(t, err)
+	assert.Equal(t, codeHash, saved.CodeHash)
+	assert.NotContains(t, saved.CodeHash, "123456")
+	assert.Equal(t, entities.AuditActionPhoneCodeSent, auditEntry.Action)
+}
+
+func TestConsumePhoneVerification(t *testing.T) {
+	encryption.SetDefault(newTestKeyring(t))
+	t.Cleanup(func() { encryption.SetDefault(nil) })
+
+	now := time.Now()
+
+	codeHash, err := models.PhoneCodeHash(7, testPhone, "123456")
+	require.NoError(t, err)
+
+	expectPending := func(mockDB *mocks.MockDatabase, pending ...models.CustomerPhoneVerification) {
+		mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+		expectTransaction(mockDB)
+		mockDB.EXPECT().Raw(gomock.Any(), lockPhoneVerificationSQL, uint(7)).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+			*dest.(*[]models.CustomerPhoneVerification) = pending
+			return nil
+		})
+	}
+
+	pending := models.CustomerPhoneVerification{CustomerID: 7, Phone: testPhone, CodeHash: codeHash, Attempts: 1, ExpiresAt: now.Add(time.Minute)}
+
+	t.Run("código certo", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := mocks.NewMockDatabase(ctrl)
+
+		expectPending(mockDB, pending)
+		mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerPhoneVerification{}), "customer_id = ?", uint(7)).Return(nil)
+		auditEntry := expectAudit(mockDB)
+
+		err := PhoneVerificationRepository{DB: mockDB}.Consume(context.Background(), 7, testPhone, "123456", 5, now)
+		require.NoError(t, err)
+		assert.Equal(t, entities.AuditActionPhoneVerified, auditEntry.Action)
+	})
+
+	t.Run("código errado conta a tentativa", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := mocks.NewMockDatabase(ctrl)
+
+		expectPending(mockDB, pending)
+		var saved models.CustomerPhoneVerification
+		mockDB.EXPECT().Save(gomock.AssignableToTypeOf(&models.CustomerPhoneVerification{})).DoAndReturn(func(data interface{}) error {
+			saved = *data.(*models.CustomerPhoneVerification)
+			return nil
+		})
+
+		err := PhoneVerificationRepository{DB: mockDB}.Consume(context.Background(), 7, testPhone, "654321", 5, now)
+		assert.ErrorIs(t, err, entities.ErrInvalidPhoneCode)
+		assert.Equal(t, 2, saved.Attempts)
+	})
+
+	t.Run("tentativas esgotadas", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := mocks.NewMockDatabase(ctrl)
+
+		exhausted := pending
+		exhausted.Attempts = 5
+		expectPending(mockDB, exhausted)
+		mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerPhoneVerification{}), "customer_id = ?", uint(7)).Return(nil)
+
+		err := PhoneVerificationRepository{DB: mockDB}.Consume(context.Background(), 7, testPhone, "123456", 5, now)
+		assert.ErrorIs(t, err, entities.ErrPhoneCodeExpired)
+	})
+
+	t.Run("código vencido", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := mocks.NewMockDatabase(ctrl)
+
+		expectPending(mockDB, pending)
+		mockDB.EXPECT().Delete(gomock.AssignableToTypeOf(&models.CustomerPhoneVerification{}), "customer_id = ?", uint(7)).Return(nil)
+
+		err := PhoneVerificationRepository{DB: mockDB}.Consume(context.Background(), 7, testPhone, "123456", 5, now.Add(time.Hour))
+		assert.ErrorIs(t, err, entities.ErrPhoneCodeExpired)
+	})
+
+	t.Run("código de outro telefone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockDB := mocks.NewMockDatabase(ctrl)
+
+		expectPending(mockDB, pending)
+
+		err := PhoneVerificationRepository{DB: mockDB}.Consume(context.Background(), 7, "+5521987654321", "123456", 5, now)
+		assert.ErrorIs(t, err, entities.ErrPhoneVerificationNotFound)
+	})
+}
+
+func TestCountPhoneCodesSent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := PhoneVerificationRepository{DB: mockDB}
+	since := time.Now().Add(-24 * time.Hour)
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), countPhoneCodesSentSQL, entities.AuditTargetCustomer, "7", entities.AuditActionPhoneCodeSent, since).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]int64) = []int64{3}
+		return nil
+	})
+
+	sent, err := repo.CountSent(context.Background(), 7, since)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"gorm.io/gorm"
+)
+
+const selectPreferencesHashSQL = `SELECT hash FROM customer_preferences WHERE customer_id = ?`
+
+type PreferencesRepository struct {
+	DB database.Database
+}
+
+func (r PreferencesRepository) Find(ctx context.Context, customerID uint) (_ *entities.DietaryPreferences, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_preferences", start, err) }(time.Now())
+
+	var model models.CustomerPreferences
+	if err := r.DB.WithContext(ctx).First(&model, "customer_id = ?", customerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			preferences, err := entities.NewDietaryPreferences(customerID, nil, nil, nil)
+			return &preferences, err
+		}
+		return nil, err
+	}
+
+	preferences, err := model.ToDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	return &preferences, nil
+}
+
+func (r PreferencesRepository) Hash(ctx context.Context, customerID uint) (_ string, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_preferences_hash", start, err) }(time.Now())
+
+	var hashes []string
+	if err := r.DB.WithContext(ctx).Raw(&hashes, selectPreferencesHashSQL, customerID); err != nil {
+		return "", err
+	}
+
+	if len(hashes) == 0 {
+		return "", nil
+	}
+
+	return hashes[0], nil
+}
+
+// Save substitui as preferências do cliente, com a linha do cliente travada para não
+// gravar preferências de um cliente sendo eliminado
+func (r PreferencesRepository) Save(ctx context.Context, preferences entities.DietaryPreferences) (_ *entities.DietaryPreferences, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("save_preferences", start, err) }(time.Now())
+
+	model, err := models.NewCustomerPreferences(preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := lockActiveCustomer(tx, preferences.CustomerID); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&model); err != nil {
+			return err
+		}
+
+		return appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionPreferencesUpdated, preferences.CustomerID))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := model.ToDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
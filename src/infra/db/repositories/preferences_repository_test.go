@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestSavePreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	encryption.SetDefault(newTestKeyring(t))
+	t.Cleanup(func() { encryption.SetDefault(nil) })
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := PreferencesRepository{DB: mockDB}
+
+	preferences, err := entities.NewDietaryPreferences(7, []string{"peanuts"}, []string{"vegan"}, []string{"Coentro"})
+	require.NoError(t, err)
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	expectLockedCustomer(mockDB, 7)
+
+	var saved models.CustomerPreferences
+	mockDB.EXPECT().Save(gomock.AssignableToTypeOf(&models.CustomerPreferences{})).DoAndReturn(func(data interface{}) error {
+		saved = *data.(*models.CustomerPreferences)
+		return nil
+	})
+	auditEntry := expectAudit(mockDB)
+
+	result, err := repo.Save(context.Background(), preferences)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), saved.CustomerID)
+	assert.Len(t, saved.Hash, 16)
+	assert.Equal(t, saved.Hash, result.Hash)
+	assert.Equal(t, []string{"coentro"}, result.DislikedIngredients)
+	assert.Equal(t, entities.AuditActionPreferencesUpdated, auditEntry.Action)
+
+	// o hash depende de todas as listas
+	other, err := entities.NewDietaryPreferences(7, []string{"peanuts"}, []string{"vegan"}, nil)
+	require.NoError(t, err)
+	otherHash, err := models.PreferencesHash(other)
+	require.NoError(t, err)
+	assert.NotEqual(t, saved.Hash, otherHash)
+}
+
+func TestSavePreferences_ErasedCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	encryption.SetDefault(newTestKeyring(t))
+	t.Cleanup(func() { encryption.SetDefault(nil) })
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	repo := PreferencesRepository{DB: mockDB}
+
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	expectTransaction(mockDB)
+	mockDB.EXPECT().Raw(gomock.Any(), lockActiveCustomerSQL, uint(7)).Return(nil)
+
+	_, err := repo.Save(context.Background(), entities.DietaryPreferences{CustomerID: 7})
+	assert.ErrorIs(t, err, entities.ErrCustomerNotFound)
+}
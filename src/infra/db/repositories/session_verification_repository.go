@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"gorm.io/gorm"
+)
+
+const lockSessionVerificationSQL = `SELECT * FROM customer_session_verifications WHERE customer_id = ? FOR UPDATE`
+
+type SessionVerificationRepository struct {
+	DB database.Database
+}
+
+func (r SessionVerificationRepository) Find(ctx context.Context, customerID uint) (_ *entities.SessionVerification, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("find_session_verification", start, err) }(time.Now())
+
+	var model models.CustomerSessionVerification
+	if err := r.DB.WithContext(ctx).First(&model, "customer_id = ?", customerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrSessionVerificationNotFound
+		}
+		return nil, err
+	}
+
+	verification := model.ToDomain()
+
+	return &verification, nil
+}
+
+// Save substitui o código pendente com a linha do cliente travada, como na verificação do telefone
+func (r SessionVerificationRepository) Save(ctx context.Context, verification entities.SessionVerification, code string) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("save_session_verification", start, err) }(time.Now())
+
+	codeHash, err := models.SessionCodeHash(verification.CustomerID, verification.Channel, verification.Destination, code)
+	if err != nil {
+		return err
+	}
+
+	model := models.CustomerSessionVerification{
+		CustomerID:  verification.CustomerID,
+		Channel:     verification.Channel,
+		Destination: verification.Destination,
+		CodeHash:    codeHash,
+		SentAt:      verification.SentAt,
+		ExpiresAt:   verification.ExpiresAt,
+	}
+
+	return r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := lockActiveCustomer(tx, verification.CustomerID); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&model); err != nil {
+			return err
+		}
+
+		return appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionSessionCodeSent, verification.CustomerID))
+	})
+}
+
+// Consume confere o código com a verificação travada. Como no telefone, as tentativas erradas
+// precisam ficar gravadas, então a transação é confirmada e o erro é devolvido depois.
+func (r SessionVerificationRepository) Consume(ctx context.Context, customerID uint, code string, maxAttempts int, now time.Time) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("consume_session_verification", start, err) }(time.Now())
+
+	var outcome error
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		var pending []models.CustomerSessionVerification
+		if err := tx.Raw(&pending, lockSessionVerificationSQL, customerID); err != nil {
+			return err
+		}
+
+		if len(pending) == 0 {
+			outcome = entities.ErrSessionVerificationNotFound
+			return nil
+		}
+
+		verification := pending[0]
+
+		if !now.Before(verification.ExpiresAt) || verification.Attempts >= maxAttempts {
+			outcome = entities.ErrSessionCodeExpired
+			return tx.Delete(&models.CustomerSessionVerification{}, "customer_id = ?", customerID)
+		}
+
+		codeHash, err := models.SessionCodeHash(customerID, verification.Channel, verification.Destination, code)
+		if err != nil {
+			return err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(verification.CodeHash), []byte(codeHash)) != 1 {
+			outcome = entities.ErrInvalidSessionCode
+			verification.Attempts++
+			return tx.Save(&verification)
+		}
+
+		if err := tx.Delete(&models.CustomerSessionVerification{}, "customer_id = ?", customerID); err != nil {
+			return err
+		}
+
+		return appendAudit(ctx, tx, entities.CustomerAuditEntry(entities.AuditActionSessionVerified, customerID))
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return outcome
+}
@@ -0,0 +1,299 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"gorm.io/gorm"
+)
+
+const (
+	insertDeliverySQL = `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, aggregate_type, aggregate_id, payload, status, attempts, next_attempt_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?) ON CONFLICT (subscription_id, event_id) DO NOTHING`
+	selectDueDeliveriesSQL   = `SELECT * FROM webhook_deliveries WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ? FOR UPDATE SKIP LOCKED`
+	updateDeliverySQL        = `UPDATE webhook_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ?, updated_at = ? WHERE id = ?`
+	deleteExpiredAttemptsSQL = `DELETE FROM webhook_delivery_attempts WHERE delivery_id IN
+		(SELECT id FROM webhook_deliveries WHERE status IN ? AND updated_at < ?)`
+)
+
+type WebhookRepository struct {
+	DB database.Database
+}
+
+func (r WebhookRepository) CreateSubscription(ctx context.Context, entity *entities.WebhookSubscription) (_ *entities.WebhookSubscription, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_create_subscription", start, err) }(time.Now())
+
+	subscription := models.WebhookSubscription{
+		URL:        entity.URL,
+		EventTypes: strings.Join(entity.EventTypes, ","),
+		Secret:     entity.Secret,
+		Active:     entity.Active,
+	}
+
+	if err := r.DB.WithContext(ctx).Create(&subscription); err != nil {
+		return nil, err
+	}
+
+	result := subscription.ToDomain()
+
+	return &result, nil
+}
+
+func (r WebhookRepository) ListSubscriptions(ctx context.Context) (_ []entities.WebhookSubscription, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_list_subscriptions", start, err) }(time.Now())
+
+	return r.findSubscriptions(ctx)
+}
+
+// ActiveSubscriptions devolve as assinaturas ativas, usadas para decidir quem recebe cada evento
+func (r WebhookRepository) ActiveSubscriptions(ctx context.Context) (_ []entities.WebhookSubscription, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_active_subscriptions", start, err) }(time.Now())
+
+	return r.findSubscriptions(ctx, "active = ?", true)
+}
+
+func (r WebhookRepository) FindSubscriptionsByIDs(ctx context.Context, ids []uint) (_ []entities.WebhookSubscription, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_find_subscriptions_by_ids", start, err) }(time.Now())
+
+	return r.findSubscriptions(ctx, "id IN ?", ids)
+}
+
+func (r WebhookRepository) findSubscriptions(ctx context.Context, conds ...interface{}) ([]entities.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+
+	if err := r.DB.WithContext(ctx).Find(&subscriptions, conds...); err != nil {
+		return nil, err
+	}
+
+	result := make([]entities.WebhookSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		result = append(result, subscription.ToDomain())
+	}
+
+	return result, nil
+}
+
+func (r WebhookRepository) DeleteSubscription(ctx context.Context, id uint) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_delete_subscription", start, err) }(time.Now())
+
+	db := r.DB.WithContext(ctx)
+
+	var subscription models.WebhookSubscription
+	if err := db.First(&subscription, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrWebhookNotFound
+		}
+		return err
+	}
+
+	return db.Delete(&subscription)
+}
+
+func (r WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID uint, status string, limit int) (_ []entities.WebhookDelivery, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_list_deliveries", start, err) }(time.Now())
+
+	db := r.DB.WithContext(ctx)
+
+	var subscription models.WebhookSubscription
+	if err := db.First(&subscription, subscriptionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	query := db.Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]entities.WebhookDelivery, 0, len(deliveries))
+	if len(deliveries) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uint, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.ID)
+	}
+
+	var attempts []models.WebhookDeliveryAttempt
+	if err := db.Where("delivery_id IN ?", ids).Order("id").Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+
+	attemptLog := map[uint][]entities.WebhookDeliveryAttempt{}
+	for _, attempt := range attempts {
+		attemptLog[attempt.DeliveryID] = append(attemptLog[attempt.DeliveryID], attempt.ToDomain())
+	}
+
+	for _, delivery := range deliveries {
+		entity, err := openDelivery(ctx, delivery)
+		if err != nil {
+			return nil, err
+		}
+		if log, ok := attemptLog[delivery.ID]; ok {
+			entity.AttemptLog = log
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
+
+func (r WebhookRepository) ReplayDelivery(ctx context.Context, subscriptionID uint, deliveryID uint) (_ *entities.WebhookDelivery, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_replay_delivery", start, err) }(time.Now())
+
+	var delivery models.WebhookDelivery
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := tx.First(&delivery, "id = ? AND subscription_id = ?", deliveryID, subscriptionID); err != nil {
+			return err
+		}
+
+		delivery.Status = entities.WebhookDeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = time.Now()
+		delivery.DeliveredAt = nil
+
+		return tx.Save(&delivery)
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	result, err := openDelivery(ctx, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// EnqueueDelivery agenda o envio do evento para a assinatura; repetir o mesmo evento não duplica a entrega.
+// O payload é gravado cifrado, como no outbox.
+func (r WebhookRepository) EnqueueDelivery(ctx context.Context, subscriptionID uint, envelope events.Envelope, payload []byte) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_enqueue_delivery", start, err) }(time.Now())
+
+	sealed, err := models.SealPayload(ctx, models.WebhookDeliveryPayloadField, payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	return r.DB.WithContext(ctx).Exec(insertDeliverySQL,
+		subscriptionID, envelope.ID, envelope.Type, envelope.AggregateType, envelope.AggregateID, sealed, entities.WebhookDeliveryPending, now, now, now)
+}
+
+// ClaimDueDeliveries reserva as entregas vencidas empurrando a próxima tentativa para depois do lease,
+// assim outra réplica não as pega enquanto o envio HTTP acontece fora da transação
+func (r WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) (_ []entities.WebhookDelivery, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_claim_due_deliveries", start, err) }(time.Now())
+
+	var deliveries []models.WebhookDelivery
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		now := time.Now()
+		if err := tx.Raw(&deliveries, selectDueDeliveriesSQL, entities.WebhookDeliveryPending, now, limit); err != nil {
+			return err
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+
+		return tx.Exec(`UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id IN ?`, now.Add(lease), ids)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]entities.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		entity, err := openDelivery(ctx, delivery)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
+
+// RecordAttempt grava o resultado da tentativa na entrega e no log de tentativas
+func (r WebhookRepository) RecordAttempt(ctx context.Context, delivery entities.WebhookDelivery, attempt entities.WebhookDeliveryAttempt) (err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_record_attempt", start, err) }(time.Now())
+
+	return r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		err := tx.Exec(updateDeliverySQL, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+			delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt, time.Now(), delivery.ID)
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&models.WebhookDeliveryAttempt{
+			DeliveryID: delivery.ID,
+			Attempt:    attempt.Attempt,
+			StatusCode: attempt.StatusCode,
+			Error:      attempt.Error,
+			DurationMs: attempt.DurationMs,
+		})
+	})
+}
+
+// PurgeDeliveries apaga as entregas encerradas (sucesso ou dead) sem alteração desde before, junto com o
+// log de tentativas. Entregas pendentes nunca são apagadas aqui.
+func (r WebhookRepository) PurgeDeliveries(ctx context.Context, before time.Time) (purged int64, err error) {
+	defer func(start time.Time) { metrics.ObserveRepositoryCall("webhook_purge_deliveries", start, err) }(time.Now())
+
+	statuses := []string{entities.WebhookDeliverySucceeded, entities.WebhookDeliveryDead}
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		if err := tx.Exec(deleteExpiredAttemptsSQL, statuses, before); err != nil {
+			return err
+		}
+
+		result := tx.Where("status IN ? AND updated_at < ?", statuses, before).Delete(&models.WebhookDelivery{})
+		purged = result.RowsAffected
+
+		return result.Error
+	})
+
+	return purged, err
+}
+
+// openDelivery converte a entrega decifrando o payload
+func openDelivery(ctx context.Context, delivery models.WebhookDelivery) (entities.WebhookDelivery, error) {
+	entity := delivery.ToDomain()
+
+	payload, err := models.OpenPayload(ctx, models.WebhookDeliveryPayloadField, delivery.Payload)
+	if err != nil {
+		return entity, err
+	}
+	entity.Payload = payload
+
+	return entity, nil
+}
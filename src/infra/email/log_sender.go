@@ -0,0 +1,52 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogSender não envia nada: grava cada email como uma linha JSON em Path ou, sem Path, no log.
+// O email leva o código de acesso, então não deve ser usado em produção.
+type LogSender struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+type loggedEmail struct {
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	SentAt  time.Time `json:"sentAt"`
+}
+
+func (s *LogSender) Send(ctx context.Context, to string, subject string, body string) error {
+	if s.Path == "" {
+		slog.InfoContext(ctx, "Email (provedor de log)", "to", to, "subject", subject, "body", body)
+		return nil
+	}
+
+	line, err := json.Marshal(loggedEmail{To: to, Subject: subject, Body: body, SentAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,62 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSender_AppendsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "email.log")
+	sender := &LogSender{Path: path}
+
+	require.NoError(t, sender.Send(context.Background(), "john@example.com", "Código de acesso", "primeiro"))
+	require.NoError(t, sender.Send(context.Background(), "john@example.com", "Código de acesso", "segundo"))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var emails []loggedEmail
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var email loggedEmail
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &email))
+		emails = append(emails, email)
+	}
+
+	require.Len(t, emails, 2)
+	assert.Equal(t, "john@example.com", emails[0].To)
+	assert.Equal(t, "primeiro", emails[0].Body)
+	assert.Equal(t, "segundo", emails[1].Body)
+}
+
+func TestNewSenderFromEnv(t *testing.T) {
+	t.Run("provedor desconhecido", func(t *testing.T) {
+		t.Setenv("EMAIL_PROVIDER", "pombo-correio")
+
+		_, err := NewSenderFromEnv()
+		assert.Error(t, err)
+	})
+
+	t.Run("smtp sem servidor", func(t *testing.T) {
+		t.Setenv("EMAIL_PROVIDER", "smtp")
+		t.Setenv("EMAIL_SMTP_ADDR", "")
+
+		_, err := NewSenderFromEnv()
+		assert.Error(t, err)
+	})
+}
+
+func TestSMTPSender_RejectsHeaderInjection(t *testing.T) {
+	sender := &SMTPSender{Addr: "localhost:25", From: "noreply@example.com"}
+
+	err := sender.Send(context.Background(), "john@example.com\r\nBcc: eve@example.com", "Código de acesso", "123456")
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+// NewSenderFromEnv escolhe o envio de email por EMAIL_PROVIDER:
+//   - "log" (padrão), para desenvolvimento e testes: o email vai para o log ou, com EMAIL_LOG_PATH, para o arquivo
+//   - "smtp": EMAIL_SMTP_ADDR (host:porta), EMAIL_FROM e, com autenticação, EMAIL_SMTP_USERNAME e EMAIL_SMTP_PASSWORD
+func NewSenderFromEnv() (gateways.EmailSender, error) {
+	switch provider := utils.GetEnv("EMAIL_PROVIDER", "log"); provider {
+	case "log":
+		return &LogSender{Path: os.Getenv("EMAIL_LOG_PATH")}, nil
+	case "smtp":
+		sender := &SMTPSender{
+			Addr:     os.Getenv("EMAIL_SMTP_ADDR"),
+			From:     os.Getenv("EMAIL_FROM"),
+			Username: os.Getenv("EMAIL_SMTP_USERNAME"),
+			Password: os.Getenv("EMAIL_SMTP_PASSWORD"),
+		}
+		if sender.Addr == "" || sender.From == "" {
+			return nil, errors.New("EMAIL_PROVIDER=smtp exige EMAIL_SMTP_ADDR e EMAIL_FROM")
+		}
+		return sender, nil
+	default:
+		return nil, fmt.Errorf("provedor de email desconhecido: %s", provider)
+	}
+}
@@ -0,0 +1,40 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPSender envia pelo servidor SMTP em Addr. Com Username a autenticação é PLAIN, que o
+// net/smtp só aceita com STARTTLS ou em localhost.
+type SMTPSender struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (s *SMTPSender) Send(ctx context.Context, to string, subject string, body string) error {
+	// destinatário ou assunto com quebra de linha injetariam cabeçalhos
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return errors.New("destinatário ou assunto de email inválido")
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, err := net.SplitHostPort(s.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.From, to, subject, body)
+
+	return smtp.SendMail(s.Addr, auth, s.From, []string{to}, []byte(message))
+}
@@ -0,0 +1,119 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeyEncrypter protege as chaves de dados (DEKs) com a chave mestra (KEK).
+// Só as DEKs embrulhadas vão para o banco; a KEK fica na configuração ou no KMS.
+type KeyEncrypter interface {
+	// ActiveKeyID é a KEK usada para embrulhar novas DEKs
+	ActiveKeyID() string
+	WrapKey(ctx context.Context, dek []byte) (wrapped []byte, kekID string, err error)
+	UnwrapKey(ctx context.Context, wrapped []byte, kekID string) ([]byte, error)
+}
+
+var errUnknownKEK = errors.New("chave mestra desconhecida")
+
+// LocalKeyEncrypter usa KEKs AES-256 informadas na configuração.
+// A primeira chave é a ativa; as demais só desembrulham DEKs antigas até o `keys rotate`.
+type LocalKeyEncrypter struct {
+	activeID string
+	keys     map[string][]byte
+}
+
+// ParseLocalKeys lê o formato "id:base64,id:base64" de ENCRYPTION_KEKS
+func ParseLocalKeys(value string) (*LocalKeyEncrypter, error) {
+	encrypter := &LocalKeyEncrypter{keys: map[string][]byte{}}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("chave mestra inválida: esperado id:base64")
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("chave mestra %s inválida: esperado 32 bytes em base64", id)
+		}
+
+		if encrypter.activeID == "" {
+			encrypter.activeID = id
+		}
+		encrypter.keys[id] = key
+	}
+
+	if encrypter.activeID == "" {
+		return nil, errors.New("nenhuma chave mestra configurada")
+	}
+
+	return encrypter, nil
+}
+
+func (e *LocalKeyEncrypter) ActiveKeyID() string {
+	return e.activeID
+}
+
+func (e *LocalKeyEncrypter) WrapKey(_ context.Context, dek []byte) ([]byte, string, error) {
+	wrapped, err := seal(e.keys[e.activeID], dek, []byte("dek"))
+	return wrapped, e.activeID, err
+}
+
+func (e *LocalKeyEncrypter) UnwrapKey(_ context.Context, wrapped []byte, kekID string) ([]byte, error) {
+	key, ok := e.keys[kekID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownKEK, kekID)
+	}
+
+	return open(key, wrapped, []byte("dek"))
+}
+
+// seal cifra com AES-GCM e devolve nonce||ciphertext
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("valor cifrado truncado")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,312 @@
+package encryption
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// formato do valor cifrado: enc:v1:<id da DEK>:<base64(nonce||ciphertext)>
+const ciphertextPrefix = "enc:v1:"
+
+var (
+	ErrNotConfigured = errors.New("criptografia de campos não configurada")
+	ErrUnknownKey    = errors.New("chave de dados desconhecida")
+)
+
+// WrappedKey é uma chave de dados (DEK) embrulhada pela KEK, como fica guardada no banco
+type WrappedKey struct {
+	ID         string
+	WrappedKey []byte
+	KEKID      string
+	Active     bool
+	CreatedAt  time.Time
+}
+
+// KeyStore guarda as DEKs embrulhadas
+type KeyStore interface {
+	ListKeys(ctx context.Context) ([]WrappedKey, error)
+	SaveKey(ctx context.Context, key WrappedKey) error
+	// ActivateKey torna id a única chave ativa
+	ActivateKey(ctx context.Context, id string) error
+}
+
+// Keyring cifra e decifra campos com envelope encryption: cada valor é cifrado com AES-256-GCM
+// por uma DEK, e as DEKs ficam no banco embrulhadas pela KEK. O nome da coluna entra como
+// dado associado, então um valor copiado para outra coluna não decifra.
+type Keyring struct {
+	store           KeyStore
+	kek             KeyEncrypter
+	indexKey        []byte
+	refreshInterval time.Duration
+
+	mu       sync.RWMutex
+	keys     map[string][]byte
+	activeID string
+	loadedAt time.Time
+}
+
+// NewKeyring carrega as DEKs do store, criando a primeira se não houver nenhuma.
+// indexKey é a chave HMAC dos índices cegos e não pode mudar sem recalcular os índices.
+func NewKeyring(ctx context.Context, store KeyStore, kek KeyEncrypter, indexKey []byte, refreshInterval time.Duration) (*Keyring, error) {
+	if len(indexKey) < 32 {
+		return nil, errors.New("chave dos índices cegos deve ter ao menos 32 bytes")
+	}
+
+	keyring := &Keyring{
+		store:           store,
+		kek:             kek,
+		indexKey:        indexKey,
+		refreshInterval: refreshInterval,
+	}
+
+	if err := keyring.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	if keyring.ActiveKeyID() == "" {
+		if _, err := keyring.Rotate(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return keyring, nil
+}
+
+// Refresh relê as DEKs do store. Outras réplicas veem uma rotação por aqui:
+// no intervalo configurado ou ao encontrar um valor cifrado com uma chave desconhecida.
+func (k *Keyring) Refresh(ctx context.Context) error {
+	stored, err := k.store.ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string][]byte, len(stored))
+	var active *WrappedKey
+
+	for i, key := range stored {
+		dek, err := k.kek.UnwrapKey(ctx, key.WrappedKey, key.KEKID)
+		if err != nil {
+			return fmt.Errorf("erro ao abrir a chave de dados %s: %w", key.ID, err)
+		}
+		keys[key.ID] = dek
+
+		// duas réplicas subindo juntas num banco vazio podem criar duas chaves ativas;
+		// todas escolhem a mais recente e as duas continuam decifrando
+		if key.Active && (active == nil || key.CreatedAt.After(active.CreatedAt)) {
+			active = &stored[i]
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys = keys
+	k.activeID = ""
+	if active != nil {
+		k.activeID = active.ID
+	}
+	k.loadedAt = time.Now()
+
+	return nil
+}
+
+// Rotate cria uma nova DEK e a torna ativa. Valores existentes continuam legíveis;
+// o comando reencrypt os migra para a nova chave.
+func (k *Keyring) Rotate(ctx context.Context) (string, error) {
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return "", err
+	}
+
+	wrapped, kekID, err := k.kek.WrapKey(ctx, dek)
+	if err != nil {
+		return "", err
+	}
+
+	id := uuid.NewString()
+	err = k.store.SaveKey(ctx, WrappedKey{
+		ID:         id,
+		WrappedKey: wrapped,
+		KEKID:      kekID,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := k.store.ActivateKey(ctx, id); err != nil {
+		return "", err
+	}
+
+	return id, k.Refresh(ctx)
+}
+
+// Rewrap embrulha de novo com a KEK ativa as DEKs que ainda usam uma KEK antiga.
+// Depois disso a KEK antiga pode ser retirada da configuração.
+func (k *Keyring) Rewrap(ctx context.Context) (int, error) {
+	stored, err := k.store.ListKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rewrapped := 0
+	for _, key := range stored {
+		if key.KEKID == k.kek.ActiveKeyID() {
+			continue
+		}
+
+		dek, err := k.kek.UnwrapKey(ctx, key.WrappedKey, key.KEKID)
+		if err != nil {
+			return rewrapped, fmt.Errorf("erro ao abrir a chave de dados %s: %w", key.ID, err)
+		}
+
+		key.WrappedKey, key.KEKID, err = k.kek.WrapKey(ctx, dek)
+		if err != nil {
+			return rewrapped, err
+		}
+
+		if err := k.store.SaveKey(ctx, key); err != nil {
+			return rewrapped, err
+		}
+		rewrapped++
+	}
+
+	return rewrapped, nil
+}
+
+func (k *Keyring) ActiveKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.activeID
+}
+
+// Encrypt cifra value com a DEK ativa; field é o nome da coluna
+func (k *Keyring) Encrypt(ctx context.Context, field string, value string) (string, error) {
+	k.refreshIfStale(ctx)
+
+	k.mu.RLock()
+	activeID := k.activeID
+	dek := k.keys[activeID]
+	k.mu.RUnlock()
+
+	if activeID == "" {
+		return "", ErrUnknownKey
+	}
+
+	sealed, err := seal(dek, []byte(value), []byte(field))
+	if err != nil {
+		return "", err
+	}
+
+	return ciphertextPrefix + activeID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decifra um valor produzido por Encrypt. Valores sem o prefixo são texto puro
+// gravado antes da criptografia e são devolvidos como estão até o reencrypt passar por eles.
+func (k *Keyring) Decrypt(ctx context.Context, field string, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(value, ciphertextPrefix), ":")
+	if !ok {
+		return "", errors.New("valor cifrado malformado")
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.New("valor cifrado malformado")
+	}
+
+	dek, err := k.key(ctx, keyID)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dek, sealed, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("erro ao decifrar %s: %w", field, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsReencryption indica se o valor está em texto puro ou cifrado com uma DEK que não é a ativa
+func (k *Keyring) NeedsReencryption(value string) bool {
+	if !IsEncrypted(value) {
+		return true
+	}
+
+	return !strings.HasPrefix(value, ciphertextPrefix+k.ActiveKeyID()+":")
+}
+
+// BlindIndex é um HMAC-SHA256 determinístico do valor, usado para busca e unicidade
+// sem expor o valor. purpose separa os índices de colunas diferentes.
+func (k *Keyring) BlindIndex(purpose string, value string) string {
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
+
+func (k *Keyring) key(ctx context.Context, id string) ([]byte, error) {
+	k.mu.RLock()
+	dek, ok := k.keys[id]
+	k.mu.RUnlock()
+
+	if ok {
+		return dek, nil
+	}
+
+	// chave criada por outra réplica depois do último carregamento
+	if err := k.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if dek, ok := k.keys[id]; ok {
+		return dek, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownKey, id)
+}
+
+func (k *Keyring) refreshIfStale(ctx context.Context) {
+	if k.refreshInterval <= 0 {
+		return
+	}
+
+	k.mu.RLock()
+	stale := time.Since(k.loadedAt) > k.refreshInterval
+	k.mu.RUnlock()
+
+	if !stale {
+		return
+	}
+
+	if err := k.Refresh(ctx); err != nil {
+		// segue com as chaves já carregadas; a próxima escrita tenta de novo
+		slog.WarnContext(ctx, "Erro ao recarregar as chaves de dados", "error", err)
+	}
+}
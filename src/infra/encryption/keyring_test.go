@@ -0,0 +1,190 @@
+package encryption
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+type memoryKeyStore struct {
+	mu   sync.Mutex
+	keys []WrappedKey
+}
+
+func (s *memoryKeyStore) ListKeys(context.Context) ([]WrappedKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]WrappedKey(nil), s.keys...), nil
+}
+
+func (s *memoryKeyStore) SaveKey(_ context.Context, key WrappedKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.keys {
+		if s.keys[i].ID == key.ID {
+			s.keys[i] = key
+			return nil
+		}
+	}
+	s.keys = append(s.keys, key)
+
+	return nil
+}
+
+func (s *memoryKeyStore) ActivateKey(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.keys {
+		s.keys[i].Active = s.keys[i].ID == id
+	}
+
+	return nil
+}
+
+func testKEK(t *testing.T, ids ...string) *LocalKeyEncrypter {
+	entries := make([]string, 0, len(ids))
+	for _, id := range ids {
+		key := sha256.Sum256([]byte(id))
+		entries = append(entries, id+":"+base64.StdEncoding.EncodeToString(key[:]))
+	}
+
+	kek, err := ParseLocalKeys(strings.Join(entries, ","))
+	require.NoError(t, err)
+
+	return kek
+}
+
+func newTestKeyring(t *testing.T, store KeyStore, kek KeyEncrypter) *Keyring {
+	keyring, err := NewKeyring(context.Background(), store, kek, []byte(strings.Repeat("k", 32)), 0)
+	require.NoError(t, err)
+
+	return keyring
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeyStore{}
+	keyring := newTestKeyring(t, store, testKEK(t, "kek-1"))
+
+	require.Len(t, store.keys, 1)
+	assert.True(t, store.keys[0].Active)
+
+	encrypted, err := keyring.Encrypt(ctx, "cpf", "12345678901")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "12345678901")
+
+	again, err := keyring.Encrypt(ctx, "cpf", "12345678901")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "o nonce deve ser aleatório")
+
+	decrypted, err := keyring.Decrypt(ctx, "cpf", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901", decrypted)
+
+	t.Run("valor de outra coluna não decifra", func(t *testing.T) {
+		_, err := keyring.Decrypt(ctx, "email", encrypted)
+		assert.Error(t, err)
+	})
+
+	t.Run("texto puro legado passa direto", func(t *testing.T) {
+		value, err := keyring.Decrypt(ctx, "cpf", "98765432100")
+		require.NoError(t, err)
+		assert.Equal(t, "98765432100", value)
+		assert.True(t, keyring.NeedsReencryption("98765432100"))
+	})
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeyStore{}
+	keyring := newTestKeyring(t, store, testKEK(t, "kek-1"))
+
+	old, err := keyring.Encrypt(ctx, "email", "jane@example.com")
+	require.NoError(t, err)
+	assert.False(t, keyring.NeedsReencryption(old))
+
+	// outra réplica, com as chaves carregadas antes da rotação
+	replica := newTestKeyring(t, store, testKEK(t, "kek-1"))
+
+	_, err = keyring.Rotate(ctx)
+	require.NoError(t, err)
+	assert.True(t, keyring.NeedsReencryption(old))
+
+	current, err := keyring.Encrypt(ctx, "email", "jane@example.com")
+	require.NoError(t, err)
+
+	value, err := keyring.Decrypt(ctx, "email", old)
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", value)
+
+	// a réplica recarrega as chaves ao encontrar uma chave desconhecida
+	value, err = replica.Decrypt(ctx, "email", current)
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", value)
+}
+
+func TestKeyring_RewrapWithNewKEK(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeyStore{}
+	keyring := newTestKeyring(t, store, testKEK(t, "kek-1"))
+
+	encrypted, err := keyring.Encrypt(ctx, "cpf", "12345678901")
+	require.NoError(t, err)
+
+	rotated := newTestKeyring(t, store, testKEK(t, "kek-2", "kek-1"))
+	rewrapped, err := rotated.Rewrap(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rewrapped)
+	assert.Equal(t, "kek-2", store.keys[0].KEKID)
+
+	// a kek-1 já pode sair da configuração
+	withoutOldKEK := newTestKeyring(t, store, testKEK(t, "kek-2"))
+	value, err := withoutOldKEK.Decrypt(ctx, "cpf", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901", value)
+}
+
+func TestKeyring_BlindIndex(t *testing.T) {
+	keyring := newTestKeyring(t, &memoryKeyStore{}, testKEK(t, "kek-1"))
+
+	index := keyring.BlindIndex("customers.cpf", "12345678901")
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, keyring.BlindIndex("customers.cpf", "12345678901"))
+	assert.NotEqual(t, index, keyring.BlindIndex("customers.email", "12345678901"))
+	assert.NotEqual(t, index, keyring.BlindIndex("customers.cpf", "12345678902"))
+}
+
+func TestSerializer(t *testing.T) {
+	type record struct {
+		CPF string `gorm:"serializer:encrypted"`
+	}
+
+	ctx := context.Background()
+	keyring := newTestKeyring(t, &memoryKeyStore{}, testKEK(t, "kek-1"))
+	SetDefault(keyring)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	parsed, err := schema.Parse(&record{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := parsed.LookUpField("CPF")
+
+	stored, err := Serializer{}.Value(ctx, field, reflect.Value{}, "12345678901")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(stored.(string)))
+
+	var loaded record
+	require.NoError(t, Serializer{}.Scan(ctx, field, reflect.ValueOf(&loaded).Elem(), []byte(stored.(string))))
+	assert.Equal(t, "12345678901", loaded.CPF)
+}
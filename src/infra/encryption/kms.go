@@ -0,0 +1,80 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// KMSClient é o subconjunto da API do KMS usado aqui; qualquer serviço compatível serve
+type KMSClient interface {
+	Encrypt(ctx context.Context, params *kms.EncryptInput, optFns ...func(*kms.Options)) (*kms.EncryptOutput, error)
+	Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// o contexto de cifragem impede que a DEK embrulhada seja decifrada para outro propósito
+var kmsEncryptionContext = map[string]string{"purpose": "customer-service-dek"}
+
+// KMSKeyEncrypter embrulha as DEKs com uma chave do KMS; a KEK nunca sai do KMS
+type KMSKeyEncrypter struct {
+	client KMSClient
+	keyID  string
+}
+
+func NewKMSKeyEncrypter(client KMSClient, keyID string) *KMSKeyEncrypter {
+	return &KMSKeyEncrypter{client: client, keyID: keyID}
+}
+
+// NewKMSKeyEncrypterFromConfig usa as credenciais padrão da AWS. Com endpoint definido
+// funciona contra um substituto local compatível, como o local-kms.
+func NewKMSKeyEncrypterFromConfig(ctx context.Context, keyID string, endpoint string) (*KMSKeyEncrypter, error) {
+	if keyID == "" {
+		return nil, errors.New("ENCRYPTION_KMS_KEY_ID não configurada")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client := kms.NewFromConfig(cfg, func(o *kms.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+
+	return NewKMSKeyEncrypter(client, keyID), nil
+}
+
+func (e *KMSKeyEncrypter) ActiveKeyID() string {
+	return e.keyID
+}
+
+func (e *KMSKeyEncrypter) WrapKey(ctx context.Context, dek []byte) ([]byte, string, error) {
+	output, err := e.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:             aws.String(e.keyID),
+		Plaintext:         dek,
+		EncryptionContext: kmsEncryptionContext,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return output.CiphertextBlob, e.keyID, nil
+}
+
+func (e *KMSKeyEncrypter) UnwrapKey(ctx context.Context, wrapped []byte, kekID string) ([]byte, error) {
+	output, err := e.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:             aws.String(kekID),
+		CiphertextBlob:    wrapped,
+		EncryptionContext: kmsEncryptionContext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Plaintext, nil
+}
@@ -0,0 +1,74 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName é o nome usado na tag `gorm:"serializer:encrypted"`
+const SerializerName = "encrypted"
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// SetDefault define o keyring usado pelo serializer do GORM
+func SetDefault(keyring *Keyring) {
+	defaultKeyring.Store(keyring)
+}
+
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
+
+// Serializer cifra a coluna ao gravar e decifra ao ler, usando o keyring padrão.
+// O nome da coluna é o dado associado do AES-GCM.
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("tipo inesperado na coluna cifrada %s: %T", field.DBName, dbValue)
+	}
+
+	if IsEncrypted(value) {
+		keyring := Default()
+		if keyring == nil {
+			return ErrNotConfigured
+		}
+
+		plaintext, err := keyring.Decrypt(ctx, field.DBName, value)
+		if err != nil {
+			return err
+		}
+		value = plaintext
+	}
+
+	return field.Set(ctx, dst, value)
+}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("coluna cifrada %s deve ser string", field.DBName)
+	}
+
+	keyring := Default()
+	if keyring == nil {
+		return nil, ErrNotConfigured
+	}
+
+	return keyring.Encrypt(ctx, field.DBName, value)
+}
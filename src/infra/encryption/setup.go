@@ -0,0 +1,89 @@
+package encryption
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+// chaves usadas apenas fora de produção quando nada é configurado
+const (
+	devKEKID    = "dev"
+	devKeySeed  = "customer-service-dev-key"
+	devIndexKey = "customer-service-dev-blind-index-key"
+)
+
+// Setup monta o keyring a partir do ambiente e o define como padrão.
+// ENCRYPTION_KEK_PROVIDER escolhe a KEK: "local" (ENCRYPTION_KEKS, padrão) ou "kms"
+// (ENCRYPTION_KMS_KEY_ID, ENCRYPTION_KMS_ENDPOINT). BLIND_INDEX_KEY é a chave dos índices cegos.
+// Fora de produção (GIN_MODE diferente de release) chaves fixas de desenvolvimento são usadas se faltarem.
+func Setup(ctx context.Context, store KeyStore) (*Keyring, error) {
+	release := utils.GetEnv("GIN_MODE", "") == "release"
+
+	kek, err := newKeyEncrypterFromEnv(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+
+	indexKey, err := blindIndexKeyFromEnv(release)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := NewKeyring(ctx, store, kek, indexKey, utils.GetEnvDuration("ENCRYPTION_KEYRING_REFRESH", 5*time.Minute))
+	if err != nil {
+		return nil, err
+	}
+
+	SetDefault(keyring)
+
+	return keyring, nil
+}
+
+func newKeyEncrypterFromEnv(ctx context.Context, release bool) (KeyEncrypter, error) {
+	switch provider := utils.GetEnv("ENCRYPTION_KEK_PROVIDER", "local"); provider {
+	case "kms":
+		return NewKMSKeyEncrypterFromConfig(ctx, utils.GetEnv("ENCRYPTION_KMS_KEY_ID", ""), utils.GetEnv("ENCRYPTION_KMS_ENDPOINT", ""))
+	case "local":
+		keys := utils.GetEnv("ENCRYPTION_KEKS", "")
+		if keys == "" {
+			if release {
+				return nil, errors.New("ENCRYPTION_KEKS não configurada")
+			}
+
+			slog.Warn("ENCRYPTION_KEKS não configurada; usando a chave mestra de desenvolvimento")
+			devKey := sha256.Sum256([]byte(devKeySeed))
+			keys = devKEKID + ":" + base64.StdEncoding.EncodeToString(devKey[:])
+		}
+
+		return ParseLocalKeys(keys)
+	default:
+		return nil, fmt.Errorf("provedor de chave mestra desconhecido: %s", provider)
+	}
+}
+
+func blindIndexKeyFromEnv(release bool) ([]byte, error) {
+	encoded := utils.GetEnv("BLIND_INDEX_KEY", "")
+	if encoded == "" {
+		if release {
+			return nil, errors.New("BLIND_INDEX_KEY não configurada")
+		}
+
+		slog.Warn("BLIND_INDEX_KEY não configurada; usando a chave de desenvolvimento")
+		devKey := sha256.Sum256([]byte(devIndexKey))
+		return devKey[:], nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("BLIND_INDEX_KEY deve estar em base64")
+	}
+
+	return key, nil
+}
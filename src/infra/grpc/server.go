@@ -0,0 +1,246 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/audit"
+	"github.com/CAVAh/api-tech-challenge/src/infra/auth"
+	customerv1 "github.com/CAVAh/api-tech-challenge/src/infra/grpc/pb/customer/v1"
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	apiKeyMetadata    = "x-api-key"
+	requestIDMetadata = "x-request-id"
+)
+
+// Server é o servidor gRPC interno, em porta separada da API HTTP
+type Server struct {
+	addr   string
+	server *grpc.Server
+	health *health.Server
+}
+
+// NewServer registra o CustomerService, o health check padrão do gRPC e a reflection.
+// Apenas os métodos do CustomerService exigem a chave de serviço. Com creds nil o servidor
+// aceita conexões sem TLS (veja TransportCredentialsFromEnv).
+func NewServer(service customerv1.CustomerServiceServer, keys auth.APIKeys, creds credentials.TransportCredentials) *Server {
+	options := []grpc.ServerOption{grpc.ChainUnaryInterceptor(
+		// primeiro da cadeia: um panic em qualquer interceptor ou handler vira codes.Internal
+		recoveryInterceptor,
+		tracingInterceptor,
+		loggingInterceptor,
+		authInterceptor(keys),
+	)}
+	if creds != nil {
+		options = append(options, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(options...)
+
+	healthServer := health.NewServer()
+
+	customerv1.RegisterCustomerServiceServer(server, service)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return &Server{
+		addr:   ":" + utils.GetEnv("GRPC_PORT", "9090"),
+		server: server,
+		health: healthServer,
+	}
+}
+
+func (s *Server) Serve() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(customerv1.CustomerService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	slog.Info("Servidor gRPC escutando", "addr", s.addr)
+
+	err = s.server.Serve(listener)
+	if errors.Is(err, grpc.ErrServerStopped) {
+		return nil
+	}
+
+	return err
+}
+
+// Shutdown marca o serviço como NOT_SERVING e espera as chamadas em andamento até o fim do contexto
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// TransportCredentialsFromEnv carrega o certificado do servidor de GRPC_TLS_CERT_FILE e GRPC_TLS_KEY_FILE.
+// Sem eles o gRPC fica sem TLS e a chave de serviço trafega em texto puro; em produção isso só é
+// aceitável quando a rede do cluster já cifra o tráfego (mTLS da service mesh), e a aplicação avisa no log.
+func TransportCredentialsFromEnv() (credentials.TransportCredentials, error) {
+	certFile, keyFile := os.Getenv("GRPC_TLS_CERT_FILE"), os.Getenv("GRPC_TLS_KEY_FILE")
+
+	switch {
+	case certFile != "" && keyFile != "":
+		return credentials.NewServerTLSFromFile(certFile, keyFile)
+	case certFile != "" || keyFile != "":
+		return nil, errors.New("informe GRPC_TLS_CERT_FILE e GRPC_TLS_KEY_FILE juntos")
+	}
+
+	if utils.GetEnv("GIN_MODE", "") == "release" {
+		slog.Warn("gRPC sem TLS: configure GRPC_TLS_CERT_FILE e GRPC_TLS_KEY_FILE ou garanta mTLS na rede do cluster")
+	}
+
+	return nil, nil
+}
+
+func isCustomerServiceMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+customerv1.CustomerService_ServiceDesc.ServiceName+"/")
+}
+
+func authInterceptor(keys auth.APIKeys) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isCustomerServiceMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(apiKeyMetadata)
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "credencial de serviço ausente")
+		}
+
+		actor, ok := keys.Lookup(values[0])
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "credencial de serviço inválida")
+		}
+
+		ctx = audit.WithActor(ctx, audit.Actor{ID: actor, Role: auth.RoleService})
+		if p, ok := peer.FromContext(ctx); ok {
+			if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+				ctx = audit.WithClientIP(ctx, host)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func recoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logging.FromContext(ctx).Error("panic ao processar chamada gRPC",
+				"error", fmt.Sprint(recovered),
+				"method", info.FullMethod,
+				"stack", string(debug.Stack()),
+			)
+			resp, err = nil, status.Error(codes.Internal, "erro interno")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func tracingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	ctx, span := tracing.Tracer().Start(ctx, strings.TrimPrefix(info.FullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCMethod(info.FullMethod),
+		),
+	)
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(status.Code(err))))
+	}
+	tracing.EndSpan(span, err)
+
+	return resp, err
+}
+
+func loggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(requestIDMetadata); len(values) > 0 {
+		ctx = logging.WithRequestID(ctx, values[0])
+	}
+
+	resp, err := handler(ctx, req)
+
+	logging.FromContext(ctx).Info("chamada gRPC",
+		"method", info.FullMethod,
+		"code", status.Code(err).String(),
+		"latency", time.Since(start),
+	)
+
+	return resp, err
+}
+
+// metadataCarrier adapta o metadata do gRPC para o propagador W3C
+type metadataCarrier metadata.MD
+
+var _ propagation.TextMapCarrier = metadataCarrier{}
+
+func (m metadataCarrier) Get(key string) string {
+	values := metadata.MD(m).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (m metadataCarrier) Set(key string, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+func (m metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/customer.v1.CustomerService/GetCustomer"}
+
+	resp, err := recoveryInterceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		panic("nil pointer")
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestTransportCredentialsFromEnv(t *testing.T) {
+	t.Run("sem TLS", func(t *testing.T) {
+		creds, err := TransportCredentialsFromEnv()
+		assert.NoError(t, err)
+		assert.Nil(t, creds)
+	})
+
+	t.Run("certificado sem chave", func(t *testing.T) {
+		t.Setenv("GRPC_TLS_CERT_FILE", "/etc/grpc/tls.crt")
+
+		_, err := TransportCredentialsFromEnv()
+		assert.Error(t, err)
+	})
+}
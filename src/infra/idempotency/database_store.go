@@ -0,0 +1,105 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"gorm.io/gorm"
+)
+
+// DatabaseStore usa a chave primária da tabela para garantir que só uma réplica reserve cada chave.
+// O corpo da resposta traz dados do cliente e é gravado cifrado.
+type DatabaseStore struct {
+	DB database.Database
+}
+
+func (s *DatabaseStore) Reserve(ctx context.Context, key string, fingerprint string, lease time.Duration) (*Record, error) {
+	db := s.DB.WithContext(ctx)
+	now := time.Now()
+
+	var existing models.IdempotencyKey
+	err := db.First(&existing, "key = ?", key)
+
+	switch {
+	case err == nil && now.Before(existing.ExpiresAt):
+		return toRecord(ctx, existing)
+	case err == nil:
+		// Chave expirada, ou reserva abandonada depois do lease: libera para ser usada novamente
+		if err := db.Delete(&models.IdempotencyKey{}, "key = ?", key); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	reserved := models.IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(lease),
+	}
+
+	if err := db.Create(&reserved); err != nil {
+		// Outra réplica reservou a chave entre a leitura e a escrita
+		if errors.Is(err, gorm.ErrDuplicatedKey) || database.IsUniqueViolation(err) {
+			if err := db.First(&existing, "key = ?", key); err != nil {
+				return nil, err
+			}
+			return toRecord(ctx, existing)
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *DatabaseStore) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte, ttl time.Duration) error {
+	db := s.DB.WithContext(ctx)
+
+	var record models.IdempotencyKey
+	if err := db.First(&record, "key = ?", key); err != nil {
+		return err
+	}
+
+	sealed, err := models.SealPayload(ctx, models.IdempotencyBodyField, body)
+	if err != nil {
+		return err
+	}
+
+	record.Completed = true
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = sealed
+	record.ExpiresAt = time.Now().Add(ttl)
+
+	return db.Save(&record)
+}
+
+func (s *DatabaseStore) Release(ctx context.Context, key string) error {
+	return s.DB.WithContext(ctx).Delete(&models.IdempotencyKey{}, "key = ?", key)
+}
+
+func (s *DatabaseStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := s.DB.WithContext(ctx).Where("expires_at < ?", before).Delete(&models.IdempotencyKey{})
+
+	return result.RowsAffected, result.Error
+}
+
+func toRecord(ctx context.Context, model models.IdempotencyKey) (*Record, error) {
+	body, err := models.OpenPayload(ctx, models.IdempotencyBodyField, model.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		Key:         model.Key,
+		Fingerprint: model.Fingerprint,
+		Completed:   model.Completed,
+		StatusCode:  model.StatusCode,
+		ContentType: model.ContentType,
+		Body:        body,
+		ExpiresAt:   model.ExpiresAt,
+	}, nil
+}
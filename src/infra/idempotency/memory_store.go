@@ -0,0 +1,81 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore guarda as chaves no processo; serve para testes e execução local com uma réplica
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]*Record
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: map[string]*Record{},
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Reserve(_ context.Context, key string, fingerprint string, lease time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[key]; ok && s.now().Before(existing.ExpiresAt) {
+		copied := *existing
+		return &copied, nil
+	}
+
+	s.records[key] = &Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   s.now().Add(lease),
+	}
+
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key string, statusCode int, contentType string, body []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	record.Completed = true
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = body
+	record.ExpiresAt = s.now().Add(ttl)
+
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+
+	return nil
+}
+
+func (s *MemoryStore) Purge(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for key, record := range s.records {
+		if record.ExpiresAt.Before(before) {
+			delete(s.records, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,54 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_AbandonedReservationExpiresAfterLease(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	existing, err := store.Reserve(ctx, "POST /customers k", "f", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	existing, err = store.Reserve(ctx, "POST /customers k", "f", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, existing.Completed)
+
+	now = now.Add(2 * time.Minute)
+	existing, err = store.Reserve(ctx, "POST /customers k", "f", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func TestMemoryStore_CompleteKeepsResponseForTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	_, err := store.Reserve(ctx, "POST /customers k", "f", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.Complete(ctx, "POST /customers k", 201, "application/json", []byte(`{"id":1}`), time.Hour))
+
+	now = now.Add(30 * time.Minute)
+	existing, err := store.Reserve(ctx, "POST /customers k", "f", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, existing.Completed)
+
+	purged, err := store.Purge(ctx, now)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = store.Purge(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
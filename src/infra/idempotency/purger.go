@@ -0,0 +1,58 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Purger apaga periodicamente as chaves vencidas do Store. Roda como server.Runnable;
+// várias réplicas podem rodar juntas, já que apagar uma chave já apagada não tem efeito.
+type Purger struct {
+	Store    Store
+	Interval time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewPurger(store Store, interval time.Duration) *Purger {
+	return &Purger{
+		Store:    store,
+		Interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (p *Purger) Serve() error {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return nil
+		case <-ticker.C:
+			purged, err := p.Store.Purge(context.Background(), time.Now())
+			if err != nil {
+				slog.Warn("Erro ao apagar as Idempotency-Keys vencidas", "error", err)
+			} else if purged > 0 {
+				slog.Info("Idempotency-Keys vencidas apagadas", "keys", purged)
+			}
+		}
+	}
+}
+
+func (p *Purger) Shutdown(ctx context.Context) error {
+	close(p.stop)
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,54 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+var ErrKeyNotFound = errors.New("chave de idempotência não encontrada")
+
+// Record é a resposta guardada para uma Idempotency-Key
+type Record struct {
+	Key         string
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+// Store guarda as respostas por Idempotency-Key.
+// Reserve grava a chave como "em andamento" por lease e devolve o registro existente quando a chave já foi
+// usada; uma reserva que não foi concluída nem liberada dentro do lease fica livre de novo. Complete guarda
+// a resposta por ttl e Purge apaga os registros vencidos até before.
+type Store interface {
+	Reserve(ctx context.Context, key string, fingerprint string, lease time.Duration) (*Record, error)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte, ttl time.Duration) error
+	Release(ctx context.Context, key string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// NewStoreFromEnv escolhe a implementação por IDEMPOTENCY_STORE: "database" (padrão, compartilhado entre réplicas) ou "memory"
+func NewStoreFromEnv(db database.Database) Store {
+	if utils.GetEnv("IDEMPOTENCY_STORE", "database") == "memory" {
+		return NewMemoryStore()
+	}
+
+	return &DatabaseStore{DB: db}
+}
+
+// TTLFromEnv retorna por quanto tempo uma resposta fica disponível para replay
+func TTLFromEnv() time.Duration {
+	return utils.GetEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+}
+
+// LeaseFromEnv retorna por quanto tempo uma chave fica reservada enquanto a primeira requisição está em
+// andamento. Deve passar do tempo máximo de uma requisição (HTTP_WRITE_TIMEOUT).
+func LeaseFromEnv() time.Duration {
+	return utils.GetEnvDuration("IDEMPOTENCY_LEASE", time.Minute)
+}
@@ -0,0 +1,26 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+type gormWriter struct{}
+
+func (gormWriter) Printf(format string, args ...interface{}) {
+	slog.Warn(fmt.Sprintf(format, args...), "component", "gorm")
+}
+
+// NewGormLogger envia os avisos do GORM (queries lentas, erros) para o slog,
+// com as queries parametrizadas para que os valores não apareçam no log
+func NewGormLogger() logger.Interface {
+	return logger.New(gormWriter{}, logger.Config{
+		SlowThreshold:             200 * time.Millisecond,
+		LogLevel:                  logger.Warn,
+		IgnoreRecordNotFoundError: true,
+		ParameterizedQueries:      true,
+	})
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type levelDto struct {
+	Level string `json:"level"`
+}
+
+func GetLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, levelDto{Level: Level.Level().String()})
+}
+
+// SetLevel altera o nível de log sem reiniciar o processo
+func SetLevel(c *gin.Context) {
+	var inputDto levelDto
+
+	if err := c.ShouldBindJSON(&inputDto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	level, err := ParseLevel(inputDto.Level)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "nível de log inválido, use debug, info, warn ou error",
+		})
+		return
+	}
+
+	Level.Set(level)
+	FromContext(c.Request.Context()).Warn("nível de log alterado", "level", level.String(), "actor", c.GetString("actor"))
+
+	c.JSON(http.StatusOK, levelDto{Level: level.String()})
+}
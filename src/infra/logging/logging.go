@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// Level é o nível de log atual; pode ser alterado em tempo de execução
+var Level = new(slog.LevelVar)
+
+// Setup configura o slog padrão com saída JSON e mascaramento de dados pessoais.
+// O nível inicial vem de LOG_LEVEL (debug, info, warn, error).
+func Setup() {
+	if level, err := ParseLevel(utils.GetEnv("LOG_LEVEL", "info")); err == nil {
+		Level.Set(level)
+	}
+
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: Level})
+	slog.SetDefault(slog.New(NewRedactingHandler(handler)))
+}
+
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.ToUpper(value)))
+
+	return level, err
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+
+	return requestID
+}
+
+// FromContext retorna o logger padrão com o request id e o trace id da requisição
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+
+	if requestID := RequestID(ctx); requestID != "" {
+		logger = logger.With("request_id", requestID)
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		logger = logger.With("trace_id", spanContext.TraceID().String())
+	}
+
+	return logger
+}
+
+// Fatal registra o erro e encerra o processo
+func Fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/-]+=*`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	cpfPattern    = regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`)
+)
+
+// sensitiveKeys são atributos cujo valor é sempre mascarado por completo
+var sensitiveKeys = map[string]bool{
+	"cpf":           true,
+	"email":         true,
+	"token":         true,
+	"authorization": true,
+	"password":      true,
+	"x-api-key":     true,
+}
+
+// Redact mascara CPF, email e tokens encontrados no texto
+func Redact(value string) string {
+	value = jwtPattern.ReplaceAllString(value, redacted)
+	value = bearerPattern.ReplaceAllString(value, "Bearer "+redacted)
+	value = emailPattern.ReplaceAllString(value, redacted)
+	value = cpfPattern.ReplaceAllString(value, redacted)
+
+	return value
+}
+
+// RedactingHandler aplica Redact na mensagem e em todos os atributos antes de repassar ao handler
+type RedactingHandler struct {
+	next slog.Handler
+}
+
+func NewRedactingHandler(next slog.Handler) *RedactingHandler {
+	return &RedactingHandler{next: next}
+}
+
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	clean := slog.NewRecord(record.Time, record.Level, Redact(record.Message), record.PC)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		clean.AddAttrs(redactAttr(attr))
+		return true
+	})
+
+	return h.next.Handle(ctx, clean)
+}
+
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		clean = append(clean, redactAttr(attr))
+	}
+
+	return &RedactingHandler{next: h.next.WithAttrs(clean)}
+}
+
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{next: h.next.WithGroup(name)}
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, redacted)
+	}
+
+	value := attr.Value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		clean := make([]any, 0, len(group))
+		for _, child := range group {
+			clean = append(clean, redactAttr(child))
+		}
+		return slog.Group(attr.Key, clean...)
+	case slog.KindString:
+		return slog.String(attr.Key, Redact(value.String()))
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, Redact(err.Error()))
+		}
+		return slog.String(attr.Key, Redact(value.String()))
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
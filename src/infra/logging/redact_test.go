@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	cases := map[string]string{
+		"GET /customers?cpf=12345678900":           "GET /customers?cpf=[REDACTED]",
+		"cpf 123.456.789-00 não encontrado":        "cpf [REDACTED] não encontrado",
+		"enviado para john@example.com":            "enviado para [REDACTED]",
+		"Authorization: Bearer abc.def-ghi":        "Authorization: Bearer [REDACTED]",
+		"token eyJhbGciOi.eyJjdXN0b21lcklk.sig_1-": "token [REDACTED]",
+		"pedido 42 criado":                         "pedido 42 criado",
+	}
+
+	for input, expected := range cases {
+		assert.Equal(t, expected, Redact(input))
+	}
+}
+
+func TestRedactingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewRedactingHandler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.With("email", "john@example.com").Info("cliente 12345678900 criado",
+		"cpf", "123",
+		"error", errors.New("duplicate key (email)=(john@example.com)"),
+		slog.Group("request", "query", "cpf=12345678900"),
+		"status", 201,
+	)
+
+	output := buf.String()
+	assert.NotContains(t, output, "12345678900")
+	assert.NotContains(t, output, "john@example.com")
+	assert.Contains(t, output, `"cpf":"[REDACTED]"`)
+	assert.Contains(t, output, `"status":201`)
+}
@@ -0,0 +1,62 @@
+package loyalty
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/loyalty"
+)
+
+// Expirer grava periodicamente a expiração dos pontos vencidos. Roda como server.Runnable;
+// várias réplicas podem rodar juntas, já que cada expiração é gravada uma única vez.
+type Expirer struct {
+	Usecase  *usecases.ExpirePointsUsecase
+	Interval time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewExpirer(usecase *usecases.ExpirePointsUsecase, interval time.Duration) *Expirer {
+	return &Expirer{
+		Usecase:  usecase,
+		Interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (e *Expirer) Serve() error {
+	defer close(e.done)
+
+	slog.Info("Expiração de pontos iniciada", "interval", e.Interval.String())
+
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stop:
+			return nil
+		case <-ticker.C:
+			expired, err := e.Usecase.Execute(context.Background())
+			if err != nil {
+				slog.Warn("Erro ao expirar pontos", "error", err)
+			} else if expired > 0 {
+				slog.Info("Pontos expirados", "points", expired)
+			}
+		}
+	}
+}
+
+func (e *Expirer) Shutdown(ctx context.Context) error {
+	close(e.stop)
+
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
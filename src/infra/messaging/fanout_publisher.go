@@ -0,0 +1,32 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+)
+
+// FanoutPublisher entrega cada evento a todos os publishers. Se algum falhar o relay
+// tenta de novo e os que já receberam o evento o recebem outra vez (pelo menos uma vez).
+type FanoutPublisher []Publisher
+
+func (f FanoutPublisher) Publish(ctx context.Context, envelope events.Envelope) error {
+	var errs []error
+	for _, publisher := range f {
+		if err := publisher.Publish(ctx, envelope); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (f FanoutPublisher) Close() error {
+	var errs []error
+	for _, publisher := range f {
+		errs = append(errs, publisher.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+package messaging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+)
+
+// MemoryPublisher mantém os eventos em memória; serve para testes e desenvolvimento local
+type MemoryPublisher struct {
+	mu        sync.Mutex
+	published []events.Envelope
+	handlers  []func(events.Envelope)
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, envelope events.Envelope) error {
+	p.mu.Lock()
+	p.published = append(p.published, envelope)
+	handlers := append([]func(events.Envelope){}, p.handlers...)
+	p.mu.Unlock()
+
+	logging.FromContext(ctx).Debug("Evento publicado em memória", slog.String("type", envelope.Type), slog.String("eventId", envelope.ID))
+
+	for _, handler := range handlers {
+		handler(envelope)
+	}
+
+	return nil
+}
+
+// Subscribe registra um consumidor chamado de forma síncrona a cada evento publicado
+func (p *MemoryPublisher) Subscribe(handler func(events.Envelope)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers = append(p.handlers, handler)
+}
+
+func (p *MemoryPublisher) Published() []events.Envelope {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]events.Envelope{}, p.published...)
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
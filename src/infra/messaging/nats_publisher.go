@@ -0,0 +1,69 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publica no JetStream, que confirma a gravação e deduplica pelo Nats-Msg-Id.
+// Os assuntos seguem <prefixo>.<tipo do evento>, por exemplo customers.CustomerCreated.
+type NATSPublisher struct {
+	conn          *nats.Conn
+	js            nats.JetStreamContext
+	subjectPrefix string
+}
+
+func NewNATSPublisher(url string, stream string, subjectPrefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("api-tech-challenge"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if _, err := js.StreamInfo(stream); errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:     stream,
+			Subjects: []string{subjectPrefix + ".>"},
+		})
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	} else if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &NATSPublisher{conn: conn, js: js, subjectPrefix: subjectPrefix}, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, envelope events.Envelope) error {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(p.subjectPrefix + "." + envelope.Type)
+	msg.Data = body
+	msg.Header.Set("Content-Type", "application/json")
+	msg.Header.Set("Event-Type", envelope.Type)
+	msg.Header.Set("Schema-Version", strconv.Itoa(envelope.SchemaVersion))
+
+	_, err = p.js.PublishMsg(msg, nats.MsgId(envelope.ID), nats.Context(ctx))
+
+	return err
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,38 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+// Publisher entrega um evento ao broker. Retornar nil significa que o broker confirmou o recebimento;
+// a entrega é pelo menos uma vez, então os consumidores devem deduplicar pelo Envelope.ID.
+type Publisher interface {
+	Publish(ctx context.Context, envelope events.Envelope) error
+	Close() error
+}
+
+// NewPublisherFromEnv escolhe o publisher pela variável EVENT_PUBLISHER (memory, nats ou sqs).
+// Sem configuração retorna nil e os eventos ficam acumulados no outbox até haver um publisher.
+func NewPublisherFromEnv(ctx context.Context) (Publisher, error) {
+	switch kind := os.Getenv("EVENT_PUBLISHER"); kind {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		return NewMemoryPublisher(), nil
+	case "nats":
+		return NewNATSPublisher(
+			utils.GetEnv("NATS_URL", "nats://localhost:4222"),
+			utils.GetEnv("NATS_STREAM", "CUSTOMERS"),
+			utils.GetEnv("NATS_SUBJECT_PREFIX", "customers"),
+		)
+	case "sqs":
+		return NewSQSPublisher(ctx, os.Getenv("SQS_QUEUE_URL"), os.Getenv("SQS_ENDPOINT"))
+	default:
+		return nil, fmt.Errorf("EVENT_PUBLISHER desconhecido: %s", kind)
+	}
+}
@@ -0,0 +1,132 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// Handler processa um evento recebido. Retornar erro devolve a mensagem para a fila, que a
+// entrega de novo depois do visibility timeout; a dead-letter fica a cargo da redrive policy.
+type Handler func(ctx context.Context, envelope events.Envelope) error
+
+// SQSConsumer lê eventos de outros serviços de uma fila SQS com long polling. Roda como server.Runnable.
+type SQSConsumer struct {
+	client   *sqs.Client
+	queueURL string
+	handler  Handler
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewSQSConsumer(ctx context.Context, queueURL string, endpoint string, handler Handler) (*SQSConsumer, error) {
+	if queueURL == "" {
+		return nil, errors.New("fila do consumidor SQS não configurada")
+	}
+
+	client, err := newSQSClient(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	consumerCtx, cancel := context.WithCancel(context.Background())
+
+	return &SQSConsumer{
+		client:   client,
+		queueURL: queueURL,
+		handler:  handler,
+		ctx:      consumerCtx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (c *SQSConsumer) Serve() error {
+	defer close(c.done)
+
+	slog.Info("Consumidor SQS iniciado", "queue", c.queueURL)
+
+	for c.ctx.Err() == nil {
+		output, err := c.client.ReceiveMessage(c.ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(c.queueURL),
+			MaxNumberOfMessages: 10,
+			WaitTimeSeconds:     20,
+		})
+		if err != nil {
+			if c.ctx.Err() != nil {
+				return nil
+			}
+
+			slog.Warn("Erro ao ler a fila SQS", "queue", c.queueURL, "error", err)
+			c.wait(5 * time.Second)
+			continue
+		}
+
+		for _, message := range output.Messages {
+			if c.handle(aws.ToString(message.Body)) {
+				c.delete(message.ReceiptHandle)
+			}
+		}
+	}
+
+	return nil
+}
+
+// handle devolve se a mensagem pode ser apagada da fila
+func (c *SQSConsumer) handle(body string) bool {
+	var envelope events.Envelope
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		// uma mensagem ilegível nunca vai dar certo; fica só no log
+		slog.Error("Mensagem SQS descartada: envelope inválido", "queue", c.queueURL, "error", err)
+		metrics.EventsConsumedTotal.WithLabelValues("unknown", "discarded").Inc()
+		return true
+	}
+
+	// o processamento da mensagem recebida termina mesmo durante o desligamento
+	if err := c.handler(context.WithoutCancel(c.ctx), envelope); err != nil {
+		slog.Warn("Erro ao processar evento; ele será entregue de novo", "type", envelope.Type, "eventId", envelope.ID, "error", err)
+		metrics.EventsConsumedTotal.WithLabelValues(envelope.Type, "retry").Inc()
+		return false
+	}
+
+	metrics.EventsConsumedTotal.WithLabelValues(envelope.Type, "success").Inc()
+
+	return true
+}
+
+func (c *SQSConsumer) delete(receiptHandle *string) {
+	_, err := c.client.DeleteMessage(context.WithoutCancel(c.ctx), &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(c.queueURL),
+		ReceiptHandle: receiptHandle,
+	})
+	if err != nil {
+		slog.Warn("Erro ao apagar mensagem SQS; ela será entregue de novo", "queue", c.queueURL, "error", err)
+	}
+}
+
+func (c *SQSConsumer) wait(d time.Duration) {
+	select {
+	case <-c.ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+func (c *SQSConsumer) Shutdown(ctx context.Context) error {
+	c.cancel()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
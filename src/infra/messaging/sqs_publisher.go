@@ -0,0 +1,84 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// SQSPublisher envia os eventos para uma fila SQS. Com endpoint definido
+// funciona contra um substituto local compatível, como o ElasticMQ.
+// Em filas FIFO o grupo é o agregado, o que preserva a ordem por cliente.
+type SQSPublisher struct {
+	client   *sqs.Client
+	queueURL string
+	fifo     bool
+}
+
+func NewSQSPublisher(ctx context.Context, queueURL string, endpoint string) (*SQSPublisher, error) {
+	if queueURL == "" {
+		return nil, errors.New("SQS_QUEUE_URL não configurada")
+	}
+
+	client, err := newSQSClient(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQSPublisher{
+		client:   client,
+		queueURL: queueURL,
+		fifo:     strings.HasSuffix(queueURL, ".fifo"),
+	}, nil
+}
+
+// newSQSClient usa a configuração padrão da AWS; endpoint aponta para um substituto local
+func newSQSClient(ctx context.Context, endpoint string) (*sqs.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	}), nil
+}
+
+func (p *SQSPublisher) Publish(ctx context.Context, envelope events.Envelope) error {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(p.queueURL),
+		MessageBody: aws.String(string(body)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"eventType":     {DataType: aws.String("String"), StringValue: aws.String(envelope.Type)},
+			"schemaVersion": {DataType: aws.String("Number"), StringValue: aws.String(strconv.Itoa(envelope.SchemaVersion))},
+		},
+	}
+
+	if p.fifo {
+		input.MessageGroupId = aws.String(envelope.AggregateType + "-" + envelope.AggregateID)
+		input.MessageDeduplicationId = aws.String(envelope.ID)
+	}
+
+	_, err = p.client.SendMessage(ctx, input)
+
+	return err
+}
+
+func (p *SQSPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "customer_service"
+
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total de requisições HTTP por rota, método e status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latência das requisições HTTP por rota e método.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	HTTPRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Requisições HTTP em andamento.",
+	})
+
+	TokensIssuedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tokens_issued_total",
+		Help:      "Tokens JWT emitidos por tipo (anonymous, identified, unknown_cpf).",
+	}, []string{"type"})
+
+	RepositoryCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_call_duration_seconds",
+		Help:      "Latência das chamadas ao repositório por operação e resultado.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "outcome"})
+
+	DuplicateCustomerConflictsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_customer_conflicts_total",
+		Help:      "Tentativas de cadastro de cliente que violaram a unicidade de CPF ou email.",
+	})
+
+	OutboxEventsPublishedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_events_published_total",
+		Help:      "Tentativas de publicação de eventos do outbox por tipo e resultado.",
+	}, []string{"type", "outcome"})
+
+	OutboxPublishLag = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "outbox_publish_lag_seconds",
+		Help:      "Tempo entre a gravação do evento no outbox e a confirmação do broker.",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+	})
+
+	RateLimitDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_decisions_total",
+		Help:      "Decisões do limite de emissão de sessão por escopo (ip, device, cpf) e resultado (allowed, limited, locked).",
+	}, []string{"scope", "result"})
+
+	SessionLookupMissesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "session_lookup_misses_total",
+		Help:      "Pedidos de sessão com CPF não cadastrado contados pelo limite de tentativas.",
+	})
+
+	SessionLockoutsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "session_lockouts_total",
+		Help:      "Bloqueios temporários de emissão de sessão por escopo (ip, device).",
+	}, []string{"scope"})
+
+	LoyaltyPointsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "loyalty_points_total",
+		Help:      "Pontos de fidelidade lançados por tipo (earn, redeem, adjust, expire), em valor absoluto.",
+	}, []string{"type"})
+
+	EventsConsumedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_consumed_total",
+		Help:      "Eventos de outros serviços consumidos por tipo e resultado (success, retry, discarded).",
+	}, []string{"type", "result"})
+
+	CEPLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cep_lookups_total",
+		Help:      "Consultas ao provedor de CEP por provedor e resultado (found, not_found, error).",
+	}, []string{"provider", "result"})
+
+	CacheLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Consultas ao cache de clientes por operação e resultado (hit, negative_hit, miss, coalesced).",
+	}, []string{"operation", "result"})
+
+	WebhookDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Tentativas de entrega de webhook por tipo de evento e resultado (success, retry, dead).",
+	}, []string{"type", "outcome"})
+)
+
+// Recorder implementa gateways.Metrics com os coletores deste pacote
+type Recorder struct{}
+
+func (Recorder) TokenIssued(tokenType string) {
+	TokensIssuedTotal.WithLabelValues(tokenType).Inc()
+}
+
+func (Recorder) LoyaltyPoints(transactionType string, points int64) {
+	LoyaltyPointsTotal.WithLabelValues(transactionType).Add(float64(points))
+}
+
+// ObserveRepositoryCall registra a latência de uma chamada ao repositório.
+// Deve ser usado com defer e um erro nomeado: defer func() { metrics.ObserveRepositoryCall("create", start, err) }()
+func ObserveRepositoryCall(operation string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+
+	RepositoryCallDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+}
+
+// RegisterDBStats expõe as estatísticas do pool de conexões (abertas, em uso, espera etc.)
+func RegisterDBStats(db *sql.DB, dbName string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
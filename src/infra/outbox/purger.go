@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+)
+
+// Purger apaga periodicamente os eventos já publicados há mais de Retention. Roda como server.Runnable;
+// eventos pendentes nunca são apagados, então o relay não perde nada mesmo com o broker fora do ar.
+type Purger struct {
+	DB        database.Database
+	Retention time.Duration
+	Interval  time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewPurger(db database.Database, retention time.Duration, interval time.Duration) *Purger {
+	return &Purger{
+		DB:        db,
+		Retention: retention,
+		Interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+func (p *Purger) Serve() error {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return nil
+		case <-ticker.C:
+			purged, err := p.PurgePublished(context.Background(), time.Now().Add(-p.Retention))
+			if err != nil {
+				slog.Warn("Erro ao apagar os eventos publicados do outbox", "error", err)
+			} else if purged > 0 {
+				slog.Info("Eventos publicados apagados do outbox", "events", purged)
+			}
+		}
+	}
+}
+
+func (p *Purger) Shutdown(ctx context.Context) error {
+	close(p.stop)
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PurgePublished apaga os eventos publicados antes de before e retorna quantos foram apagados
+func (p *Purger) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := p.DB.WithContext(ctx).Where("published_at < ?", before).Delete(&models.OutboxEvent{})
+
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,130 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/messaging"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+)
+
+const selectPendingSQL = `SELECT * FROM outbox_events WHERE published_at IS NULL ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED`
+
+// Relay lê o outbox e publica os eventos pendentes. Roda como server.Runnable.
+// Com FOR UPDATE SKIP LOCKED várias réplicas podem rodar o relay sem publicar o mesmo lote em paralelo.
+// Um evento só é marcado como publicado depois da confirmação do broker (entrega pelo menos uma vez).
+type Relay struct {
+	DB        database.Database
+	Publisher messaging.Publisher
+	BatchSize int
+	Interval  time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewRelay(db database.Database, publisher messaging.Publisher, batchSize int, interval time.Duration) *Relay {
+	return &Relay{
+		DB:        db,
+		Publisher: publisher,
+		BatchSize: batchSize,
+		Interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+func (r *Relay) Serve() error {
+	defer close(r.done)
+
+	slog.Info("Relay do outbox iniciado", "interval", r.Interval.String(), "batchSize", r.BatchSize)
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return nil
+		case <-ticker.C:
+			for {
+				published, err := r.PublishPending(context.Background())
+				if err != nil {
+					slog.Warn("Erro ao publicar eventos do outbox", "error", err)
+				}
+				// lote cheio: provavelmente há mais eventos esperando
+				if err != nil || published < r.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (r *Relay) Shutdown(ctx context.Context) error {
+	close(r.stop)
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return r.Publisher.Close()
+}
+
+// PublishPending publica um lote e retorna quantos eventos foram confirmados.
+// Se um evento falha, os seguintes do mesmo cliente ficam para a próxima rodada para manter a ordem por agregado.
+func (r *Relay) PublishPending(ctx context.Context) (published int, err error) {
+	err = r.DB.WithContext(ctx).Transaction(func(tx database.Database) error {
+		var pending []models.OutboxEvent
+		if err := tx.Raw(&pending, selectPendingSQL, r.BatchSize); err != nil {
+			return err
+		}
+
+		blocked := map[string]bool{}
+
+		for _, event := range pending {
+			aggregate := event.AggregateType + ":" + event.AggregateID
+			if blocked[aggregate] {
+				continue
+			}
+
+			publishErr := r.publish(ctx, event)
+			if publishErr != nil {
+				blocked[aggregate] = true
+				metrics.OutboxEventsPublishedTotal.WithLabelValues(event.Type, "error").Inc()
+				slog.Warn("Falha ao publicar evento", "eventId", event.EventID, "type", event.Type, "attempts", event.Attempts+1, "error", publishErr)
+
+				if err := tx.Exec(`UPDATE outbox_events SET attempts = attempts + 1, last_error = ? WHERE id = ?`, publishErr.Error(), event.ID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Exec(`UPDATE outbox_events SET published_at = ?, attempts = attempts + 1, last_error = '' WHERE id = ?`, time.Now(), event.ID); err != nil {
+				return err
+			}
+
+			metrics.OutboxEventsPublishedTotal.WithLabelValues(event.Type, "success").Inc()
+			metrics.OutboxPublishLag.Observe(time.Since(event.OccurredAt).Seconds())
+			published++
+		}
+
+		return nil
+	})
+
+	return published, err
+}
+
+func (r *Relay) publish(ctx context.Context, event models.OutboxEvent) error {
+	envelope, err := event.ToEnvelope(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Publisher.Publish(ctx, envelope)
+}
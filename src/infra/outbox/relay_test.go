@@ -0,0 +1,164 @@
+package outbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/mocks"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/models"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/CAVAh/api-tech-challenge/src/infra/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type failingPublisher struct {
+	messaging.Publisher
+	failType string
+	sent     []events.Envelope
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, envelope events.Envelope) error {
+	if envelope.Type == p.failType {
+		return errors.New("broker indisponível")
+	}
+	p.sent = append(p.sent, envelope)
+	return nil
+}
+
+func outboxEvent(t *testing.T, id uint, envelope events.Envelope, err error) models.OutboxEvent {
+	require.NoError(t, err)
+	event, err := models.NewOutboxEvent(envelope)
+	require.NoError(t, err)
+	event.ID = id
+	return *event
+}
+
+func expectPending(mockDB *mocks.MockDatabase, pending []models.OutboxEvent) {
+	mockDB.EXPECT().WithContext(gomock.Any()).Return(mockDB)
+	mockDB.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(tx database.Database) error) error {
+		return fn(mockDB)
+	})
+	mockDB.EXPECT().Raw(gomock.Any(), selectPendingSQL, 10).DoAndReturn(func(dest interface{}, sql string, values ...interface{}) error {
+		*dest.(*[]models.OutboxEvent) = pending
+		return nil
+	})
+}
+
+func TestRelay_PublishPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	publisher := messaging.NewMemoryPublisher()
+	relay := NewRelay(mockDB, publisher, 10, 0)
+
+	created, err := events.NewCustomerCreated(entities.Customer{ID: 1, Name: "John Doe"})
+	pending := []models.OutboxEvent{outboxEvent(t, 1, created, err)}
+	erased, err := events.NewCustomerErased(2)
+	pending = append(pending, outboxEvent(t, 2, erased, err))
+
+	expectPending(mockDB, pending)
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), uint(1)).Return(nil)
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), uint(2)).Return(nil)
+
+	published, err := relay.PublishPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	sent := publisher.Published()
+	require.Len(t, sent, 2)
+	assert.Equal(t, created.ID, sent[0].ID)
+	assert.Equal(t, events.CustomerErased, sent[1].Type)
+}
+
+func TestRelay_PublishPending_KeepsAggregateOrderOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	publisher := &failingPublisher{failType: events.CustomerCreated}
+	relay := NewRelay(mockDB, publisher, 10, 0)
+
+	created, err := events.NewCustomerCreated(entities.Customer{ID: 1})
+	pending := []models.OutboxEvent{outboxEvent(t, 1, created, err)}
+	updated, err := events.NewCustomerUpdated(entities.Customer{ID: 1}, []string{"name"})
+	pending = append(pending, outboxEvent(t, 2, updated, err))
+	other, err := events.NewCustomerUpdated(entities.Customer{ID: 2}, []string{"email"})
+	pending = append(pending, outboxEvent(t, 3, other, err))
+
+	expectPending(mockDB, pending)
+	// falha registrada no evento 1, evento 2 fica para depois e o 3 (outro cliente) é publicado
+	mockDB.EXPECT().Exec(gomock.Any(), "broker indisponível", uint(1)).Return(nil)
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), uint(3)).Return(nil)
+
+	published, err := relay.PublishPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	require.Len(t, publisher.sent, 1)
+	assert.Equal(t, other.ID, publisher.sent[0].ID)
+}
+
+type singleKeyStore struct {
+	keys []encryption.WrappedKey
+}
+
+func (s *singleKeyStore) ListKeys(context.Context) ([]encryption.WrappedKey, error) {
+	return s.keys, nil
+}
+
+func (s *singleKeyStore) SaveKey(_ context.Context, key encryption.WrappedKey) error {
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *singleKeyStore) ActivateKey(_ context.Context, id string) error {
+	for i := range s.keys {
+		s.keys[i].Active = s.keys[i].ID == id
+	}
+	return nil
+}
+
+func TestRelay_PublishPending_DecryptsPayload(t *testing.T) {
+	key := sha256.Sum256([]byte("kek"))
+	kek, err := encryption.ParseLocalKeys("kek:" + base64.StdEncoding.EncodeToString(key[:]))
+	require.NoError(t, err)
+	keyring, err := encryption.NewKeyring(context.Background(), &singleKeyStore{}, kek, []byte(strings.Repeat("k", 32)), 0)
+	require.NoError(t, err)
+	encryption.SetDefault(keyring)
+	t.Cleanup(func() { encryption.SetDefault(nil) })
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	publisher := messaging.NewMemoryPublisher()
+	relay := NewRelay(mockDB, publisher, 10, 0)
+
+	created, err := events.NewCustomerCreated(entities.Customer{ID: 1, Name: "John Doe", Email: "john@example.com"})
+	event := outboxEvent(t, 1, created, err)
+	require.NoError(t, event.BeforeCreate(&gorm.DB{Statement: &gorm.Statement{Context: context.Background()}}))
+	assert.NotContains(t, string(event.Payload), "John")
+	assert.NotContains(t, string(event.Payload), "john@example.com")
+
+	expectPending(mockDB, []models.OutboxEvent{event})
+	mockDB.EXPECT().Exec(gomock.Any(), gomock.Any(), uint(1)).Return(nil)
+
+	published, err := relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+
+	sent := publisher.Published()
+	require.Len(t, sent, 1)
+	assert.Equal(t, created.ID, sent[0].ID)
+	assert.Contains(t, string(sent[0].Data), "John Doe")
+}
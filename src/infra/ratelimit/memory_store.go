@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore guarda buckets e contadores na memória da réplica.
+// Ao passar de maxKeys as entradas ociosas são descartadas: um bucket cheio
+// ou um contador expirado equivalem a não ter entrada.
+type MemoryStore struct {
+	mu       sync.Mutex
+	maxKeys  int
+	buckets  map[string]*memoryBucket
+	counters map[string]*memoryCounter
+	now      func() time.Time
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+type memoryCounter struct {
+	value     int64
+	expiresAt time.Time
+}
+
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	return &MemoryStore{
+		maxKeys:  maxKeys,
+		buckets:  map[string]*memoryBucket{},
+		counters: map[string]*memoryCounter{},
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		s.sweep(now)
+		bucket = &memoryBucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = bucket
+	}
+
+	tokens, result := refill(bucket.tokens, now.Sub(bucket.updated), limit)
+	bucket.tokens, bucket.updated, bucket.limit = tokens, now, limit
+
+	return result, nil
+}
+
+func (s *MemoryStore) Increment(_ context.Context, key string, window time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.expiresAt) {
+		s.sweep(now)
+		counter = &memoryCounter{expiresAt: now.Add(window)}
+		s.counters[key] = counter
+	}
+
+	counter.value++
+
+	return counter.value, nil
+}
+
+func (s *MemoryStore) Count(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || !s.now().Before(counter.expiresAt) {
+		return 0, nil
+	}
+
+	return counter.value, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if len(s.buckets)+len(s.counters) < s.maxKeys {
+		return
+	}
+
+	for key, bucket := range s.buckets {
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*bucket.limit.Rate >= float64(bucket.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+
+	for key, counter := range s.counters {
+		if !now.Before(counter.expiresAt) {
+			delete(s.counters, key)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package ratelimit
+
+import "context"
+
+type outcomeKey struct{}
+
+// Outcome é preenchido durante a requisição para o guard saber se a busca por CPF falhou
+type Outcome struct {
+	Miss bool
+}
+
+func WithOutcome(ctx context.Context) (context.Context, *Outcome) {
+	outcome := &Outcome{}
+	return context.WithValue(ctx, outcomeKey{}, outcome), outcome
+}
+
+// ReportMiss marca a requisição como busca por um CPF não cadastrado.
+// Fora de uma requisição protegida pelo guard não faz nada.
+func ReportMiss(ctx context.Context) {
+	if outcome, ok := ctx.Value(outcomeKey{}).(*Outcome); ok {
+		outcome.Miss = true
+	}
+}
+
+// MissReporter implementa gateways.SessionLookupMisses com o Outcome da requisição
+type MissReporter struct{}
+
+func (MissReporter) ReportMiss(ctx context.Context) {
+	ReportMiss(ctx)
+}
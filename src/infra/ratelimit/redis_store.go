@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript aplica o token bucket de forma atômica no Redis. O horário vem do próprio Redis
+// para que réplicas com relógios diferentes enxerguem o mesmo bucket.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = redis.call('TIME')
+local now_ms = now[1] * 1000 + math.floor(now[2] / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now_ms
+
+tokens = math.min(burst, tokens + (now_ms - updated) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now_ms)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+var incrementScript = redis.NewScript(`
+local value = redis.call('INCR', KEYS[1])
+if value == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return value
+`)
+
+// RedisStore compartilha os limites entre as réplicas
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	values, err := takeScript.Run(ctx, s.client, []string{s.prefix + "bucket:" + key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(values) != 2 {
+		return Result{}, errors.New("resposta inesperada do script de rate limit")
+	}
+
+	tokens, err := strconv.ParseFloat(values[1].(string), 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// o script já consumiu a ficha; refill com elapsed zero só monta o resultado a partir do saldo
+	allowed := values[0].(int64) == 1
+	if allowed {
+		tokens++
+	}
+	_, result := refill(tokens, 0, limit)
+
+	return result, nil
+}
+
+func (s *RedisStore) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return incrementScript.Run(ctx, s.client, []string{s.prefix + "counter:" + key}, window.Milliseconds()).Int64()
+}
+
+func (s *RedisStore) Count(ctx context.Context, key string) (int64, error) {
+	value, err := s.client.Get(ctx, s.prefix+"counter:"+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	return value, err
+}
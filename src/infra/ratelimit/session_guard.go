@@ -0,0 +1,225 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+// Escopos dos limites, também usados como rótulo das métricas
+const (
+	ScopeIP     = "ip"
+	ScopeDevice = "device"
+	ScopeCPF    = "cpf"
+)
+
+// Subject identifica quem pede a sessão. Device e CPF podem vir vazios.
+type Subject struct {
+	IP     string
+	Device string
+	CPF    string
+}
+
+type SessionGuardConfig struct {
+	IPLimit     Limit
+	DeviceLimit Limit
+	CPFLimit    Limit
+	// a partir de DelayAfter buscas sem sucesso na janela, cada resposta atrasa BaseDelay·2^n, até MaxDelay
+	DelayAfter int64
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// com LockoutAfter buscas sem sucesso na janela, desde que sejam ao menos LockoutMissPercent% das buscas
+	// do IP ou dispositivo, ele fica bloqueado por LockoutDuration. A proporção poupa IPs compartilhados
+	// (quiosques atrás do mesmo NAT), onde os erros de digitação se diluem entre muitas buscas certas.
+	LockoutAfter       int64
+	LockoutMissPercent int64
+	MissWindow         time.Duration
+	LockoutDuration    time.Duration
+}
+
+func LoadSessionGuardConfig() SessionGuardConfig {
+	return SessionGuardConfig{
+		IPLimit:            PerMinute(utils.GetEnvInt("SESSION_LIMIT_IP_PER_MINUTE", 30)),
+		DeviceLimit:        PerMinute(utils.GetEnvInt("SESSION_LIMIT_DEVICE_PER_MINUTE", 10)),
+		CPFLimit:           PerMinute(utils.GetEnvInt("SESSION_LIMIT_CPF_PER_MINUTE", 5)),
+		DelayAfter:         int64(utils.GetEnvInt("SESSION_MISS_DELAY_AFTER", 3)),
+		BaseDelay:          utils.GetEnvDuration("SESSION_MISS_BASE_DELAY", 250*time.Millisecond),
+		MaxDelay:           utils.GetEnvDuration("SESSION_MISS_MAX_DELAY", 5*time.Second),
+		LockoutAfter:       int64(utils.GetEnvInt("SESSION_LOCKOUT_AFTER", 20)),
+		LockoutMissPercent: int64(utils.GetEnvInt("SESSION_LOCKOUT_MISS_PERCENT", 50)),
+		MissWindow:         utils.GetEnvDuration("SESSION_MISS_WINDOW", 15*time.Minute),
+		LockoutDuration:    utils.GetEnvDuration("SESSION_LOCKOUT_DURATION", 15*time.Minute),
+	}
+}
+
+// Decision é o resultado da verificação antes de emitir a sessão
+type Decision struct {
+	Allowed bool
+	// Scope é o limite que negou a requisição, ou o mais restritivo quando permitida
+	Scope string
+	// Result alimenta os cabeçalhos RateLimit-*
+	Result Result
+	Locked bool
+	// Delay é o atraso a aplicar antes de responder, pelas buscas sem sucesso anteriores
+	Delay time.Duration
+}
+
+// SessionGuard protege a emissão de sessão contra enumeração de CPFs: limita por IP,
+// dispositivo e CPF, atrasa as respostas de quem erra muito e bloqueia quem insiste.
+// O documento só chega ao store como índice cego do Keyring.
+type SessionGuard struct {
+	Store   Store
+	Keyring *encryption.Keyring
+	Config  SessionGuardConfig
+}
+
+// sessionGuardPurpose separa o índice cego do limite dos demais índices do Keyring
+const sessionGuardPurpose = "customers.session_guard"
+
+type scopedKey struct {
+	scope string
+	key   string
+	limit Limit
+}
+
+func (g *SessionGuard) Check(ctx context.Context, subject Subject) (Decision, error) {
+	for _, scoped := range g.missKeys(subject) {
+		locked, err := g.Store.Count(ctx, "lock:"+scoped.key)
+		if err != nil {
+			return Decision{}, err
+		}
+
+		if locked > 0 {
+			metrics.RateLimitDecisionsTotal.WithLabelValues(scoped.scope, "locked").Inc()
+			return Decision{
+				Scope:  scoped.scope,
+				Locked: true,
+				Result: Result{Limit: scoped.limit.Burst, RetryAfter: g.Config.LockoutDuration, Reset: g.Config.LockoutDuration},
+			}, nil
+		}
+	}
+
+	decision := Decision{Allowed: true}
+	first := true
+	for _, scoped := range g.bucketKeys(subject) {
+		result, err := g.Store.Take(ctx, scoped.key, scoped.limit)
+		if err != nil {
+			return Decision{}, err
+		}
+
+		if !result.Allowed {
+			metrics.RateLimitDecisionsTotal.WithLabelValues(scoped.scope, "limited").Inc()
+			return Decision{Scope: scoped.scope, Result: result}, nil
+		}
+
+		if first || result.Remaining < decision.Result.Remaining {
+			decision.Scope, decision.Result = scoped.scope, result
+			first = false
+		}
+	}
+
+	// só a busca por documento conta na proporção de erros; a sessão anônima não busca ninguém
+	if subject.CPF != "" {
+		for _, scoped := range g.missKeys(subject) {
+			if _, err := g.Store.Increment(ctx, "lookup:"+scoped.key, g.Config.MissWindow); err != nil {
+				return Decision{}, err
+			}
+		}
+	}
+
+	misses, err := g.misses(ctx, subject)
+	if err != nil {
+		return Decision{}, err
+	}
+	decision.Delay = g.delay(misses)
+
+	metrics.RateLimitDecisionsTotal.WithLabelValues(decision.Scope, "allowed").Inc()
+
+	return decision, nil
+}
+
+// RecordMiss conta uma busca por CPF não cadastrado e bloqueia quem chegou ao limite
+// com a proporção de erros de quem enumera
+func (g *SessionGuard) RecordMiss(ctx context.Context, subject Subject) error {
+	for _, scoped := range g.missKeys(subject) {
+		misses, err := g.Store.Increment(ctx, "miss:"+scoped.key, g.Config.MissWindow)
+		if err != nil {
+			return err
+		}
+
+		if misses < g.Config.LockoutAfter {
+			continue
+		}
+
+		lookups, err := g.Store.Count(ctx, "lookup:"+scoped.key)
+		if err != nil {
+			return err
+		}
+
+		if misses*100 < lookups*g.Config.LockoutMissPercent {
+			continue
+		}
+
+		locks, err := g.Store.Increment(ctx, "lock:"+scoped.key, g.Config.LockoutDuration)
+		if err != nil {
+			return err
+		}
+		if locks == 1 {
+			metrics.SessionLockoutsTotal.WithLabelValues(scoped.scope).Inc()
+		}
+	}
+
+	metrics.SessionLookupMissesTotal.Inc()
+
+	return nil
+}
+
+func (g *SessionGuard) misses(ctx context.Context, subject Subject) (int64, error) {
+	var highest int64
+	for _, scoped := range g.missKeys(subject) {
+		misses, err := g.Store.Count(ctx, "miss:"+scoped.key)
+		if err != nil {
+			return 0, err
+		}
+		highest = max(highest, misses)
+	}
+
+	return highest, nil
+}
+
+func (g *SessionGuard) delay(misses int64) time.Duration {
+	if g.Config.DelayAfter <= 0 || misses < g.Config.DelayAfter {
+		return 0
+	}
+
+	delay := g.Config.BaseDelay
+	for i := g.Config.DelayAfter; i < misses && delay < g.Config.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, g.Config.MaxDelay)
+}
+
+// missKeys são os escopos que acumulam erros. O CPF fica de fora: quem enumera erra
+// em CPFs diferentes, e bloquear um CPF deixaria um terceiro travar o acesso do titular.
+func (g *SessionGuard) missKeys(subject Subject) []scopedKey {
+	keys := []scopedKey{{scope: ScopeIP, key: "ip:" + subject.IP, limit: g.Config.IPLimit}}
+	if subject.Device != "" {
+		keys = append(keys, scopedKey{scope: ScopeDevice, key: "device:" + subject.Device, limit: g.Config.DeviceLimit})
+	}
+
+	return keys
+}
+
+func (g *SessionGuard) bucketKeys(subject Subject) []scopedKey {
+	keys := g.missKeys(subject)
+	if subject.CPF != "" {
+		// o CPF não vai em claro para o Redis, nem como hash sem chave, que se inverte testando todos os CPFs
+		keys = append(keys, scopedKey{scope: ScopeCPF, key: "cpf:" + g.Keyring.BlindIndex(sessionGuardPurpose, subject.CPF)[:32], limit: g.Config.CPFLimit})
+	}
+
+	return keys
+}
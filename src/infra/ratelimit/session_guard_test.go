@@ -0,0 +1,213 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type singleKeyStore struct {
+	keys []encryption.WrappedKey
+}
+
+func (s *singleKeyStore) ListKeys(context.Context) ([]encryption.WrappedKey, error) {
+	return s.keys, nil
+}
+
+func (s *singleKeyStore) SaveKey(_ context.Context, key encryption.WrappedKey) error {
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *singleKeyStore) ActivateKey(_ context.Context, id string) error {
+	for i := range s.keys {
+		s.keys[i].Active = s.keys[i].ID == id
+	}
+	return nil
+}
+
+func newTestKeyring(t *testing.T) *encryption.Keyring {
+	key := sha256.Sum256([]byte("kek"))
+	kek, err := encryption.ParseLocalKeys("kek:" + base64.StdEncoding.EncodeToString(key[:]))
+	require.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(context.Background(), &singleKeyStore{}, kek, []byte(strings.Repeat("k", 32)), 0)
+	require.NoError(t, err)
+
+	return keyring
+}
+
+func newTestGuard(t *testing.T, now *time.Time) *SessionGuard {
+	store := NewMemoryStore(1000)
+	store.now = func() time.Time { return *now }
+
+	return &SessionGuard{
+		Store:   store,
+		Keyring: newTestKeyring(t),
+		Config: SessionGuardConfig{
+			IPLimit:            PerMinute(10),
+			DeviceLimit:        PerMinute(5),
+			CPFLimit:           PerMinute(2),
+			DelayAfter:         2,
+			BaseDelay:          100 * time.Millisecond,
+			MaxDelay:           time.Second,
+			LockoutAfter:       5,
+			LockoutMissPercent: 50,
+			MissWindow:         15 * time.Minute,
+			LockoutDuration:    10 * time.Minute,
+		},
+	}
+}
+
+func TestMemoryStore_TakeRefillsOverTime(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	store := NewMemoryStore(1000)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+	limit := PerMinute(2)
+
+	first, _ := store.Take(ctx, "k", limit)
+	second, _ := store.Take(ctx, "k", limit)
+	third, _ := store.Take(ctx, "k", limit)
+
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.True(t, second.Allowed)
+	assert.Equal(t, 0, second.Remaining)
+	assert.False(t, third.Allowed)
+	assert.Equal(t, 30*time.Second, third.RetryAfter)
+	assert.Equal(t, time.Minute, third.Reset)
+
+	now = now.Add(30 * time.Second)
+	fourth, _ := store.Take(ctx, "k", limit)
+	assert.True(t, fourth.Allowed)
+}
+
+func TestMemoryStore_CounterExpires(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	store := NewMemoryStore(1000)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store.Increment(ctx, "k", time.Minute)
+	value, _ := store.Increment(ctx, "k", time.Minute)
+	assert.Equal(t, int64(2), value)
+
+	now = now.Add(time.Minute)
+	count, _ := store.Count(ctx, "k")
+	assert.Zero(t, count)
+}
+
+func TestSessionGuard_LimitsPerCPF(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	guard := newTestGuard(t, &now)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		decision, err := guard.Check(ctx, Subject{IP: "10.0.0.1", CPF: "12345678900"})
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	}
+
+	decision, err := guard.Check(ctx, Subject{IP: "10.0.0.2", CPF: "12345678900"})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, ScopeCPF, decision.Scope)
+
+	// outro CPF do mesmo IP segue liberado
+	decision, err = guard.Check(ctx, Subject{IP: "10.0.0.1", CPF: "98765432100"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+}
+
+func TestSessionGuard_ReportsMostRestrictiveBucket(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	guard := newTestGuard(t, &now)
+
+	decision, err := guard.Check(context.Background(), Subject{IP: "10.0.0.1", Device: "device-123", CPF: "12345678900"})
+
+	require.NoError(t, err)
+	assert.Equal(t, ScopeCPF, decision.Scope)
+	assert.Equal(t, 2, decision.Result.Limit)
+	assert.Equal(t, 1, decision.Result.Remaining)
+}
+
+func TestSessionGuard_DelaysAndLocksAfterMisses(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	guard := newTestGuard(t, &now)
+	ctx := context.Background()
+	subject := Subject{IP: "10.0.0.1", Device: "device-123"}
+
+	delays := []time.Duration{}
+	for i := 0; i < 5; i++ {
+		decision, err := guard.Check(ctx, subject)
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+		delays = append(delays, decision.Delay)
+
+		require.NoError(t, guard.RecordMiss(ctx, subject))
+	}
+
+	assert.Equal(t, []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}, delays)
+
+	decision, err := guard.Check(ctx, subject)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.True(t, decision.Locked)
+	assert.Equal(t, 10*time.Minute, decision.Result.RetryAfter)
+
+	// bloqueio vale para o IP mesmo trocando de dispositivo
+	decision, err = guard.Check(ctx, Subject{IP: "10.0.0.1", Device: "device-456"})
+	require.NoError(t, err)
+	assert.True(t, decision.Locked)
+
+	now = now.Add(10 * time.Minute)
+	decision, err = guard.Check(ctx, Subject{IP: "10.0.0.1"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+}
+
+func TestSessionGuard_DoesNotLockSharedIPWithFewMisses(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	guard := newTestGuard(t, &now)
+	guard.Config.IPLimit = PerMinute(100)
+	ctx := context.Background()
+
+	// quiosques atrás do mesmo IP: 5 erros de digitação em 15 buscas ficam abaixo de 50%
+	for i := 0; i < 15; i++ {
+		subject := Subject{IP: "10.0.0.1", CPF: "1234567890" + string(rune('a'+i))}
+		decision, err := guard.Check(ctx, subject)
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+
+		if i%3 == 0 {
+			require.NoError(t, guard.RecordMiss(ctx, subject))
+		}
+	}
+
+	decision, err := guard.Check(ctx, Subject{IP: "10.0.0.1", CPF: "12345678900"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.False(t, decision.Locked)
+}
+
+func TestSessionGuard_CPFKeyIsBlindIndex(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	guard := newTestGuard(t, &now)
+
+	keys := guard.bucketKeys(Subject{IP: "10.0.0.1", CPF: "12345678900"})
+	cpfKey := keys[len(keys)-1].key
+
+	sum := sha256.Sum256([]byte("12345678900"))
+	assert.NotContains(t, cpfKey, "12345678900")
+	assert.NotContains(t, cpfKey, hex.EncodeToString(sum[:]))
+	assert.Equal(t, "cpf:"+guard.Keyring.BlindIndex(sessionGuardPurpose, "12345678900")[:32], cpfKey)
+}
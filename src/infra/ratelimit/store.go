@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// Limit é um token bucket: Burst fichas no máximo, repostas a Rate fichas por segundo
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute permite n requisições por minuto, todas de uma vez se o bucket estiver cheio
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// Result é o estado do bucket após a tentativa
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset é o tempo até o bucket encher de novo
+	Reset time.Duration
+	// RetryAfter é o tempo até a próxima ficha quando a tentativa foi negada
+	RetryAfter time.Duration
+}
+
+// Store guarda os buckets e contadores. A implementação em memória vale por réplica;
+// a do Redis é compartilhada, então os limites valem para o serviço inteiro.
+type Store interface {
+	// Take consome uma ficha do bucket key
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Increment soma um ao contador key; a janela começa no primeiro incremento
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+	// Count lê o contador key, zero se não existir ou tiver expirado
+	Count(ctx context.Context, key string) (int64, error)
+}
+
+// NewStoreFromEnv usa o Redis de RATE_LIMIT_REDIS_URL (ou o do cache, CACHE_REDIS_URL) quando configurado
+func NewStoreFromEnv() Store {
+	redisURL := utils.GetEnv("RATE_LIMIT_REDIS_URL", utils.GetEnv("CACHE_REDIS_URL", ""))
+	if redisURL == "" {
+		return NewMemoryStore(utils.GetEnvInt("RATE_LIMIT_LOCAL_KEYS", 100000))
+	}
+
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		slog.Warn("URL do Redis inválida para rate limit; usando limites por réplica", "error", err)
+		return NewMemoryStore(utils.GetEnvInt("RATE_LIMIT_LOCAL_KEYS", 100000))
+	}
+
+	return NewRedisStore(redis.NewClient(options), "customer-service:ratelimit:")
+}
+
+// refill calcula as fichas do bucket depois de elapsed e o resultado de consumir uma
+func refill(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+
+	result := Result{Limit: limit.Burst}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate)
+
+	return tokens, result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,51 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogSender não envia nada: grava cada SMS como uma linha JSON em Path ou, sem Path, no log.
+// A mensagem leva o código de verificação, então não deve ser usado em produção.
+type LogSender struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+type loggedMessage struct {
+	Phone   string    `json:"phone"`
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sentAt"`
+}
+
+func (s *LogSender) Send(ctx context.Context, phone string, message string) error {
+	if s.Path == "" {
+		slog.InfoContext(ctx, "SMS (provedor de log)", "phone", phone, "message", message)
+		return nil
+	}
+
+	line, err := json.Marshal(loggedMessage{Phone: phone, Message: message, SentAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,45 @@
+package sms
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSender_AppendsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sms.log")
+	sender := &LogSender{Path: path}
+
+	require.NoError(t, sender.Send(context.Background(), "+5511987654321", "primeira"))
+	require.NoError(t, sender.Send(context.Background(), "+5511987654321", "segunda"))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var messages []loggedMessage
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var message loggedMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &message))
+		messages = append(messages, message)
+	}
+
+	require.Len(t, messages, 2)
+	assert.Equal(t, "+5511987654321", messages[0].Phone)
+	assert.Equal(t, "primeira", messages[0].Message)
+	assert.Equal(t, "segunda", messages[1].Message)
+}
+
+func TestNewSenderFromEnv_UnknownProvider(t *testing.T) {
+	t.Setenv("SMS_PROVIDER", "pombo-correio")
+
+	_, err := NewSenderFromEnv()
+	assert.Error(t, err)
+}
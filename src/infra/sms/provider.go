@@ -0,0 +1,20 @@
+package sms
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+// NewSenderFromEnv escolhe o envio de SMS por SMS_PROVIDER. Por enquanto só há "log" (padrão),
+// para desenvolvimento e testes: a mensagem vai para o log ou, com SMS_LOG_PATH, para o arquivo.
+func NewSenderFromEnv() (gateways.SMSSender, error) {
+	switch provider := utils.GetEnv("SMS_PROVIDER", "log"); provider {
+	case "log":
+		return &LogSender{Path: os.Getenv("SMS_LOG_PATH")}, nil
+	default:
+		return nil, fmt.Errorf("provedor de SMS desconhecido: %s", provider)
+	}
+}
@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/CAVAh/api-tech-challenge"
+
+// Setup configura o TracerProvider global e o propagador W3C (traceparent/baggage).
+// OTEL_TRACES_EXPORTER escolhe o exportador: "otlp", "stdout" para depuração local ou "none" (padrão).
+// O endpoint OTLP segue as variáveis padrão do OpenTelemetry (OTEL_EXPORTER_OTLP_ENDPOINT etc.).
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, err := newExporter(ctx, utils.GetEnv("OTEL_TRACES_EXPORTER", "none"))
+	if err != nil {
+		return nil, err
+	}
+
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(utils.GetEnv("OTEL_SERVICE_NAME", "customer-service")),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, name string) (sdktrace.SpanExporter, error) {
+	switch name {
+	case "otlp":
+		return otlptracehttp.New(ctx)
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("exportador de traces desconhecido: %s", name)
+	}
+}
+
+// Tracer retorna o tracer da aplicação a partir do provider global
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// EndSpan registra o erro (se houver) e finaliza o span.
+// Nunca adicione CPF, email ou tokens como atributo de span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// UsecaseTracer implementa gateways.Tracer com o tracer da aplicação
+type UsecaseTracer struct{}
+
+func (UsecaseTracer) Start(ctx context.Context, name string) (context.Context, gateways.Span) {
+	ctx, span := Tracer().Start(ctx, name)
+	return ctx, usecaseSpan{Span: span}
+}
+
+type usecaseSpan struct {
+	trace.Span
+}
+
+func (s usecaseSpan) End(err error) {
+	EndSpan(s.Span, err)
+}
+
+// Transport propaga o traceparent nas chamadas HTTP de saída
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := Tracer().Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+		),
+	)
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := base.RoundTrip(req)
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	EndSpan(span, err)
+
+	return resp, err
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Readiness guarda se a instância pode receber tráfego novo.
+// Durante o desligamento ela é marcada como não pronta antes de drenar as requisições.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+func (r *Readiness) IsReady() bool {
+	return r.ready.Load()
+}
+
+func Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+func (r *Readiness) Ready(c *gin.Context) {
+	if !r.IsReady() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "shutting down",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
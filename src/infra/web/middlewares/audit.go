@@ -0,0 +1,15 @@
+package middlewares
+
+import (
+	"github.com/CAVAh/api-tech-challenge/src/infra/audit"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditContext coloca o IP do chamador no contexto da requisição para a auditoria.
+// O ator começa anônimo e é trocado pelos middlewares de autenticação.
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(audit.WithClientIP(c.Request.Context(), c.ClientIP()))
+		c.Next()
+	}
+}
@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/audit"
+	"github.com/CAVAh/api-tech-challenge/src/infra/auth"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+
+	ActorKey = "actor"
+	RoleKey  = "role"
+)
+
+// RequireAPIKey exige uma chave X-API-Key válida e registra ator e papel no contexto do gin
+func RequireAPIKey(role string, keys auth.APIKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := keys.Lookup(c.GetHeader(APIKeyHeader))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "credencial inválida",
+			})
+			return
+		}
+
+		c.Set(ActorKey, actor)
+		c.Set(RoleKey, role)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{ID: actor, Role: role}))
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CustomMethodParam é o parâmetro usado para registrar métodos customizados no estilo
+// "/recurso:metodo". O gin trata ":" como início de parâmetro, então "/v2/customers:method"
+// captura ":batchGet" e CustomMethods despacha para o handler correspondente.
+const CustomMethodParam = "method"
+
+func CustomMethods(handlers map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler, ok := handlers[c.Param(CustomMethodParam)]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "rota não encontrada",
+			})
+			return
+		}
+
+		handler(c)
+	}
+}
@@ -0,0 +1,106 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/audit"
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const CustomerIDKey = "customerId"
+
+// SessionNotVerifiedReason é o motivo devolvido quando a rota exige a sessão confirmada
+const SessionNotVerifiedReason = "session_not_verified"
+
+// RequireCustomerToken exige um token de sessão identificado (Authorization: Bearer <token>)
+// e registra o id do cliente no contexto do gin. Tokens emitidos para um cliente incorporado numa
+// fusão continuam válidos e passam a identificar o sobrevivente. Tokens de clientes que deixaram de
+// estar ativos depois da emissão são recusados com o código do motivo, e os de clientes apagados com 401.
+func RequireCustomerToken(redirects gateways.CustomerRedirects, customers gateways.CustomerRepository) gin.HandlerFunc {
+	return requireCustomerToken(redirects, customers, false)
+}
+
+// RequireVerifiedCustomerToken é RequireCustomerToken para os dados pessoais: só aceita o token de
+// sessão confirmada, emitido depois que o cliente informou o código enviado por SMS ou email.
+// O token de sessão emitido só com o CPF é recusado com o motivo session_not_verified.
+func RequireVerifiedCustomerToken(redirects gateways.CustomerRedirects, customers gateways.CustomerRepository) gin.HandlerFunc {
+	return requireCustomerToken(redirects, customers, true)
+}
+
+func requireCustomerToken(redirects gateways.CustomerRedirects, customers gateways.CustomerRepository, verified bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "token de sessão ausente",
+			})
+			return
+		}
+
+		claims, err := utils.ParseJWT(tokenString)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "token de sessão inválido",
+			})
+			return
+		}
+
+		customerID, err := strconv.ParseUint(claims.CustomerId, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "sessão anônima não identifica um cliente",
+			})
+			return
+		}
+
+		if verified && claims.Scope != utils.ScopeVerifiedCustomer {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":  entities.ErrSessionNotVerified.Error(),
+				"reason": SessionNotVerifiedReason,
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+		resolvedID, err := redirects.Resolve(ctx, uint(customerID))
+		if err != nil {
+			logging.FromContext(ctx).Error("erro ao resolver o cliente do token de sessão", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "ocorreu um erro desconhecido ao validar o token de sessão",
+			})
+			return
+		}
+
+		// sem o cliente (apagado, ou a consulta falhou) o token não vale: seguir com o id dele
+		// deixaria a rota agir sobre um cliente que não existe mais
+		customer, err := customers.FindByID(ctx, resolvedID)
+		if err != nil {
+			if !errors.Is(err, entities.ErrCustomerNotFound) {
+				logging.FromContext(ctx).Error("erro ao consultar a situação do cliente do token de sessão", "error", err)
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "token de sessão não identifica um cliente existente",
+			})
+			return
+		}
+
+		if customer.Status != entities.CustomerActive {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":  entities.ErrCustomerNotActive.Error(),
+				"reason": customer.Status.SessionReason(),
+			})
+			return
+		}
+
+		c.Set(CustomerIDKey, resolvedID)
+		c.Request = c.Request.WithContext(audit.WithActor(ctx, audit.CustomerActor(resolvedID)))
+		c.Next()
+	}
+}
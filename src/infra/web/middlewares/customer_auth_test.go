@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noRedirects struct{}
+
+func (noRedirects) Resolve(ctx context.Context, id uint) (uint, error) {
+	return id, nil
+}
+
+func (noRedirects) ResolveMany(ctx context.Context, ids []uint) (map[uint]uint, error) {
+	return map[uint]uint{}, nil
+}
+
+type customerLookup struct {
+	gateways.CustomerRepository
+	customer *entities.Customer
+	err      error
+}
+
+func (r customerLookup) FindByID(ctx context.Context, id uint) (*entities.Customer, error) {
+	return r.customer, r.err
+}
+
+func TestRequireCustomerToken_CustomerLookup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	token, err := utils.GenerateJWT(uint(7))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		customers customerLookup
+		status    int
+	}{
+		"cliente ativo":          {customerLookup{customer: &entities.Customer{ID: 7, Status: entities.CustomerActive}}, http.StatusOK},
+		"cliente suspenso":       {customerLookup{customer: &entities.Customer{ID: 7, Status: entities.CustomerSuspended}}, http.StatusForbidden},
+		"cliente apagado":        {customerLookup{err: entities.ErrCustomerNotFound}, http.StatusUnauthorized},
+		"falha ao ler o cliente": {customerLookup{err: errors.New("conexão recusada")}, http.StatusUnauthorized},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/v2/customers/me", RequireCustomerToken(noRedirects{}, test.customers), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"customerId": c.GetUint(CustomerIDKey)})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/v2/customers/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marca as respostas com os headers Deprecation, Sunset (RFC 8594) e o link para a versão sucessora
+func Deprecated(deprecatedAt time.Time, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	link := fmt.Sprintf("<%s>; rel=\"successor-version\"", successor)
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		c.Header("Link", link)
+		c.Next()
+	}
+}
@@ -0,0 +1,151 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/idempotency"
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// Idempotency guarda a primeira resposta de cada Idempotency-Key e a devolve nas repetições.
+// Reusar a chave com outro corpo retorna 422; repetir enquanto a primeira ainda está em andamento retorna 409.
+// Respostas 5xx e pânicos do handler liberam a chave para que o cliente possa tentar de novo com ela; se nem
+// isso for possível (a réplica caiu, por exemplo), a reserva vence sozinha depois de lease.
+func Idempotency(store idempotency.Store, ttl time.Duration, lease time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Idempotency-Key deve ter no máximo 255 caracteres",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentRequestBytes))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": "corpo da requisição acima de 1 MiB",
+			})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "não foi possível ler o corpo da requisição",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		scopedKey := c.Request.Method + " " + c.FullPath() + " " + key
+		fingerprint := requestFingerprint(c.Request.Method, c.FullPath(), body)
+
+		existing, err := store.Reserve(ctx, scopedKey, fingerprint, lease)
+		if err != nil {
+			logging.FromContext(ctx).Error("erro ao reservar Idempotency-Key", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "ocorreu um erro desconhecido ao processar a Idempotency-Key",
+			})
+			return
+		}
+
+		if existing != nil {
+			replay(c, existing, fingerprint)
+			return
+		}
+
+		// o cliente pode desistir da requisição no meio; a chave ainda precisa ser concluída ou liberada
+		storeCtx := context.WithoutCancel(ctx)
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if err := store.Release(storeCtx, scopedKey); err != nil {
+					logging.FromContext(ctx).Error("erro ao liberar Idempotency-Key", "error", err)
+				}
+				panic(recovered)
+			}
+		}()
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		if writer.Status() >= http.StatusInternalServerError {
+			err = store.Release(storeCtx, scopedKey)
+		} else {
+			err = store.Complete(storeCtx, scopedKey, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes(), ttl)
+		}
+
+		if err != nil {
+			logging.FromContext(ctx).Error("erro ao gravar resposta da Idempotency-Key", "error", err)
+		}
+	}
+}
+
+func replay(c *gin.Context, existing *idempotency.Record, fingerprint string) {
+	if existing.Fingerprint != fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Idempotency-Key já utilizada com outra requisição",
+		})
+		return
+	}
+
+	if !existing.Completed {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "requisição com essa Idempotency-Key ainda está em processamento",
+		})
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+	c.Abort()
+}
+
+func requestFingerprint(method string, route string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(route))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
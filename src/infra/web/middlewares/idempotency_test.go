@@ -0,0 +1,137 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/idempotency"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newIdempotentRouter(calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.POST("/customers", Idempotency(idempotency.NewMemoryStore(), time.Hour, time.Minute), func(c *gin.Context) {
+		*calls++
+		if *calls > 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "cliente já existe no sistema"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	return r
+}
+
+func postWithKey(r *gin.Engine, key string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/customers", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	calls := 0
+	r := newIdempotentRouter(&calls)
+	body := `{"name":"John Doe","cpf":"12345678900","email":"john@example.com"}`
+
+	first := postWithKey(r, "kiosk-1-abc", body)
+	second := postWithKey(r, "kiosk-1-abc", body)
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.JSONEq(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_DifferentBodyReturns422(t *testing.T) {
+	calls := 0
+	r := newIdempotentRouter(&calls)
+
+	postWithKey(r, "kiosk-1-abc", `{"name":"John Doe"}`)
+	w := postWithKey(r, "kiosk-1-abc", `{"name":"Jane Doe"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_WithoutKey(t *testing.T) {
+	calls := 0
+	r := newIdempotentRouter(&calls)
+
+	postWithKey(r, "", `{"name":"John Doe"}`)
+	w := postWithKey(r, "", `{"name":"John Doe"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	calls := 0
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.POST("/customers", Idempotency(idempotency.NewMemoryStore(), time.Hour, time.Minute), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("nil pointer")
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	first := postWithKey(r, "kiosk-1-abc", `{"name":"John Doe"}`)
+	second := postWithKey(r, "kiosk-1-abc", `{"name":"John Doe"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_CompletesAfterClientCancels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	store := idempotency.NewMemoryStore()
+	r := gin.New()
+	r.POST("/customers", Idempotency(store, time.Hour, time.Minute), func(c *gin.Context) {
+		// o cliente desiste depois que o cadastro foi gravado
+		cancel()
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/customers", bytes.NewBufferString(`{"name":"John Doe"}`))
+	req.Header.Set(IdempotencyKeyHeader, "kiosk-1-abc")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	retry := postWithKey(r, "kiosk-1-abc", `{"name":"John Doe"}`)
+
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_BodyTooLarge(t *testing.T) {
+	calls := 0
+	r := newIdempotentRouter(&calls)
+
+	w := postWithKey(r, "kiosk-1-abc", `{"name":"`+strings.Repeat("a", maxIdempotentRequestBytes)+`"}`)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Zero(t, calls)
+}
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog substitui o logger padrão do gin. Registra apenas o path, sem a query string,
+// e passa pelo mascaramento do logging como qualquer outra linha.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		logging.FromContext(c.Request.Context()).LogAttrs(c.Request.Context(), level, "requisição HTTP",
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		)
+	}
+}
+
+// Recovery registra o panic no log estruturado sem despejar os headers da requisição
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		logging.FromContext(c.Request.Context()).Error("panic ao processar requisição",
+			"error", fmt.Sprint(recovered),
+			"path", c.Request.URL.Path,
+		)
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics registra as métricas RED (taxa, erros e duração) de cada rota.
+// O rótulo usa o padrão da rota (c.FullPath) para não explodir a cardinalidade.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		method := c.Request.Method
+		metrics.HTTPRequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,198 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+const DeviceIDHeader = "X-Device-ID"
+
+// maxSessionBody é o maior corpo aceito na emissão de sessão. O corpo inteiro é lido para achar o
+// documento: um corpo que não coubesse ou não fosse JSON passaria pelo handler sem o limite por CPF.
+const maxSessionBody = 4 << 10
+
+var (
+	errSessionBodyTooLarge = errors.New("corpo da requisição maior que o permitido")
+	errSessionBodyInvalid  = errors.New("corpo da requisição não é um JSON válido")
+)
+
+var deviceIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{8,128}$`)
+
+// SessionRateLimit aplica o SessionGuard às rotas de emissão de sessão. O CPF vem da query (v1)
+// ou do corpo JSON (v2), onde também pode vir outro documento ou o telefone, limitados da mesma
+// forma. Responde 429 quando um limite estoura ou o chamador está bloqueado e atrasa a resposta de
+// quem acumula buscas por CPFs não cadastrados. Corpo grande demais responde 413 e corpo que não é
+// JSON responde 400, para ninguém escapar do limite por CPF.
+// Com o store fora do ar a requisição segue: o limite não derruba o login.
+func SessionRateLimit(guard *ratelimit.SessionGuard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		document, err := sessionDocument(c)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, errSessionBodyTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+
+			c.AbortWithStatusJSON(status, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		subject := ratelimit.Subject{
+			IP:     c.ClientIP(),
+			Device: deviceID(c),
+			CPF:    document,
+		}
+
+		decision, err := guard.Check(ctx, subject)
+		if err != nil {
+			slog.WarnContext(ctx, "Erro ao consultar o rate limit; seguindo sem limite", "error", err)
+			c.Next()
+			return
+		}
+
+		setRateLimitHeaders(c, decision.Result)
+
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(decision.Result.RetryAfter)))
+
+			message := "muitas tentativas; aguarde para tentar de novo"
+			if decision.Locked {
+				message = "muitas tentativas sem sucesso; acesso bloqueado temporariamente"
+			}
+
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": message,
+			})
+			return
+		}
+
+		if decision.Delay > 0 {
+			timer := time.NewTimer(decision.Delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				c.Abort()
+				return
+			}
+		}
+
+		ctx, outcome := ratelimit.WithOutcome(ctx)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if outcome.Miss {
+			// o contexto da requisição pode já ter sido cancelado pelo cliente
+			if err := guard.RecordMiss(context.WithoutCancel(ctx), subject); err != nil {
+				slog.WarnContext(ctx, "Erro ao registrar tentativa sem sucesso no rate limit", "error", err)
+			}
+		}
+	}
+}
+
+// deviceID ignora identificadores fora do formato para não criar chaves arbitrárias no store
+func deviceID(c *gin.Context) string {
+	id := c.GetHeader(DeviceIDHeader)
+	if !deviceIDPattern.MatchString(id) {
+		return ""
+	}
+
+	return id
+}
+
+// sessionDocument devolve o CPF ou, para os demais documentos, tipo, país e número normalizados,
+// para que variar a pontuação ou as maiúsculas não escape do limite. O telefone entra em E.164.
+// O corpo lido volta para o handler; vazio fica para o handler recusar.
+func sessionDocument(c *gin.Context) (string, error) {
+	if cpf := c.Query("cpf"); cpf != "" {
+		return cpf, nil
+	}
+
+	if c.Request.Body == nil || c.Request.Method == http.MethodGet {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSessionBody+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxSessionBody {
+		return "", errSessionBodyTooLarge
+	}
+	c.Request.Body = readCloser{bytes.NewReader(body), c.Request.Body}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return "", nil
+	}
+
+	var request struct {
+		CPF      string `json:"cpf"`
+		Document *struct {
+			Type    string `json:"type"`
+			Number  string `json:"number"`
+			Country string `json:"country"`
+		} `json:"document"`
+		Phone string `json:"phone"`
+	}
+	if json.Unmarshal(body, &request) != nil {
+		return "", errSessionBodyInvalid
+	}
+
+	if request.CPF == "" && request.Document != nil {
+		document := entities.IdentityDocument{
+			Type:    entities.DocumentType(strings.ToLower(request.Document.Type)),
+			Number:  entities.NormalizeDocument(request.Document.Number),
+			Country: strings.ToUpper(request.Document.Country),
+		}
+
+		// o CPF no objeto conta no mesmo limite do CPF informado no campo cpf
+		if document.Type == entities.DocumentCPF {
+			return document.Number, nil
+		}
+
+		return document.Key(), nil
+	}
+
+	if request.CPF == "" && request.Phone != "" {
+		if phone, err := entities.NormalizePhone(request.Phone); err == nil {
+			return phone, nil
+		}
+		return request.Phone, nil
+	}
+
+	return request.CPF, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func setRateLimitHeaders(c *gin.Context, result ratelimit.Result) {
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
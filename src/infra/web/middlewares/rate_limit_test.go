@@ -0,0 +1,201 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	"github.com/CAVAh/api-tech-challenge/src/infra/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type singleKeyStore struct {
+	keys []encryption.WrappedKey
+}
+
+func (s *singleKeyStore) ListKeys(context.Context) ([]encryption.WrappedKey, error) {
+	return s.keys, nil
+}
+
+func (s *singleKeyStore) SaveKey(_ context.Context, key encryption.WrappedKey) error {
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *singleKeyStore) ActivateKey(_ context.Context, id string) error {
+	for i := range s.keys {
+		s.keys[i].Active = s.keys[i].ID == id
+	}
+	return nil
+}
+
+func newTestKeyring(t *testing.T) *encryption.Keyring {
+	key := sha256.Sum256([]byte("kek"))
+	kek, err := encryption.ParseLocalKeys("kek:" + base64.StdEncoding.EncodeToString(key[:]))
+	require.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(context.Background(), &singleKeyStore{}, kek, []byte(strings.Repeat("k", 32)), 0)
+	require.NoError(t, err)
+
+	return keyring
+}
+
+func newRateLimitedRouter(t *testing.T, known map[string]bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	guard := &ratelimit.SessionGuard{
+		Store:   ratelimit.NewMemoryStore(1000),
+		Keyring: newTestKeyring(t),
+		Config: ratelimit.SessionGuardConfig{
+			IPLimit:            ratelimit.PerMinute(100),
+			DeviceLimit:        ratelimit.PerMinute(100),
+			CPFLimit:           ratelimit.PerMinute(2),
+			LockoutAfter:       3,
+			LockoutMissPercent: 50,
+			MissWindow:         time.Minute,
+			LockoutDuration:    time.Minute,
+		},
+	}
+
+	r := gin.New()
+	r.POST("/v2/sessions", SessionRateLimit(guard), func(c *gin.Context) {
+		var body struct {
+			CPF string `json:"cpf"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !known[body.CPF] {
+			ratelimit.ReportMiss(c.Request.Context())
+		}
+		c.JSON(http.StatusCreated, gin.H{"cpf": body.CPF})
+	})
+
+	return r
+}
+
+func postSession(r *gin.Engine, cpf string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/v2/sessions", bytes.NewBufferString(`{"cpf":"`+cpf+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestSessionRateLimit_LimitsPerCPFFromBody(t *testing.T) {
+	r := newRateLimitedRouter(t, map[string]bool{"12345678900": true})
+
+	first := postSession(r, "12345678900")
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.JSONEq(t, `{"cpf":"12345678900"}`, first.Body.String())
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+
+	postSession(r, "12345678900")
+	third := postSession(r, "12345678900")
+
+	assert.Equal(t, http.StatusTooManyRequests, third.Code)
+	assert.Equal(t, "30", third.Header().Get("Retry-After"))
+	assert.Equal(t, "0", third.Header().Get("RateLimit-Remaining"))
+}
+
+func TestSessionRateLimit_LocksOutAfterMisses(t *testing.T) {
+	r := newRateLimitedRouter(t, map[string]bool{"12345678900": true})
+
+	for _, cpf := range []string{"00000000001", "00000000002", "00000000003"} {
+		assert.Equal(t, http.StatusCreated, postSession(r, cpf).Code)
+	}
+
+	locked := postSession(r, "12345678900")
+	assert.Equal(t, http.StatusTooManyRequests, locked.Code)
+	assert.Equal(t, "60", locked.Header().Get("Retry-After"))
+}
+
+func TestSessionRateLimit_DoesNotLockOutMostlySuccessfulLookups(t *testing.T) {
+	known := map[string]bool{}
+	for _, cpf := range []string{"00000000011", "00000000012", "00000000013", "00000000014", "00000000015", "00000000016", "00000000017"} {
+		known[cpf] = true
+	}
+	r := newRateLimitedRouter(t, known)
+
+	for cpf := range known {
+		assert.Equal(t, http.StatusCreated, postSession(r, cpf).Code)
+	}
+	for _, cpf := range []string{"00000000001", "00000000002", "00000000003"} {
+		assert.Equal(t, http.StatusCreated, postSession(r, cpf).Code)
+	}
+
+	assert.Equal(t, http.StatusCreated, postSession(r, "00000000011").Code)
+}
+
+func TestSessionRateLimit_LimitsPerDocumentIgnoringFormatting(t *testing.T) {
+	r := newRateLimitedRouter(t, map[string]bool{})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/v2/sessions", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, post(`{"document":{"type":"passport","number":"ab123456","country":"fr"}}`).Code)
+	assert.Equal(t, http.StatusCreated, post(`{"document":{"type":"PASSPORT","number":"AB 123.456","country":"FR"}}`).Code)
+
+	third := post(`{"document":{"type":"passport","number":"AB123456","country":"FR"}}`)
+	assert.Equal(t, http.StatusTooManyRequests, third.Code)
+
+	// o mesmo número em outro país é outro documento
+	assert.Equal(t, http.StatusCreated, post(`{"document":{"type":"passport","number":"AB123456","country":"PT"}}`).Code)
+}
+
+func TestSessionRateLimit_LimitsPerPhoneIgnoringFormatting(t *testing.T) {
+	r := newRateLimitedRouter(t, map[string]bool{})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/v2/sessions", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, post(`{"phone":"(11) 98765-4321"}`).Code)
+	assert.Equal(t, http.StatusCreated, post(`{"phone":"+55 11 98765-4321"}`).Code)
+	assert.Equal(t, http.StatusTooManyRequests, post(`{"phone":"11987654321"}`).Code)
+	assert.Equal(t, http.StatusCreated, post(`{"phone":"21987654321"}`).Code)
+}
+
+func TestSessionRateLimit_RejectsBodiesItCannotRead(t *testing.T) {
+	r := newRateLimitedRouter(t, map[string]bool{})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/v2/sessions", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// o CPF depois do preenchimento escaparia do limite por CPF se o corpo fosse lido só em parte
+	padded := `{"padding":"` + strings.Repeat("x", maxSessionBody) + `","cpf":"12345678900"}`
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(padded).Code)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"cpf":"12345678900"`).Code)
+}
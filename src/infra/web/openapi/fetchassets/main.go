@@ -0,0 +1,145 @@
+// fetchassets baixa do registro do npm os arquivos do Swagger UI e do Redoc servidos em /docs,
+// nas versões fixadas abaixo, e os grava em ../assets para o go:embed. O pacote baixado é conferido
+// com o hash de integridade (SHA-512) que o registro publica para a versão.
+//
+// Uso: go generate ./src/infra/web/openapi
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const registry = "https://registry.npmjs.org"
+
+type npmPackage struct {
+	Name    string
+	Version string
+	// Files mapeia o caminho dentro do pacote para o nome gravado em assets
+	Files map[string]string
+}
+
+var packages = []npmPackage{
+	{
+		Name:    "swagger-ui-dist",
+		Version: "5.17.14",
+		Files: map[string]string{
+			"package/swagger-ui.css":       "swagger-ui.css",
+			"package/swagger-ui-bundle.js": "swagger-ui-bundle.js",
+		},
+	},
+	{
+		Name:    "redoc",
+		Version: "2.1.5",
+		Files: map[string]string{
+			"package/bundles/redoc.standalone.js": "redoc.standalone.js",
+		},
+	},
+}
+
+var client = &http.Client{Timeout: time.Minute}
+
+func main() {
+	dir := "assets"
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+
+	for _, pkg := range packages {
+		if err := fetch(pkg, dir); err != nil {
+			log.Fatalf("erro ao baixar %s@%s: %v", pkg.Name, pkg.Version, err)
+		}
+	}
+}
+
+func fetch(pkg npmPackage, dir string) error {
+	var metadata struct {
+		Dist struct {
+			Tarball   string `json:"tarball"`
+			Integrity string `json:"integrity"`
+		} `json:"dist"`
+	}
+
+	body, err := get(fmt.Sprintf("%s/%s/%s", registry, pkg.Name, pkg.Version))
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return err
+	}
+
+	tarball, err := get(metadata.Dist.Tarball)
+	if err != nil {
+		return err
+	}
+
+	digest := sha512.Sum512(tarball)
+	if "sha512-"+base64.StdEncoding.EncodeToString(digest[:]) != metadata.Dist.Integrity {
+		return errors.New("o hash do pacote não confere com a integridade publicada")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(tarball))
+	if err != nil {
+		return err
+	}
+
+	found := 0
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name, ok := pkg.Files[header.Name]
+		if !ok {
+			continue
+		}
+
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			return err
+		}
+		found++
+	}
+
+	if found != len(pkg.Files) {
+		return fmt.Errorf("pacote sem os arquivos esperados (%d de %d)", found, len(pkg.Files))
+	}
+
+	return nil
+}
+
+func get(url string) ([]byte, error) {
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, response.Status)
+	}
+
+	return io.ReadAll(response.Body)
+}
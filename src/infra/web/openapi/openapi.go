@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"net/http"
+	"path"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+// Spec é a especificação OpenAPI do serviço. O teste de rotas garante que ela
+// cobre todas as rotas do gin e que os schemas batem com os DTOs.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// Load interpreta e valida a especificação embutida
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func ServeSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", Spec)
+}
+
+// Assets são os arquivos do Swagger UI e do Redoc, servidos pelo próprio serviço em /docs/assets para que
+// as páginas de documentação não carreguem scripts de CDNs. Os arquivos de terceiros são baixados pelo
+// go generate nas versões fixadas em fetchassets, com o hash de integridade conferido.
+//
+//go:generate go run ./fetchassets assets
+//go:embed assets
+var Assets embed.FS
+
+// ServeAsset serve os arquivos de Assets pela rota /docs/assets/*filepath
+func ServeAsset(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.FileFromFS(path.Join("assets", c.Param("filepath")), http.FS(Assets))
+}
+
+func ServeSwaggerUI(c *gin.Context) {
+	servePage(c, swaggerUIPage, "swagger-ui-bundle.js", "swagger-ui.css")
+}
+
+func ServeRedoc(c *gin.Context) {
+	servePage(c, redocPage, "redoc.standalone.js")
+}
+
+// servePage responde 503 quando o binário foi gerado sem rodar o go generate
+func servePage(c *gin.Context, page string, assets ...string) {
+	for _, asset := range assets {
+		if _, err := fs.Stat(Assets, path.Join("assets", asset)); err != nil {
+			c.String(http.StatusServiceUnavailable, "documentação indisponível: gere os arquivos com go generate ./src/infra/web/openapi")
+			return
+		}
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+  <meta charset="utf-8">
+  <title>customer-service - Swagger UI</title>
+  <link rel="stylesheet" href="/docs/assets/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="/docs/assets/swagger-ui-bundle.js"></script>
+  <script src="/docs/assets/swagger-init.js"></script>
+</body>
+</html>`
+
+const redocPage = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+  <meta charset="utf-8">
+  <title>customer-service - Redoc</title>
+</head>
+<body>
+  <redoc spec-url="/openapi.json"></redoc>
+  <script src="/docs/assets/redoc.standalone.js"></script>
+</body>
+</html>`
@@ -0,0 +1,33 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocsPagesLoadOnlyLocalAssets(t *testing.T) {
+	for name, page := range map[string]string{"swagger": swaggerUIPage, "redoc": redocPage} {
+		assert.NotContains(t, page, "https://", name)
+		assert.NotContains(t, page, "http://", name)
+	}
+}
+
+func TestServeAsset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/docs/assets/*filepath", ServeAsset)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/assets/swagger-init.js", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "SwaggerUIBundle")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/assets/../openapi.json", nil))
+	assert.NotEqual(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// o arquivo da importação é validado linha a linha pelo caso de uso; o decoder padrão do CSV falharia
+// na primeira linha malformada, antes do relatório, e o NDJSON não tem decoder. O registro é global
+// no openapi3filter, então é feito uma vez só.
+func init() {
+	openapi3filter.RegisterBodyDecoder("text/csv", openapi3filter.FileBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.FileBodyDecoder)
+}
+
+// Validator valida requisições e respostas contra a especificação.
+// Requisições fora do contrato recebem 400 e corpos acima de maxBodyBytes, que o validador precisa
+// ler inteiros, recebem 413; respostas fora do contrato são registradas no log, já que o corpo já
+// foi enviado. Deve ser usado apenas fora de produção.
+func Validator(doc *openapi3.T, maxBodyBytes int64) (gin.HandlerFunc, error) {
+	// Sem servers o roteador aceita qualquer host; o contrato só depende do path
+	routingDoc := *doc
+	routingDoc.Servers = nil
+
+	router, err := gorillamux.NewRouter(&routingDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		// A autenticação é verificada pelos middlewares de cada rota
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			// Rotas operacionais (health, métricas, documentação) não fazem parte do contrato
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		var body []byte
+		if c.Request.Body != nil {
+			body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+					"error": "corpo da requisição acima do limite",
+				})
+				return
+			}
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": "não foi possível ler o corpo da requisição",
+				})
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		requestInput := &openapi3filter.RequestValidationInput{
+			Request:    c.Request.Clone(ctx),
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		requestInput.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err := openapi3filter.ValidateRequest(ctx, requestInput); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: requestInput,
+			Status:                 writer.Status(),
+			Header:                 writer.Header(),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+		}
+		responseInput.SetBodyBytes(writer.body.Bytes())
+
+		if err := openapi3filter.ValidateResponse(ctx, responseInput); err != nil {
+			logging.FromContext(ctx).Error("resposta fora da especificação OpenAPI",
+				"route", route.Path,
+				"method", route.Method,
+				"status", writer.Status(),
+				"error", err,
+			)
+		}
+	}, nil
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMaxBodyBytes = 1 << 10
+
+func newValidatedRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	doc, err := Load()
+	require.NoError(t, err)
+
+	validator, err := Validator(doc, testMaxBodyBytes)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(validator)
+	r.POST("/v2/sessions", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"token": "abc", "identified": false, "expiresAt": "2026-10-20T00:00:00Z"})
+	})
+
+	r.POST("/v2/customers:method", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"customers": []gin.H{}, "missingIds": []uint{1}})
+	})
+
+	r.POST("/admin/customers:method", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"summary":       gin.H{"dryRun": true, "rows": 1, "created": 0, "updated": 0, "skipped": 0, "failed": 1},
+			"rows":          []gin.H{{"line": 2, "outcome": "failed", "error": "linha malformada"}},
+			"rowsTruncated": false,
+		})
+	})
+
+	return r
+}
+
+func TestValidator_RejectsRequestOutsideSpec(t *testing.T) {
+	r := newValidatedRouter(t)
+
+	req, _ := http.NewRequest(http.MethodPost, "/v2/sessions", bytes.NewBufferString(`{"cpf":"abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"error"`)
+}
+
+func TestValidator_AcceptsRequestInSpec(t *testing.T) {
+	r := newValidatedRouter(t)
+
+	req, _ := http.NewRequest(http.MethodPost, "/v2/sessions", bytes.NewBufferString(`{"cpf":"12345678900"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestValidator_CustomMethodRoute(t *testing.T) {
+	r := newValidatedRouter(t)
+
+	req, _ := http.NewRequest(http.MethodPost, "/v2/customers:batchGet", bytes.NewBufferString(`{"ids":["abc"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestValidator_ImportUploadWithMalformedRow(t *testing.T) {
+	r := newValidatedRouter(t)
+
+	// a linha com aspas abertas é problema do relatório da importação, não do contrato
+	req, _ := http.NewRequest(http.MethodPost, "/admin/customers:import?dryRun=true", bytes.NewBufferString("name,cpf,email\n\"João,123\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestValidator_RejectsBodyOverLimit(t *testing.T) {
+	r := newValidatedRouter(t)
+
+	body := "name,cpf,email\n" + strings.Repeat("João da Silva,12345678900,joao@gmail.com\n", testMaxBodyBytes/32)
+	req, _ := http.NewRequest(http.MethodPost, "/admin/customers:import?dryRun=true", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
@@ -1,35 +1,611 @@
 package routes
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
+	addresscontrollers "github.com/CAVAh/api-tech-challenge/src/adapters/controllers/address"
+	auditcontrollers "github.com/CAVAh/api-tech-challenge/src/adapters/controllers/audit"
 	controllers "github.com/CAVAh/api-tech-challenge/src/adapters/controllers/customer"
+	duplicatecontrollers "github.com/CAVAh/api-tech-challenge/src/adapters/controllers/duplicates"
+	fiscalcontrollers "github.com/CAVAh/api-tech-challenge/src/adapters/controllers/fiscal"
+	loyaltycontrollers "github.com/CAVAh/api-tech-challenge/src/adapters/controllers/loyalty"
+	phonecontrollers "github.com/CAVAh/api-tech-challenge/src/adapters/controllers/phone"
+	preferencescontrollers "github.com/CAVAh/api-tech-challenge/src/adapters/controllers/preferences"
+	webhookcontrollers "github.com/CAVAh/api-tech-challenge/src/adapters/controllers/webhook"
+	"github.com/CAVAh/api-tech-challenge/src/adapters/eventhandlers"
+	"github.com/CAVAh/api-tech-challenge/src/adapters/gateways"
+	"github.com/CAVAh/api-tech-challenge/src/adapters/grpchandlers"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	addressusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/address"
+	auditusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/audit"
 	usecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/customer"
+	duplicateusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/duplicates"
+	fiscalusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/fiscal"
+	loyaltyusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/loyalty"
+	phoneusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/phone"
+	preferencesusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/preferences"
+	webhookusecases "github.com/CAVAh/api-tech-challenge/src/core/domain/usecases/webhook"
+	"github.com/CAVAh/api-tech-challenge/src/infra/auth"
+	"github.com/CAVAh/api-tech-challenge/src/infra/cache"
+	"github.com/CAVAh/api-tech-challenge/src/infra/cep"
 	"github.com/CAVAh/api-tech-challenge/src/infra/db/database"
 	"github.com/CAVAh/api-tech-challenge/src/infra/db/repositories"
+	"github.com/CAVAh/api-tech-challenge/src/infra/email"
+	"github.com/CAVAh/api-tech-challenge/src/infra/encryption"
+	grpcserver "github.com/CAVAh/api-tech-challenge/src/infra/grpc"
+	"github.com/CAVAh/api-tech-challenge/src/infra/idempotency"
+	"github.com/CAVAh/api-tech-challenge/src/infra/logging"
+	"github.com/CAVAh/api-tech-challenge/src/infra/loyalty"
+	"github.com/CAVAh/api-tech-challenge/src/infra/messaging"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"github.com/CAVAh/api-tech-challenge/src/infra/outbox"
+	"github.com/CAVAh/api-tech-challenge/src/infra/ratelimit"
+	"github.com/CAVAh/api-tech-challenge/src/infra/sms"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/health"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/middlewares"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/openapi"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/server"
+	"github.com/CAVAh/api-tech-challenge/src/infra/webhooks"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func HandleRequests() {
-	router := gin.Default()
+// v1DeprecatedAt é a data em que a v2 foi publicada e a v1 passou a ser descontinuada
+var v1DeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+func HandleRequests() error {
+	readiness := health.NewReadiness()
+	customerRepository := NewCustomerRepository()
+	redirects := &repositories.CustomerRedirectRepository{DB: database.DB}
+	idempotencyStore := idempotency.NewStoreFromEnv(database.DB)
+	router := NewRouter(readiness, customerRepository, idempotencyStore)
+	grpcCreds, err := grpcserver.TransportCredentialsFromEnv()
+	if err != nil {
+		return err
+	}
+	grpcServer := grpcserver.NewServer(newCustomerGRPCService(customerRepository, redirects), auth.ParseAPIKeys(os.Getenv("SERVICE_API_KEYS")), grpcCreds)
+	runnables := []server.Runnable{
+		grpcServer,
+		idempotency.NewPurger(idempotencyStore, utils.GetEnvDuration("IDEMPOTENCY_PURGE_INTERVAL", time.Hour)),
+		outbox.NewPurger(database.DB, utils.GetEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour), time.Hour),
+	}
+
+	publisher, err := messaging.NewPublisherFromEnv(context.Background())
+	if err != nil {
+		return err
+	}
+
+	var publishers messaging.FanoutPublisher
+	if publisher != nil {
+		publishers = append(publishers, publisher)
+	}
+
+	if utils.GetEnvBool("WEBHOOKS_ENABLED", false) {
+		webhookRepository := &repositories.WebhookRepository{DB: database.DB}
+		publishers = append(publishers, &webhooks.Enqueuer{Store: webhookRepository})
+		runnables = append(runnables,
+			webhooks.NewDispatcher(webhookRepository, webhooks.LoadDispatcherConfig()),
+			webhooks.NewPurger(webhookRepository, utils.GetEnvDuration("WEBHOOK_DELIVERY_RETENTION", 30*24*time.Hour), time.Hour),
+		)
+	}
+
+	loyaltyRunnables, err := newLoyaltyRunnables(customerRepository, redirects)
+	if err != nil {
+		return err
+	}
+	runnables = append(runnables, loyaltyRunnables...)
+
+	if len(publishers) > 0 {
+		runnables = append(runnables, outbox.NewRelay(
+			database.DB,
+			publishers,
+			utils.GetEnvInt("OUTBOX_BATCH_SIZE", 100),
+			utils.GetEnvDuration("OUTBOX_RELAY_INTERVAL", time.Second),
+		))
+	} else {
+		slog.Warn("EVENT_PUBLISHER e WEBHOOKS_ENABLED não configurados; eventos ficarão no outbox sem publicação")
+	}
+
+	return server.Run(router, readiness, server.LoadConfig(), runnables...)
+}
+
+// NewCustomerRepository monta o repositório de clientes com o cache read-through.
+// A mesma instância deve ser usada pelo HTTP e pelo gRPC para que as invalidações valham para os dois;
+// os comandos que alteram clientes também passam por ele para invalidar o cache compartilhado.
+func NewCustomerRepository() gateways.CustomerRepository {
 	customerRepository := &repositories.CustomerRepository{
 		DB: database.DB,
 	}
-	listUsecase := &usecases.ListCustomerUsecase{CustomerRepository: customerRepository}
-	createUsecase := &usecases.CreateCustomerUsecase{CustomerRepository: customerRepository}
 
-	router.GET("/customers", func(c *gin.Context) {
-		controllers.ListCustomers(c, listUsecase)
+	if !utils.GetEnvBool("CUSTOMER_CACHE_ENABLED", true) {
+		return customerRepository
+	}
+
+	return cache.NewCustomerRepository(
+		customerRepository,
+		cache.NewStoreFromEnv(),
+		encryption.Default(),
+		utils.GetEnvDuration("CUSTOMER_CACHE_TTL", 5*time.Minute),
+		utils.GetEnvDuration("CUSTOMER_CACHE_NEGATIVE_TTL", 30*time.Second),
+	)
+}
+
+// newLoyaltyRunnables monta a expiração de pontos e, com ORDER_EVENTS_QUEUE_URL, o consumidor
+// dos pedidos pagos que credita os pontos
+func newLoyaltyRunnables(customerRepository gateways.CustomerRepository, redirects gateways.CustomerRedirects) ([]server.Runnable, error) {
+	policy := loyaltyPolicy()
+	ledger := &repositories.LoyaltyRepository{DB: database.DB}
+
+	var runnables []server.Runnable
+
+	if policy.Expiry != loyaltyusecases.ExpiryNever {
+		runnables = append(runnables, loyalty.NewExpirer(
+			&loyaltyusecases.ExpirePointsUsecase{Ledger: ledger, Policy: policy, Metrics: metrics.Recorder{}, BatchSize: utils.GetEnvInt("LOYALTY_EXPIRY_BATCH_SIZE", 500), Tracer: tracing.UsecaseTracer{}},
+			utils.GetEnvDuration("LOYALTY_EXPIRY_INTERVAL", time.Hour),
+		))
+	}
+
+	if queueURL := os.Getenv("ORDER_EVENTS_QUEUE_URL"); queueURL != "" {
+		handler := &eventhandlers.OrderPaidHandler{
+			EarnUsecase: &loyaltyusecases.EarnPointsUsecase{CustomerRepository: customerRepository, Redirects: redirects, Ledger: ledger, Policy: policy, Metrics: metrics.Recorder{}, Tracer: tracing.UsecaseTracer{}},
+		}
+
+		consumer, err := messaging.NewSQSConsumer(context.Background(), queueURL, os.Getenv("SQS_ENDPOINT"), handler.Handle)
+		if err != nil {
+			return nil, err
+		}
+		runnables = append(runnables, consumer)
+	}
+
+	return runnables, nil
+}
+
+func loyaltyPolicy() loyaltyusecases.LoyaltyPolicy {
+	policy, err := loyaltyusecases.LoadLoyaltyPolicy()
+	if err != nil {
+		logging.Fatal("Configuração do programa de fidelidade inválida", err)
+	}
+
+	return policy
+}
+
+func newCEPProvider() gateways.CEPProvider {
+	provider, err := cep.NewProviderFromEnv()
+	if err != nil {
+		logging.Fatal("Configuração do provedor de CEP inválida", err)
+	}
+
+	return provider
+}
+
+// newSMSSender monta o envio dos códigos por SMS. O provedor de log entregaria o código a quem lê
+// os logs, então em produção ele deixa o SMS desligado (nil): a verificação do telefone responde 503
+// e o código da sessão vai por email, sem derrubar o resto da API.
+func newSMSSender() gateways.SMSSender {
+	sender, err := sms.NewSenderFromEnv()
+	if err != nil {
+		logging.Fatal("Configuração do provedor de SMS inválida", err)
+	}
+
+	if _, ok := sender.(*sms.LogSender); ok && gin.Mode() == gin.ReleaseMode {
+		slog.Warn("SMS_PROVIDER=log não é usado em produção; envio de SMS desligado")
+		return nil
+	}
+
+	return sender
+}
+
+// newEmailSender monta o envio do código de acesso por email. O provedor de log entregaria o código
+// a quem lê os logs, então não é aceito em produção.
+func newEmailSender() gateways.EmailSender {
+	sender, err := email.NewSenderFromEnv()
+	if err != nil {
+		logging.Fatal("Configuração do provedor de email inválida", err)
+	}
+
+	if _, ok := sender.(*email.LogSender); ok && gin.Mode() == gin.ReleaseMode {
+		logging.Fatal("Configuração do provedor de email inválida", errors.New("EMAIL_PROVIDER=log não é permitido em produção"))
+	}
+
+	return sender
+}
+
+func newCustomerGRPCService(customerRepository gateways.CustomerRepository, redirects gateways.CustomerRedirects) *grpchandlers.CustomerService {
+	auditLog := &repositories.AuditRepository{DB: database.DB}
+
+	return &grpchandlers.CustomerService{
+		GetUsecase:      &usecases.GetCustomerUsecase{CustomerRepository: customerRepository, AuditLog: auditLog, Redirects: redirects, Tracer: tracing.UsecaseTracer{}},
+		BatchGetUsecase: &usecases.BatchGetCustomersUsecase{CustomerRepository: customerRepository, AuditLog: auditLog, Redirects: redirects, MaxIDs: utils.GetEnvInt("BATCH_GET_MAX_IDS", usecases.DefaultBatchGetMaxIDs), Tracer: tracing.UsecaseTracer{}},
+		SessionUsecase:  newSessionUsecase(customerRepository, auditLog),
+		VerifyUsecase:   &usecases.VerifyTokenUsecase{CustomerRepository: customerRepository, Redirects: redirects, Tracer: tracing.UsecaseTracer{}},
+	}
+}
+
+// newSessionUsecase monta a emissão de sessão do HTTP e do gRPC. SESSION_TOKEN_PREFERENCES_HASH=true
+// inclui no token o hash das preferências alimentares do cliente.
+func newSessionUsecase(customerRepository gateways.CustomerRepository, auditLog gateways.AuditLog) *usecases.CreateSessionUsecase {
+	return &usecases.CreateSessionUsecase{
+		CustomerRepository:    customerRepository,
+		PreferencesRepository: &repositories.PreferencesRepository{DB: database.DB},
+		AuditLog:              auditLog,
+		Metrics:               metrics.Recorder{},
+		EmbedPreferencesHash:  utils.GetEnvBool("SESSION_TOKEN_PREFERENCES_HASH", false),
+		LookupMisses:          ratelimit.MissReporter{},
+		Tracer:                tracing.UsecaseTracer{},
+	}
+}
+
+func NewRouter(readiness *health.Readiness, customerRepository gateways.CustomerRepository, idempotencyStore idempotency.Store) *gin.Engine {
+	router := gin.New()
+
+	// sem proxies confiáveis o IP do cliente é o da conexão; X-Forwarded-For de qualquer um burlaria o rate limit
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		logging.Fatal("TRUSTED_PROXIES inválido", err)
+	}
+
+	router.Use(
+		middlewares.Tracing(),
+		middlewares.RequestID(),
+		middlewares.AuditContext(),
+		middlewares.AccessLog(),
+		middlewares.Recovery(),
+		middlewares.Metrics(),
+	)
+
+	// o maior corpo aceito é o da importação; o validador lê o corpo inteiro e não pode cortá-la antes
+	maxUploadBytes := int64(utils.GetEnvInt("IMPORT_MAX_UPLOAD_MB", 100)) << 20
+
+	if utils.GetEnvBool("OPENAPI_VALIDATION", gin.Mode() != gin.ReleaseMode) {
+		router.Use(openAPIValidator(maxUploadBytes))
+	}
+
+	auditLog := &repositories.AuditRepository{DB: database.DB}
+	redirects := &repositories.CustomerRedirectRepository{DB: database.DB}
+
+	listUsecase := &usecases.ListCustomerUsecase{CustomerRepository: customerRepository, AuditLog: auditLog, Metrics: metrics.Recorder{}, LookupMisses: ratelimit.MissReporter{}, Tracer: tracing.UsecaseTracer{}}
+	createUsecase := &usecases.CreateCustomerUsecase{CustomerRepository: customerRepository, RequireVerification: utils.GetEnvBool("CUSTOMER_REQUIRE_VERIFICATION", false), Tracer: tracing.UsecaseTracer{}}
+	changeStatusUsecase := &usecases.ChangeCustomerStatusUsecase{CustomerRepository: customerRepository, Tracer: tracing.UsecaseTracer{}}
+	importUsecase := &usecases.ImportCustomersUsecase{Create: createUsecase, BatchSize: utils.GetEnvInt("IMPORT_BATCH_SIZE", usecases.DefaultImportBatchSize), Indexes: repositories.CustomerIndexes{}, Tracer: tracing.UsecaseTracer{}}
+	importUpload := controllers.ImportUpload{
+		MaxBytes:      maxUploadBytes,
+		Timeout:       utils.GetEnvDuration("IMPORT_UPLOAD_TIMEOUT", 30*time.Minute),
+		MaxReportRows: utils.GetEnvInt("IMPORT_MAX_REPORT_ROWS", 1000),
+	}
+	sessionUsecase := newSessionUsecase(customerRepository, auditLog)
+	getUsecase := &usecases.GetCustomerUsecase{CustomerRepository: customerRepository, AuditLog: auditLog, Redirects: redirects, Tracer: tracing.UsecaseTracer{}}
+	batchGetUsecase := &usecases.BatchGetCustomersUsecase{CustomerRepository: customerRepository, AuditLog: auditLog, Redirects: redirects, MaxIDs: utils.GetEnvInt("BATCH_GET_MAX_IDS", usecases.DefaultBatchGetMaxIDs), Tracer: tracing.UsecaseTracer{}}
+	updateUsecase := &usecases.UpdateCustomerUsecase{CustomerRepository: customerRepository, Tracer: tracing.UsecaseTracer{}}
+	eraseUsecase := &usecases.EraseCustomerUsecase{CustomerRepository: customerRepository, Tracer: tracing.UsecaseTracer{}}
+	ledger := &repositories.LoyaltyRepository{DB: database.DB}
+	addressRepository := &repositories.AddressRepository{DB: database.DB}
+	preferencesRepository := &repositories.PreferencesRepository{DB: database.DB}
+	fiscalProfileRepository := &repositories.FiscalProfileRepository{DB: database.DB}
+	exportUsecase := &usecases.ExportCustomerDataUsecase{CustomerRepository: customerRepository, AddressRepository: addressRepository, PreferencesRepository: preferencesRepository, FiscalProfileRepository: fiscalProfileRepository, LoyaltyLedger: ledger, AuditLog: auditLog, Tracer: tracing.UsecaseTracer{}}
+	listChangesUsecase := &usecases.ListCustomerChangesUsecase{ChangeFeed: &repositories.CustomerChangeRepository{DB: database.DB}, AuditLog: auditLog, Tracer: tracing.UsecaseTracer{}}
+	listAuditUsecase := &auditusecases.ListAuditEntriesUsecase{AuditLog: auditLog, Tracer: tracing.UsecaseTracer{}}
+
+	duplicateRepository := &repositories.DuplicateRepository{DB: database.DB}
+	listDuplicatesUsecase := &duplicateusecases.ListDuplicatesUsecase{DuplicateRepository: duplicateRepository, CustomerRepository: customerRepository, AuditLog: auditLog, Tracer: tracing.UsecaseTracer{}}
+	dismissDuplicateUsecase := &duplicateusecases.DismissDuplicateUsecase{DuplicateRepository: duplicateRepository, Tracer: tracing.UsecaseTracer{}}
+	maxAddresses := utils.GetEnvInt("ADDRESS_MAX_PER_CUSTOMER", addressusecases.DefaultMaxAddresses)
+	mergeCustomersUsecase := &duplicateusecases.MergeCustomersUsecase{CustomerRepository: customerRepository, MaxAddresses: maxAddresses, Tracer: tracing.UsecaseTracer{}}
+
+	policy := loyaltyPolicy()
+	loyaltyBalanceUsecase := &loyaltyusecases.GetLoyaltyBalanceUsecase{Ledger: ledger, Policy: policy, Tracer: tracing.UsecaseTracer{}}
+	loyaltyTransactionsUsecase := &loyaltyusecases.ListLoyaltyTransactionsUsecase{Ledger: ledger, Tracer: tracing.UsecaseTracer{}}
+	earnUsecase := &loyaltyusecases.EarnPointsUsecase{CustomerRepository: customerRepository, Redirects: redirects, Ledger: ledger, Policy: policy, Metrics: metrics.Recorder{}, Tracer: tracing.UsecaseTracer{}}
+	redeemUsecase := &loyaltyusecases.RedeemPointsUsecase{Redirects: redirects, Ledger: ledger, Policy: policy, Metrics: metrics.Recorder{}, Tracer: tracing.UsecaseTracer{}}
+	adjustUsecase := &loyaltyusecases.AdjustPointsUsecase{CustomerRepository: customerRepository, Ledger: ledger, Policy: policy, Metrics: metrics.Recorder{}, Tracer: tracing.UsecaseTracer{}}
+
+	cepProvider := newCEPProvider()
+	listAddressesUsecase := &addressusecases.ListAddressesUsecase{AddressRepository: addressRepository, Tracer: tracing.UsecaseTracer{}}
+	getAddressUsecase := &addressusecases.GetAddressUsecase{AddressRepository: addressRepository, Tracer: tracing.UsecaseTracer{}}
+	createAddressUsecase := &addressusecases.CreateAddressUsecase{AddressRepository: addressRepository, CEPProvider: cepProvider, MaxAddresses: maxAddresses, Tracer: tracing.UsecaseTracer{}}
+	updateAddressUsecase := &addressusecases.UpdateAddressUsecase{AddressRepository: addressRepository, CEPProvider: cepProvider, Tracer: tracing.UsecaseTracer{}}
+	deleteAddressUsecase := &addressusecases.DeleteAddressUsecase{AddressRepository: addressRepository, Tracer: tracing.UsecaseTracer{}}
+	lookupCEPUsecase := &addressusecases.LookupCEPUsecase{CEPProvider: cepProvider, Tracer: tracing.UsecaseTracer{}}
+
+	getPreferencesUsecase := &preferencesusecases.GetPreferencesUsecase{CustomerRepository: customerRepository, PreferencesRepository: preferencesRepository, AuditLog: auditLog, Redirects: redirects, Tracer: tracing.UsecaseTracer{}}
+	updatePreferencesUsecase := &preferencesusecases.UpdatePreferencesUsecase{PreferencesRepository: preferencesRepository, Tracer: tracing.UsecaseTracer{}}
+
+	getFiscalProfileUsecase := &fiscalusecases.GetFiscalProfileUsecase{FiscalProfileRepository: fiscalProfileRepository, AuditLog: auditLog, Tracer: tracing.UsecaseTracer{}}
+	saveFiscalProfileUsecase := &fiscalusecases.SaveFiscalProfileUsecase{FiscalProfileRepository: fiscalProfileRepository, Tracer: tracing.UsecaseTracer{}}
+	deleteFiscalProfileUsecase := &fiscalusecases.DeleteFiscalProfileUsecase{FiscalProfileRepository: fiscalProfileRepository, Tracer: tracing.UsecaseTracer{}}
+	resolveFiscalProfileUsecase := &fiscalusecases.ResolveFiscalProfileUsecase{GetFiscalProfile: getFiscalProfileUsecase, Tracer: tracing.UsecaseTracer{}}
+
+	smsSender := newSMSSender()
+	sessionVerificationRepository := &repositories.SessionVerificationRepository{DB: database.DB}
+	sendSessionCodeUsecase := &usecases.SendSessionCodeUsecase{
+		CustomerRepository:            customerRepository,
+		SessionVerificationRepository: sessionVerificationRepository,
+		SMSSender:                     smsSender,
+		EmailSender:                   newEmailSender(),
+		CodeTTL:                       utils.GetEnvDuration("SESSION_CODE_TTL", usecases.DefaultSessionCodeTTL),
+		ResendInterval:                utils.GetEnvDuration("SESSION_CODE_RESEND_INTERVAL", usecases.DefaultSessionResendInterval),
+		Tracer:                        tracing.UsecaseTracer{},
+	}
+	verifySessionCodeUsecase := &usecases.VerifySessionCodeUsecase{
+		SessionVerificationRepository: sessionVerificationRepository,
+		Metrics:                       metrics.Recorder{},
+		MaxAttempts:                   utils.GetEnvInt("SESSION_CODE_MAX_ATTEMPTS", usecases.DefaultSessionMaxAttempts),
+		TokenTTL:                      utils.GetEnvDuration("VERIFIED_SESSION_TTL", utils.VerifiedTokenTTL),
+		Tracer:                        tracing.UsecaseTracer{},
+	}
+
+	phoneVerificationRepository := &repositories.PhoneVerificationRepository{DB: database.DB}
+	sendPhoneCodeUsecase := &phoneusecases.SendPhoneCodeUsecase{
+		CustomerRepository:          customerRepository,
+		PhoneVerificationRepository: phoneVerificationRepository,
+		SMSSender:                   smsSender,
+		CodeTTL:                     utils.GetEnvDuration("PHONE_CODE_TTL", phoneusecases.DefaultCodeTTL),
+		ResendInterval:              utils.GetEnvDuration("PHONE_CODE_RESEND_INTERVAL", phoneusecases.DefaultResendInterval),
+		DailySendLimit:              utils.GetEnvInt("PHONE_CODE_DAILY_LIMIT", phoneusecases.DefaultDailySendLimit),
+		Tracer:                      tracing.UsecaseTracer{},
+	}
+	verifyPhoneUsecase := &phoneusecases.VerifyPhoneUsecase{CustomerRepository: customerRepository, PhoneVerificationRepository: phoneVerificationRepository, MaxAttempts: utils.GetEnvInt("PHONE_CODE_MAX_ATTEMPTS", phoneusecases.DefaultMaxAttempts), Tracer: tracing.UsecaseTracer{}}
+
+	webhookRepository := &repositories.WebhookRepository{
+		DB: database.DB,
+	}
+	createSubscriptionUsecase := &webhookusecases.CreateSubscriptionUsecase{WebhookRepository: webhookRepository, AllowInsecureURL: gin.Mode() != gin.ReleaseMode, Tracer: tracing.UsecaseTracer{}}
+	listSubscriptionsUsecase := &webhookusecases.ListSubscriptionsUsecase{WebhookRepository: webhookRepository, Tracer: tracing.UsecaseTracer{}}
+	deleteSubscriptionUsecase := &webhookusecases.DeleteSubscriptionUsecase{WebhookRepository: webhookRepository, Tracer: tracing.UsecaseTracer{}}
+	listDeliveriesUsecase := &webhookusecases.ListDeliveriesUsecase{WebhookRepository: webhookRepository, Tracer: tracing.UsecaseTracer{}}
+	replayDeliveryUsecase := &webhookusecases.ReplayDeliveryUsecase{WebhookRepository: webhookRepository, Tracer: tracing.UsecaseTracer{}}
+
+	idempotent := middlewares.Idempotency(idempotencyStore, idempotency.TTLFromEnv(), idempotency.LeaseFromEnv())
+	sessionLimit := newSessionRateLimit()
+	customerToken := middlewares.RequireCustomerToken(redirects, customerRepository)
+	verifiedToken := middlewares.RequireVerifiedCustomerToken(redirects, customerRepository)
+
+	router.GET("/health/live", health.Live)
+	router.GET("/health/ready", readiness.Ready)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/openapi.json", openapi.ServeSpec)
+	router.GET("/docs", openapi.ServeSwaggerUI)
+	router.GET("/docs/redoc", openapi.ServeRedoc)
+	router.GET("/docs/assets/*filepath", openapi.ServeAsset)
+
+	admin := router.Group("/admin", middlewares.RequireAPIKey(auth.RoleAdmin, auth.ParseAPIKeys(os.Getenv("ADMIN_API_KEYS"))))
+	admin.GET("/log-level", logging.GetLevel)
+	admin.PUT("/log-level", logging.SetLevel)
+	admin.GET("/customers/changes", func(c *gin.Context) {
+		controllers.ListCustomerChanges(c, listChangesUsecase)
+	})
+	admin.GET("/customers/duplicates", func(c *gin.Context) {
+		duplicatecontrollers.ListDuplicates(c, listDuplicatesUsecase)
+	})
+	admin.POST("/customers:"+middlewares.CustomMethodParam, middlewares.CustomMethods(map[string]gin.HandlerFunc{
+		":import": func(c *gin.Context) {
+			controllers.ImportCustomers(c, importUsecase, importUpload)
+		},
+	}))
+	admin.POST("/customers/duplicates/:id/dismiss", func(c *gin.Context) {
+		duplicatecontrollers.DismissDuplicate(c, dismissDuplicateUsecase)
+	})
+	admin.POST("/customers/:id/merge", func(c *gin.Context) {
+		duplicatecontrollers.MergeCustomers(c, mergeCustomersUsecase)
+	})
+	admin.DELETE("/customers/:id", func(c *gin.Context) {
+		controllers.EraseCustomer(c, eraseUsecase)
+	})
+	admin.POST("/customers/:id/status:"+middlewares.CustomMethodParam, middlewares.CustomMethods(map[string]gin.HandlerFunc{
+		":activate": func(c *gin.Context) {
+			controllers.ChangeCustomerStatus(c, changeStatusUsecase, entities.CustomerActive)
+		},
+		":suspend": func(c *gin.Context) {
+			controllers.ChangeCustomerStatus(c, changeStatusUsecase, entities.CustomerSuspended)
+		},
+		":close": func(c *gin.Context) {
+			controllers.ChangeCustomerStatus(c, changeStatusUsecase, entities.CustomerClosed)
+		},
+	}))
+	admin.POST("/customers/:id/loyalty/adjustments", func(c *gin.Context) {
+		loyaltycontrollers.AdjustPoints(c, adjustUsecase)
+	})
+	admin.GET("/audit", func(c *gin.Context) {
+		auditcontrollers.ListAuditEntries(c, listAuditUsecase)
 	})
 
-	router.POST("/customers", func(c *gin.Context) {
-		controllers.CreateCustomer(c, createUsecase)
+	admin.POST("/webhooks", func(c *gin.Context) {
+		webhookcontrollers.CreateSubscription(c, createSubscriptionUsecase)
+	})
+	admin.GET("/webhooks", func(c *gin.Context) {
+		webhookcontrollers.ListSubscriptions(c, listSubscriptionsUsecase)
+	})
+	admin.DELETE("/webhooks/:id", func(c *gin.Context) {
+		webhookcontrollers.DeleteSubscription(c, deleteSubscriptionUsecase)
+	})
+	admin.GET("/webhooks/:id/deliveries", func(c *gin.Context) {
+		webhookcontrollers.ListDeliveries(c, listDeliveriesUsecase)
+	})
+	admin.POST("/webhooks/:id/deliveries/:deliveryId/replay", func(c *gin.Context) {
+		webhookcontrollers.ReplayDelivery(c, replayDeliveryUsecase)
 	})
 
-	err := router.Run()
+	// v1: contrato congelado. As rotas sem prefixo continuam respondendo para os clientes antigos.
+	v1Sunset := v1DeprecatedAt.AddDate(0, 6, 0)
+	if sunset, err := time.Parse(time.DateOnly, os.Getenv("API_V1_SUNSET")); err == nil {
+		v1Sunset = sunset
+	}
+
+	for _, v1 := range []*gin.RouterGroup{router.Group("/v1"), router.Group("")} {
+		v1.GET("/customers", middlewares.Deprecated(v1DeprecatedAt, v1Sunset, "/v2/sessions"), sessionLimit, func(c *gin.Context) {
+			controllers.ListCustomers(c, listUsecase)
+		})
+
+		v1.POST("/customers", middlewares.Deprecated(v1DeprecatedAt, v1Sunset, "/v2/customers"), idempotent, func(c *gin.Context) {
+			controllers.CreateCustomer(c, createUsecase)
+		})
+	}
+
+	v2 := router.Group("/v2")
 
+	v2.POST("/sessions", sessionLimit, func(c *gin.Context) {
+		controllers.CreateSession(c, sessionUsecase)
+	})
+
+	// o token de sessão identifica o cliente só pelo CPF; o código enviado a ele confirma a sessão
+	v2.POST("/sessions/current:"+middlewares.CustomMethodParam, customerToken, middlewares.CustomMethods(map[string]gin.HandlerFunc{
+		":sendCode": func(c *gin.Context) {
+			controllers.SendSessionCode(c, sendSessionCodeUsecase)
+		},
+		":verify": func(c *gin.Context) {
+			controllers.VerifySessionCode(c, verifySessionCodeUsecase)
+		},
+	}))
+
+	v2.POST("/customers", idempotent, func(c *gin.Context) {
+		controllers.CreateCustomerV2(c, createUsecase)
+	})
+
+	v2.GET("/customers/me", verifiedToken, func(c *gin.Context) {
+		controllers.GetCurrentCustomer(c, getUsecase)
+	})
+
+	v2.GET("/customers/me/export", verifiedToken, func(c *gin.Context) {
+		controllers.ExportCurrentCustomerData(c, exportUsecase)
+	})
+
+	v2.GET("/customers/me/loyalty", verifiedToken, func(c *gin.Context) {
+		loyaltycontrollers.GetCurrentLoyaltyBalance(c, loyaltyBalanceUsecase)
+	})
+
+	v2.GET("/customers/me/loyalty/transactions", verifiedToken, func(c *gin.Context) {
+		loyaltycontrollers.ListCurrentLoyaltyTransactions(c, loyaltyTransactionsUsecase)
+	})
+
+	v2.GET("/customers/me/addresses", verifiedToken, func(c *gin.Context) {
+		addresscontrollers.ListCurrentAddresses(c, listAddressesUsecase)
+	})
+
+	v2.POST("/customers/me/addresses", verifiedToken, func(c *gin.Context) {
+		addresscontrollers.CreateCurrentAddress(c, createAddressUsecase)
+	})
+
+	v2.GET("/customers/me/addresses/:addressId", verifiedToken, func(c *gin.Context) {
+		addresscontrollers.GetCurrentAddress(c, getAddressUsecase)
+	})
+
+	v2.PATCH("/customers/me/addresses/:addressId", verifiedToken, func(c *gin.Context) {
+		addresscontrollers.UpdateCurrentAddress(c, updateAddressUsecase)
+	})
+
+	v2.DELETE("/customers/me/addresses/:addressId", verifiedToken, func(c *gin.Context) {
+		addresscontrollers.DeleteCurrentAddress(c, deleteAddressUsecase)
+	})
+
+	v2.GET("/customers/me/preferences", verifiedToken, func(c *gin.Context) {
+		preferencescontrollers.GetCurrentPreferences(c, getPreferencesUsecase)
+	})
+
+	v2.PUT("/customers/me/preferences", verifiedToken, func(c *gin.Context) {
+		preferencescontrollers.UpdateCurrentPreferences(c, updatePreferencesUsecase)
+	})
+
+	v2.GET("/customers/me/fiscal-profile", verifiedToken, func(c *gin.Context) {
+		fiscalcontrollers.GetCurrentFiscalProfile(c, getFiscalProfileUsecase)
+	})
+
+	v2.PUT("/customers/me/fiscal-profile", verifiedToken, func(c *gin.Context) {
+		fiscalcontrollers.SaveCurrentFiscalProfile(c, saveFiscalProfileUsecase)
+	})
+
+	v2.DELETE("/customers/me/fiscal-profile", verifiedToken, func(c *gin.Context) {
+		fiscalcontrollers.DeleteCurrentFiscalProfile(c, deleteFiscalProfileUsecase)
+	})
+
+	v2.POST("/customers/me/phone:"+middlewares.CustomMethodParam, verifiedToken, middlewares.CustomMethods(map[string]gin.HandlerFunc{
+		":sendCode": func(c *gin.Context) {
+			phonecontrollers.SendCurrentPhoneCode(c, sendPhoneCodeUsecase)
+		},
+		":verify": func(c *gin.Context) {
+			phonecontrollers.VerifyCurrentPhone(c, verifyPhoneUsecase)
+		},
+	}))
+
+	v2.GET("/ceps/:cep", customerToken, func(c *gin.Context) {
+		addresscontrollers.LookupCEP(c, lookupCEPUsecase)
+	})
+
+	v2.PATCH("/customers/me", verifiedToken, func(c *gin.Context) {
+		controllers.UpdateCurrentCustomer(c, updateUsecase)
+	})
+
+	v2.DELETE("/customers/me", verifiedToken, func(c *gin.Context) {
+		controllers.EraseCurrentCustomer(c, eraseUsecase)
+	})
+
+	serviceOnly := middlewares.RequireAPIKey(auth.RoleService, auth.ParseAPIKeys(os.Getenv("SERVICE_API_KEYS")))
+
+	v2.POST("/customers:"+middlewares.CustomMethodParam, serviceOnly, middlewares.CustomMethods(map[string]gin.HandlerFunc{
+		":batchGet": func(c *gin.Context) {
+			controllers.BatchGetCustomers(c, batchGetUsecase)
+		},
+		":resolveFiscalProfile": func(c *gin.Context) {
+			fiscalcontrollers.ResolveFiscalProfile(c, resolveFiscalProfileUsecase)
+		},
+	}))
+
+	v2.GET("/customers/:id/preferences", serviceOnly, func(c *gin.Context) {
+		preferencescontrollers.GetCustomerPreferences(c, getPreferencesUsecase)
+	})
+
+	v2.POST("/customers/:id/loyalty:"+middlewares.CustomMethodParam, serviceOnly, middlewares.CustomMethods(map[string]gin.HandlerFunc{
+		":earn": func(c *gin.Context) {
+			loyaltycontrollers.EarnPoints(c, earnUsecase)
+		},
+		":redeem": func(c *gin.Context) {
+			loyaltycontrollers.RedeemPoints(c, redeemUsecase)
+		},
+	}))
+
+	return router
+}
+
+// trustedProxies lê TRUSTED_PROXIES: IPs ou CIDRs separados por vírgula
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	return proxies
+}
+
+// newSessionRateLimit protege a emissão de sessão contra enumeração de CPFs.
+// SESSION_RATE_LIMIT_ENABLED=false desliga a proteção.
+func newSessionRateLimit() gin.HandlerFunc {
+	if !utils.GetEnvBool("SESSION_RATE_LIMIT_ENABLED", true) {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return middlewares.SessionRateLimit(&ratelimit.SessionGuard{
+		Store:   ratelimit.NewStoreFromEnv(),
+		Keyring: encryption.Default(),
+		Config:  ratelimit.LoadSessionGuardConfig(),
+	})
+}
+
+func openAPIValidator(maxBodyBytes int64) gin.HandlerFunc {
+	doc, err := openapi.Load()
 	if err != nil {
-		log.Panic(err)
-		return
+		logging.Fatal("Especificação OpenAPI inválida", err)
 	}
+
+	validator, err := openapi.Validator(doc, maxBodyBytes)
+	if err != nil {
+		logging.Fatal("Erro ao criar validador OpenAPI", err)
+	}
+
+	return validator
 }
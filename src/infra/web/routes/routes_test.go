@@ -0,0 +1,209 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/dtos"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/db/repositories"
+	"github.com/CAVAh/api-tech-challenge/src/infra/idempotency"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/health"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/middlewares"
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/openapi"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Rotas operacionais que não fazem parte do contrato da API
+var undocumentedRoutes = map[string]bool{
+	"GET /health/live":           true,
+	"GET /health/ready":          true,
+	"GET /metrics":               true,
+	"GET /openapi.json":          true,
+	"GET /docs":                  true,
+	"GET /docs/redoc":            true,
+	"GET /docs/assets/*filepath": true,
+}
+
+// Métodos customizados ("/recurso:metodo") são registrados no gin como parâmetro
+var customMethodRoutes = map[string][]string{
+	"POST /v2/customers:method":               {"POST /v2/customers:batchGet", "POST /v2/customers:resolveFiscalProfile"},
+	"POST /v2/customers/:id/loyalty:method":   {"POST /v2/customers/{id}/loyalty:earn", "POST /v2/customers/{id}/loyalty:redeem"},
+	"POST /v2/customers/me/phone:method":      {"POST /v2/customers/me/phone:sendCode", "POST /v2/customers/me/phone:verify"},
+	"POST /v2/sessions/current:method":        {"POST /v2/sessions/current:sendCode", "POST /v2/sessions/current:verify"},
+	"POST /admin/customers:method":            {"POST /admin/customers:import"},
+	"POST /admin/customers/:id/status:method": {"POST /admin/customers/{id}/status:activate", "POST /admin/customers/{id}/status:suspend", "POST /admin/customers/{id}/status:close"},
+}
+
+var ginParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+func TestOpenAPISpecMatchesRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("OPENAPI_VALIDATION", "false")
+
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	router := NewRouter(health.NewReadiness(), &repositories.CustomerRepository{}, idempotency.NewMemoryStore())
+
+	registered := []string{}
+	for _, route := range router.Routes() {
+		if methods, ok := customMethodRoutes[route.Method+" "+route.Path]; ok {
+			registered = append(registered, methods...)
+			continue
+		}
+
+		key := route.Method + " " + ginParam.ReplaceAllString(route.Path, "{$1}")
+		if !undocumentedRoutes[key] {
+			registered = append(registered, key)
+		}
+	}
+
+	documented := []string{}
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(registered)
+	sort.Strings(documented)
+	assert.Equal(t, registered, documented, "openapi.json e as rotas do gin divergem")
+}
+
+// Todas as rotas do próprio cliente, inclusive a exportação dos dados, exigem a sessão confirmada por código
+func TestCurrentCustomerRoutesRequireVerifiedSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("OPENAPI_VALIDATION", "false")
+
+	router := NewRouter(health.NewReadiness(), &repositories.CustomerRepository{}, idempotency.NewMemoryStore())
+	token, err := utils.GenerateJWT(uint(7))
+	require.NoError(t, err)
+
+	checked := 0
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/v2/customers/me") {
+			continue
+		}
+
+		path := ginParam.ReplaceAllString(route.Path, "1")
+		req := httptest.NewRequest(route.Method, path, strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code, route.Method+" "+route.Path)
+		assert.Contains(t, w.Body.String(), middlewares.SessionNotVerifiedReason, route.Method+" "+route.Path)
+		checked++
+	}
+
+	assert.Contains(t, routePaths(router), "GET /v2/customers/me/export")
+	assert.Contains(t, routePaths(router), "PATCH /v2/customers/me")
+	assert.Contains(t, routePaths(router), "DELETE /v2/customers/me")
+	assert.Greater(t, checked, 1)
+}
+
+func routePaths(router *gin.Engine) []string {
+	paths := []string{}
+	for _, route := range router.Routes() {
+		paths = append(paths, route.Method+" "+route.Path)
+	}
+
+	return paths
+}
+
+func TestOpenAPISchemasMatchDtos(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	schemas := map[string]interface{}{
+		"CreateCustomer":            dtos.CreateCustomerDto{},
+		"LegacyCreateCustomer":      dtos.LegacyCreateCustomerDto{},
+		"LegacyCustomer":            dtos.LegacyCustomerDto{},
+		"CreateSession":             dtos.CreateSessionDto{},
+		"Customer":                  entities.Customer{},
+		"Session":                   entities.Session{},
+		"SendSessionCode":           dtos.SendSessionCodeDto{},
+		"VerifySessionCode":         dtos.VerifySessionCodeDto{},
+		"SessionCodeSent":           dtos.SessionCodeSentDto{},
+		"IdentityDocument":          dtos.IdentityDocumentDto{},
+		"CustomerPhone":             entities.CustomerPhone{},
+		"VerifyPhone":               dtos.VerifyPhoneDto{},
+		"PhoneCodeSent":             dtos.PhoneCodeSentDto{},
+		"BatchGetCustomers":         dtos.BatchGetCustomersDto{},
+		"BatchGetCustomersResult":   dtos.BatchGetCustomersResultDto{},
+		"UpdateCustomer":            dtos.UpdateCustomerDto{},
+		"CreateWebhookSubscription": dtos.CreateWebhookSubscriptionDto{},
+		"WebhookSubscription":       entities.WebhookSubscription{},
+		"WebhookDelivery":           entities.WebhookDelivery{},
+		"WebhookDeliveryAttempt":    entities.WebhookDeliveryAttempt{},
+		"CustomerChange":            entities.CustomerChange{},
+		"CustomerChangesPage":       dtos.CustomerChangesPageDto{},
+		"AuditEntry":                entities.AuditEntry{},
+		"AuditEntriesPage":          dtos.AuditEntriesPageDto{},
+		"AccessRecord":              dtos.AccessRecordDto{},
+		"CustomerDataExport":        dtos.CustomerDataExportDto{},
+		"EarnPoints":                dtos.EarnPointsDto{},
+		"RedeemPoints":              dtos.RedeemPointsDto{},
+		"AdjustPoints":              dtos.AdjustPointsDto{},
+		"LoyaltyTransaction":        entities.LoyaltyTransaction{},
+		"LoyaltyTransactionsPage":   dtos.LoyaltyTransactionsPageDto{},
+		"LoyaltyBalance":            dtos.LoyaltyBalanceDto{},
+		"LoyaltyPosting":            dtos.LoyaltyPostingDto{},
+		"ExpiringPoints":            entities.ExpiringPoints{},
+		"Address":                   entities.Address{},
+		"CreateAddress":             dtos.CreateAddressDto{},
+		"UpdateAddress":             dtos.UpdateAddressDto{},
+		"CEPAddress":                entities.CEPAddress{},
+		"DietaryPreferences":        entities.DietaryPreferences{},
+		"UpdatePreferences":         dtos.UpdatePreferencesDto{},
+		"FiscalProfile":             entities.FiscalProfile{},
+		"SaveFiscalProfile":         dtos.SaveFiscalProfileDto{},
+		"ResolveFiscalProfile":      dtos.ResolveFiscalProfileDto{},
+		"DuplicateCandidate":        entities.DuplicateCandidate{},
+		"MergeCustomers":            dtos.MergeCustomersDto{},
+		"ChangeCustomerStatus":      dtos.ChangeCustomerStatusDto{},
+		"CustomerStatusChange":      entities.CustomerStatusChange{},
+		"ImportRowResult":           dtos.ImportRowResultDto{},
+		"ImportCustomersSummary":    dtos.ImportCustomersSummaryDto{},
+		"ImportCustomersReport":     dtos.ImportCustomersReportDto{},
+	}
+
+	for name, dto := range schemas {
+		schema, ok := doc.Components.Schemas[name]
+		require.True(t, ok, "schema %s não encontrado", name)
+
+		documented := []string{}
+		for property := range schema.Value.Properties {
+			documented = append(documented, property)
+		}
+
+		sort.Strings(documented)
+		assert.Equal(t, jsonFields(reflect.TypeOf(dto)), documented, "schema %s diverge do DTO", name)
+	}
+}
+
+func jsonFields(dtoType reflect.Type) []string {
+	fields := []string{}
+
+	for i := 0; i < dtoType.NumField(); i++ {
+		name, _, _ := strings.Cut(dtoType.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
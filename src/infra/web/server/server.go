@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/infra/web/health"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+type Config struct {
+	Addr                string
+	ReadTimeout         time.Duration
+	ReadHeaderTimeout   time.Duration
+	WriteTimeout        time.Duration
+	IdleTimeout         time.Duration
+	ShutdownGracePeriod time.Duration
+	ReadinessDrainDelay time.Duration
+}
+
+// LoadConfig lê os timeouts do servidor HTTP das variáveis de ambiente
+func LoadConfig() Config {
+	return Config{
+		Addr:                ":" + utils.GetEnv("PORT", "8080"),
+		ReadTimeout:         utils.GetEnvDuration("HTTP_READ_TIMEOUT", 10*time.Second),
+		ReadHeaderTimeout:   utils.GetEnvDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:        utils.GetEnvDuration("HTTP_WRITE_TIMEOUT", 15*time.Second),
+		IdleTimeout:         utils.GetEnvDuration("HTTP_IDLE_TIMEOUT", 60*time.Second),
+		ShutdownGracePeriod: utils.GetEnvDuration("SHUTDOWN_GRACE_PERIOD", 20*time.Second),
+		ReadinessDrainDelay: utils.GetEnvDuration("READINESS_DRAIN_DELAY", 5*time.Second),
+	}
+}
+
+// Runnable é um servidor auxiliar (gRPC, workers) que acompanha o ciclo de vida do servidor HTTP
+type Runnable interface {
+	Serve() error
+	Shutdown(ctx context.Context) error
+}
+
+// Run inicia o servidor e bloqueia até receber SIGINT/SIGTERM ou algum servidor falhar.
+// No desligamento a instância deixa de estar pronta, espera o balanceador perceber
+// e então drena as requisições em andamento dentro do período de graça.
+func Run(handler http.Handler, readiness *health.Readiness, cfg Config, runnables ...Runnable) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1+len(runnables))
+
+	go func() {
+		slog.Info("Servidor HTTP escutando", "addr", cfg.Addr)
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	for _, runnable := range runnables {
+		go func(runnable Runnable) {
+			serverErr <- runnable.Serve()
+		}(runnable)
+	}
+
+	readiness.SetReady(true)
+
+	var runErr error
+
+	select {
+	case runErr = <-serverErr:
+		if errors.Is(runErr, http.ErrServerClosed) {
+			runErr = nil
+		}
+	case <-ctx.Done():
+	}
+
+	stop()
+	slog.Info("Encerrando servidor, drenando requisições")
+
+	readiness.SetReady(false)
+	time.Sleep(cfg.ReadinessDrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownGracePeriod)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, runnable := range runnables {
+		wg.Add(1)
+		go func(runnable Runnable) {
+			defer wg.Done()
+			if err := runnable.Shutdown(shutdownCtx); err != nil {
+				slog.Warn("Erro ao encerrar servidor auxiliar", "error", err)
+			}
+		}(runnable)
+	}
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Período de graça esgotado, encerrando conexões restantes", "error", err)
+		runErr = errors.Join(runErr, srv.Close())
+	}
+
+	wg.Wait()
+
+	return runErr
+}
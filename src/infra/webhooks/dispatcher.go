@@ -0,0 +1,233 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/infra/metrics"
+	"github.com/CAVAh/api-tech-challenge/src/infra/tracing"
+	"github.com/CAVAh/api-tech-challenge/src/utils"
+)
+
+type DispatcherConfig struct {
+	Interval    time.Duration
+	BatchSize   int
+	Timeout     time.Duration
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+// LoadDispatcherConfig lê a configuração do envio de webhooks das variáveis de ambiente
+func LoadDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		Interval:    utils.GetEnvDuration("WEBHOOK_DISPATCH_INTERVAL", time.Second),
+		BatchSize:   utils.GetEnvInt("WEBHOOK_BATCH_SIZE", 20),
+		Timeout:     utils.GetEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		MaxAttempts: utils.GetEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		BackoffBase: utils.GetEnvDuration("WEBHOOK_BACKOFF_BASE", 30*time.Second),
+		BackoffMax:  utils.GetEnvDuration("WEBHOOK_BACKOFF_MAX", 6*time.Hour),
+	}
+}
+
+// Dispatcher envia as entregas pendentes. Roda como server.Runnable.
+// Respostas 2xx encerram a entrega; qualquer outra resposta ou erro de rede agenda nova tentativa
+// com backoff exponencial até MaxAttempts, quando a entrega vai para a dead-letter (status dead).
+type Dispatcher struct {
+	Store  Store
+	Client *http.Client
+	Config DispatcherConfig
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewDispatcher(store Store, config DispatcherConfig) *Dispatcher {
+	return &Dispatcher{
+		Store: store,
+		Client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: &tracing.Transport{},
+			// redirecionamentos não são seguidos: a URL registrada é a única que recebe o evento
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		Config: config,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+func (d *Dispatcher) Serve() error {
+	defer close(d.done)
+
+	slog.Info("Dispatcher de webhooks iniciado", "interval", d.Config.Interval.String())
+
+	ticker := time.NewTicker(d.Config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return nil
+		case <-ticker.C:
+			if _, err := d.DispatchDue(context.Background()); err != nil {
+				slog.Warn("Erro ao enviar webhooks", "error", err)
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	close(d.stop)
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DispatchDue envia um lote de entregas vencidas e retorna quantas foram processadas
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	// o lease cobre o pior caso do lote inteiro, já que os envios são sequenciais
+	lease := d.Config.Timeout*time.Duration(d.Config.BatchSize) + time.Minute
+
+	deliveries, err := d.Store.ClaimDueDeliveries(ctx, d.Config.BatchSize, lease)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	ids := make([]uint, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.SubscriptionID)
+	}
+
+	found, err := d.Store.FindSubscriptionsByIDs(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[uint]entities.WebhookSubscription, len(found))
+	for _, subscription := range found {
+		subscriptions[subscription.ID] = subscription
+	}
+
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok || !subscription.Active {
+			d.record(ctx, delivery, 0, fmt.Errorf("assinatura %d removida ou inativa", delivery.SubscriptionID), 0, true)
+			continue
+		}
+
+		d.deliver(ctx, delivery, subscription)
+	}
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery entities.WebhookDelivery, subscription entities.WebhookSubscription) {
+	start := time.Now()
+
+	statusCode, err := d.send(ctx, delivery, subscription)
+
+	d.record(ctx, delivery, statusCode, err, time.Since(start), false)
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery entities.WebhookDelivery, subscription entities.WebhookSubscription) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "customer-service-webhooks/1")
+	req.Header.Set(EventIDHeader, delivery.EventID)
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, time.Now(), delivery.Payload))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// descarta no máximo 64KiB do corpo para reaproveitar a conexão
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("resposta %d do destino", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) record(ctx context.Context, delivery entities.WebhookDelivery, statusCode int, sendErr error, duration time.Duration, deadLetter bool) {
+	now := time.Now()
+
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+
+	outcome := "success"
+	switch {
+	case sendErr == nil:
+		delivery.Status = entities.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	case deadLetter || delivery.Attempts >= d.Config.MaxAttempts:
+		delivery.Status = entities.WebhookDeliveryDead
+		delivery.LastError = sendErr.Error()
+		outcome = "dead"
+	default:
+		delivery.Status = entities.WebhookDeliveryPending
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = now.Add(Backoff(delivery.Attempts, d.Config.BackoffBase, d.Config.BackoffMax))
+		outcome = "retry"
+	}
+
+	metrics.WebhookDeliveriesTotal.WithLabelValues(delivery.EventType, outcome).Inc()
+
+	attempt := entities.WebhookDeliveryAttempt{
+		Attempt:    delivery.Attempts,
+		StatusCode: statusCode,
+		Error:      delivery.LastError,
+		DurationMs: duration.Milliseconds(),
+	}
+
+	if err := d.Store.RecordAttempt(ctx, delivery, attempt); err != nil {
+		// sem o registro a entrega volta a ficar disponível quando o lease vencer
+		slog.Warn("Erro ao registrar tentativa de webhook", "deliveryId", delivery.ID, "error", err)
+	}
+
+	if outcome == "dead" {
+		slog.Warn("Webhook enviado para a dead-letter", "deliveryId", delivery.ID, "subscriptionId", delivery.SubscriptionID, "error", delivery.LastError)
+	}
+}
+
+// Backoff devolve a espera antes da próxima tentativa: base * 2^(tentativa-1), limitada a max,
+// com variação de ±20% para espalhar as tentativas quando um destino volta a responder
+func Backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempt && wait < max; i++ {
+		wait *= 2
+	}
+
+	if wait > max {
+		wait = max
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(wait)/5*2+1)) - wait/5
+
+	return wait + jitter
+}
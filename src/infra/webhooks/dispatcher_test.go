@@ -0,0 +1,124 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	subscriptions []entities.WebhookSubscription
+	due           []entities.WebhookDelivery
+	enqueued      []uint
+	recorded      []entities.WebhookDelivery
+	purgedBefore  time.Time
+}
+
+func (s *fakeStore) ActiveSubscriptions(ctx context.Context) ([]entities.WebhookSubscription, error) {
+	return s.subscriptions, nil
+}
+
+func (s *fakeStore) FindSubscriptionsByIDs(ctx context.Context, ids []uint) ([]entities.WebhookSubscription, error) {
+	return s.subscriptions, nil
+}
+
+func (s *fakeStore) EnqueueDelivery(ctx context.Context, subscriptionID uint, envelope events.Envelope, payload []byte) error {
+	s.enqueued = append(s.enqueued, subscriptionID)
+	return nil
+}
+
+func (s *fakeStore) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, error) {
+	return s.due, nil
+}
+
+func (s *fakeStore) RecordAttempt(ctx context.Context, delivery entities.WebhookDelivery, attempt entities.WebhookDeliveryAttempt) error {
+	s.recorded = append(s.recorded, delivery)
+	return nil
+}
+
+func (s *fakeStore) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	s.purgedBefore = before
+	return 0, nil
+}
+
+func testConfig() DispatcherConfig {
+	return DispatcherConfig{BatchSize: 10, Timeout: time.Second, MaxAttempts: 3, BackoffBase: time.Minute, BackoffMax: time.Hour}
+}
+
+func TestEnqueuer_Publish(t *testing.T) {
+	store := &fakeStore{subscriptions: []entities.WebhookSubscription{
+		{ID: 1, EventTypes: []string{events.CustomerCreated}},
+		{ID: 2, EventTypes: []string{events.CustomerErased}},
+	}}
+	enqueuer := Enqueuer{Store: store}
+
+	envelope, err := events.NewCustomerErased(10)
+	require.NoError(t, err)
+
+	assert.NoError(t, enqueuer.Publish(context.Background(), envelope))
+	assert.Equal(t, []uint{2}, store.enqueued)
+}
+
+func TestDispatcher_DispatchDue(t *testing.T) {
+	var signature string
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	store := &fakeStore{
+		subscriptions: []entities.WebhookSubscription{{ID: 1, URL: receiver.URL, Secret: "whsec_segredo-de-teste", Active: true}},
+		due:           []entities.WebhookDelivery{{ID: 5, SubscriptionID: 1, EventType: events.CustomerCreated, Payload: []byte(`{"id":"1"}`)}},
+	}
+
+	dispatcher := NewDispatcher(store, testConfig())
+	processed, err := dispatcher.DispatchDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	assert.NoError(t, Verify("whsec_segredo-de-teste", signature, body, time.Minute, time.Now()))
+	require.Len(t, store.recorded, 1)
+	assert.Equal(t, entities.WebhookDeliverySucceeded, store.recorded[0].Status)
+	assert.Equal(t, 1, store.recorded[0].Attempts)
+}
+
+func TestDispatcher_RetriesAndDeadLetters(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	store := &fakeStore{
+		subscriptions: []entities.WebhookSubscription{{ID: 1, URL: receiver.URL, Secret: "whsec_segredo-de-teste", Active: true}},
+		due: []entities.WebhookDelivery{
+			{ID: 5, SubscriptionID: 1, Attempts: 0},
+			{ID: 6, SubscriptionID: 1, Attempts: 2},
+			{ID: 7, SubscriptionID: 9},
+		},
+	}
+
+	dispatcher := NewDispatcher(store, testConfig())
+	_, err := dispatcher.DispatchDue(context.Background())
+	require.NoError(t, err)
+	require.Len(t, store.recorded, 3)
+
+	retry := store.recorded[0]
+	assert.Equal(t, entities.WebhookDeliveryPending, retry.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, retry.LastStatusCode)
+	assert.True(t, retry.NextAttemptAt.After(time.Now().Add(30*time.Second)))
+
+	assert.Equal(t, entities.WebhookDeliveryDead, store.recorded[1].Status)
+	// assinatura removida vai direto para a dead-letter
+	assert.Equal(t, entities.WebhookDeliveryDead, store.recorded[2].Status)
+}
@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/entities"
+	"github.com/CAVAh/api-tech-challenge/src/core/domain/events"
+)
+
+// Store é o acesso a dados usado pelo enfileirador, pelo dispatcher e pelo purger (repositories.WebhookRepository)
+type Store interface {
+	ActiveSubscriptions(ctx context.Context) ([]entities.WebhookSubscription, error)
+	FindSubscriptionsByIDs(ctx context.Context, ids []uint) ([]entities.WebhookSubscription, error)
+	EnqueueDelivery(ctx context.Context, subscriptionID uint, envelope events.Envelope, payload []byte) error
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery entities.WebhookDelivery, attempt entities.WebhookDeliveryAttempt) error
+	PurgeDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Enqueuer é um messaging.Publisher: recebe os eventos do relay do outbox e cria
+// uma entrega para cada assinatura ativa interessada no tipo do evento
+type Enqueuer struct {
+	Store Store
+}
+
+func (e *Enqueuer) Publish(ctx context.Context, envelope events.Envelope) error {
+	subscriptions, err := e.Store.ActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		if !slices.Contains(subscription.EventTypes, envelope.Type) {
+			continue
+		}
+
+		if err := e.Store.EnqueueDelivery(ctx, subscription.ID, envelope, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Enqueuer) Close() error {
+	return nil
+}
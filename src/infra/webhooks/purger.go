@@ -0,0 +1,64 @@
+package webhooks
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Purger apaga periodicamente as entregas encerradas há mais de Retention, com o payload e o log de
+// tentativas. Roda como server.Runnable; depois disso a entrega não pode mais ser reenviada.
+type Purger struct {
+	Store     Store
+	Retention time.Duration
+	Interval  time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewPurger(store Store, retention time.Duration, interval time.Duration) *Purger {
+	return &Purger{
+		Store:     store,
+		Retention: retention,
+		Interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+func (p *Purger) Serve() error {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return nil
+		case <-ticker.C:
+			p.purge(context.Background(), time.Now())
+		}
+	}
+}
+
+func (p *Purger) Shutdown(ctx context.Context) error {
+	close(p.stop)
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Purger) purge(ctx context.Context, now time.Time) {
+	purged, err := p.Store.PurgeDeliveries(ctx, now.Add(-p.Retention))
+	if err != nil {
+		slog.Warn("Erro ao apagar as entregas de webhook encerradas", "error", err)
+	} else if purged > 0 {
+		slog.Info("Entregas de webhook encerradas apagadas", "deliveries", purged)
+	}
+}
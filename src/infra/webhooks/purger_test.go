@@ -0,0 +1,19 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurger_PurgesDeliveriesOlderThanRetention(t *testing.T) {
+	store := &fakeStore{}
+	purger := NewPurger(store, 30*24*time.Hour, time.Hour)
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+
+	purger.purge(context.Background(), now)
+
+	assert.Equal(t, now.Add(-30*24*time.Hour), store.purgedBefore)
+}
@@ -0,0 +1,81 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cabeçalhos enviados em cada entrega. O formato da assinatura está descrito em docs/webhooks.md.
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventIDHeader   = "X-Webhook-Event-Id"
+	EventTypeHeader = "X-Webhook-Event-Type"
+	DeliveryHeader  = "X-Webhook-Delivery-Id"
+)
+
+var (
+	ErrInvalidSignatureHeader = errors.New("cabeçalho de assinatura malformado")
+	ErrSignatureMismatch      = errors.New("assinatura não confere")
+	ErrSignatureExpired       = errors.New("timestamp da assinatura fora da tolerância")
+)
+
+// Sign gera o valor do X-Webhook-Signature: t=<unix>,v1=<hex(HMAC-SHA256(segredo, "<unix>.<corpo>"))>
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	return "t=" + unix + ",v1=" + computeSignature(secret, unix, body)
+}
+
+// Verify confere a assinatura e recusa timestamps mais distantes que tolerance de now,
+// o que impede a reutilização de uma entrega capturada
+func Verify(secret string, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var unix string
+	var signatures []string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return ErrInvalidSignatureHeader
+		}
+
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignatureHeader
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := computeSignature(secret, unix, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return ErrSignatureMismatch
+}
+
+func computeSignature(secret string, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
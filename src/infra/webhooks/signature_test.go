@@ -0,0 +1,45 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	body := []byte(`{"type":"CustomerCreated"}`)
+	header := Sign("whsec_segredo-de-teste", now, body)
+
+	assert.Equal(t, "t=1760000000,v1=", header[:16])
+	assert.NoError(t, Verify("whsec_segredo-de-teste", header, body, 5*time.Minute, now.Add(time.Minute)))
+
+	t.Run("corpo alterado", func(t *testing.T) {
+		err := Verify("whsec_segredo-de-teste", header, []byte(`{"type":"CustomerErased"}`), 5*time.Minute, now)
+		assert.ErrorIs(t, err, ErrSignatureMismatch)
+	})
+
+	t.Run("segredo errado", func(t *testing.T) {
+		err := Verify("outro-segredo-qualquer", header, body, 5*time.Minute, now)
+		assert.ErrorIs(t, err, ErrSignatureMismatch)
+	})
+
+	t.Run("fora da tolerância", func(t *testing.T) {
+		err := Verify("whsec_segredo-de-teste", header, body, 5*time.Minute, now.Add(10*time.Minute))
+		assert.ErrorIs(t, err, ErrSignatureExpired)
+	})
+
+	t.Run("cabeçalho malformado", func(t *testing.T) {
+		err := Verify("whsec_segredo-de-teste", "v1=abc", body, 5*time.Minute, now)
+		assert.ErrorIs(t, err, ErrInvalidSignatureHeader)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, expected := range map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 4: 4 * time.Minute, 20: time.Hour} {
+		wait := Backoff(attempt, 30*time.Second, time.Hour)
+		assert.GreaterOrEqual(t, wait, expected-expected/5, "tentativa %d", attempt)
+		assert.LessOrEqual(t, wait, expected+expected/5, "tentativa %d", attempt)
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetEnv returns the value of the environment variable or the fallback when it is unset
+func GetEnv(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// GetEnvDuration parses the environment variable as a time.Duration (e.g. "15s")
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+// GetEnvInt parses the environment variable as an int
+func GetEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+// GetEnvBool parses the environment variable as a bool
+func GetEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -11,20 +12,43 @@ import (
 var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
 var jwtIssuer = (os.Getenv("JWT_ISSUER"))
 
+// TokenTTL é a validade dos tokens emitidos
+const TokenTTL = time.Hour * 24
+
+// VerifiedTokenTTL é a validade padrão do token de sessão confirmada
+const VerifiedTokenTTL = 15 * time.Minute
+
+// ScopeVerifiedCustomer marca o token emitido depois que o cliente confirmou a sessão com um código
+// enviado por SMS ou email; só ele dá acesso aos dados pessoais em /v2/customers/me
+const ScopeVerifiedCustomer = "customer:verified"
+
 type CustomClaims struct {
 	CustomerId string `json:"customerId"`
+	// PreferencesHash lets kiosks notice that the customer's dietary preferences changed
+	PreferencesHash string `json:"prefsHash,omitempty"`
+	Scope           string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // GenerateJWT generates a JWT token with a given payload
 func GenerateJWT(customerID interface{}) (string, error) {
+	return NewJWT(customerID, time.Now().Add(TokenTTL))
+}
+
+// NewJWT generates a JWT token for the customer that expires at the given time
+func NewJWT(customerID interface{}, expiresAt time.Time) (string, error) {
+	return NewSessionJWT(customerID, "", expiresAt)
+}
+
+// NewSessionJWT generates a token like NewJWT, embedding the preferences hash when it is not empty
+func NewSessionJWT(customerID interface{}, preferencesHash string, expiresAt time.Time) (string, error) {
 	var claims CustomClaims
 
 	if customerID == nil {
 		claims = CustomClaims{
 			RegisteredClaims: jwt.RegisteredClaims{
 				Issuer:    jwtIssuer,
-				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
 			},
 		}
 	} else {
@@ -32,11 +56,13 @@ func GenerateJWT(customerID interface{}) (string, error) {
 			CustomerId: fmt.Sprintf("%v", customerID),
 			RegisteredClaims: jwt.RegisteredClaims{
 				Issuer:    jwtIssuer,
-				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
 			},
 		}
 	}
 
+	claims.PreferencesHash = preferencesHash
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString(jwtSecret)
@@ -47,3 +73,36 @@ func GenerateJWT(customerID interface{}) (string, error) {
 
 	return tokenString, nil
 }
+
+// NewVerifiedJWT generates the token of a customer that confirmed the session with a verification code
+func NewVerifiedJWT(customerID uint, expiresAt time.Time) (string, error) {
+	claims := CustomClaims{
+		CustomerId: fmt.Sprintf("%v", customerID),
+		Scope:      ScopeVerifiedCustomer,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+}
+
+// ParseJWT validates the signature, issuer and expiration of a token generated by NewJWT
+func ParseJWT(tokenString string) (*CustomClaims, error) {
+	claims := &CustomClaims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(jwtIssuer), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, errors.New("token inválido")
+	}
+
+	return claims, nil
+}